@@ -88,8 +88,13 @@ to quickly create a Cobra application.`,
 			CREATE TABLE IF NOT EXISTS refresh_tokens (
 				id UUID DEFAULT gen_random_uuid(),
 				user_id UUID NOT NULL,
+				family_id UUID DEFAULT gen_random_uuid() NOT NULL,
+				device_name VARCHAR,
+				user_agent VARCHAR,
+				ip_address VARCHAR,
     			create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
     			update_time TIMESTAMP,
+    			rotate_time TIMESTAMP,
 				CONSTRAINT refresh_tokens_pk PRIMARY KEY (id),
 				CONSTRAINT fk_user
         			FOREIGN KEY (user_id) 
@@ -105,6 +110,23 @@ to quickly create a Cobra application.`,
 			clogg.Error(ctx, "error creating refresh_tokens table", clogg.String("error", err.Error()))
 		}
 
+		// Add the rotation and device columns to refresh tokens tables created before them
+		stmt, err = db.Prepare(`
+			ALTER TABLE refresh_tokens
+				ADD COLUMN IF NOT EXISTS family_id UUID DEFAULT gen_random_uuid() NOT NULL,
+				ADD COLUMN IF NOT EXISTS rotate_time TIMESTAMP,
+				ADD COLUMN IF NOT EXISTS device_name VARCHAR,
+				ADD COLUMN IF NOT EXISTS user_agent VARCHAR,
+				ADD COLUMN IF NOT EXISTS ip_address VARCHAR
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter refresh_tokens table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering refresh_tokens table", clogg.String("error", err.Error()))
+		}
+
 		// Create access tokens table if not exists
 		stmt, err = db.Prepare(`
 			CREATE TABLE IF NOT EXISTS access_tokens (
@@ -132,21 +154,101 @@ to quickly create a Cobra application.`,
 			clogg.Error(ctx, "error creating access_tokens table", clogg.String("error", err.Error()))
 		}
 
+		// Create notebooks table if not exists
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS notebooks (
+				id UUID DEFAULT gen_random_uuid(),
+				user_id UUID NOT NULL,
+				parent_id UUID,
+				name VARCHAR NOT NULL,
+				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT notebooks_pk PRIMARY KEY (id),
+				CONSTRAINT fk_user
+					FOREIGN KEY (user_id) 
+					REFERENCES users(id)
+					ON DELETE CASCADE,
+				CONSTRAINT fk_parent
+					FOREIGN KEY (parent_id) 
+					REFERENCES notebooks(id)
+					ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notebooks table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notebooks table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to walk the notebooks hierarchy
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notebooks_parent_id_idx ON notebooks (parent_id)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notebooks parent index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notebooks parent index", clogg.String("error", err.Error()))
+		}
+
+		// Create the sequence of the changes to the notes and the files used by the delta sync
+		stmt, err = db.Prepare(`
+			CREATE SEQUENCE IF NOT EXISTS change_seq
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create change sequence", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating change sequence", clogg.String("error", err.Error()))
+		}
+
+		// Create the function that moves a changed row to the end of the change sequence
+		stmt, err = db.Prepare(`
+			CREATE OR REPLACE FUNCTION set_change_seq() RETURNS trigger AS $$
+			BEGIN
+				NEW.change_seq := nextval('change_seq');
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create change sequence function", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating change sequence function", clogg.String("error", err.Error()))
+		}
+
 		// Create notes table if not exists
 		stmt, err = db.Prepare(`
 			CREATE TABLE IF NOT EXISTS notes (
 				id UUID DEFAULT gen_random_uuid(),
 				user_id UUID NOT NULL,
+				notebook_id UUID,
 				title VARCHAR,
 				content VARCHAR,
     			create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
     			update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
     			delete_time TIMESTAMP,
+				version INTEGER DEFAULT 1 NOT NULL,
+				change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL,
+				search_vector TSVECTOR GENERATED ALWAYS AS (
+					setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+					setweight(to_tsvector('english', COALESCE(content, '')), 'B')
+				) STORED,
 				CONSTRAINT notes_pk PRIMARY KEY (id),
 				CONSTRAINT fk_user
         			FOREIGN KEY (user_id) 
         			REFERENCES users(id)
-        			ON DELETE CASCADE
+        			ON DELETE CASCADE,
+				CONSTRAINT fk_notebook
+					FOREIGN KEY (notebook_id) 
+					REFERENCES notebooks(id)
+					ON DELETE SET NULL
 			)
 		`)
 		if err != nil {
@@ -157,6 +259,183 @@ to quickly create a Cobra application.`,
 			clogg.Error(ctx, "error creating notes table", clogg.String("error", err.Error()))
 		}
 
+		// Add the full-text search column to notes tables created before it
+		stmt, err = db.Prepare(`
+			ALTER TABLE notes
+				ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+					setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+					setweight(to_tsvector('english', COALESCE(content, '')), 'B')
+				) STORED
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter notes table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering notes table", clogg.String("error", err.Error()))
+		}
+
+		// Add the notebook column to notes tables created before it
+		stmt, err = db.Prepare(`
+			ALTER TABLE notes
+				ADD COLUMN IF NOT EXISTS notebook_id UUID REFERENCES notebooks(id) ON DELETE SET NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter notes table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering notes table", clogg.String("error", err.Error()))
+		}
+
+		// Add the version column used for the optimistic concurrency of the updates to notes tables created before it
+		stmt, err = db.Prepare(`
+			ALTER TABLE notes
+				ADD COLUMN IF NOT EXISTS version INTEGER DEFAULT 1 NOT NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter notes table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering notes table", clogg.String("error", err.Error()))
+		}
+
+		// Add the pinned and archived states to notes tables created before them
+		stmt, err = db.Prepare(`
+			ALTER TABLE notes
+				ADD COLUMN IF NOT EXISTS pinned_time TIMESTAMP,
+				ADD COLUMN IF NOT EXISTS archive_time TIMESTAMP
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter notes table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering notes table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to purge the expired notes of the trash
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_delete_time_idx ON notes (delete_time, id) WHERE delete_time IS NOT NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes trash index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes trash index", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to list the archived notes of a user
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_user_id_archive_time_idx ON notes (user_id, archive_time, id) WHERE archive_time IS NOT NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes archive index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes archive index", clogg.String("error", err.Error()))
+		}
+
+		// Add the change sequence column to notes tables created before it
+		stmt, err = db.Prepare(`
+			ALTER TABLE notes
+				ADD COLUMN IF NOT EXISTS change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter notes table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering notes table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to list the changed notes of a user
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_user_id_change_seq_idx ON notes (user_id, change_seq)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes change index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes change index", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to sort and filter the notes of a user by create time
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_user_id_create_time_idx ON notes (user_id, create_time, id)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes create time index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes create time index", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to sort and filter the notes of a user by update time
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_user_id_update_time_idx ON notes (user_id, update_time, id)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes update time index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes update time index", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to sort and filter the notes of a user by title
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_user_id_title_idx ON notes (user_id, (COALESCE(title, '')), id)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes title index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes title index", clogg.String("error", err.Error()))
+		}
+
+		// Move the updated notes to the end of the change sequence
+		stmt, err = db.Prepare(`
+			CREATE OR REPLACE TRIGGER notes_change_seq BEFORE UPDATE ON notes FOR EACH ROW EXECUTE FUNCTION set_change_seq()
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes change trigger", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes change trigger", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to filter the notes by notebook
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_notebook_id_idx ON notes (notebook_id)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes notebook index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes notebook index", clogg.String("error", err.Error()))
+		}
+
+		// Create the full-text search index of the notes
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS notes_search_vector_idx ON notes USING GIN (search_vector)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes search index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes search index", clogg.String("error", err.Error()))
+		}
+
 		// Create files table if not exists
 		stmt, err = db.Prepare(`
 			CREATE TABLE IF NOT EXISTS files (
@@ -167,6 +446,11 @@ to quickly create a Cobra application.`,
 				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
 				update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
 				delete_time TIMESTAMP,
+				change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL,
+				processing_status VARCHAR DEFAULT 'queued' NOT NULL,
+				duration DOUBLE PRECISION,
+				sample_rate INTEGER,
+				waveform REAL[],
 				CONSTRAINT pk PRIMARY KEY (id),
 				CONSTRAINT fk_note
 					FOREIGN KEY (note_id) 
@@ -182,6 +466,411 @@ to quickly create a Cobra application.`,
 			clogg.Error(ctx, "error creating files table", clogg.String("error", err.Error()))
 		}
 
+		// Add the change sequence column to files tables created before it
+		stmt, err = db.Prepare(`
+			ALTER TABLE files
+				ADD COLUMN IF NOT EXISTS change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter files table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering files table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to list the changed files
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS files_change_seq_idx ON files (change_seq)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create files change index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating files change index", clogg.String("error", err.Error()))
+		}
+
+		// Move the updated files to the end of the change sequence
+		stmt, err = db.Prepare(`
+			CREATE OR REPLACE TRIGGER files_change_seq BEFORE UPDATE ON files FOR EACH ROW EXECUTE FUNCTION set_change_seq()
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create files change trigger", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating files change trigger", clogg.String("error", err.Error()))
+		}
+
+		// Add the processing status column to files tables created before it, the existing files are backfilled below
+		stmt, err = db.Prepare(`
+			ALTER TABLE files
+				ADD COLUMN IF NOT EXISTS processing_status VARCHAR
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter files processing status", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering files processing status", clogg.String("error", err.Error()))
+		}
+
+		// Backfill the processing status of the existing files, the files without a processed copy are queued
+		stmt, err = db.Prepare(`
+			UPDATE files SET
+				processing_status = CASE WHEN processed_file IS NULL OR processed_file = '' THEN 'queued' ELSE 'succeeded' END
+			WHERE processing_status IS NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to backfill files processing status", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error backfilling files processing status", clogg.String("error", err.Error()))
+		}
+
+		// Set the default processing status of the new files
+		stmt, err = db.Prepare(`
+			ALTER TABLE files
+				ALTER COLUMN processing_status SET DEFAULT 'queued',
+				ALTER COLUMN processing_status SET NOT NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to set files processing status default", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error setting files processing status default", clogg.String("error", err.Error()))
+		}
+
+		// Add the audio metadata columns to files tables created before them, they are set when the audio files are processed
+		stmt, err = db.Prepare(`
+			ALTER TABLE files
+				ADD COLUMN IF NOT EXISTS duration DOUBLE PRECISION,
+				ADD COLUMN IF NOT EXISTS sample_rate INTEGER,
+				ADD COLUMN IF NOT EXISTS waveform REAL[]
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to alter files audio metadata", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error altering files audio metadata", clogg.String("error", err.Error()))
+		}
+
+		// Create file_jobs table if not exists, the queue of the files to process
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS file_jobs (
+				id UUID DEFAULT gen_random_uuid(),
+				file_id UUID NOT NULL,
+				status VARCHAR DEFAULT 'queued' NOT NULL,
+				attempts INTEGER DEFAULT 0 NOT NULL,
+				last_error VARCHAR,
+				run_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				lease_owner VARCHAR,
+				lease_expire_time TIMESTAMP,
+				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT file_jobs_pk PRIMARY KEY (id),
+				CONSTRAINT file_jobs_file_fk
+					FOREIGN KEY (file_id)
+					REFERENCES files(id)
+					ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create file_jobs table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating file_jobs table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to claim the due file jobs
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS file_jobs_run_time_idx ON file_jobs (run_time) WHERE status IN ('queued', 'running')
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create file_jobs run time index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating file_jobs run time index", clogg.String("error", err.Error()))
+		}
+
+		// Queue the files that were never processed and have no job, they were left unprocessed by a failed processing
+		stmt, err = db.Prepare(`
+			INSERT INTO file_jobs (file_id)
+			SELECT id FROM files
+			WHERE processing_status = 'queued' AND NOT EXISTS (SELECT 1 FROM file_jobs WHERE file_jobs.file_id = files.id)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to queue unprocessed files", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error queueing unprocessed files", clogg.String("error", err.Error()))
+		}
+
+		// Create file_renditions table if not exists, the resized copies of the processed images
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS file_renditions (
+				id UUID DEFAULT gen_random_uuid(),
+				file_id UUID NOT NULL,
+				size INTEGER NOT NULL,
+				object_name VARCHAR NOT NULL,
+				width INTEGER NOT NULL,
+				height INTEGER NOT NULL,
+				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT file_renditions_pk PRIMARY KEY (id),
+				CONSTRAINT file_renditions_file_id_size_key UNIQUE (file_id, size),
+				CONSTRAINT file_renditions_file_fk
+					FOREIGN KEY (file_id)
+					REFERENCES files(id)
+					ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create file_renditions table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating file_renditions table", clogg.String("error", err.Error()))
+		}
+
+		// Create tags table if not exists
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS tags (
+				id UUID DEFAULT gen_random_uuid(),
+				user_id UUID NOT NULL,
+				name VARCHAR NOT NULL,
+				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT tags_pk PRIMARY KEY (id),
+				CONSTRAINT tags_user_id_name_key UNIQUE (user_id, name),
+				CONSTRAINT fk_user
+					FOREIGN KEY (user_id) 
+					REFERENCES users(id)
+					ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create tags table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating tags table", clogg.String("error", err.Error()))
+		}
+
+		// Create note tags table if not exists
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS note_tags (
+				note_id UUID NOT NULL,
+				tag_id UUID NOT NULL,
+				user_id UUID NOT NULL,
+				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT note_tags_pk PRIMARY KEY (note_id, tag_id),
+				CONSTRAINT fk_note
+					FOREIGN KEY (note_id) 
+					REFERENCES notes(id)
+					ON DELETE CASCADE,
+				CONSTRAINT fk_tag
+					FOREIGN KEY (tag_id) 
+					REFERENCES tags(id)
+					ON DELETE CASCADE,
+				CONSTRAINT fk_user
+					FOREIGN KEY (user_id) 
+					REFERENCES users(id)
+					ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create note_tags table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating note_tags table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to filter the notes by tag
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS note_tags_tag_id_idx ON note_tags (tag_id)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create note_tags tag index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating note_tags tag index", clogg.String("error", err.Error()))
+		}
+
+		// Create note revisions table if not exists
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS note_revisions (
+				id UUID DEFAULT gen_random_uuid(),
+				note_id UUID NOT NULL,
+				user_id UUID NOT NULL,
+				title VARCHAR,
+				content VARCHAR,
+				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT note_revisions_pk PRIMARY KEY (id),
+				CONSTRAINT fk_note
+					FOREIGN KEY (note_id) 
+					REFERENCES notes(id)
+					ON DELETE CASCADE,
+				CONSTRAINT fk_user
+					FOREIGN KEY (user_id) 
+					REFERENCES users(id)
+					ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create note_revisions table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating note_revisions table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to list the revisions of a note
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS note_revisions_note_id_create_time_idx ON note_revisions (note_id, create_time DESC)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create note_revisions note index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating note_revisions note index", clogg.String("error", err.Error()))
+		}
+
+		// Create object_deletions table if not exists, the outbox of the objects to remove from the object storage service
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS object_deletions (
+				id UUID DEFAULT gen_random_uuid(),
+				bucket VARCHAR NOT NULL,
+				object_name VARCHAR NOT NULL,
+				attempts INTEGER DEFAULT 0 NOT NULL,
+				last_error VARCHAR,
+				next_attempt_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				dead_time TIMESTAMP,
+				create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT object_deletions_pk PRIMARY KEY (id)
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create object_deletions table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating object_deletions table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to list the object deletions due for an attempt
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS object_deletions_next_attempt_time_idx ON object_deletions (next_attempt_time) WHERE dead_time IS NULL
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create object_deletions attempt index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating object_deletions attempt index", clogg.String("error", err.Error()))
+		}
+
+		// Create tombstones table if not exists, the hard deleted notes and files are reported by the delta sync
+		stmt, err = db.Prepare(`
+			CREATE TABLE IF NOT EXISTS tombstones (
+				id UUID DEFAULT gen_random_uuid(),
+				user_id UUID NOT NULL,
+				entity_type VARCHAR NOT NULL,
+				entity_id UUID NOT NULL,
+				note_id UUID NOT NULL,
+				change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL,
+				delete_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				CONSTRAINT tombstones_pk PRIMARY KEY (id)
+			)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create tombstones table", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating tombstones table", clogg.String("error", err.Error()))
+		}
+
+		// Create the index used to list the tombstones of a user
+		stmt, err = db.Prepare(`
+			CREATE INDEX IF NOT EXISTS tombstones_user_id_change_seq_idx ON tombstones (user_id, change_seq)
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create tombstones change index", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating tombstones change index", clogg.String("error", err.Error()))
+		}
+
+		// Create the function that keeps a tombstone of a hard deleted note
+		stmt, err = db.Prepare(`
+			CREATE OR REPLACE FUNCTION create_note_tombstone() RETURNS trigger AS $$
+			BEGIN
+				INSERT INTO tombstones (user_id, entity_type, entity_id, note_id) VALUES (OLD.user_id, 'note', OLD.id, OLD.id);
+				RETURN OLD;
+			END;
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create note tombstone function", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating note tombstone function", clogg.String("error", err.Error()))
+		}
+
+		// Keep a tombstone of the hard deleted notes
+		stmt, err = db.Prepare(`
+			CREATE OR REPLACE TRIGGER notes_tombstone AFTER DELETE ON notes FOR EACH ROW EXECUTE FUNCTION create_note_tombstone()
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create notes tombstone trigger", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating notes tombstone trigger", clogg.String("error", err.Error()))
+		}
+
+		// Create the function that keeps a tombstone of a hard deleted file, the files deleted along with their note are covered by the tombstone of the note
+		stmt, err = db.Prepare(`
+			CREATE OR REPLACE FUNCTION create_file_tombstone() RETURNS trigger AS $$
+			BEGIN
+				INSERT INTO tombstones (user_id, entity_type, entity_id, note_id)
+				SELECT notes.user_id, 'file', OLD.id, OLD.note_id FROM notes WHERE notes.id = OLD.note_id;
+				RETURN OLD;
+			END;
+			$$ LANGUAGE plpgsql
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create file tombstone function", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating file tombstone function", clogg.String("error", err.Error()))
+		}
+
+		// Keep a tombstone of the hard deleted files
+		stmt, err = db.Prepare(`
+			CREATE OR REPLACE TRIGGER files_tombstone AFTER DELETE ON files FOR EACH ROW EXECUTE FUNCTION create_file_tombstone()
+		`)
+		if err != nil {
+			clogg.Error(ctx, "error preparing sql to create files tombstone trigger", clogg.String("error", err.Error()))
+		}
+		_, err = stmt.Exec()
+		if err != nil {
+			clogg.Error(ctx, "error creating files tombstone trigger", clogg.String("error", err.Error()))
+		}
+
 		clogg.Info(ctx, "Database tables created successfully")
 	},
 }
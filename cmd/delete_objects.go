@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/data"
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/oss"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// deleteObjectsCmd represents the delete-objects command
+var deleteObjectsCmd = &cobra.Command{
+	Use:   "delete-objects",
+	Short: "Remove the objects of the deleted files from the object storage service",
+	Long: `Remove the objects of the deleted files from the object storage service. The objects are recorded
+in an outbox by the transaction that deletes their files and removed once it is committed.
+
+A failed removal is retried with a backoff that doubles on every attempt, starting at OBJECT_DELETION_RETRY_BACKOFF.
+After OBJECT_DELETION_MAX_ATTEMPTS attempts it is dead-lettered, use --requeue-dead to retry the dead-lettered objects.
+The server removes the objects every OBJECT_DELETION_INTERVAL, set it to 0 to leave it to this command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Set up clogg
+		handler := slog.NewJSONHandler(os.Stdout, nil)
+		logger := clogg.GetLogger(clogg.LoggerConfig{
+			BufferSize: 100,
+			Handler:    handler,
+		})
+		defer logger.Shutdown()
+
+		// Config
+		cfg := config.LoadServerConfig()
+
+		// Flags, the zero values use the config
+		opts := service.DeleteObjectsOptions{
+			BatchSize:    cfg.ObjectDeletionBatchSize,
+			MaxAttempts:  cfg.ObjectDeletionMaxAttempts,
+			RetryBackoff: cfg.ObjectDeletionRetryBackoff,
+		}
+		batchSize, err := cmd.Flags().GetInt("batch-size")
+		if err != nil || batchSize < 0 {
+			clogg.Error(ctx, "batch-size flag must be a valid non negative integer")
+			os.Exit(1)
+		}
+		if batchSize > 0 {
+			opts.BatchSize = batchSize
+		}
+		requeueDead, err := cmd.Flags().GetBool("requeue-dead")
+		if err != nil {
+			clogg.Error(ctx, "error getting requeue-dead flag", clogg.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		// Database connection
+		db, err := database.Open(ctx, cfg.DatabaseUrl)
+		if err != nil {
+			clogg.Error(ctx, "error opening database", clogg.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer database.Close(ctx, db)
+
+		// Database queries
+		dbQueries := database.New(db)
+
+		// Object storage service
+		oss := oss.NewDigitalOceanWithMinio(cfg)
+
+		// Healthcheck
+		if err := oss.HealthCheck(); err != nil {
+			clogg.Error(ctx, "error checking object storage service health", clogg.String("error", err.Error()))
+		}
+
+		// Datasources
+		objectDeletionDatabaseDs := data.NewObjectDeletionDatabaseDs(dbQueries)
+
+		// Repositories
+		objectDeletionRepository := domain.NewObjectDeletionRepository(objectDeletionDatabaseDs, oss)
+
+		// Services
+		objectDeletionService := service.NewObjectDeletionService(objectDeletionRepository, db)
+
+		if requeueDead {
+			requeued, err := objectDeletionService.RequeueDeadObjectDeletions(ctx)
+			if err != nil {
+				clogg.Error(ctx, "error requeuing the dead-lettered object deletions", clogg.String("error", err.Error()))
+				os.Exit(1)
+			}
+			clogg.Info(ctx, "dead-lettered object deletions requeued", clogg.Int("deletions", int(requeued)))
+		}
+
+		if err := deleteObjects(ctx, objectDeletionService, opts); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// deleteObjects runs the object deletion worker and logs its summary
+func deleteObjects(ctx context.Context, objectDeletionService service.ObjectDeletionService, opts service.DeleteObjectsOptions) error {
+	start := time.Now()
+	summary, err := objectDeletionService.DeleteObjects(ctx, opts)
+	attrs := []clogg.Attr{
+		clogg.Int("removed", summary.Removed),
+		clogg.Int("failed", summary.Failed),
+		clogg.Int("dead_lettered", summary.DeadLettered),
+		clogg.Int("batches", summary.Batches),
+		clogg.String("duration", time.Since(start).String()),
+	}
+	if err != nil {
+		clogg.Error(ctx, "error deleting objects", append(attrs, clogg.String("error", err.Error()))...)
+		return err
+	}
+	// An idle run is not logged, the server runs the worker every interval
+	if summary.Batches > 0 {
+		clogg.Info(ctx, "objects deleted", attrs...)
+	}
+	return nil
+}
+
+// scheduleObjectDeletions runs the object deletion worker every interval until the context is done
+func scheduleObjectDeletions(ctx context.Context, objectDeletionService service.ObjectDeletionService, interval time.Duration, opts service.DeleteObjectsOptions) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleteObjects(ctx, objectDeletionService, opts)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(deleteObjectsCmd)
+	deleteObjectsCmd.Flags().Int("batch-size", 0, "Number of objects removed in each transaction, defaults to OBJECT_DELETION_BATCH_SIZE")
+	deleteObjectsCmd.Flags().Bool("requeue-dead", false, "Retry the dead-lettered objects before removing the due ones")
+}
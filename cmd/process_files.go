@@ -5,15 +5,18 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
 	"log/slog"
 	"os"
 
 	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/cache"
 	"github.com/daniarmas/notes/internal/config"
 	"github.com/daniarmas/notes/internal/data"
 	"github.com/daniarmas/notes/internal/database"
 	"github.com/daniarmas/notes/internal/domain"
 	"github.com/daniarmas/notes/internal/oss"
+	"github.com/daniarmas/notes/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -44,30 +47,10 @@ to quickly create a Cobra application.`,
 		// Config
 		cfg := config.LoadServerConfig()
 
-		// Database connection
-		db, err := database.Open(ctx, cfg.DatabaseUrl)
-		if err != nil {
-			clogg.Error(ctx, "error opening database", clogg.String("error", err.Error()))
-			os.Exit(1)
-		}
-		defer database.Close(ctx, db)
-
-		// Database queries
-		dbQueries := database.New(db)
-
-		// Object storage service
-		oss := oss.NewDigitalOceanWithMinio(cfg)
-
-		// Healthcheck
-		if err := oss.HealthCheck(); err != nil {
-			clogg.Error(ctx, "error checking object storage service health", clogg.String("error", err.Error()))
-		}
-
-		// Datasources
-		fileDatabaseDs := data.NewFileDatabaseDs(dbQueries)
-
-		// Repositories
-		fileRepository := domain.NewFileRepository(fileDatabaseDs, oss, cfg)
+		// Connections and repositories
+		fp := openFileProcessing(ctx, cfg)
+		defer fp.close()
+		db, fileRepository, noteRepository, noteEventRepository := fp.db, fp.fileRepository, fp.noteRepository, fp.noteEventRepository
 
 		// Access files
 		files, err := cmd.Flags().GetStringSlice("files")
@@ -76,29 +59,21 @@ to quickly create a Cobra application.`,
 			os.Exit(1)
 		}
 
-		// Process files concurrently
 		for _, file := range files {
-			// Start the sql transaction
-			tx, err := db.BeginTx(ctx, nil)
+			processedFile, err := processFile(ctx, db, fileRepository, file)
 			if err != nil {
-				clogg.Error(ctx, "error starting transaction", clogg.String("error", err.Error()))
-				os.Exit(0)
+				clogg.Error(ctx, "error processing file", clogg.String("error", err.Error()))
+				continue
 			}
 
-			// Defer the transaction rollback or commit
-			defer func() {
-				if p := recover(); p != nil {
-					tx.Rollback()
-					panic(p)
-				} else if err != nil {
-					tx.Rollback()
-				} else {
-					err = tx.Commit()
-				}
-			}()
-
-			if err := fileRepository.Process(ctx, tx, file); err != nil {
-				clogg.Error(ctx, "error processing file", clogg.String("error", err.Error()))
+			// Notify the owner of the note once the processed file is committed
+			note, err := noteRepository.GetNote(ctx, processedFile.NoteId)
+			if err != nil {
+				clogg.Error(ctx, "error getting the note of the processed file", clogg.String("error", err.Error()))
+				continue
+			}
+			if err := noteEventRepository.PublishNoteEvent(ctx, domain.NewFileProcessedEvent(note.UserId, processedFile), cfg.NoteEventsStreamLength); err != nil {
+				clogg.Error(ctx, "error publishing the note event", clogg.String("error", err.Error()))
 			}
 		}
 
@@ -108,6 +83,100 @@ to quickly create a Cobra application.`,
 	},
 }
 
+// fileProcessing holds the connections and the repositories shared by the file processing commands
+type fileProcessing struct {
+	db                  *sql.DB
+	fileRepository      domain.FileRepository
+	fileJobRepository   domain.FileJobRepository
+	noteRepository      domain.NoteRepository
+	noteEventRepository domain.NoteEventRepository
+	close               func()
+}
+
+// openFileProcessing connects to the database, the object storage service and redis, it exits on a failed connection
+func openFileProcessing(ctx context.Context, cfg *config.Configuration) *fileProcessing {
+	// Database connection
+	db, err := database.Open(ctx, cfg.DatabaseUrl)
+	if err != nil {
+		clogg.Error(ctx, "error opening database", clogg.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Database queries
+	dbQueries := database.New(db)
+
+	// Object storage service
+	oss := oss.NewDigitalOceanWithMinio(cfg)
+
+	// Healthcheck
+	if err := oss.HealthCheck(); err != nil {
+		clogg.Error(ctx, "error checking object storage service health", clogg.String("error", err.Error()))
+	}
+
+	// The audio, WebP and HEIC files are processed with ffmpeg
+	if err := domain.CheckFfmpeg(); err != nil {
+		clogg.Error(ctx, "error checking ffmpeg", clogg.String("error", err.Error()))
+	}
+
+	// Cache connection
+	rdb, err := cache.OpenRedis(ctx, cfg.RedisHost, cfg.RedisPort, cfg.RedisPassword, cfg.RedisDb)
+	if err != nil {
+		clogg.Error(ctx, "error connecting to redis", clogg.String("error", err.Error()))
+		database.Close(ctx, db)
+		os.Exit(1)
+	}
+
+	// Datasources
+	fileDatabaseDs := data.NewFileDatabaseDs(dbQueries)
+	fileJobDatabaseDs := data.NewFileJobDatabaseDs(dbQueries)
+	objectDeletionDatabaseDs := data.NewObjectDeletionDatabaseDs(dbQueries)
+	noteCacheDs := data.NewNoteCacheDs(rdb)
+	noteDatabaseDs := data.NewNoteDatabaseDs(dbQueries)
+	// The command only publishes events, it never reads the feeds
+	noteEventDs := data.NewNoteEventDs(rdb, rdb)
+
+	// Repositories
+	return &fileProcessing{
+		db:                  db,
+		fileRepository:      domain.NewFileRepository(fileDatabaseDs, objectDeletionDatabaseDs, oss, cfg),
+		fileJobRepository:   domain.NewFileJobRepository(fileJobDatabaseDs),
+		noteRepository:      domain.NewNoteRepository(&noteCacheDs, &noteDatabaseDs),
+		noteEventRepository: domain.NewNoteEventRepository(noteEventDs),
+		close: func() {
+			rdb.Close()
+			database.Close(ctx, db)
+		},
+	}
+}
+
+// fileJobService returns the service of the file processing jobs
+func (fp *fileProcessing) fileJobService(cfg *config.Configuration) service.FileJobService {
+	return service.NewFileJobService(fp.fileJobRepository, fp.fileRepository, fp.noteRepository, fp.noteEventRepository, *cfg, fp.db)
+}
+
+// processFile processes a file in its own transaction, so a failed file doesn't roll back the others
+func processFile(ctx context.Context, db *sql.DB, fileRepository domain.FileRepository, ossFileId string) (processedFile *domain.File, err error) {
+	// Start the sql transaction
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	return fileRepository.Process(ctx, tx, ossFileId)
+}
+
 func init() {
 	rootCmd.AddCommand(processFileCmd)
 	processFileCmd.Flags().StringSliceVarP(&files, "files", "f", []string{}, "Files to process")
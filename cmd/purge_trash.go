@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/cache"
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/data"
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/oss"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// purgeTrashCmd represents the purge-trash command
+var purgeTrashCmd = &cobra.Command{
+	Use:   "purge-trash",
+	Short: "Hard delete the notes that are in the trash for longer than the retention",
+	Long: `Hard delete the notes that are in the trash for longer than the retention, with their
+files in the object storage service. The notes are purged in batches, each one in its own transaction.
+
+The retention and the batch size default to the TRASH_RETENTION and TRASH_PURGE_BATCH_SIZE
+enviroment variables. Use --dry-run to count the notes and files to purge without deleting them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Set up clogg
+		handler := slog.NewJSONHandler(os.Stdout, nil)
+		logger := clogg.GetLogger(clogg.LoggerConfig{
+			BufferSize: 100,
+			Handler:    handler,
+		})
+		defer logger.Shutdown()
+
+		// Config
+		cfg := config.LoadServerConfig()
+
+		// Flags, the zero values use the config
+		opts := service.PurgeTrashOptions{
+			Retention: cfg.TrashRetention,
+			BatchSize: cfg.TrashPurgeBatchSize,
+		}
+		retention, err := cmd.Flags().GetDuration("retention")
+		if err != nil || retention < 0 {
+			clogg.Error(ctx, "retention flag must be a valid non negative duration")
+			os.Exit(1)
+		}
+		if retention > 0 {
+			opts.Retention = retention
+		}
+		batchSize, err := cmd.Flags().GetInt("batch-size")
+		if err != nil || batchSize < 0 {
+			clogg.Error(ctx, "batch-size flag must be a valid non negative integer")
+			os.Exit(1)
+		}
+		if batchSize > 0 {
+			opts.BatchSize = batchSize
+		}
+		if opts.DryRun, err = cmd.Flags().GetBool("dry-run"); err != nil {
+			clogg.Error(ctx, "error getting dry-run flag", clogg.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		// Database connection
+		db, err := database.Open(ctx, cfg.DatabaseUrl)
+		if err != nil {
+			clogg.Error(ctx, "error opening database", clogg.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer database.Close(ctx, db)
+
+		// Database queries
+		dbQueries := database.New(db)
+
+		// Object storage service
+		oss := oss.NewDigitalOceanWithMinio(cfg)
+
+		// Healthcheck
+		if err := oss.HealthCheck(); err != nil {
+			clogg.Error(ctx, "error checking object storage service health", clogg.String("error", err.Error()))
+		}
+
+		// Cache connection
+		rdb, err := cache.OpenRedis(ctx, cfg.RedisHost, cfg.RedisPort, cfg.RedisPassword, cfg.RedisDb)
+		if err != nil {
+			clogg.Error(ctx, "error connecting to redis", clogg.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer rdb.Close()
+
+		// Datasources
+		fileDatabaseDs := data.NewFileDatabaseDs(dbQueries)
+		objectDeletionDatabaseDs := data.NewObjectDeletionDatabaseDs(dbQueries)
+		noteCacheDs := data.NewNoteCacheDs(rdb)
+		noteDatabaseDs := data.NewNoteDatabaseDs(dbQueries)
+		// The command only publishes events, it never reads the feeds
+		noteEventDs := data.NewNoteEventDs(rdb, rdb)
+
+		// Repositories
+		fileRepository := domain.NewFileRepository(fileDatabaseDs, objectDeletionDatabaseDs, oss, cfg)
+		noteRepository := domain.NewNoteRepository(&noteCacheDs, &noteDatabaseDs)
+		noteEventRepository := domain.NewNoteEventRepository(noteEventDs)
+
+		// Services
+		trashService := service.NewTrashService(noteRepository, fileRepository, noteEventRepository, *cfg, db)
+
+		if err := purgeTrash(ctx, trashService, opts); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// purgeTrash runs a purge of the trash and logs its summary
+func purgeTrash(ctx context.Context, trashService service.TrashService, opts service.PurgeTrashOptions) error {
+	start := time.Now()
+	summary, err := trashService.PurgeTrash(ctx, opts)
+	attrs := []clogg.Attr{
+		clogg.Time("before", summary.Before),
+		clogg.Int("notes", summary.Notes),
+		clogg.Int("files", summary.Files),
+		clogg.Int("batches", summary.Batches),
+		clogg.Int("failed_batches", summary.FailedBatches),
+		clogg.Bool("dry_run", summary.DryRun),
+		clogg.String("duration", time.Since(start).String()),
+	}
+	if err != nil {
+		clogg.Error(ctx, "error purging the trash", append(attrs, clogg.String("error", err.Error()))...)
+		return err
+	}
+	if summary.DryRun {
+		clogg.Info(ctx, "trash purge dry run completed", attrs...)
+	} else {
+		clogg.Info(ctx, "trash purged", attrs...)
+	}
+	return nil
+}
+
+// scheduleTrashPurge purges the trash every interval until the context is done
+func scheduleTrashPurge(ctx context.Context, trashService service.TrashService, interval time.Duration, opts service.PurgeTrashOptions) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purgeTrash(ctx, trashService, opts)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(purgeTrashCmd)
+	purgeTrashCmd.Flags().Duration("retention", 0, "How long a note stays in the trash before it is purged, defaults to TRASH_RETENTION")
+	purgeTrashCmd.Flags().Int("batch-size", 0, "Number of notes purged in each transaction, defaults to TRASH_PURGE_BATCH_SIZE")
+	purgeTrashCmd.Flags().Bool("dry-run", false, "Count the notes and files to purge without deleting them")
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// reprocessBatchSize is the number of files listed at once by the reprocess command
+const reprocessBatchSize = 100
+
+// reprocessCmd represents the reprocess command
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess",
+	Short: "Process again the files of the notes",
+	Long: `Process again the files of the notes selected by the note, the user, the type (picture or audio)
+and the creation date range. The dates are RFC 3339 times or 2006-01-02 dates, the after date is inclusive and
+the before date is exclusive. Use --unprocessed to select only the files whose processing didn't succeed.
+
+The files that already have a processed copy are skipped, use --force to regenerate their processed copies.
+The replaced copies are removed from the object storage service by the object deletion worker.
+The files processed by a worker at the moment are left out. Use --dry-run to list the files without processing them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		// Set up clogg
+		handler := slog.NewJSONHandler(os.Stdout, nil)
+		logger := clogg.GetLogger(clogg.LoggerConfig{
+			BufferSize: 100,
+			Handler:    handler,
+		})
+		defer logger.Shutdown()
+
+		// Config
+		cfg := config.LoadServerConfig()
+
+		// Flags, the zero values use the config
+		opts, err := reprocessOptions(cmd, cfg)
+		if err != nil {
+			clogg.Error(ctx, err.Error())
+			os.Exit(1)
+		}
+
+		// Connections and repositories
+		fp := openFileProcessing(ctx, cfg)
+		defer fp.close()
+
+		// Services
+		fileJobService := fp.fileJobService(cfg)
+
+		if err := reprocessFiles(ctx, fileJobService, opts); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+// reprocessOptions parses the flags of the reprocess command
+func reprocessOptions(cmd *cobra.Command, cfg *config.Configuration) (service.ReprocessFilesOptions, error) {
+	opts := service.ReprocessFilesOptions{
+		Concurrency: cfg.FileJobConcurrency,
+		BatchSize:   reprocessBatchSize,
+	}
+	flags := cmd.Flags()
+
+	var err error
+	if opts.Filter.NoteId, err = parseIdFlag(cmd, "note"); err != nil {
+		return opts, err
+	}
+	if opts.Filter.UserId, err = parseIdFlag(cmd, "user"); err != nil {
+		return opts, err
+	}
+	opts.Filter.Type, _ = flags.GetString("type")
+	if opts.Filter.Type != "" && domain.FileTypeExtensions(opts.Filter.Type) == nil {
+		return opts, fmt.Errorf("type flag must be picture or audio")
+	}
+	if opts.Filter.CreatedAfter, err = parseTimeFlag(cmd, "created-after"); err != nil {
+		return opts, err
+	}
+	if opts.Filter.CreatedBefore, err = parseTimeFlag(cmd, "created-before"); err != nil {
+		return opts, err
+	}
+	opts.Filter.Unprocessed, _ = flags.GetBool("unprocessed")
+	opts.Force, _ = flags.GetBool("force")
+	opts.DryRun, _ = flags.GetBool("dry-run")
+
+	concurrency, err := flags.GetInt("concurrency")
+	if err != nil || concurrency < 0 {
+		return opts, fmt.Errorf("concurrency flag must be a valid non negative integer")
+	}
+	if concurrency > 0 {
+		opts.Concurrency = concurrency
+	}
+	return opts, nil
+}
+
+// parseIdFlag parses a uuid flag, an empty flag is a nil id
+func parseIdFlag(cmd *cobra.Command, name string) (uuid.UUID, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s flag must be a valid uuid", name)
+	}
+	return id, nil
+}
+
+// parseTimeFlag parses a RFC 3339 time or a date flag, an empty flag is a zero time
+func parseTimeFlag(cmd *cobra.Command, name string) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s flag must be a valid RFC 3339 time or date", name)
+	}
+	return t, nil
+}
+
+// reprocessFiles reprocesses the selected files, logs the progress of every file and the summary
+func reprocessFiles(ctx context.Context, fileJobService service.FileJobService, opts service.ReprocessFilesOptions) error {
+	start := time.Now()
+	opts.Progress = func(progress service.ReprocessFilesProgress) {
+		done := progress.Summary.Reprocessed + progress.Summary.Skipped + progress.Summary.Failed
+		attrs := []clogg.Attr{
+			clogg.String("file", progress.File.OriginalFile),
+			clogg.String("progress", fmt.Sprintf("%d/%d", done, progress.Summary.Total)),
+		}
+		switch {
+		case progress.Err != nil:
+			clogg.Error(ctx, "error reprocessing file", append(attrs, clogg.String("error", progress.Err.Error()))...)
+		case progress.Skipped:
+			clogg.Info(ctx, "file skipped, it already has a processed copy", attrs...)
+		case opts.DryRun:
+			clogg.Info(ctx, "file would be reprocessed", attrs...)
+		default:
+			clogg.Info(ctx, "file reprocessed", attrs...)
+		}
+	}
+
+	summary, err := fileJobService.ReprocessFiles(ctx, opts)
+	if summary == nil {
+		summary = &service.ReprocessFilesSummary{}
+	}
+	attrs := []clogg.Attr{
+		clogg.Int("total", summary.Total),
+		clogg.Int("reprocessed", summary.Reprocessed),
+		clogg.Int("skipped", summary.Skipped),
+		clogg.Int("failed", summary.Failed),
+		clogg.String("duration", time.Since(start).String()),
+	}
+	if err != nil {
+		clogg.Error(ctx, "error reprocessing files", append(attrs, clogg.String("error", err.Error()))...)
+		return err
+	}
+	if opts.DryRun {
+		clogg.Info(ctx, "files reprocessing dry run completed", attrs...)
+	} else {
+		clogg.Info(ctx, "files reprocessed", attrs...)
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d files failed", summary.Failed)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(reprocessCmd)
+	reprocessCmd.Flags().String("note", "", "Reprocess the files of the note")
+	reprocessCmd.Flags().String("user", "", "Reprocess the files of the notes of the user")
+	reprocessCmd.Flags().String("type", "", "Reprocess the files of the type, picture or audio")
+	reprocessCmd.Flags().String("created-after", "", "Reprocess the files created at or after the time")
+	reprocessCmd.Flags().String("created-before", "", "Reprocess the files created before the time")
+	reprocessCmd.Flags().Bool("unprocessed", false, "Reprocess only the files whose processing didn't succeed")
+	reprocessCmd.Flags().Bool("force", false, "Regenerate and replace the processed copies of the files that already have one")
+	reprocessCmd.Flags().Int("concurrency", 0, "Number of files processed at once, defaults to FILE_JOB_CONCURRENCY")
+	reprocessCmd.Flags().Bool("dry-run", false, "List the files to reprocess without processing them")
+}
@@ -86,6 +86,19 @@ func run(ctx context.Context) error {
 		clogg.Info(ctx, "redis connection closed")
 	}()
 
+	// The note event feeds block on their own pool of redis connections
+	eventsRdb, err := cache.OpenRedisWithPool(ctx, cfg.RedisHost, cfg.RedisPort, cfg.RedisPassword, cfg.RedisDb, cfg.NoteEventsReadersPoolSize)
+	if err != nil {
+		clogg.Error(ctx, "error connecting to redis", clogg.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer func() {
+		err := eventsRdb.Close()
+		if err != nil {
+			clogg.Error(ctx, "error closing redis connection", clogg.String("error", err.Error()))
+		}
+	}()
+
 	// Object storage service
 	oss := oss.NewDigitalOceanWithMinio(cfg)
 	// Healthcheck
@@ -105,17 +118,36 @@ func run(ctx context.Context) error {
 	noteCacheDs := data.NewNoteCacheDs(rdb)
 	noteDatabaseDs := data.NewNoteDatabaseDs(dbQueries)
 	fileDatabaseDs := data.NewFileDatabaseDs(dbQueries)
+	fileJobDatabaseDs := data.NewFileJobDatabaseDs(dbQueries)
+	objectDeletionDatabaseDs := data.NewObjectDeletionDatabaseDs(dbQueries)
+	tagDatabaseDs := data.NewTagDatabaseDs(dbQueries)
+	notebookDatabaseDs := data.NewNotebookDatabaseDs(dbQueries)
+	noteRevisionDatabaseDs := data.NewNoteRevisionDatabaseDs(dbQueries)
+	noteEventDs := data.NewNoteEventDs(rdb, eventsRdb)
+	syncDatabaseDs := data.NewSyncDatabaseDs(dbQueries)
 
 	// Repositories
 	userRepository := domain.NewUserRepository(&userCacheDs, &userDatabaseDs)
 	accessTokenRepository := domain.NewAccessTokenRepository(accessTokenCacheDs, accessTokenDatabaseDs)
 	refreshTokenRepository := domain.NewRefreshTokenRepository(&refreshTokenCacheDs, &refreshTokenDatabaseDs)
 	noteRepository := domain.NewNoteRepository(&noteCacheDs, &noteDatabaseDs)
-	fileRepository := domain.NewFileRepository(fileDatabaseDs, oss, cfg)
+	fileRepository := domain.NewFileRepository(fileDatabaseDs, objectDeletionDatabaseDs, oss, cfg)
+	fileJobRepository := domain.NewFileJobRepository(fileJobDatabaseDs)
+	objectDeletionRepository := domain.NewObjectDeletionRepository(objectDeletionDatabaseDs, oss)
+	tagRepository := domain.NewTagRepository(tagDatabaseDs)
+	notebookRepository := domain.NewNotebookRepository(notebookDatabaseDs)
+	noteRevisionRepository := domain.NewNoteRevisionRepository(noteRevisionDatabaseDs)
+	noteEventRepository := domain.NewNoteEventRepository(noteEventDs)
+	syncRepository := domain.NewSyncRepository(syncDatabaseDs)
 
 	// Services
 	authenticationService := service.NewAuthenticationService(jwtDatasource, hashDatasource, userRepository, accessTokenRepository, refreshTokenRepository, db)
-	noteService := service.NewNoteService(noteRepository, oss, fileRepository, *cfg, k8sClient, db)
+	noteService := service.NewNoteService(noteRepository, oss, fileRepository, tagRepository, notebookRepository, noteRevisionRepository, noteEventRepository, syncRepository, *cfg, fileJobRepository, db)
+	tagService := service.NewTagService(tagRepository, db)
+	notebookService := service.NewNotebookService(notebookRepository, noteRepository, noteEventRepository, *cfg, db)
+	trashService := service.NewTrashService(noteRepository, fileRepository, noteEventRepository, *cfg, db)
+	objectDeletionService := service.NewObjectDeletionService(objectDeletionRepository, db)
+	fileJobService := service.NewFileJobService(fileJobRepository, fileRepository, noteRepository, noteEventRepository, *cfg, db)
 
 	// Httpw server
 	routes := []httpw.HandleFunc{
@@ -123,17 +155,52 @@ func run(ctx context.Context) error {
 		{Pattern: "GET /swagger.json", Handler: handler.OpenApiHanlder},
 		// Authentication
 		{Pattern: "GET /me", Handler: middleware.LoggedOnly(handler.Me(authenticationService)).(http.HandlerFunc)},
+		{Pattern: "POST /sign-up", Handler: handler.SignUp(authenticationService)},
 		{Pattern: "POST /sign-in", Handler: handler.SignIn(authenticationService)},
+		{Pattern: "POST /refresh", Handler: handler.RefreshToken(authenticationService)},
 		{Pattern: "POST /sign-out", Handler: middleware.LoggedOnly(handler.SignOut(authenticationService)).(http.HandlerFunc)},
+		{Pattern: "GET /session", Handler: middleware.LoggedOnly(handler.ListSessions(authenticationService)).(http.HandlerFunc)},
+		{Pattern: "DELETE /session/others", Handler: middleware.LoggedOnly(handler.RevokeOtherSessions(authenticationService)).(http.HandlerFunc)},
+		{Pattern: "DELETE /session/{id}", Handler: middleware.LoggedOnly(handler.RevokeSession(authenticationService)).(http.HandlerFunc)},
 		// Note
 		{Pattern: "GET /note/trash", Handler: middleware.LoggedOnly(handler.ListTrashNotesByUser(noteService)).(http.HandlerFunc)},
+		{Pattern: "DELETE /note/trash", Handler: middleware.LoggedOnly(handler.EmptyTrash(noteService)).(http.HandlerFunc)},
+		{Pattern: "POST /note/bulk/delete", Handler: middleware.LoggedOnly(handler.SoftDeleteNotes(noteService)).(http.HandlerFunc)},
+		{Pattern: "POST /note/bulk/restore", Handler: middleware.LoggedOnly(handler.RestoreNotes(noteService)).(http.HandlerFunc)},
+		{Pattern: "POST /note/bulk/hard-delete", Handler: middleware.LoggedOnly(handler.HardDeleteNotes(noteService)).(http.HandlerFunc)},
+		{Pattern: "GET /note/archive", Handler: middleware.LoggedOnly(handler.ListArchivedNotesByUser(noteService)).(http.HandlerFunc)},
+		{Pattern: "GET /note/search", Handler: middleware.LoggedOnly(handler.SearchNotes(noteService)).(http.HandlerFunc)},
+		{Pattern: "GET /note/events", Handler: middleware.LoggedOnly(handler.NoteEvents(noteService)).(http.HandlerFunc)},
 		{Pattern: "GET /note", Handler: middleware.LoggedOnly(handler.ListNotesByUser(noteService)).(http.HandlerFunc)},
 		{Pattern: "POST /note", Handler: middleware.LoggedOnly(handler.CreateNote(noteService)).(http.HandlerFunc)},
+		{Pattern: "GET /note/{id}", Handler: middleware.LoggedOnly(handler.GetNote(noteService)).(http.HandlerFunc)},
 		{Pattern: "DELETE /note/{id}/hard", Handler: middleware.LoggedOnly(handler.HardDeleteNote(noteService)).(http.HandlerFunc)},
 		{Pattern: "DELETE /note/{id}", Handler: middleware.LoggedOnly(handler.SoftDeleteNote(noteService)).(http.HandlerFunc)},
 		{Pattern: "PATCH /note/{id}", Handler: middleware.LoggedOnly(handler.UpdateNote(noteService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /note/{id}/notebook", Handler: middleware.LoggedOnly(handler.MoveNote(noteService)).(http.HandlerFunc)},
+		{Pattern: "GET /note/{id}/revision", Handler: middleware.LoggedOnly(handler.ListNoteRevisions(noteService)).(http.HandlerFunc)},
+		{Pattern: "GET /note/{id}/revision/diff", Handler: middleware.LoggedOnly(handler.DiffNoteRevisions(noteService)).(http.HandlerFunc)},
+		{Pattern: "GET /note/{id}/revision/{revisionId}", Handler: middleware.LoggedOnly(handler.GetNoteRevision(noteService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /note/{id}/revision/{revisionId}/restore", Handler: middleware.LoggedOnly(handler.RestoreNoteRevision(noteService)).(http.HandlerFunc)},
 		{Pattern: "PATCH /note/{id}/restore", Handler: middleware.LoggedOnly(handler.RestoreNote(noteService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /note/{id}/pin", Handler: middleware.LoggedOnly(handler.PinNote(noteService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /note/{id}/unpin", Handler: middleware.LoggedOnly(handler.UnpinNote(noteService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /note/{id}/archive", Handler: middleware.LoggedOnly(handler.ArchiveNote(noteService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /note/{id}/unarchive", Handler: middleware.LoggedOnly(handler.UnarchiveNote(noteService)).(http.HandlerFunc)},
 		{Pattern: "POST /note/presigned-urls", Handler: middleware.LoggedOnly(handler.GetPresignedUrls(noteService)).(http.HandlerFunc)},
+		// Sync
+		{Pattern: "GET /sync", Handler: middleware.LoggedOnly(handler.Sync(noteService)).(http.HandlerFunc)},
+		// Tag
+		{Pattern: "GET /tag", Handler: middleware.LoggedOnly(handler.ListTags(tagService)).(http.HandlerFunc)},
+		{Pattern: "POST /tag", Handler: middleware.LoggedOnly(handler.CreateTag(tagService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /tag/{id}", Handler: middleware.LoggedOnly(handler.UpdateTag(tagService)).(http.HandlerFunc)},
+		{Pattern: "DELETE /tag/{id}", Handler: middleware.LoggedOnly(handler.DeleteTag(tagService)).(http.HandlerFunc)},
+		// Notebook
+		{Pattern: "GET /notebook", Handler: middleware.LoggedOnly(handler.ListNotebooks(notebookService)).(http.HandlerFunc)},
+		{Pattern: "POST /notebook", Handler: middleware.LoggedOnly(handler.CreateNotebook(notebookService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /notebook/{id}", Handler: middleware.LoggedOnly(handler.RenameNotebook(notebookService)).(http.HandlerFunc)},
+		{Pattern: "PATCH /notebook/{id}/move", Handler: middleware.LoggedOnly(handler.MoveNotebook(notebookService)).(http.HandlerFunc)},
+		{Pattern: "DELETE /notebook/{id}", Handler: middleware.LoggedOnly(handler.DeleteNotebook(notebookService)).(http.HandlerFunc)},
 	}
 
 	httpwServer := httpw.New(httpw.Options{
@@ -143,18 +210,17 @@ func run(ctx context.Context) error {
 		IdleTimeout:  10 * time.Second,
 		Middlewares: []cmiddleware.Middleware{
 			cmiddleware.LoggingMiddleware,
-			middleware.SetUserInContext(jwtDatasource),
-			cmiddleware.AllowCors(cmiddleware.CorsOptions{
-				AllowedOrigin:  fmt.Sprintf("http://localhost:%s", cfg.RestServerPort),
-				AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-				AllowedHeaders: []string{"Content-Type", "Authorization"},
-			}),
+			middleware.SetUserInContext(jwtDatasource, accessTokenRepository),
+			middleware.SetClientInContext(cfg.TrustedProxies),
+			middleware.Cors(cfg.AllowedOrigins),
 			cmiddleware.RecoverMiddleware,
 		},
 	}, routes...)
+	// Keep the writer of the server reachable for the streaming endpoints
+	httpwServer.HttpServer.Handler = middleware.SetResponseWriterInContext(httpwServer.HttpServer.Handler)
 
 	// Http server
-	graphSrv := httpserver.NewGraphQLServer(authenticationService, noteService, *cfg, jwtDatasource)
+	graphSrv := httpserver.NewGraphQLServer(authenticationService, noteService, tagService, notebookService, *cfg, jwtDatasource, accessTokenRepository)
 
 	var wg sync.WaitGroup
 	wg.Add(3)
@@ -177,6 +243,28 @@ func run(ctx context.Context) error {
 		}
 	}()
 
+	// Purge the trash periodically when an interval is configured
+	if cfg.TrashPurgeInterval > 0 {
+		go scheduleTrashPurge(ctx, trashService, cfg.TrashPurgeInterval, service.PurgeTrashOptions{
+			Retention: cfg.TrashRetention,
+			BatchSize: cfg.TrashPurgeBatchSize,
+		})
+	}
+
+	// Remove the objects of the deleted files periodically, unless it is left to the delete-objects command
+	if cfg.ObjectDeletionInterval > 0 {
+		go scheduleObjectDeletions(ctx, objectDeletionService, cfg.ObjectDeletionInterval, service.DeleteObjectsOptions{
+			BatchSize:    cfg.ObjectDeletionBatchSize,
+			MaxAttempts:  cfg.ObjectDeletionMaxAttempts,
+			RetryBackoff: cfg.ObjectDeletionRetryBackoff,
+		})
+	}
+
+	// Process the files of the notes, in k8s the worker command runs in its own deployment
+	if !cfg.InK8s {
+		go runFileJobs(ctx, fileJobService, cfg.FileJobPollInterval, fileJobOptions(cfg))
+	}
+
 	// Graceful shutdown
 	go func() {
 		defer wg.Done()
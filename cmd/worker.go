@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// workerCmd represents the worker command
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Process the files of the notes from the job queue",
+	Long: `Process the files of the notes from the job queue until it is stopped. A job is queued for every file
+by the transaction that creates it, and leased to a worker for FILE_JOB_LEASE while it is processed.
+
+A failed job is retried with a backoff that doubles on every attempt, starting at FILE_JOB_RETRY_BACKOFF.
+After FILE_JOB_MAX_ATTEMPTS attempts it is failed and the processing status of its file is set to failed.
+Any number of workers can run at once, the server runs one too unless it runs in k8s.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		// Set up clogg
+		handler := slog.NewJSONHandler(os.Stdout, nil)
+		logger := clogg.GetLogger(clogg.LoggerConfig{
+			BufferSize: 100,
+			Handler:    handler,
+		})
+		defer logger.Shutdown()
+
+		// Config
+		cfg := config.LoadServerConfig()
+
+		// Flags, the zero values use the config
+		opts := fileJobOptions(cfg)
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		if err != nil || concurrency < 0 {
+			clogg.Error(ctx, "concurrency flag must be a valid non negative integer")
+			os.Exit(1)
+		}
+		if concurrency > 0 {
+			opts.Concurrency = concurrency
+		}
+		interval, err := cmd.Flags().GetDuration("poll-interval")
+		if err != nil || interval < 0 {
+			clogg.Error(ctx, "poll-interval flag must be a valid non negative duration")
+			os.Exit(1)
+		}
+		if interval == 0 {
+			interval = cfg.FileJobPollInterval
+		}
+
+		// Connections and repositories
+		fp := openFileProcessing(ctx, cfg)
+		defer fp.close()
+
+		// Services
+		fileJobService := fp.fileJobService(cfg)
+
+		clogg.Info(ctx, "worker started", clogg.String("worker", opts.Worker), clogg.Int("concurrency", opts.Concurrency))
+		runFileJobs(ctx, fileJobService, interval, opts)
+		clogg.Info(ctx, "worker stopped", clogg.String("worker", opts.Worker))
+	},
+}
+
+// fileJobOptions are the options of the file processing worker from the config
+func fileJobOptions(cfg *config.Configuration) service.ProcessFileJobsOptions {
+	worker, err := os.Hostname()
+	if err != nil {
+		worker = "worker"
+	}
+	return service.ProcessFileJobsOptions{
+		Worker:       fmt.Sprintf("%s-%d", worker, os.Getpid()),
+		Concurrency:  cfg.FileJobConcurrency,
+		Lease:        cfg.FileJobLease,
+		MaxAttempts:  cfg.FileJobMaxAttempts,
+		RetryBackoff: cfg.FileJobRetryBackoff,
+	}
+}
+
+// runFileJobs processes the file jobs until the context is done, it only waits for the interval when the queue is empty
+func runFileJobs(ctx context.Context, fileJobService service.FileJobService, interval time.Duration, opts service.ProcessFileJobsOptions) {
+	for {
+		claimed := processFileJobs(ctx, fileJobService, opts)
+		if claimed > 0 {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// processFileJobs runs the file processing worker once, logs its summary and returns the number of claimed jobs
+func processFileJobs(ctx context.Context, fileJobService service.FileJobService, opts service.ProcessFileJobsOptions) int {
+	start := time.Now()
+	summary, err := fileJobService.ProcessFileJobs(ctx, opts)
+	if err != nil {
+		if ctx.Err() == nil {
+			clogg.Error(ctx, "error processing file jobs", clogg.String("error", err.Error()))
+		}
+		return 0
+	}
+	// An idle run is not logged, the worker polls the queue every interval
+	if summary.Claimed > 0 {
+		clogg.Info(ctx, "file jobs processed",
+			clogg.Int("claimed", summary.Claimed),
+			clogg.Int("succeeded", summary.Succeeded),
+			clogg.Int("retried", summary.Retried),
+			clogg.Int("failed", summary.Failed),
+			clogg.Int("lost", summary.Lost),
+			clogg.String("duration", time.Since(start).String()),
+		)
+	}
+	return summary.Claimed
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+	workerCmd.Flags().Int("concurrency", 0, "Number of files processed at once, defaults to FILE_JOB_CONCURRENCY")
+	workerCmd.Flags().Duration("poll-interval", 0, "Wait between the polls of an empty queue, defaults to FILE_JOB_POLL_INTERVAL")
+}
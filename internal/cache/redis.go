@@ -31,15 +31,26 @@ func validateRedisConfig(host, port string, db int) error {
 }
 
 func OpenRedis(ctx context.Context, host, port, password string, db int) (*redis.Client, error) {
+	// A zero pool size keeps the default of the client
+	return OpenRedisWithPool(ctx, host, port, password, db, 0)
+}
+
+// OpenRedisWithPool opens a Redis client with its own pool of poolSize connections.
+// It is used by the blocking reads, that hold a connection for as long as they wait.
+func OpenRedisWithPool(ctx context.Context, host, port, password string, db int, poolSize int) (*redis.Client, error) {
 	// Validate the Redis configuration
 	if err := validateRedisConfig(host, port, db); err != nil {
 		return nil, err
 	}
+	if poolSize < 0 {
+		return nil, fmt.Errorf("pool size must be a non-negative integer")
+	}
 	// Create a new Redis client
 	client := redis.NewClient(&redis.Options{
 		Addr:     net.JoinHostPort(host, port),
 		Password: password,
 		DB:       db,
+		PoolSize: poolSize,
 	})
 	// Check if the connection is successful
 	if err := client.Ping(ctx).Err(); err != nil {
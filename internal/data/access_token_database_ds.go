@@ -67,15 +67,18 @@ func (d *accessTokenDatabaseDs) GetAccessTokenById(ctx context.Context, id uuid.
 	return parseAccessTokenToDomain(&res), nil
 }
 
-func (d *accessTokenDatabaseDs) DeleteAccessTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (*uuid.UUID, error) {
-	id, err := d.queries.WithTx(tx).DeleteAccessTokenByUserId(ctx, userId)
+func (d *accessTokenDatabaseDs) DeleteAccessTokensByRefreshTokenId(ctx context.Context, tx *sql.Tx, refreshTokenId uuid.UUID) (*[]uuid.UUID, error) {
+	ids, err := d.queries.WithTx(tx).DeleteAccessTokensByRefreshTokenId(ctx, refreshTokenId)
 	if err != nil {
-		switch err.Error() {
-		case "sql: no rows in result set":
-			return nil, &customerrors.RecordNotFound{}
-		default:
-			return nil, err
-		}
+		return nil, err
+	}
+	return &ids, nil
+}
+
+func (d *accessTokenDatabaseDs) DeleteAccessTokensByRefreshTokenFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) (*[]uuid.UUID, error) {
+	ids, err := d.queries.WithTx(tx).DeleteAccessTokensByRefreshTokenFamilyId(ctx, familyId)
+	if err != nil {
+		return nil, err
 	}
-	return &id, nil
+	return &ids, nil
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/database"
 	"github.com/daniarmas/notes/internal/domain"
 	"github.com/google/uuid"
@@ -14,16 +15,40 @@ import (
 // Parses a file from the database to a domain.File
 func parseFromDatabaseToDomain(f database.File) domain.File {
 	return domain.File{
-		Id:            f.ID,
-		NoteId:        f.NoteID,
-		OriginalFile:  f.OriginalFile,
-		ProcessedFile: f.ProcessedFile.String,
-		CreateTime:    f.CreateTime,
-		UpdateTime:    f.UpdateTime,
-		DeleteTime:    f.DeleteTime.Time,
+		Id:               f.ID,
+		NoteId:           f.NoteID,
+		OriginalFile:     f.OriginalFile,
+		ProcessedFile:    f.ProcessedFile.String,
+		CreateTime:       f.CreateTime,
+		UpdateTime:       f.UpdateTime,
+		DeleteTime:       f.DeleteTime.Time,
+		ChangeSeq:        f.ChangeSeq,
+		ChangeXid:        f.ChangeXid,
+		ProcessingStatus: f.ProcessingStatus,
+		Audio:            parseAudioMetadata(f),
 	}
 }
 
+// Parses the audio metadata of a file from the database, the files without a duration have no metadata
+func parseAudioMetadata(f database.File) *domain.AudioMetadata {
+	if !f.Duration.Valid {
+		return nil
+	}
+	return &domain.AudioMetadata{
+		Duration:   f.Duration.Float64,
+		SampleRate: f.SampleRate.Int32,
+		Peaks:      f.Waveform,
+	}
+}
+
+// Parses the audio metadata of a file to the nullable columns of the database
+func audioMetadataToDatabase(audio *domain.AudioMetadata) (sql.NullFloat64, sql.NullInt32, []float32) {
+	if audio == nil {
+		return sql.NullFloat64{}, sql.NullInt32{}, nil
+	}
+	return sql.NullFloat64{Float64: audio.Duration, Valid: true}, sql.NullInt32{Int32: audio.SampleRate, Valid: true}, audio.Peaks
+}
+
 type fileDatabaseDs struct {
 	queries *database.Queries
 }
@@ -47,6 +72,19 @@ func (d *fileDatabaseDs) ListFilesByNotesIds(ctx context.Context, noteId []uuid.
 	return &response, nil
 }
 
+func (d *fileDatabaseDs) LockFilesByNotesIds(ctx context.Context, tx *sql.Tx, noteIds []uuid.UUID) (*[]domain.File, error) {
+	res, err := d.queries.WithTx(tx).LockFilesByNotesIds(ctx, noteIds)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.File, 0, len(res))
+	for _, file := range res {
+		response = append(response, parseFromDatabaseToDomain(file))
+	}
+	return &response, nil
+}
+
 func (d *fileDatabaseDs) ListFilesByNoteId(ctx context.Context, noteId uuid.UUID) (*[]domain.File, error) {
 	res, err := d.queries.ListFileByNoteId(ctx, noteId)
 	if err != nil {
@@ -75,23 +113,30 @@ func (d *fileDatabaseDs) CreateFile(ctx context.Context, tx *sql.Tx, file *domai
 		return nil, err
 	}
 	return &domain.File{
-		Id:           res.ID,
-		NoteId:       res.NoteID,
-		OriginalFile: res.OriginalFile,
-		CreateTime:   res.CreateTime,
-		UpdateTime:   res.UpdateTime,
-		DeleteTime:   res.DeleteTime.Time,
+		Id:               res.ID,
+		NoteId:           res.NoteID,
+		OriginalFile:     res.OriginalFile,
+		CreateTime:       res.CreateTime,
+		UpdateTime:       res.UpdateTime,
+		DeleteTime:       res.DeleteTime.Time,
+		ProcessingStatus: res.ProcessingStatus,
 	}, nil
 }
 
-func (d *fileDatabaseDs) UpdateFileByOriginalId(ctx context.Context, tx *sql.Tx, originalFileId, processFileId string) (*domain.File, error) {
+func (d *fileDatabaseDs) UpdateFileByOriginalId(ctx context.Context, tx *sql.Tx, originalFileId, processFileId string, audio *domain.AudioMetadata) (*domain.File, error) {
 	// Get current time
 	timeNow := time.Now().UTC()
 
-	res, err := d.queries.WithTx(tx).UpdateFileByOriginalId(ctx, database.UpdateFileByOriginalIdParams{OriginalFile: originalFileId, ProcessedFile: sql.NullString{String: processFileId, Valid: true}, UpdateTime: timeNow})
+	duration, sampleRate, waveform := audioMetadataToDatabase(audio)
+	res, err := d.queries.WithTx(tx).UpdateFileByOriginalId(ctx, database.UpdateFileByOriginalIdParams{OriginalFile: originalFileId, ProcessedFile: sql.NullString{String: processFileId, Valid: true}, UpdateTime: timeNow, Duration: duration, SampleRate: sampleRate, Waveform: waveform})
 	if err != nil {
 		clogg.Error(ctx, "error updating file by original id")
-		return nil, err
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
 	}
 
 	file := parseToDomain(res)
@@ -112,6 +157,115 @@ func (d *fileDatabaseDs) HardDeleteFilesByNoteId(ctx context.Context, tx *sql.Tx
 	return &response, nil
 }
 
+func (d *fileDatabaseDs) ListFilesForReprocessing(ctx context.Context, filter domain.FileFilter, cursor uuid.UUID, limit int32) (*[]domain.File, error) {
+	params := database.ListFilesForReprocessingParams{
+		CursorID:      uuid.NullUUID{UUID: cursor, Valid: cursor != uuid.Nil},
+		NoteID:        uuid.NullUUID{UUID: filter.NoteId, Valid: filter.NoteId != uuid.Nil},
+		UserID:        uuid.NullUUID{UUID: filter.UserId, Valid: filter.UserId != uuid.Nil},
+		Extensions:    fileTypeExtensions(filter.Type),
+		CreatedAfter:  timeToNullTime(filter.CreatedAfter),
+		CreatedBefore: timeToNullTime(filter.CreatedBefore),
+		Unprocessed:   filter.Unprocessed,
+		PageLimit:     limit,
+	}
+	res, err := d.queries.ListFilesForReprocessing(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.File, 0, len(res))
+	for _, file := range res {
+		response = append(response, parseFromDatabaseToDomain(file))
+	}
+	return &response, nil
+}
+
+func (d *fileDatabaseDs) CountFilesForReprocessing(ctx context.Context, filter domain.FileFilter) (int64, error) {
+	return d.queries.CountFilesForReprocessing(ctx, database.CountFilesForReprocessingParams{
+		NoteID:        uuid.NullUUID{UUID: filter.NoteId, Valid: filter.NoteId != uuid.Nil},
+		UserID:        uuid.NullUUID{UUID: filter.UserId, Valid: filter.UserId != uuid.Nil},
+		Extensions:    fileTypeExtensions(filter.Type),
+		CreatedAfter:  timeToNullTime(filter.CreatedAfter),
+		CreatedBefore: timeToNullTime(filter.CreatedBefore),
+		Unprocessed:   filter.Unprocessed,
+	})
+}
+
+func (d *fileDatabaseDs) ReplaceProcessedFile(ctx context.Context, tx *sql.Tx, id uuid.UUID, processedFileId string, audio *domain.AudioMetadata) (*domain.File, error) {
+	duration, sampleRate, waveform := audioMetadataToDatabase(audio)
+	res, err := d.queries.WithTx(tx).ReplaceProcessedFileById(ctx, database.ReplaceProcessedFileByIdParams{
+		ID:            id,
+		ProcessedFile: sql.NullString{String: processedFileId, Valid: true},
+		UpdateTime:    time.Now().UTC(),
+		Duration:      duration,
+		SampleRate:    sampleRate,
+		Waveform:      waveform,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseToDomain(res), nil
+}
+
+func (d *fileDatabaseDs) ListFileRenditionsByFileIds(ctx context.Context, fileIds []uuid.UUID) (*[]domain.FileRendition, error) {
+	res, err := d.queries.ListFileRenditionsByFileIds(ctx, fileIds)
+	if err != nil {
+		return nil, err
+	}
+	return parseFileRenditionsFromDatabaseToDomain(res), nil
+}
+
+func (d *fileDatabaseDs) ReplaceFileRenditions(ctx context.Context, tx *sql.Tx, fileId uuid.UUID, renditions []domain.FileRendition) (*[]domain.FileRendition, *[]domain.FileRendition, error) {
+	replaced, err := d.queries.WithTx(tx).DeleteFileRenditionsByFileId(ctx, fileId)
+	if err != nil {
+		return nil, nil, err
+	}
+	params := database.CreateFileRenditionsParams{
+		FileID:      fileId,
+		Sizes:       make([]int32, 0, len(renditions)),
+		ObjectNames: make([]string, 0, len(renditions)),
+		Widths:      make([]int32, 0, len(renditions)),
+		Heights:     make([]int32, 0, len(renditions)),
+	}
+	for _, rendition := range renditions {
+		params.Sizes = append(params.Sizes, rendition.Size)
+		params.ObjectNames = append(params.ObjectNames, rendition.ObjectName)
+		params.Widths = append(params.Widths, rendition.Width)
+		params.Heights = append(params.Heights, rendition.Height)
+	}
+	created, err := d.queries.WithTx(tx).CreateFileRenditions(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseFileRenditionsFromDatabaseToDomain(replaced), parseFileRenditionsFromDatabaseToDomain(created), nil
+}
+
+// Parses the renditions of the files from the database to domain.FileRendition
+func parseFileRenditionsFromDatabaseToDomain(renditions []database.FileRendition) *[]domain.FileRendition {
+	// Preallocate slice with the length of the result set
+	response := make([]domain.FileRendition, 0, len(renditions))
+	for _, r := range renditions {
+		response = append(response, domain.FileRendition{
+			Id:         r.ID,
+			FileId:     r.FileID,
+			Size:       r.Size,
+			ObjectName: r.ObjectName,
+			Width:      r.Width,
+			Height:     r.Height,
+			CreateTime: r.CreateTime,
+		})
+	}
+	return &response
+}
+
+// fileTypeExtensions returns no extensions for an empty type, so the files of every type are selected
+func fileTypeExtensions(fileType string) []string {
+	if fileType == "" {
+		return nil
+	}
+	return domain.FileTypeExtensions(fileType)
+}
+
 // ParseToDomain parses a file from the database to a domain.File
 func parseToDomain(f database.File) *domain.File {
 	// Parse UUIDs and handle potential errors
@@ -120,12 +274,14 @@ func parseToDomain(f database.File) *domain.File {
 
 	// Return the parsed domain.File
 	return &domain.File{
-		Id:            id,
-		NoteId:        noteId,
-		OriginalFile:  f.OriginalFile,
-		ProcessedFile: f.ProcessedFile.String,
-		CreateTime:    f.CreateTime,
-		UpdateTime:    f.UpdateTime,
-		DeleteTime:    f.DeleteTime.Time,
+		Id:               id,
+		NoteId:           noteId,
+		OriginalFile:     f.OriginalFile,
+		ProcessedFile:    f.ProcessedFile.String,
+		CreateTime:       f.CreateTime,
+		UpdateTime:       f.UpdateTime,
+		DeleteTime:       f.DeleteTime.Time,
+		ProcessingStatus: f.ProcessingStatus,
+		Audio:            parseAudioMetadata(f),
 	}
 }
@@ -0,0 +1,126 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Parses a claimed file job from the database to a domain.FileJob
+func parseFileJobFromDatabaseToDomain(j database.ClaimFileJobsRow) domain.FileJob {
+	return domain.FileJob{
+		Id:              j.ID,
+		FileId:          j.FileID,
+		OriginalFile:    j.OriginalFile,
+		Status:          j.Status,
+		Attempts:        j.Attempts,
+		LastError:       j.LastError.String,
+		RunTime:         j.RunTime,
+		LeaseOwner:      j.LeaseOwner.String,
+		LeaseExpireTime: j.LeaseExpireTime.Time,
+		CreateTime:      j.CreateTime,
+		UpdateTime:      j.UpdateTime,
+	}
+}
+
+type fileJobDatabaseDs struct {
+	queries *database.Queries
+}
+
+func NewFileJobDatabaseDs(queries *database.Queries) domain.FileJobDatabaseDs {
+	return &fileJobDatabaseDs{
+		queries: queries,
+	}
+}
+
+func (d *fileJobDatabaseDs) EnqueueFileJobs(ctx context.Context, tx *sql.Tx, fileIds []uuid.UUID) error {
+	if err := d.queries.WithTx(tx).EnqueueFileJobs(ctx, fileIds); err != nil {
+		return err
+	}
+	return d.setFilesProcessingStatus(ctx, tx, fileIds, domain.FileProcessingQueued)
+}
+
+func (d *fileJobDatabaseDs) ClaimFileJobs(ctx context.Context, tx *sql.Tx, worker string, now time.Time, leaseExpireTime time.Time, limit int32) (*[]domain.FileJob, error) {
+	res, err := d.queries.WithTx(tx).ClaimFileJobs(ctx, database.ClaimFileJobsParams{
+		Worker:          worker,
+		LeaseExpireTime: leaseExpireTime,
+		Now:             now,
+		PageLimit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.FileJob, 0, len(res))
+	fileIds := make([]uuid.UUID, 0, len(res))
+	for _, job := range res {
+		response = append(response, parseFileJobFromDatabaseToDomain(job))
+		fileIds = append(fileIds, job.FileID)
+	}
+	if len(fileIds) > 0 {
+		if err := d.setFilesProcessingStatus(ctx, tx, fileIds, domain.FileProcessingRunning); err != nil {
+			return nil, err
+		}
+	}
+	return &response, nil
+}
+
+func (d *fileJobDatabaseDs) CompleteFileJob(ctx context.Context, tx *sql.Tx, job *domain.FileJob, worker string) error {
+	rows, err := d.queries.WithTx(tx).CompleteFileJob(ctx, database.CompleteFileJobParams{
+		ID:     job.Id,
+		Worker: worker,
+		Now:    time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	// The lease expired and the job was claimed by another worker
+	if rows == 0 {
+		return &customerrors.RecordNotFound{}
+	}
+	return d.setFilesProcessingStatus(ctx, tx, []uuid.UUID{job.FileId}, domain.FileProcessingSucceeded)
+}
+
+func (d *fileJobDatabaseDs) FailFileJob(ctx context.Context, tx *sql.Tx, job *domain.FileJob, worker string, lastError string, runTime time.Time, dead bool) error {
+	// A job that is retried goes back to the queue
+	status := domain.FileJobQueued
+	if dead {
+		status = domain.FileJobFailed
+	}
+	rows, err := d.queries.WithTx(tx).FailFileJob(ctx, database.FailFileJobParams{
+		ID:        job.Id,
+		Worker:    worker,
+		Status:    status,
+		LastError: sql.NullString{String: lastError, Valid: true},
+		RunTime:   runTime,
+		Now:       time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	// The lease expired and the job was claimed by another worker
+	if rows == 0 {
+		return &customerrors.RecordNotFound{}
+	}
+	return d.setFilesProcessingStatus(ctx, tx, []uuid.UUID{job.FileId}, status)
+}
+
+func (d *fileJobDatabaseDs) CompleteFileJobsByFileId(ctx context.Context, tx *sql.Tx, fileId uuid.UUID) error {
+	return d.queries.WithTx(tx).CompleteFileJobsByFileId(ctx, database.CompleteFileJobsByFileIdParams{
+		FileID:     fileId,
+		UpdateTime: time.Now().UTC(),
+	})
+}
+
+// setFilesProcessingStatus keeps the processing status of the files in step with their jobs
+func (d *fileJobDatabaseDs) setFilesProcessingStatus(ctx context.Context, tx *sql.Tx, fileIds []uuid.UUID, status string) error {
+	return d.queries.WithTx(tx).SetFilesProcessingStatus(ctx, database.SetFilesProcessingStatusParams{
+		Ids:              fileIds,
+		ProcessingStatus: status,
+	})
+}
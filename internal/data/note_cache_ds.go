@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/domain"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -13,11 +14,15 @@ import (
 type Note struct {
 	Id              string    `redis:"id"`
 	UserId          string    `redis:"user_id"`
+	NotebookId      string    `redis:"notebook_id"`
 	Title           string    `redis:"title"`
 	Content         string    `redis:"content"`
 	CreateTime      time.Time `redis:"create_time"`
 	UpdateTime      time.Time `redis:"update_time"`
 	DeleteTime      time.Time `redis:"delete_time"`
+	PinnedTime      time.Time `redis:"pinned_time"`
+	ArchiveTime     time.Time `redis:"archive_time"`
+	Version         int32     `redis:"version"`
 }
 
 // ParseToDomain converts a data.Note to a domain.Note
@@ -27,15 +32,26 @@ func (n *Note) parseToDomain() (*domain.Note, error) {
 		return nil, nil
 	}
 
+	// An empty notebook id means the note is not in a notebook
+	var notebookId *uuid.UUID
+	if n.NotebookId != "" {
+		id := uuid.MustParse(n.NotebookId)
+		notebookId = &id
+	}
+
 	// Convert data.Note to domain.Note
 	return &domain.Note{
 		Id:              uuid.MustParse(n.Id),
 		UserId:          uuid.MustParse(n.UserId),
+		NotebookId:      notebookId,
 		Title:           n.Title,
 		Content:         n.Content,
 		CreateTime:      n.CreateTime,
 		UpdateTime:      n.UpdateTime,
 		DeleteTime:      n.DeleteTime,
+		PinnedTime:      n.PinnedTime,
+		ArchiveTime:     n.ArchiveTime,
+		Version:         n.Version,
 	}, nil
 }
 
@@ -46,15 +62,24 @@ func parseFromDomain(note *domain.Note) *Note {
 		return nil
 	}
 
+	var notebookId string
+	if note.NotebookId != nil {
+		notebookId = note.NotebookId.String()
+	}
+
 	// Convert domain.Note to data.Note
 	return &Note{
 		Id:              note.Id.String(),
 		UserId:          note.UserId.String(),
+		NotebookId:      notebookId,
 		Title:           note.Title,
 		Content:         note.Content,
 		CreateTime:      note.CreateTime,
 		UpdateTime:      note.UpdateTime,
 		DeleteTime:      note.DeleteTime,
+		PinnedTime:      note.PinnedTime,
+		ArchiveTime:     note.ArchiveTime,
+		Version:         note.Version,
 	}
 }
 
@@ -84,3 +109,23 @@ func (n *noteCacheDs) CreateNote(ctx context.Context, note *domain.Note) error {
 	}
 	return nil
 }
+
+func (n *noteCacheDs) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+	key := fmt.Sprintf("note:%s", id)
+	var response Note
+	if err := n.redis.HGetAll(ctx, key).Scan(&response); err != nil {
+		return nil, err
+	}
+	if response.Id == "" {
+		return nil, &customerrors.RecordNotFound{}
+	}
+	return response.parseToDomain()
+}
+
+func (n *noteCacheDs) DeleteNote(ctx context.Context, id uuid.UUID) error {
+	key := fmt.Sprintf("note:%s", id)
+	if _, err := n.redis.Del(ctx, key).Result(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -11,6 +11,46 @@ import (
 	"github.com/google/uuid"
 )
 
+// Parses a note from the database to a domain.Note
+func parseNoteFromDatabaseToDomain(n database.Note) domain.Note {
+	return domain.Note{
+		Id:          n.ID,
+		UserId:      n.UserID,
+		NotebookId:  nullUUIDToPointer(n.NotebookID),
+		Title:       n.Title.String,
+		Content:     n.Content.String,
+		CreateTime:  n.CreateTime,
+		UpdateTime:  n.UpdateTime,
+		DeleteTime:  n.DeleteTime.Time,
+		PinnedTime:  n.PinnedTime.Time,
+		ArchiveTime: n.ArchiveTime.Time,
+		Version:     n.Version,
+		ChangeSeq:   n.ChangeSeq,
+		ChangeXid:   n.ChangeXid,
+	}
+}
+
+// nullUUIDToPointer returns nil for a NULL uuid column
+func nullUUIDToPointer(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+// pointerToNullUUID returns a NULL uuid column for a nil id
+func pointerToNullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+// timeToNullTime returns a NULL timestamp column for a zero time
+func timeToNullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
 type noteDatabaseDs struct {
 	queries *database.Queries
 }
@@ -31,65 +71,214 @@ func (d *noteDatabaseDs) CreateNote(ctx context.Context, tx *sql.Tx, note *domai
 		Content:    sql.NullString{String: note.Content, Valid: true},
 		CreateTime: timeNow,
 		UpdateTime: timeNow,
+		NotebookID: pointerToNullUUID(note.NotebookId),
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &domain.Note{
-		Id:         res.ID,
-		UserId:     res.UserID,
-		Title:      res.Title.String,
-		Content:    res.Content.String,
-		CreateTime: res.CreateTime,
-		UpdateTime: res.UpdateTime,
-		DeleteTime: res.DeleteTime.Time,
-	}, nil
+	response := parseNoteFromDatabaseToDomain(res)
+	return &response, nil
 }
 
-func (d *noteDatabaseDs) ListNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]domain.Note, error) {
-	res, err := d.queries.ListNotesByUserId(ctx, database.ListNotesByUserIdParams{UserID: user_id, UpdateTime: cursor})
+func (d *noteDatabaseDs) ListNotesByUser(ctx context.Context, user_id uuid.UUID, sort domain.NoteSort, cursor *domain.NoteListCursor, limit int32, filter domain.NoteFilter) (*[]domain.Note, error) {
+	// Every sort key and direction has its own query, so the listing goes through the index of the sort.
+	// The queries of the time keys share their parameters, the title ones take the title of the cursor.
+	params := database.ListNotesByUserIdUpdateTimeDescParams{
+		UserID:          user_id,
+		IncludeArchived: filter.IncludeArchived,
+		CreatedAfter:    timeToNullTime(filter.CreatedAfter),
+		CreatedBefore:   timeToNullTime(filter.CreatedBefore),
+		UpdatedAfter:    timeToNullTime(filter.UpdatedAfter),
+		UpdatedBefore:   timeToNullTime(filter.UpdatedBefore),
+		TagID:           uuid.NullUUID{UUID: filter.TagId, Valid: filter.TagId != uuid.Nil},
+		NotebookID:      uuid.NullUUID{UUID: filter.NotebookId, Valid: filter.NotebookId != uuid.Nil},
+		PageLimit:       limit,
+	}
+	if cursor != nil {
+		params.HasCursor = true
+		params.CursorPinned = cursor.Pinned
+		params.CursorTime = cursor.Time
+		params.CursorID = cursor.Id
+	}
+	titleParams := database.ListNotesByUserIdTitleDescParams{
+		UserID:          params.UserID,
+		IncludeArchived: params.IncludeArchived,
+		HasCursor:       params.HasCursor,
+		CursorPinned:    params.CursorPinned,
+		CursorID:        params.CursorID,
+		CreatedAfter:    params.CreatedAfter,
+		CreatedBefore:   params.CreatedBefore,
+		UpdatedAfter:    params.UpdatedAfter,
+		UpdatedBefore:   params.UpdatedBefore,
+		TagID:           params.TagID,
+		NotebookID:      params.NotebookID,
+		PageLimit:       params.PageLimit,
+	}
+	if cursor != nil {
+		titleParams.CursorTitle = cursor.Title
+	}
+
+	var (
+		res []database.Note
+		err error
+	)
+	switch {
+	case sort.Key == domain.NoteSortCreateTime && sort.Ascending:
+		res, err = d.queries.ListNotesByUserIdCreateTimeAsc(ctx, database.ListNotesByUserIdCreateTimeAscParams(params))
+	case sort.Key == domain.NoteSortCreateTime:
+		res, err = d.queries.ListNotesByUserIdCreateTimeDesc(ctx, database.ListNotesByUserIdCreateTimeDescParams(params))
+	case sort.Key == domain.NoteSortTitle && sort.Ascending:
+		res, err = d.queries.ListNotesByUserIdTitleAsc(ctx, database.ListNotesByUserIdTitleAscParams(titleParams))
+	case sort.Key == domain.NoteSortTitle:
+		res, err = d.queries.ListNotesByUserIdTitleDesc(ctx, titleParams)
+	case sort.Ascending:
+		res, err = d.queries.ListNotesByUserIdUpdateTimeAsc(ctx, database.ListNotesByUserIdUpdateTimeAscParams(params))
+	default:
+		res, err = d.queries.ListNotesByUserIdUpdateTimeDesc(ctx, params)
+	}
 	if err != nil {
 		return nil, err
 	}
 	// Preallocate slice with the length of the result set
 	response := make([]domain.Note, 0, len(res))
 	for _, note := range res {
-		response = append(response, domain.Note{
-			Id:         note.ID,
-			UserId:     note.UserID,
-			Title:      note.Title.String,
-			Content:    note.Content.String,
-			CreateTime: note.CreateTime,
-			UpdateTime: note.UpdateTime,
-			DeleteTime: note.DeleteTime.Time,
-		})
+		response = append(response, parseNoteFromDatabaseToDomain(note))
+	}
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *domain.NoteListCursor, limit int32, filter domain.NoteFilter) (*[]domain.Note, error) {
+	params := database.ListTrashNotesByUserIdParams{
+		UserID:        user_id,
+		CreatedAfter:  timeToNullTime(filter.CreatedAfter),
+		CreatedBefore: timeToNullTime(filter.CreatedBefore),
+		UpdatedAfter:  timeToNullTime(filter.UpdatedAfter),
+		UpdatedBefore: timeToNullTime(filter.UpdatedBefore),
+		TagID:         uuid.NullUUID{UUID: filter.TagId, Valid: filter.TagId != uuid.Nil},
+		NotebookID:    uuid.NullUUID{UUID: filter.NotebookId, Valid: filter.NotebookId != uuid.Nil},
+		PageLimit:     limit,
+	}
+	if cursor != nil {
+		params.HasCursor = true
+		params.CursorTime = cursor.Time
+		params.CursorID = cursor.Id
+	}
+	res, err := d.queries.ListTrashNotesByUserId(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Note, 0, len(res))
+	for _, note := range res {
+		response = append(response, parseNoteFromDatabaseToDomain(note))
+	}
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) ListArchivedNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *domain.NoteListCursor, limit int32, filter domain.NoteFilter) (*[]domain.Note, error) {
+	params := database.ListArchivedNotesByUserIdParams{
+		UserID:        user_id,
+		CreatedAfter:  timeToNullTime(filter.CreatedAfter),
+		CreatedBefore: timeToNullTime(filter.CreatedBefore),
+		UpdatedAfter:  timeToNullTime(filter.UpdatedAfter),
+		UpdatedBefore: timeToNullTime(filter.UpdatedBefore),
+		TagID:         uuid.NullUUID{UUID: filter.TagId, Valid: filter.TagId != uuid.Nil},
+		NotebookID:    uuid.NullUUID{UUID: filter.NotebookId, Valid: filter.NotebookId != uuid.Nil},
+		PageLimit:     limit,
+	}
+	if cursor != nil {
+		params.HasCursor = true
+		params.CursorTime = cursor.Time
+		params.CursorID = cursor.Id
+	}
+	res, err := d.queries.ListArchivedNotesByUserId(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Note, 0, len(res))
+	for _, note := range res {
+		response = append(response, parseNoteFromDatabaseToDomain(note))
+	}
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) ListExpiredTrashNotes(ctx context.Context, tx *sql.Tx, before time.Time, cursor *domain.NoteListCursor, limit int32) (*[]domain.Note, error) {
+	params := database.ListExpiredTrashNotesParams{
+		Before:    before,
+		PageLimit: limit,
+	}
+	if cursor != nil {
+		params.HasCursor = true
+		params.CursorTime = cursor.Time
+		params.CursorID = cursor.Id
+	}
+	res, err := d.queries.WithTx(tx).ListExpiredTrashNotes(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Note, 0, len(res))
+	for _, note := range res {
+		response = append(response, parseNoteFromDatabaseToDomain(note))
 	}
 	return &response, nil
 }
 
-func (d *noteDatabaseDs) ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]domain.Note, error) {
-	res, err := d.queries.ListTrashNotesByUserId(ctx, database.ListTrashNotesByUserIdParams{UserID: user_id, DeleteTime: sql.NullTime{Time: cursor, Valid: true}})
+func (d *noteDatabaseDs) HardDeleteTrashNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) (*[]domain.Note, error) {
+	res, err := d.queries.WithTx(tx).HardDeleteTrashNotesByIds(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 	// Preallocate slice with the length of the result set
 	response := make([]domain.Note, 0, len(res))
 	for _, note := range res {
-		response = append(response, domain.Note{
-			Id:         note.ID,
-			UserId:     note.UserID,
-			Title:      note.Title.String,
-			Content:    note.Content.String,
-			CreateTime: note.CreateTime,
-			UpdateTime: note.UpdateTime,
-			DeleteTime: note.DeleteTime.Time,
+		response = append(response, parseNoteFromDatabaseToDomain(note))
+	}
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) SearchNotes(ctx context.Context, userId uuid.UUID, query string, includeTrash bool, cursor *domain.NoteSearchCursor, limit int32) (*[]domain.NoteSearchResult, error) {
+	params := database.SearchNotesParams{
+		Query:        query,
+		UserID:       userId,
+		IncludeTrash: includeTrash,
+		PageLimit:    limit,
+	}
+	if cursor != nil {
+		params.HasCursor = true
+		params.CursorRank = cursor.Rank
+		params.CursorID = cursor.Id
+	}
+	res, err := d.queries.SearchNotes(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.NoteSearchResult, 0, len(res))
+	for _, item := range res {
+		response = append(response, domain.NoteSearchResult{
+			Note:           parseNoteFromDatabaseToDomain(item.Note),
+			Rank:           item.Rank,
+			TitleSnippet:   item.TitleSnippet,
+			ContentSnippet: item.ContentSnippet,
 		})
 	}
 	return &response, nil
 }
 
 func (d *noteDatabaseDs) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
-	return nil, nil
+	res, err := d.queries.GetNoteById(ctx, id)
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNoteFromDatabaseToDomain(res)
+	return &response, nil
 }
 
 func (d *noteDatabaseDs) UpdateNote(ctx context.Context, tx *sql.Tx, note *domain.Note) (*domain.Note, error) {
@@ -98,6 +287,88 @@ func (d *noteDatabaseDs) UpdateNote(ctx context.Context, tx *sql.Tx, note *domai
 		Column2:    note.Title,
 		Column3:    note.Content,
 		UpdateTime: time.Now().UTC(),
+		UserID:     note.UserId,
+		Version:    note.Version,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNoteFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) MoveNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, notebookId *uuid.UUID) (*domain.Note, error) {
+	res, err := d.queries.WithTx(tx).MoveNoteById(ctx, database.MoveNoteByIdParams{
+		ID:         id,
+		UserID:     userId,
+		NotebookID: pointerToNullUUID(notebookId),
+		UpdateTime: time.Now().UTC(),
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNoteFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) RemoveNotesFromNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID, notebookIds []uuid.UUID, trash bool) (*[]domain.Note, error) {
+	now := time.Now().UTC()
+	res, err := d.queries.WithTx(tx).RemoveNotesFromNotebooks(ctx, database.RemoveNotesFromNotebooksParams{
+		Trash:       trash,
+		DeleteTime:  now,
+		UpdateTime:  now,
+		UserID:      userId,
+		NotebookIds: notebookIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := make([]domain.Note, 0, len(res))
+	for _, note := range res {
+		response = append(response, parseNoteFromDatabaseToDomain(note))
+	}
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) SetNotePinned(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, pinned bool) (*domain.Note, error) {
+	now := time.Now().UTC()
+	res, err := d.queries.WithTx(tx).SetNotePinnedById(ctx, database.SetNotePinnedByIdParams{
+		ID:         id,
+		UserID:     userId,
+		Pinned:     pinned,
+		PinnedTime: now,
+		UpdateTime: now,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNoteFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *noteDatabaseDs) SetNoteArchived(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, archived bool) (*domain.Note, error) {
+	now := time.Now().UTC()
+	res, err := d.queries.WithTx(tx).SetNoteArchivedById(ctx, database.SetNoteArchivedByIdParams{
+		ID:          id,
+		UserID:      userId,
+		Archived:    archived,
+		ArchiveTime: now,
+		UpdateTime:  now,
 	})
 	if err != nil {
 		switch err.Error() {
@@ -107,19 +378,15 @@ func (d *noteDatabaseDs) UpdateNote(ctx context.Context, tx *sql.Tx, note *domai
 			return nil, err
 		}
 	}
-	return &domain.Note{
-		Id:         res.ID,
-		UserId:     res.UserID,
-		Title:      res.Title.String,
-		Content:    res.Content.String,
-		CreateTime: res.CreateTime,
-		UpdateTime: res.UpdateTime,
-		DeleteTime: res.DeleteTime.Time,
-	}, nil
+	response := parseNoteFromDatabaseToDomain(res)
+	return &response, nil
 }
 
-func (d *noteDatabaseDs) RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*domain.Note, error) {
-	res, err := d.queries.WithTx(tx).RestoreNoteById(ctx, id)
+func (d *noteDatabaseDs) RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) (*domain.Note, error) {
+	res, err := d.queries.WithTx(tx).RestoreNoteById(ctx, database.RestoreNoteByIdParams{
+		ID:     id,
+		UserID: userId,
+	})
 	if err != nil {
 		switch err.Error() {
 		case "sql: no rows in result set":
@@ -128,19 +395,15 @@ func (d *noteDatabaseDs) RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UU
 			return nil, err
 		}
 	}
-	return &domain.Note{
-		Id:         res.ID,
-		UserId:     res.UserID,
-		Title:      res.Title.String,
-		Content:    res.Content.String,
-		CreateTime: res.CreateTime,
-		UpdateTime: res.UpdateTime,
-		DeleteTime: res.DeleteTime.Time,
-	}, nil
+	response := parseNoteFromDatabaseToDomain(res)
+	return &response, nil
 }
 
-func (d *noteDatabaseDs) HardDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
-	_, err := d.queries.WithTx(tx).HardDeleteNoteById(ctx, id)
+func (d *noteDatabaseDs) HardDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	_, err := d.queries.WithTx(tx).HardDeleteNoteById(ctx, database.HardDeleteNoteByIdParams{
+		ID:     id,
+		UserID: userId,
+	})
 	if err != nil {
 		switch err.Error() {
 		case "sql: no rows in result set":
@@ -152,10 +415,11 @@ func (d *noteDatabaseDs) HardDeleteNote(ctx context.Context, tx *sql.Tx, id uuid
 	return nil
 }
 
-func (d *noteDatabaseDs) SoftDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+func (d *noteDatabaseDs) SoftDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
 	_, err := d.queries.WithTx(tx).SoftDeleteNoteById(ctx, database.SoftDeleteNoteByIdParams{
 		ID:         id,
 		DeleteTime: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		UserID:     userId,
 	})
 	if err != nil {
 		switch err.Error() {
@@ -167,3 +431,54 @@ func (d *noteDatabaseDs) SoftDeleteNote(ctx context.Context, tx *sql.Tx, id uuid
 	}
 	return nil
 }
+
+func (d *noteDatabaseDs) SoftDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]domain.Note, error) {
+	res, err := d.queries.WithTx(tx).SoftDeleteNotesByIds(ctx, database.SoftDeleteNotesByIdsParams{
+		Ids:        ids,
+		DeleteTime: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		UserID:     userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseNotesFromDatabaseToDomain(res), nil
+}
+
+func (d *noteDatabaseDs) RestoreNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]domain.Note, error) {
+	res, err := d.queries.WithTx(tx).RestoreNotesByIds(ctx, database.RestoreNotesByIdsParams{
+		Ids:    ids,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseNotesFromDatabaseToDomain(res), nil
+}
+
+func (d *noteDatabaseDs) HardDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]domain.Note, error) {
+	res, err := d.queries.WithTx(tx).HardDeleteNotesByIds(ctx, database.HardDeleteNotesByIdsParams{
+		Ids:    ids,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseNotesFromDatabaseToDomain(res), nil
+}
+
+func (d *noteDatabaseDs) ListTrashNoteIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID, limit int32) ([]uuid.UUID, error) {
+	return d.queries.WithTx(tx).ListTrashNoteIdsByUserId(ctx, database.ListTrashNoteIdsByUserIdParams{
+		UserID: userId,
+		Limit:  limit,
+	})
+}
+
+// parseNotesFromDatabaseToDomain converts the notes of a result set to domain notes
+func parseNotesFromDatabaseToDomain(res []database.Note) *[]domain.Note {
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Note, 0, len(res))
+	for _, note := range res {
+		response = append(response, parseNoteFromDatabaseToDomain(note))
+	}
+	return &response
+}
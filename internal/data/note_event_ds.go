@@ -0,0 +1,173 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// noteEventsChannel returns the pub/sub channel with the note events of the user
+func noteEventsChannel(userId uuid.UUID) string {
+	return fmt.Sprintf("note_events:%s", userId)
+}
+
+// noteEventsStream returns the bounded stream with the note events of the user, it is used to resume the SSE feed
+func noteEventsStream(userId uuid.UUID) string {
+	return fmt.Sprintf("note_events_stream:%s", userId)
+}
+
+type noteEventDs struct {
+	redis *redis.Client
+	// readers holds the connections of the subscriptions and the blocking stream reads, it has its own pool
+	// so the open feeds can't take every connection of the client shared with the rest of the server
+	readers *redis.Client
+}
+
+func NewNoteEventDs(redis *redis.Client, readers *redis.Client) domain.NoteEventDs {
+	return &noteEventDs{
+		redis:   redis,
+		readers: readers,
+	}
+}
+
+func (n *noteEventDs) PublishNoteEvent(ctx context.Context, event *domain.NoteEvent, streamLength int64) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Append the event to the stream, the id of the entry is the id of the event
+	id, err := n.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: noteEventsStream(event.UserId),
+		MaxLen: streamLength,
+		Approx: true,
+		Values: map[string]interface{}{"event": payload},
+	}).Result()
+	if err != nil {
+		return err
+	}
+	event.Id = id
+
+	payload, err = json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return n.redis.Publish(ctx, noteEventsChannel(event.UserId), payload).Err()
+}
+
+func (n *noteEventDs) SubscribeNoteEvents(ctx context.Context, userId uuid.UUID) (<-chan domain.NoteEvent, error) {
+	pubsub := n.readers.Subscribe(ctx, noteEventsChannel(userId))
+
+	// Wait for the confirmation of the subscription, so no event is missed after returning
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan domain.NoteEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event domain.NoteEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (n *noteEventDs) ReadNoteEvents(ctx context.Context, userId uuid.UUID, lastEventId string) (<-chan domain.NoteEvent, error) {
+	key := noteEventsStream(userId)
+
+	// Start after the last event of the stream when there is nothing to resume
+	if lastEventId == "" {
+		last, err := n.readers.XRevRangeN(ctx, key, "+", "-", 1).Result()
+		if err != nil {
+			return nil, err
+		}
+		lastEventId = "0-0"
+		if len(last) > 0 {
+			lastEventId = last[0].ID
+		}
+	}
+
+	// Check if events after the last received one were trimmed from the stream
+	reset := false
+	info, err := n.readers.XInfoStream(ctx, key).Result()
+	if err != nil && err.Error() != "ERR no such key" {
+		return nil, err
+	}
+	if info != nil && info.MaxDeletedEntryID != "" && domain.NoteEventIdBefore(lastEventId, info.MaxDeletedEntryID) {
+		reset = true
+	}
+
+	events := make(chan domain.NoteEvent)
+	go func() {
+		defer close(events)
+
+		if reset {
+			select {
+			case events <- domain.NoteEvent{Type: domain.NoteEventsReset, UserId: userId, Time: time.Now().UTC()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for ctx.Err() == nil {
+			streams, err := n.readers.XRead(ctx, &redis.XReadArgs{
+				Streams: []string{key, lastEventId},
+				Count:   100,
+				Block:   5 * time.Second,
+			}).Result()
+			if err == redis.Nil {
+				continue
+			} else if err != nil {
+				// The stream is closed, the client resumes it with the id of the last received event
+				return
+			}
+			for _, stream := range streams {
+				for _, message := range stream.Messages {
+					lastEventId = message.ID
+					payload, ok := message.Values["event"].(string)
+					if !ok {
+						continue
+					}
+					var event domain.NoteEvent
+					if err := json.Unmarshal([]byte(payload), &event); err != nil {
+						continue
+					}
+					event.Id = message.ID
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,83 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Parses a note revision from the database to a domain.NoteRevision
+func parseNoteRevisionFromDatabaseToDomain(r database.NoteRevision) domain.NoteRevision {
+	return domain.NoteRevision{
+		Id:         r.ID,
+		NoteId:     r.NoteID,
+		UserId:     r.UserID,
+		Title:      r.Title.String,
+		Content:    r.Content.String,
+		CreateTime: r.CreateTime,
+	}
+}
+
+type noteRevisionDatabaseDs struct {
+	queries *database.Queries
+}
+
+func NewNoteRevisionDatabaseDs(queries *database.Queries) domain.NoteRevisionDatabaseDs {
+	return &noteRevisionDatabaseDs{
+		queries: queries,
+	}
+}
+
+func (d *noteRevisionDatabaseDs) CreateNoteRevision(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID) error {
+	return d.queries.WithTx(tx).CreateNoteRevision(ctx, database.CreateNoteRevisionParams{
+		ID:         noteId,
+		UserID:     userId,
+		CreateTime: time.Now().UTC(),
+	})
+}
+
+func (d *noteRevisionDatabaseDs) ListNoteRevisions(ctx context.Context, noteId uuid.UUID, userId uuid.UUID) (*[]domain.NoteRevision, error) {
+	res, err := d.queries.ListNoteRevisionsByNoteId(ctx, database.ListNoteRevisionsByNoteIdParams{
+		NoteID: noteId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.NoteRevision, 0, len(res))
+	for _, revision := range res {
+		response = append(response, parseNoteRevisionFromDatabaseToDomain(revision))
+	}
+	return &response, nil
+}
+
+func (d *noteRevisionDatabaseDs) GetNoteRevision(ctx context.Context, id uuid.UUID, noteId uuid.UUID, userId uuid.UUID) (*domain.NoteRevision, error) {
+	res, err := d.queries.GetNoteRevisionById(ctx, database.GetNoteRevisionByIdParams{
+		ID:     id,
+		NoteID: noteId,
+		UserID: userId,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNoteRevisionFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *noteRevisionDatabaseDs) PruneNoteRevisions(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, keep int) error {
+	return d.queries.WithTx(tx).PruneNoteRevisions(ctx, database.PruneNoteRevisionsParams{
+		NoteID: noteId,
+		Keep:   int32(keep),
+	})
+}
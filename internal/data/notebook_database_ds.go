@@ -0,0 +1,142 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Parses a notebook from the database to a domain.Notebook
+func parseNotebookFromDatabaseToDomain(n database.Notebook) domain.Notebook {
+	return domain.Notebook{
+		Id:         n.ID,
+		UserId:     n.UserID,
+		ParentId:   nullUUIDToPointer(n.ParentID),
+		Name:       n.Name,
+		CreateTime: n.CreateTime,
+		UpdateTime: n.UpdateTime,
+	}
+}
+
+type notebookDatabaseDs struct {
+	queries *database.Queries
+}
+
+func NewNotebookDatabaseDs(queries *database.Queries) domain.NotebookDatabaseDs {
+	return &notebookDatabaseDs{
+		queries: queries,
+	}
+}
+
+func (d *notebookDatabaseDs) GetNotebook(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Notebook, error) {
+	res, err := d.queries.GetNotebookById(ctx, database.GetNotebookByIdParams{ID: id, UserID: userId})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNotebookFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *notebookDatabaseDs) ListNotebooksByUser(ctx context.Context, userId uuid.UUID) (*[]domain.Notebook, error) {
+	res, err := d.queries.ListNotebooksByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Notebook, 0, len(res))
+	for _, notebook := range res {
+		response = append(response, parseNotebookFromDatabaseToDomain(notebook))
+	}
+	return &response, nil
+}
+
+// LockNotebooks locks the notebooks of the user, so their tree doesn't change until the transaction ends
+func (d *notebookDatabaseDs) LockNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	return d.queries.WithTx(tx).LockNotebooksByUserId(ctx, userId)
+}
+
+func (d *notebookDatabaseDs) ListNotebookTreeIds(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error) {
+	return d.queries.WithTx(tx).ListNotebookTreeIds(ctx, database.ListNotebookTreeIdsParams{ID: id, UserID: userId})
+}
+
+func (d *notebookDatabaseDs) CreateNotebook(ctx context.Context, tx *sql.Tx, notebook *domain.Notebook) (*domain.Notebook, error) {
+	// Get current time
+	timeNow := time.Now().UTC()
+
+	res, err := d.queries.WithTx(tx).CreateNotebook(ctx, database.CreateNotebookParams{
+		UserID:     notebook.UserId,
+		ParentID:   pointerToNullUUID(notebook.ParentId),
+		Name:       notebook.Name,
+		CreateTime: timeNow,
+		UpdateTime: timeNow,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := parseNotebookFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *notebookDatabaseDs) RenameNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, name string) (*domain.Notebook, error) {
+	res, err := d.queries.WithTx(tx).RenameNotebookById(ctx, database.RenameNotebookByIdParams{
+		ID:         id,
+		UserID:     userId,
+		Name:       name,
+		UpdateTime: time.Now().UTC(),
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNotebookFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *notebookDatabaseDs) MoveNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, parentId *uuid.UUID) (*domain.Notebook, error) {
+	res, err := d.queries.WithTx(tx).MoveNotebookById(ctx, database.MoveNotebookByIdParams{
+		ID:         id,
+		UserID:     userId,
+		ParentID:   pointerToNullUUID(parentId),
+		UpdateTime: time.Now().UTC(),
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		default:
+			return nil, err
+		}
+	}
+	response := parseNotebookFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *notebookDatabaseDs) DeleteNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	_, err := d.queries.WithTx(tx).DeleteNotebookById(ctx, database.DeleteNotebookByIdParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return &customerrors.RecordNotFound{}
+		default:
+			return err
+		}
+	}
+	return nil
+}
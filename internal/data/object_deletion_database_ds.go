@@ -0,0 +1,78 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Parses an object deletion from the database to a domain.ObjectDeletion
+func parseObjectDeletionFromDatabaseToDomain(d database.ObjectDeletion) domain.ObjectDeletion {
+	return domain.ObjectDeletion{
+		Id:              d.ID,
+		Bucket:          d.Bucket,
+		ObjectName:      d.ObjectName,
+		Attempts:        d.Attempts,
+		LastError:       d.LastError.String,
+		NextAttemptTime: d.NextAttemptTime,
+		DeadTime:        d.DeadTime.Time,
+		CreateTime:      d.CreateTime,
+	}
+}
+
+type objectDeletionDatabaseDs struct {
+	queries *database.Queries
+}
+
+func NewObjectDeletionDatabaseDs(queries *database.Queries) domain.ObjectDeletionDatabaseDs {
+	return &objectDeletionDatabaseDs{
+		queries: queries,
+	}
+}
+
+func (d *objectDeletionDatabaseDs) CreateObjectDeletions(ctx context.Context, tx *sql.Tx, bucket string, objectNames []string) error {
+	return d.queries.WithTx(tx).CreateObjectDeletions(ctx, database.CreateObjectDeletionsParams{
+		Bucket:      bucket,
+		ObjectNames: objectNames,
+	})
+}
+
+func (d *objectDeletionDatabaseDs) ListDueObjectDeletions(ctx context.Context, tx *sql.Tx, now time.Time, limit int32) (*[]domain.ObjectDeletion, error) {
+	res, err := d.queries.WithTx(tx).ListDueObjectDeletions(ctx, database.ListDueObjectDeletionsParams{
+		Now:       now,
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.ObjectDeletion, 0, len(res))
+	for _, deletion := range res {
+		response = append(response, parseObjectDeletionFromDatabaseToDomain(deletion))
+	}
+	return &response, nil
+}
+
+func (d *objectDeletionDatabaseDs) DeleteObjectDeletions(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) error {
+	return d.queries.WithTx(tx).DeleteObjectDeletionsByIds(ctx, ids)
+}
+
+func (d *objectDeletionDatabaseDs) FailObjectDeletion(ctx context.Context, tx *sql.Tx, id uuid.UUID, lastError string, nextAttemptTime time.Time, dead bool) error {
+	params := database.FailObjectDeletionByIdParams{
+		ID:              id,
+		LastError:       sql.NullString{String: lastError, Valid: true},
+		NextAttemptTime: nextAttemptTime,
+	}
+	if dead {
+		params.DeadTime = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+	return d.queries.WithTx(tx).FailObjectDeletionById(ctx, params)
+}
+
+func (d *objectDeletionDatabaseDs) RequeueDeadObjectDeletions(ctx context.Context) (int64, error) {
+	return d.queries.RequeueDeadObjectDeletions(ctx)
+}
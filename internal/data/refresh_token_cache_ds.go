@@ -14,19 +14,30 @@ import (
 type RefreshToken struct {
 	Id         string    `redis:"id"`
 	UserId     string    `redis:"user_id"`
+	FamilyId   string    `redis:"family_id"`
+	DeviceName string    `redis:"device_name"`
+	UserAgent  string    `redis:"user_agent"`
+	IpAddress  string    `redis:"ip_address"`
 	CreateTime time.Time `redis:"create_time"`
 	UpdateTime time.Time `redis:"update_time"`
+	RotateTime time.Time `redis:"rotate_time"`
 }
 
 func (u *RefreshToken) ParseToDomain() *domain.RefreshToken {
 	if u.Id != "" {
 		id := uuid.MustParse(u.Id)
 		userId := uuid.MustParse(u.UserId)
+		familyId, _ := uuid.Parse(u.FamilyId)
 		return &domain.RefreshToken{
 			Id:         id,
 			UserId:     userId,
+			FamilyId:   familyId,
+			DeviceName: u.DeviceName,
+			UserAgent:  u.UserAgent,
+			IpAddress:  u.IpAddress,
 			CreateTime: u.CreateTime,
 			UpdateTime: u.UpdateTime,
+			RotateTime: u.RotateTime,
 		}
 	}
 	return nil
@@ -36,8 +47,13 @@ func parseRefreshTokenFromDomain(refreshToken *domain.RefreshToken) *RefreshToke
 	return &RefreshToken{
 		Id:         refreshToken.Id.String(),
 		UserId:     refreshToken.UserId.String(),
+		FamilyId:   refreshToken.FamilyId.String(),
+		DeviceName: refreshToken.DeviceName,
+		UserAgent:  refreshToken.UserAgent,
+		IpAddress:  refreshToken.IpAddress,
 		CreateTime: refreshToken.CreateTime,
 		UpdateTime: refreshToken.UpdateTime,
+		RotateTime: refreshToken.RotateTime,
 	}
 }
 
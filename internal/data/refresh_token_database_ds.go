@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/database"
@@ -18,8 +19,13 @@ func parseRefreshTokenToDomain(refreshToken *database.RefreshToken) *domain.Refr
 	return &domain.RefreshToken{
 		Id:         refreshToken.ID,
 		UserId:     refreshToken.UserID,
+		FamilyId:   refreshToken.FamilyID,
+		DeviceName: refreshToken.DeviceName.String,
+		UserAgent:  refreshToken.UserAgent.String,
+		IpAddress:  refreshToken.IpAddress.String,
 		CreateTime: refreshToken.CreateTime,
 		UpdateTime: refreshToken.UpdateTime.Time,
+		RotateTime: refreshToken.RotateTime.Time,
 	}
 }
 
@@ -30,7 +36,13 @@ func NewRefreshTokenDatabaseDs(queries *database.Queries) domain.RefreshTokenDat
 }
 
 func (d *refreshTokenDatabaseDs) CreateRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken *domain.RefreshToken) (*domain.RefreshToken, error) {
-	res, err := d.queries.WithTx(tx).CreateRefreshToken(ctx, refreshToken.UserId)
+	res, err := d.queries.WithTx(tx).CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+		UserID:     refreshToken.UserId,
+		FamilyID:   refreshToken.FamilyId,
+		DeviceName: sql.NullString{String: refreshToken.DeviceName, Valid: refreshToken.DeviceName != ""},
+		UserAgent:  sql.NullString{String: refreshToken.UserAgent, Valid: refreshToken.UserAgent != ""},
+		IpAddress:  sql.NullString{String: refreshToken.IpAddress, Valid: refreshToken.IpAddress != ""},
+	})
 	if err != nil {
 		switch err.Error() {
 		case "ERROR: insert on table \"refresh_tokens\" violates foreign key constraint \"fk_user\" (SQLSTATE 23503)":
@@ -55,8 +67,11 @@ func (d *refreshTokenDatabaseDs) GetRefreshTokenById(ctx context.Context, id uui
 	return parseRefreshTokenToDomain(&res), nil
 }
 
-func (d *refreshTokenDatabaseDs) DeleteRefreshTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (*uuid.UUID, error) {
-	id, err := d.queries.WithTx(tx).DeleteRefreshTokenByUserId(ctx, userId)
+func (d *refreshTokenDatabaseDs) RotateRefreshToken(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*domain.RefreshToken, error) {
+	res, err := d.queries.WithTx(tx).RotateRefreshTokenById(ctx, database.RotateRefreshTokenByIdParams{
+		ID:         id,
+		RotateTime: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	})
 	if err != nil {
 		switch err.Error() {
 		case "sql: no rows in result set":
@@ -65,5 +80,36 @@ func (d *refreshTokenDatabaseDs) DeleteRefreshTokenByUserId(ctx context.Context,
 			return nil, err
 		}
 	}
-	return &id, nil
+	return parseRefreshTokenToDomain(&res), nil
+}
+
+func (d *refreshTokenDatabaseDs) DeleteRefreshTokensByFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) (*[]uuid.UUID, error) {
+	ids, err := d.queries.WithTx(tx).DeleteRefreshTokensByFamilyId(ctx, familyId)
+	if err != nil {
+		return nil, err
+	}
+	return &ids, nil
+}
+
+func (d *refreshTokenDatabaseDs) ListSessionsByUserId(ctx context.Context, userId uuid.UUID) (*[]domain.Session, error) {
+	// Leave out the sessions whose latest refresh token is expired
+	res, err := d.queries.ListSessionsByUserId(ctx, database.ListSessionsByUserIdParams{
+		UserID:     userId,
+		CreateTime: time.Now().Add(-domain.RefreshTokenLifetime),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]domain.Session, 0, len(res))
+	for _, item := range res {
+		sessions = append(sessions, domain.Session{
+			Id:             item.FamilyID,
+			DeviceName:     item.DeviceName.String,
+			UserAgent:      item.UserAgent.String,
+			IpAddress:      item.IpAddress.String,
+			SignInTime:     item.SignInTime,
+			LastActiveTime: item.CreateTime,
+		})
+	}
+	return &sessions, nil
 }
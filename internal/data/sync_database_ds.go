@@ -0,0 +1,88 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+type syncDatabaseDs struct {
+	queries *database.Queries
+}
+
+func NewSyncDatabaseDs(queries *database.Queries) domain.SyncDatabaseDs {
+	return &syncDatabaseDs{
+		queries: queries,
+	}
+}
+
+func (d *syncDatabaseDs) GetSyncHorizon(ctx context.Context, tx *sql.Tx) (int64, error) {
+	return d.queries.WithTx(tx).GetSyncHorizon(ctx)
+}
+
+func (d *syncDatabaseDs) ListNotesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.Note, error) {
+	res, err := d.queries.WithTx(tx).ListNotesChangedSince(ctx, database.ListNotesChangedSinceParams{
+		UserID:    userId,
+		ChangeXid: position.ChangeXid,
+		ChangeSeq: position.ChangeSeq,
+		Horizon:   horizon,
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Note, 0, len(res))
+	for _, note := range res {
+		response = append(response, parseNoteFromDatabaseToDomain(note))
+	}
+	return &response, nil
+}
+
+func (d *syncDatabaseDs) ListFilesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.File, error) {
+	res, err := d.queries.WithTx(tx).ListFilesChangedSince(ctx, database.ListFilesChangedSinceParams{
+		UserID:    userId,
+		ChangeXid: position.ChangeXid,
+		ChangeSeq: position.ChangeSeq,
+		Horizon:   horizon,
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.File, 0, len(res))
+	for _, file := range res {
+		response = append(response, parseFromDatabaseToDomain(file))
+	}
+	return &response, nil
+}
+
+func (d *syncDatabaseDs) ListTombstonesSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.Tombstone, error) {
+	res, err := d.queries.WithTx(tx).ListTombstonesSince(ctx, database.ListTombstonesSinceParams{
+		UserID:    userId,
+		ChangeXid: position.ChangeXid,
+		ChangeSeq: position.ChangeSeq,
+		Horizon:   horizon,
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Tombstone, 0, len(res))
+	for _, tombstone := range res {
+		response = append(response, domain.Tombstone{
+			EntityType: tombstone.EntityType,
+			EntityId:   tombstone.EntityID,
+			NoteId:     tombstone.NoteID,
+			DeleteTime: tombstone.DeleteTime,
+			ChangeSeq:  tombstone.ChangeSeq,
+			ChangeXid:  tombstone.ChangeXid,
+		})
+	}
+	return &response, nil
+}
@@ -0,0 +1,169 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/database"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Parses a tag from the database to a domain.Tag
+func parseTagFromDatabaseToDomain(t database.Tag) domain.Tag {
+	return domain.Tag{
+		Id:         t.ID,
+		UserId:     t.UserID,
+		Name:       t.Name,
+		CreateTime: t.CreateTime,
+		UpdateTime: t.UpdateTime,
+	}
+}
+
+type tagDatabaseDs struct {
+	queries *database.Queries
+}
+
+func NewTagDatabaseDs(queries *database.Queries) domain.TagDatabaseDs {
+	return &tagDatabaseDs{
+		queries: queries,
+	}
+}
+
+func (d *tagDatabaseDs) ListTagsByUser(ctx context.Context, userId uuid.UUID) (*[]domain.Tag, error) {
+	res, err := d.queries.ListTagsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Tag, 0, len(res))
+	for _, tag := range res {
+		response = append(response, parseTagFromDatabaseToDomain(tag))
+	}
+	return &response, nil
+}
+
+func (d *tagDatabaseDs) ListTagsByIds(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (*[]domain.Tag, error) {
+	res, err := d.queries.ListTagsByIds(ctx, database.ListTagsByIdsParams{UserID: userId, Column2: ids})
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Tag, 0, len(res))
+	for _, tag := range res {
+		response = append(response, parseTagFromDatabaseToDomain(tag))
+	}
+	return &response, nil
+}
+
+func (d *tagDatabaseDs) ListTagsByNotesIds(ctx context.Context, noteIds []uuid.UUID) (*[]domain.NoteTag, error) {
+	res, err := d.queries.ListTagsByNotesIds(ctx, noteIds)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.NoteTag, 0, len(res))
+	for _, row := range res {
+		response = append(response, domain.NoteTag{
+			NoteId: row.NoteID,
+			Tag: domain.Tag{
+				Id:         row.ID,
+				UserId:     row.UserID,
+				Name:       row.Name,
+				CreateTime: row.CreateTime,
+				UpdateTime: row.UpdateTime,
+			},
+		})
+	}
+	return &response, nil
+}
+
+func (d *tagDatabaseDs) ListTagsByNoteId(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) (*[]domain.Tag, error) {
+	res, err := d.queries.WithTx(tx).ListTagsByNoteId(ctx, noteId)
+	if err != nil {
+		return nil, err
+	}
+	// Preallocate slice with the length of the result set
+	response := make([]domain.Tag, 0, len(res))
+	for _, tag := range res {
+		response = append(response, parseTagFromDatabaseToDomain(tag))
+	}
+	return &response, nil
+}
+
+func (d *tagDatabaseDs) CreateTag(ctx context.Context, tx *sql.Tx, tag *domain.Tag) (*domain.Tag, error) {
+	// Get current time
+	timeNow := time.Now().UTC()
+
+	res, err := d.queries.WithTx(tx).CreateTag(ctx, database.CreateTagParams{
+		UserID:     tag.UserId,
+		Name:       tag.Name,
+		CreateTime: timeNow,
+		UpdateTime: timeNow,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "ERROR: duplicate key value violates unique constraint \"tags_user_id_name_key\" (SQLSTATE 23505)":
+			return nil, &customerrors.DuplicateRecord{Field: "name"}
+		default:
+			return nil, err
+		}
+	}
+	response := parseTagFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *tagDatabaseDs) UpdateTag(ctx context.Context, tx *sql.Tx, tag *domain.Tag) (*domain.Tag, error) {
+	res, err := d.queries.WithTx(tx).UpdateTagById(ctx, database.UpdateTagByIdParams{
+		ID:         tag.Id,
+		UserID:     tag.UserId,
+		Name:       tag.Name,
+		UpdateTime: time.Now().UTC(),
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return nil, &customerrors.RecordNotFound{}
+		case "ERROR: duplicate key value violates unique constraint \"tags_user_id_name_key\" (SQLSTATE 23505)":
+			return nil, &customerrors.DuplicateRecord{Field: "name"}
+		default:
+			return nil, err
+		}
+	}
+	response := parseTagFromDatabaseToDomain(res)
+	return &response, nil
+}
+
+func (d *tagDatabaseDs) DeleteTag(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	_, err := d.queries.WithTx(tx).DeleteTagById(ctx, database.DeleteTagByIdParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "sql: no rows in result set":
+			return &customerrors.RecordNotFound{}
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *tagDatabaseDs) AttachTagsToNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error {
+	return d.queries.WithTx(tx).AttachTagsToNote(ctx, database.AttachTagsToNoteParams{
+		NoteID:  noteId,
+		UserID:  userId,
+		Column3: tagIds,
+	})
+}
+
+func (d *tagDatabaseDs) DetachTagsFromNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error {
+	return d.queries.WithTx(tx).DetachTagsFromNote(ctx, database.DetachTagsFromNoteParams{
+		NoteID:  noteId,
+		UserID:  userId,
+		Column3: tagIds,
+	})
+}
@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/daniarmas/notes/internal/customerrors"
@@ -20,8 +21,8 @@ func NewUserDatabaseDs(queries *database.Queries) domain.UserDatabaseDs {
 	}
 }
 
-func (d *userDatabaseDs) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
-	res, err := d.queries.CreateUser(ctx, database.CreateUserParams{
+func (d *userDatabaseDs) CreateUser(ctx context.Context, tx *sql.Tx, user *domain.User) (*domain.User, error) {
+	res, err := d.queries.WithTx(tx).CreateUser(ctx, database.CreateUserParams{
 		Name:     user.Name,
 		Email:    user.Email,
 		Password: user.Password,
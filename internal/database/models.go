@@ -20,30 +20,126 @@ type AccessToken struct {
 }
 
 type File struct {
-	ID            uuid.UUID
-	ProcessedFile sql.NullString
-	OriginalFile  string
-	NoteID        uuid.UUID
-	CreateTime    time.Time
-	UpdateTime    time.Time
-	DeleteTime    sql.NullTime
+	ID               uuid.UUID
+	ProcessedFile    sql.NullString
+	OriginalFile     string
+	NoteID           uuid.UUID
+	CreateTime       time.Time
+	UpdateTime       time.Time
+	DeleteTime       sql.NullTime
+	ChangeSeq        int64
+	ChangeXid        int64
+	ProcessingStatus string
+	Duration         sql.NullFloat64
+	SampleRate       sql.NullInt32
+	Waveform         []float32
+}
+
+type FileJob struct {
+	ID              uuid.UUID
+	FileID          uuid.UUID
+	Status          string
+	Attempts        int32
+	LastError       sql.NullString
+	RunTime         time.Time
+	LeaseOwner      sql.NullString
+	LeaseExpireTime sql.NullTime
+	CreateTime      time.Time
+	UpdateTime      time.Time
+}
+
+type FileRendition struct {
+	ID         uuid.UUID
+	FileID     uuid.UUID
+	Size       int32
+	ObjectName string
+	Width      int32
+	Height     int32
+	CreateTime time.Time
 }
 
 type Note struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	NotebookID   uuid.NullUUID
+	Title        sql.NullString
+	Content      sql.NullString
+	CreateTime   time.Time
+	UpdateTime   time.Time
+	DeleteTime   sql.NullTime
+	PinnedTime   sql.NullTime
+	ArchiveTime  sql.NullTime
+	Version      int32
+	ChangeSeq    int64
+	ChangeXid    int64
+	SearchVector interface{}
+}
+
+type NoteRevision struct {
 	ID         uuid.UUID
+	NoteID     uuid.UUID
 	UserID     uuid.UUID
 	Title      sql.NullString
 	Content    sql.NullString
 	CreateTime time.Time
+}
+
+type NoteTag struct {
+	NoteID     uuid.UUID
+	TagID      uuid.UUID
+	UserID     uuid.UUID
+	CreateTime time.Time
+}
+
+type Notebook struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	ParentID   uuid.NullUUID
+	Name       string
+	CreateTime time.Time
 	UpdateTime time.Time
-	DeleteTime sql.NullTime
+}
+
+type ObjectDeletion struct {
+	ID              uuid.UUID
+	Bucket          string
+	ObjectName      string
+	Attempts        int32
+	LastError       sql.NullString
+	NextAttemptTime time.Time
+	DeadTime        sql.NullTime
+	CreateTime      time.Time
 }
 
 type RefreshToken struct {
 	ID         uuid.UUID
 	UserID     uuid.UUID
+	FamilyID   uuid.UUID
+	DeviceName sql.NullString
+	UserAgent  sql.NullString
+	IpAddress  sql.NullString
 	CreateTime time.Time
 	UpdateTime sql.NullTime
+	RotateTime sql.NullTime
+}
+
+type Tag struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+type Tombstone struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	EntityType string
+	EntityID   uuid.UUID
+	NoteID     uuid.UUID
+	ChangeSeq  int64
+	ChangeXid  int64
+	DeleteTime time.Time
 }
 
 type User struct {
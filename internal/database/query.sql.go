@@ -14,6 +14,179 @@ import (
 	"github.com/lib/pq"
 )
 
+const attachTagsToNote = `-- name: AttachTagsToNote :exec
+INSERT INTO note_tags (
+  note_id, tag_id, user_id
+)
+SELECT $1, unnest($3::uuid[]), $2
+ON CONFLICT DO NOTHING
+`
+
+type AttachTagsToNoteParams struct {
+	NoteID  uuid.UUID
+	UserID  uuid.UUID
+	Column3 []uuid.UUID
+}
+
+func (q *Queries) AttachTagsToNote(ctx context.Context, arg AttachTagsToNoteParams) error {
+	_, err := q.db.ExecContext(ctx, attachTagsToNote, arg.NoteID, arg.UserID, pq.Array(arg.Column3))
+	return err
+}
+
+const claimFileJobs = `-- name: ClaimFileJobs :many
+UPDATE file_jobs SET
+  status = 'running',
+  attempts = file_jobs.attempts + 1,
+  lease_owner = $1::varchar,
+  lease_expire_time = $2::timestamp,
+  update_time = $3
+FROM files
+WHERE files.id = file_jobs.file_id AND file_jobs.id IN (
+  SELECT id FROM file_jobs AS due
+  WHERE (due.status = 'queued' AND due.run_time <= $3) OR (due.status = 'running' AND due.lease_expire_time <= $3)
+  ORDER BY due.run_time, due.id
+  LIMIT $4
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING file_jobs.id, file_jobs.file_id, file_jobs.status, file_jobs.attempts, file_jobs.last_error, file_jobs.run_time, file_jobs.lease_owner, file_jobs.lease_expire_time, file_jobs.create_time, file_jobs.update_time, files.original_file
+`
+
+type ClaimFileJobsParams struct {
+	Worker          string
+	LeaseExpireTime time.Time
+	Now             time.Time
+	PageLimit       int32
+}
+
+type ClaimFileJobsRow struct {
+	ID              uuid.UUID
+	FileID          uuid.UUID
+	Status          string
+	Attempts        int32
+	LastError       sql.NullString
+	RunTime         time.Time
+	LeaseOwner      sql.NullString
+	LeaseExpireTime sql.NullTime
+	CreateTime      time.Time
+	UpdateTime      time.Time
+	OriginalFile    string
+}
+
+func (q *Queries) ClaimFileJobs(ctx context.Context, arg ClaimFileJobsParams) ([]ClaimFileJobsRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimFileJobs,
+		arg.Worker,
+		arg.LeaseExpireTime,
+		arg.Now,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimFileJobsRow
+	for rows.Next() {
+		var i ClaimFileJobsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileID,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.RunTime,
+			&i.LeaseOwner,
+			&i.LeaseExpireTime,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.OriginalFile,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeFileJob = `-- name: CompleteFileJob :execrows
+UPDATE file_jobs SET
+  status = 'succeeded',
+  last_error = NULL,
+  lease_owner = NULL,
+  lease_expire_time = NULL,
+  update_time = $1
+WHERE id = $2 AND status = 'running' AND lease_owner = $3::varchar
+`
+
+type CompleteFileJobParams struct {
+	Now    time.Time
+	ID     uuid.UUID
+	Worker string
+}
+
+func (q *Queries) CompleteFileJob(ctx context.Context, arg CompleteFileJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeFileJob, arg.Now, arg.ID, arg.Worker)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const completeFileJobsByFileId = `-- name: CompleteFileJobsByFileId :exec
+UPDATE file_jobs SET
+  status = 'succeeded', lease_owner = NULL, lease_expire_time = NULL, update_time = $1
+WHERE file_id = $2 AND status <> 'running'
+`
+
+type CompleteFileJobsByFileIdParams struct {
+	UpdateTime time.Time
+	FileID     uuid.UUID
+}
+
+func (q *Queries) CompleteFileJobsByFileId(ctx context.Context, arg CompleteFileJobsByFileIdParams) error {
+	_, err := q.db.ExecContext(ctx, completeFileJobsByFileId, arg.UpdateTime, arg.FileID)
+	return err
+}
+
+const countFilesForReprocessing = `-- name: CountFilesForReprocessing :one
+SELECT count(*) FROM files
+JOIN notes ON notes.id = files.note_id
+WHERE files.delete_time IS NULL AND files.processing_status <> 'running'
+  AND ($1::uuid IS NULL OR files.note_id = $1::uuid)
+  AND ($2::uuid IS NULL OR notes.user_id = $2::uuid)
+  AND ($3::varchar[] IS NULL OR lower(substring(files.original_file from '\.[^./]*$')) = ANY($3::varchar[]))
+  AND ($4::timestamp IS NULL OR files.create_time >= $4::timestamp)
+  AND ($5::timestamp IS NULL OR files.create_time < $5::timestamp)
+  AND (NOT $6::boolean OR files.processing_status <> 'succeeded' OR files.processed_file IS NULL OR files.processed_file = '')
+`
+
+type CountFilesForReprocessingParams struct {
+	NoteID        uuid.NullUUID
+	UserID        uuid.NullUUID
+	Extensions    []string
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	Unprocessed   bool
+}
+
+func (q *Queries) CountFilesForReprocessing(ctx context.Context, arg CountFilesForReprocessingParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFilesForReprocessing,
+		arg.NoteID,
+		arg.UserID,
+		pq.Array(arg.Extensions),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Unprocessed,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAccessToken = `-- name: CreateAccessToken :one
 INSERT INTO access_tokens (
   user_id, refresh_token_id
@@ -47,7 +220,7 @@ INSERT INTO files (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING id, processed_file, original_file, note_id, create_time, update_time, delete_time
+RETURNING id, processed_file, original_file, note_id, create_time, update_time, delete_time, change_seq, change_xid, processing_status, duration, sample_rate, waveform
 `
 
 type CreateFileParams struct {
@@ -73,17 +246,74 @@ func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, e
 		&i.CreateTime,
 		&i.UpdateTime,
 		&i.DeleteTime,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.ProcessingStatus,
+		&i.Duration,
+		&i.SampleRate,
+		pq.Array(&i.Waveform),
 	)
 	return i, err
 }
 
+const createFileRenditions = `-- name: CreateFileRenditions :many
+INSERT INTO file_renditions (file_id, size, object_name, width, height)
+SELECT $1, unnest($2::integer[]), unnest($3::varchar[]), unnest($4::integer[]), unnest($5::integer[])
+RETURNING id, file_id, size, object_name, width, height, create_time
+`
+
+type CreateFileRenditionsParams struct {
+	FileID      uuid.UUID
+	Sizes       []int32
+	ObjectNames []string
+	Widths      []int32
+	Heights     []int32
+}
+
+func (q *Queries) CreateFileRenditions(ctx context.Context, arg CreateFileRenditionsParams) ([]FileRendition, error) {
+	rows, err := q.db.QueryContext(ctx, createFileRenditions,
+		arg.FileID,
+		pq.Array(arg.Sizes),
+		pq.Array(arg.ObjectNames),
+		pq.Array(arg.Widths),
+		pq.Array(arg.Heights),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FileRendition
+	for rows.Next() {
+		var i FileRendition
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileID,
+			&i.Size,
+			&i.ObjectName,
+			&i.Width,
+			&i.Height,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createNote = `-- name: CreateNote :one
 INSERT INTO notes (
-  user_id, title, content, create_time, update_time
+  user_id, title, content, create_time, update_time, notebook_id
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id, user_id, title, content, create_time, update_time, delete_time
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
 `
 
 type CreateNoteParams struct {
@@ -92,6 +322,7 @@ type CreateNoteParams struct {
 	Content    sql.NullString
 	CreateTime time.Time
 	UpdateTime time.Time
+	NotebookID uuid.NullUUID
 }
 
 func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) (Note, error) {
@@ -101,153 +332,191 @@ func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) (Note, e
 		arg.Content,
 		arg.CreateTime,
 		arg.UpdateTime,
+		arg.NotebookID,
 	)
 	var i Note
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.NotebookID,
 		&i.Title,
 		&i.Content,
 		&i.CreateTime,
 		&i.UpdateTime,
 		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
-const createRefreshToken = `-- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (
-  user_id
-) VALUES (
-  $1
+const createNoteRevision = `-- name: CreateNoteRevision :exec
+INSERT INTO note_revisions (
+  note_id, user_id, title, content, create_time
 )
-RETURNING id, user_id, create_time, update_time
+SELECT notes.id, notes.user_id, notes.title, notes.content, $3
+FROM notes
+WHERE notes.id = $1 AND notes.user_id = $2
 `
 
-func (q *Queries) CreateRefreshToken(ctx context.Context, userID uuid.UUID) (RefreshToken, error) {
-	row := q.db.QueryRowContext(ctx, createRefreshToken, userID)
-	var i RefreshToken
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.CreateTime,
-		&i.UpdateTime,
-	)
-	return i, err
+type CreateNoteRevisionParams struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	CreateTime time.Time
 }
 
-const createUser = `-- name: CreateUser :one
-INSERT INTO users (
-  name, email, password
+func (q *Queries) CreateNoteRevision(ctx context.Context, arg CreateNoteRevisionParams) error {
+	_, err := q.db.ExecContext(ctx, createNoteRevision, arg.ID, arg.UserID, arg.CreateTime)
+	return err
+}
+
+const createNotebook = `-- name: CreateNotebook :one
+INSERT INTO notebooks (
+  user_id, parent_id, name, create_time, update_time
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
-RETURNING id, name, email, password, create_time, update_time
+RETURNING id, user_id, parent_id, name, create_time, update_time
 `
 
-type CreateUserParams struct {
-	Name     string
-	Email    string
-	Password string
+type CreateNotebookParams struct {
+	UserID     uuid.UUID
+	ParentID   uuid.NullUUID
+	Name       string
+	CreateTime time.Time
+	UpdateTime time.Time
 }
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Name, arg.Email, arg.Password)
-	var i User
+func (q *Queries) CreateNotebook(ctx context.Context, arg CreateNotebookParams) (Notebook, error) {
+	row := q.db.QueryRowContext(ctx, createNotebook,
+		arg.UserID,
+		arg.ParentID,
+		arg.Name,
+		arg.CreateTime,
+		arg.UpdateTime,
+	)
+	var i Notebook
 	err := row.Scan(
 		&i.ID,
+		&i.UserID,
+		&i.ParentID,
 		&i.Name,
-		&i.Email,
-		&i.Password,
 		&i.CreateTime,
 		&i.UpdateTime,
 	)
 	return i, err
 }
 
-const deleteAccessTokenByUserId = `-- name: DeleteAccessTokenByUserId :one
-DELETE FROM access_tokens WHERE user_id = $1 RETURNING id
+const createObjectDeletions = `-- name: CreateObjectDeletions :exec
+INSERT INTO object_deletions (bucket, object_name)
+SELECT $1, unnest($2::varchar[])
 `
 
-func (q *Queries) DeleteAccessTokenByUserId(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, deleteAccessTokenByUserId, userID)
-	var id uuid.UUID
-	err := row.Scan(&id)
-	return id, err
+type CreateObjectDeletionsParams struct {
+	Bucket      string
+	ObjectNames []string
 }
 
-const deleteRefreshTokenByUserId = `-- name: DeleteRefreshTokenByUserId :one
-DELETE FROM refresh_tokens WHERE user_id = $1 RETURNING id
-`
-
-func (q *Queries) DeleteRefreshTokenByUserId(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, deleteRefreshTokenByUserId, userID)
-	var id uuid.UUID
-	err := row.Scan(&id)
-	return id, err
+func (q *Queries) CreateObjectDeletions(ctx context.Context, arg CreateObjectDeletionsParams) error {
+	_, err := q.db.ExecContext(ctx, createObjectDeletions, arg.Bucket, pq.Array(arg.ObjectNames))
+	return err
 }
 
-const getAccessTokenById = `-- name: GetAccessTokenById :one
-SELECT id, user_id, refresh_token_id, create_time, update_time FROM access_tokens
-WHERE id = $1 LIMIT 1
+const createRefreshToken = `-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (
+  user_id, family_id, device_name, user_agent, ip_address
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, user_id, family_id, device_name, user_agent, ip_address, create_time, update_time, rotate_time
 `
 
-func (q *Queries) GetAccessTokenById(ctx context.Context, id uuid.UUID) (AccessToken, error) {
-	row := q.db.QueryRowContext(ctx, getAccessTokenById, id)
-	var i AccessToken
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.RefreshTokenID,
-		&i.CreateTime,
-		&i.UpdateTime,
-	)
-	return i, err
+type CreateRefreshTokenParams struct {
+	UserID     uuid.UUID
+	FamilyID   uuid.UUID
+	DeviceName sql.NullString
+	UserAgent  sql.NullString
+	IpAddress  sql.NullString
 }
 
-const getRefreshTokenById = `-- name: GetRefreshTokenById :one
-SELECT id, user_id, create_time, update_time FROM refresh_tokens
-WHERE id = $1 LIMIT 1
-`
-
-func (q *Queries) GetRefreshTokenById(ctx context.Context, id uuid.UUID) (RefreshToken, error) {
-	row := q.db.QueryRowContext(ctx, getRefreshTokenById, id)
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, createRefreshToken,
+		arg.UserID,
+		arg.FamilyID,
+		arg.DeviceName,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
 	var i RefreshToken
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.FamilyID,
+		&i.DeviceName,
+		&i.UserAgent,
+		&i.IpAddress,
 		&i.CreateTime,
 		&i.UpdateTime,
+		&i.RotateTime,
 	)
 	return i, err
 }
 
-const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password, create_time, update_time FROM users
-WHERE email = $1 LIMIT 1
+const createTag = `-- name: CreateTag :one
+INSERT INTO tags (
+  user_id, name, create_time, update_time
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, user_id, name, create_time, update_time
 `
 
-func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
-	var i User
+type CreateTagParams struct {
+	UserID     uuid.UUID
+	Name       string
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, createTag,
+		arg.UserID,
+		arg.Name,
+		arg.CreateTime,
+		arg.UpdateTime,
+	)
+	var i Tag
 	err := row.Scan(
 		&i.ID,
+		&i.UserID,
 		&i.Name,
-		&i.Email,
-		&i.Password,
 		&i.CreateTime,
 		&i.UpdateTime,
 	)
 	return i, err
 }
 
-const getUserById = `-- name: GetUserById :one
-SELECT id, name, email, password, create_time, update_time FROM users
-WHERE id = $1 LIMIT 1
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (
+  name, email, password
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, name, email, password, create_time, update_time
 `
 
-func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserById, id)
+type CreateUserParams struct {
+	Name     string
+	Email    string
+	Password string
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser, arg.Name, arg.Email, arg.Password)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -260,31 +529,25 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 	return i, err
 }
 
-const hardDeleteFilesByNoteId = `-- name: HardDeleteFilesByNoteId :many
-DELETE FROM files WHERE note_id = $1 RETURNING id, processed_file, original_file, note_id, create_time, update_time, delete_time
+const deleteAccessTokensByRefreshTokenFamilyId = `-- name: DeleteAccessTokensByRefreshTokenFamilyId :many
+DELETE FROM access_tokens USING refresh_tokens
+WHERE access_tokens.refresh_token_id = refresh_tokens.id AND refresh_tokens.family_id = $1
+RETURNING access_tokens.id
 `
 
-func (q *Queries) HardDeleteFilesByNoteId(ctx context.Context, noteID uuid.UUID) ([]File, error) {
-	rows, err := q.db.QueryContext(ctx, hardDeleteFilesByNoteId, noteID)
+func (q *Queries) DeleteAccessTokensByRefreshTokenFamilyId(ctx context.Context, familyID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, deleteAccessTokensByRefreshTokenFamilyId, familyID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []File
+	var items []uuid.UUID
 	for rows.Next() {
-		var i File
-		if err := rows.Scan(
-			&i.ID,
-			&i.ProcessedFile,
-			&i.OriginalFile,
-			&i.NoteID,
-			&i.CreateTime,
-			&i.UpdateTime,
-			&i.DeleteTime,
-		); err != nil {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -295,47 +558,55 @@ func (q *Queries) HardDeleteFilesByNoteId(ctx context.Context, noteID uuid.UUID)
 	return items, nil
 }
 
-const hardDeleteNoteById = `-- name: HardDeleteNoteById :one
-DELETE FROM notes WHERE id = $1 RETURNING id, user_id, title, content, create_time, update_time, delete_time
+const deleteAccessTokensByRefreshTokenId = `-- name: DeleteAccessTokensByRefreshTokenId :many
+DELETE FROM access_tokens WHERE refresh_token_id = $1 RETURNING id
 `
 
-func (q *Queries) HardDeleteNoteById(ctx context.Context, id uuid.UUID) (Note, error) {
-	row := q.db.QueryRowContext(ctx, hardDeleteNoteById, id)
-	var i Note
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Title,
-		&i.Content,
-		&i.CreateTime,
-		&i.UpdateTime,
-		&i.DeleteTime,
-	)
-	return i, err
+func (q *Queries) DeleteAccessTokensByRefreshTokenId(ctx context.Context, refreshTokenID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, deleteAccessTokensByRefreshTokenId, refreshTokenID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const listFileByNoteId = `-- name: ListFileByNoteId :many
-SELECT id, processed_file, original_file, note_id, create_time, update_time, delete_time FROM files 
-WHERE note_id = $1
+const deleteFileRenditionsByFileId = `-- name: DeleteFileRenditionsByFileId :many
+DELETE FROM file_renditions
+WHERE file_id = $1 RETURNING id, file_id, size, object_name, width, height, create_time
 `
 
-func (q *Queries) ListFileByNoteId(ctx context.Context, noteID uuid.UUID) ([]File, error) {
-	rows, err := q.db.QueryContext(ctx, listFileByNoteId, noteID)
+func (q *Queries) DeleteFileRenditionsByFileId(ctx context.Context, fileID uuid.UUID) ([]FileRendition, error) {
+	rows, err := q.db.QueryContext(ctx, deleteFileRenditionsByFileId, fileID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []File
+	var items []FileRendition
 	for rows.Next() {
-		var i File
+		var i FileRendition
 		if err := rows.Scan(
 			&i.ID,
-			&i.ProcessedFile,
-			&i.OriginalFile,
-			&i.NoteID,
+			&i.FileID,
+			&i.Size,
+			&i.ObjectName,
+			&i.Width,
+			&i.Height,
 			&i.CreateTime,
-			&i.UpdateTime,
-			&i.DeleteTime,
 		); err != nil {
 			return nil, err
 		}
@@ -350,9 +621,769 @@ func (q *Queries) ListFileByNoteId(ctx context.Context, noteID uuid.UUID) ([]Fil
 	return items, nil
 }
 
-const listFilesByNotesIds = `-- name: ListFilesByNotesIds :many
-SELECT id, processed_file, original_file, note_id, create_time, update_time, delete_time FROM files 
-WHERE note_id = ANY($1::uuid[])
+const deleteNotebookById = `-- name: DeleteNotebookById :one
+DELETE FROM notebooks WHERE id = $1 AND user_id = $2 RETURNING id, user_id, parent_id, name, create_time, update_time
+`
+
+type DeleteNotebookByIdParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteNotebookById(ctx context.Context, arg DeleteNotebookByIdParams) (Notebook, error) {
+	row := q.db.QueryRowContext(ctx, deleteNotebookById, arg.ID, arg.UserID)
+	var i Notebook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const deleteObjectDeletionsByIds = `-- name: DeleteObjectDeletionsByIds :exec
+DELETE FROM object_deletions WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) DeleteObjectDeletionsByIds(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteObjectDeletionsByIds, pq.Array(ids))
+	return err
+}
+
+const deleteRefreshTokensByFamilyId = `-- name: DeleteRefreshTokensByFamilyId :many
+DELETE FROM refresh_tokens WHERE family_id = $1 RETURNING id
+`
+
+func (q *Queries) DeleteRefreshTokensByFamilyId(ctx context.Context, familyID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, deleteRefreshTokensByFamilyId, familyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteTagById = `-- name: DeleteTagById :one
+DELETE FROM tags WHERE id = $1 AND user_id = $2 RETURNING id, user_id, name, create_time, update_time
+`
+
+type DeleteTagByIdParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteTagById(ctx context.Context, arg DeleteTagByIdParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, deleteTagById, arg.ID, arg.UserID)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const detachTagsFromNote = `-- name: DetachTagsFromNote :exec
+DELETE FROM note_tags
+WHERE note_id = $1 AND user_id = $2 AND tag_id = ANY($3::uuid[])
+`
+
+type DetachTagsFromNoteParams struct {
+	NoteID  uuid.UUID
+	UserID  uuid.UUID
+	Column3 []uuid.UUID
+}
+
+func (q *Queries) DetachTagsFromNote(ctx context.Context, arg DetachTagsFromNoteParams) error {
+	_, err := q.db.ExecContext(ctx, detachTagsFromNote, arg.NoteID, arg.UserID, pq.Array(arg.Column3))
+	return err
+}
+
+const enqueueFileJobs = `-- name: EnqueueFileJobs :exec
+INSERT INTO file_jobs (file_id)
+SELECT unnest($1::uuid[])
+`
+
+func (q *Queries) EnqueueFileJobs(ctx context.Context, fileIds []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, enqueueFileJobs, pq.Array(fileIds))
+	return err
+}
+
+const failFileJob = `-- name: FailFileJob :execrows
+UPDATE file_jobs SET
+  status = $1,
+  last_error = $2,
+  run_time = $3,
+  lease_owner = NULL,
+  lease_expire_time = NULL,
+  update_time = $4
+WHERE id = $5 AND status = 'running' AND lease_owner = $6::varchar
+`
+
+type FailFileJobParams struct {
+	Status    string
+	LastError sql.NullString
+	RunTime   time.Time
+	Now       time.Time
+	ID        uuid.UUID
+	Worker    string
+}
+
+func (q *Queries) FailFileJob(ctx context.Context, arg FailFileJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, failFileJob,
+		arg.Status,
+		arg.LastError,
+		arg.RunTime,
+		arg.Now,
+		arg.ID,
+		arg.Worker,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const failObjectDeletionById = `-- name: FailObjectDeletionById :exec
+UPDATE object_deletions SET
+  attempts = attempts + 1,
+  last_error = $1,
+  next_attempt_time = $2,
+  dead_time = $3
+WHERE id = $4
+`
+
+type FailObjectDeletionByIdParams struct {
+	LastError       sql.NullString
+	NextAttemptTime time.Time
+	DeadTime        sql.NullTime
+	ID              uuid.UUID
+}
+
+func (q *Queries) FailObjectDeletionById(ctx context.Context, arg FailObjectDeletionByIdParams) error {
+	_, err := q.db.ExecContext(ctx, failObjectDeletionById,
+		arg.LastError,
+		arg.NextAttemptTime,
+		arg.DeadTime,
+		arg.ID,
+	)
+	return err
+}
+
+const getAccessTokenById = `-- name: GetAccessTokenById :one
+SELECT id, user_id, refresh_token_id, create_time, update_time FROM access_tokens
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccessTokenById(ctx context.Context, id uuid.UUID) (AccessToken, error) {
+	row := q.db.QueryRowContext(ctx, getAccessTokenById, id)
+	var i AccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RefreshTokenID,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const getNoteById = `-- name: GetNoteById :one
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetNoteById(ctx context.Context, id uuid.UUID) (Note, error) {
+	row := q.db.QueryRowContext(ctx, getNoteById, id)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.NotebookID,
+		&i.Title,
+		&i.Content,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const getNoteRevisionById = `-- name: GetNoteRevisionById :one
+SELECT id, note_id, user_id, title, content, create_time FROM note_revisions
+WHERE id = $1 AND note_id = $2 AND user_id = $3 LIMIT 1
+`
+
+type GetNoteRevisionByIdParams struct {
+	ID     uuid.UUID
+	NoteID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetNoteRevisionById(ctx context.Context, arg GetNoteRevisionByIdParams) (NoteRevision, error) {
+	row := q.db.QueryRowContext(ctx, getNoteRevisionById, arg.ID, arg.NoteID, arg.UserID)
+	var i NoteRevision
+	err := row.Scan(
+		&i.ID,
+		&i.NoteID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const getNotebookById = `-- name: GetNotebookById :one
+SELECT id, user_id, parent_id, name, create_time, update_time FROM notebooks
+WHERE id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetNotebookByIdParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetNotebookById(ctx context.Context, arg GetNotebookByIdParams) (Notebook, error) {
+	row := q.db.QueryRowContext(ctx, getNotebookById, arg.ID, arg.UserID)
+	var i Notebook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const getRefreshTokenById = `-- name: GetRefreshTokenById :one
+SELECT id, user_id, family_id, device_name, user_agent, ip_address, create_time, update_time, rotate_time FROM refresh_tokens
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetRefreshTokenById(ctx context.Context, id uuid.UUID) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshTokenById, id)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.DeviceName,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.RotateTime,
+	)
+	return i, err
+}
+
+const getSyncHorizon = `-- name: GetSyncHorizon :one
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS horizon
+`
+
+// The oldest transaction still in progress, the changes of the older transactions can't change their place anymore
+func (q *Queries) GetSyncHorizon(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getSyncHorizon)
+	var horizon int64
+	err := row.Scan(&horizon)
+	return horizon, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, email, password, create_time, update_time FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const getUserById = `-- name: GetUserById :one
+SELECT id, name, email, password, create_time, update_time FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserById, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const hardDeleteFilesByNoteId = `-- name: HardDeleteFilesByNoteId :many
+DELETE FROM files WHERE note_id = $1 RETURNING id, processed_file, original_file, note_id, create_time, update_time, delete_time, change_seq, change_xid, processing_status, duration, sample_rate, waveform
+`
+
+func (q *Queries) HardDeleteFilesByNoteId(ctx context.Context, noteID uuid.UUID) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, hardDeleteFilesByNoteId, noteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProcessedFile,
+			&i.OriginalFile,
+			&i.NoteID,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.ProcessingStatus,
+			&i.Duration,
+			&i.SampleRate,
+			pq.Array(&i.Waveform),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hardDeleteNoteById = `-- name: HardDeleteNoteById :one
+DELETE FROM notes WHERE id = $1 AND user_id = $2 RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type HardDeleteNoteByIdParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) HardDeleteNoteById(ctx context.Context, arg HardDeleteNoteByIdParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, hardDeleteNoteById, arg.ID, arg.UserID)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.NotebookID,
+		&i.Title,
+		&i.Content,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const hardDeleteNotesByIds = `-- name: HardDeleteNotesByIds :many
+DELETE FROM notes WHERE id = ANY($1::uuid[]) AND user_id = $2 RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type HardDeleteNotesByIdsParams struct {
+	Ids    []uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) HardDeleteNotesByIds(ctx context.Context, arg HardDeleteNotesByIdsParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, hardDeleteNotesByIds, pq.Array(arg.Ids), arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hardDeleteTrashNotesByIds = `-- name: HardDeleteTrashNotesByIds :many
+DELETE FROM notes WHERE id = ANY($1::uuid[]) AND delete_time IS NOT NULL RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+func (q *Queries) HardDeleteTrashNotesByIds(ctx context.Context, ids []uuid.UUID) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, hardDeleteTrashNotesByIds, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArchivedNotesByUserId = `-- name: ListArchivedNotesByUserId :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NULL AND notes.archive_time IS NOT NULL
+  AND (NOT $2::boolean OR (notes.archive_time, notes.id) < ($3::timestamp, $4::uuid))
+  AND ($5::timestamp IS NULL OR notes.create_time >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR notes.create_time < $6::timestamp)
+  AND ($7::timestamp IS NULL OR notes.update_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.update_time < $8::timestamp)
+  AND ($9::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $9::uuid
+  ))
+  AND ($10::uuid IS NULL OR notes.notebook_id = $10::uuid)
+ORDER BY notes.archive_time DESC, notes.id DESC
+LIMIT $11
+`
+
+type ListArchivedNotesByUserIdParams struct {
+	UserID        uuid.UUID
+	HasCursor     bool
+	CursorTime    time.Time
+	CursorID      uuid.UUID
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	UpdatedAfter  sql.NullTime
+	UpdatedBefore sql.NullTime
+	TagID         uuid.NullUUID
+	NotebookID    uuid.NullUUID
+	PageLimit     int32
+}
+
+func (q *Queries) ListArchivedNotesByUserId(ctx context.Context, arg ListArchivedNotesByUserIdParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listArchivedNotesByUserId,
+		arg.UserID,
+		arg.HasCursor,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueObjectDeletions = `-- name: ListDueObjectDeletions :many
+SELECT id, bucket, object_name, attempts, last_error, next_attempt_time, dead_time, create_time FROM object_deletions
+WHERE dead_time IS NULL AND next_attempt_time <= $1::timestamp
+ORDER BY next_attempt_time, id
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type ListDueObjectDeletionsParams struct {
+	Now       time.Time
+	PageLimit int32
+}
+
+func (q *Queries) ListDueObjectDeletions(ctx context.Context, arg ListDueObjectDeletionsParams) ([]ObjectDeletion, error) {
+	rows, err := q.db.QueryContext(ctx, listDueObjectDeletions, arg.Now, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ObjectDeletion
+	for rows.Next() {
+		var i ObjectDeletion
+		if err := rows.Scan(
+			&i.ID,
+			&i.Bucket,
+			&i.ObjectName,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptTime,
+			&i.DeadTime,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiredTrashNotes = `-- name: ListExpiredTrashNotes :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.delete_time IS NOT NULL AND notes.delete_time < $1::timestamp
+  AND (NOT $2::boolean OR (notes.delete_time, notes.id) > ($3::timestamp, $4::uuid))
+ORDER BY notes.delete_time, notes.id
+LIMIT $5
+FOR UPDATE SKIP LOCKED
+`
+
+type ListExpiredTrashNotesParams struct {
+	Before     time.Time
+	HasCursor  bool
+	CursorTime time.Time
+	CursorID   uuid.UUID
+	PageLimit  int32
+}
+
+func (q *Queries) ListExpiredTrashNotes(ctx context.Context, arg ListExpiredTrashNotesParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredTrashNotes,
+		arg.Before,
+		arg.HasCursor,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFileByNoteId = `-- name: ListFileByNoteId :many
+SELECT id, processed_file, original_file, note_id, create_time, update_time, delete_time, change_seq, change_xid, processing_status, duration, sample_rate, waveform FROM files 
+WHERE note_id = $1
+`
+
+func (q *Queries) ListFileByNoteId(ctx context.Context, noteID uuid.UUID) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, listFileByNoteId, noteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProcessedFile,
+			&i.OriginalFile,
+			&i.NoteID,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.ProcessingStatus,
+			&i.Duration,
+			&i.SampleRate,
+			pq.Array(&i.Waveform),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFileRenditionsByFileIds = `-- name: ListFileRenditionsByFileIds :many
+SELECT id, file_id, size, object_name, width, height, create_time FROM file_renditions
+WHERE file_id = ANY($1::uuid[])
+ORDER BY file_id, size
+`
+
+func (q *Queries) ListFileRenditionsByFileIds(ctx context.Context, fileIds []uuid.UUID) ([]FileRendition, error) {
+	rows, err := q.db.QueryContext(ctx, listFileRenditionsByFileIds, pq.Array(fileIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FileRendition
+	for rows.Next() {
+		var i FileRendition
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileID,
+			&i.Size,
+			&i.ObjectName,
+			&i.Width,
+			&i.Height,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFilesByNotesIds = `-- name: ListFilesByNotesIds :many
+SELECT id, processed_file, original_file, note_id, create_time, update_time, delete_time, change_seq, change_xid, processing_status, duration, sample_rate, waveform FROM files 
+WHERE note_id = ANY($1::uuid[])
 `
 
 func (q *Queries) ListFilesByNotesIds(ctx context.Context, dollar_1 []uuid.UUID) ([]File, error) {
@@ -361,17 +1392,1438 @@ func (q *Queries) ListFilesByNotesIds(ctx context.Context, dollar_1 []uuid.UUID)
 		return nil, err
 	}
 	defer rows.Close()
-	var items []File
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProcessedFile,
+			&i.OriginalFile,
+			&i.NoteID,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.ProcessingStatus,
+			&i.Duration,
+			&i.SampleRate,
+			pq.Array(&i.Waveform),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFilesChangedSince = `-- name: ListFilesChangedSince :many
+SELECT files.id, files.processed_file, files.original_file, files.note_id, files.create_time, files.update_time, files.delete_time, files.change_seq, files.change_xid, files.processing_status, files.duration, files.sample_rate, files.waveform FROM files
+JOIN notes ON notes.id = files.note_id
+WHERE notes.user_id = $1
+	AND (files.change_xid, files.change_seq) > ($2::bigint, $3::bigint)
+	AND files.change_xid < $4::bigint
+ORDER BY files.change_xid, files.change_seq
+LIMIT $5
+`
+
+type ListFilesChangedSinceParams struct {
+	UserID    uuid.UUID
+	ChangeXid int64
+	ChangeSeq int64
+	Horizon   int64
+	PageLimit int32
+}
+
+func (q *Queries) ListFilesChangedSince(ctx context.Context, arg ListFilesChangedSinceParams) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, listFilesChangedSince,
+		arg.UserID,
+		arg.ChangeXid,
+		arg.ChangeSeq,
+		arg.Horizon,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProcessedFile,
+			&i.OriginalFile,
+			&i.NoteID,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.ProcessingStatus,
+			&i.Duration,
+			&i.SampleRate,
+			pq.Array(&i.Waveform),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFilesForReprocessing = `-- name: ListFilesForReprocessing :many
+SELECT files.id, files.processed_file, files.original_file, files.note_id, files.create_time, files.update_time, files.delete_time, files.change_seq, files.change_xid, files.processing_status, files.duration, files.sample_rate, files.waveform FROM files
+JOIN notes ON notes.id = files.note_id
+WHERE files.delete_time IS NULL AND files.processing_status <> 'running'
+  AND ($1::uuid IS NULL OR files.id > $1::uuid)
+  AND ($2::uuid IS NULL OR files.note_id = $2::uuid)
+  AND ($3::uuid IS NULL OR notes.user_id = $3::uuid)
+  AND ($4::varchar[] IS NULL OR lower(substring(files.original_file from '\.[^./]*$')) = ANY($4::varchar[]))
+  AND ($5::timestamp IS NULL OR files.create_time >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR files.create_time < $6::timestamp)
+  AND (NOT $7::boolean OR files.processing_status <> 'succeeded' OR files.processed_file IS NULL OR files.processed_file = '')
+ORDER BY files.id
+LIMIT $8
+`
+
+type ListFilesForReprocessingParams struct {
+	CursorID      uuid.NullUUID
+	NoteID        uuid.NullUUID
+	UserID        uuid.NullUUID
+	Extensions    []string
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	Unprocessed   bool
+	PageLimit     int32
+}
+
+func (q *Queries) ListFilesForReprocessing(ctx context.Context, arg ListFilesForReprocessingParams) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, listFilesForReprocessing,
+		arg.CursorID,
+		arg.NoteID,
+		arg.UserID,
+		pq.Array(arg.Extensions),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Unprocessed,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProcessedFile,
+			&i.OriginalFile,
+			&i.NoteID,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.ProcessingStatus,
+			&i.Duration,
+			&i.SampleRate,
+			pq.Array(&i.Waveform),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNoteRevisionsByNoteId = `-- name: ListNoteRevisionsByNoteId :many
+SELECT id, note_id, user_id, title, content, create_time FROM note_revisions
+WHERE note_id = $1 AND user_id = $2
+ORDER BY create_time DESC, id DESC
+`
+
+type ListNoteRevisionsByNoteIdParams struct {
+	NoteID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) ListNoteRevisionsByNoteId(ctx context.Context, arg ListNoteRevisionsByNoteIdParams) ([]NoteRevision, error) {
+	rows, err := q.db.QueryContext(ctx, listNoteRevisionsByNoteId, arg.NoteID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NoteRevision
+	for rows.Next() {
+		var i NoteRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.NoteID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotebookTreeIds = `-- name: ListNotebookTreeIds :many
+WITH RECURSIVE tree AS (
+  SELECT notebooks.id FROM notebooks WHERE notebooks.id = $1 AND notebooks.user_id = $2
+  UNION
+  SELECT children.id FROM notebooks children JOIN tree ON children.parent_id = tree.id
+)
+SELECT tree.id FROM tree
+`
+
+type ListNotebookTreeIdsParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) ListNotebookTreeIds(ctx context.Context, arg ListNotebookTreeIdsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listNotebookTreeIds, arg.ID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotebooksByUserId = `-- name: ListNotebooksByUserId :many
+SELECT id, user_id, parent_id, name, create_time, update_time FROM notebooks
+WHERE user_id = $1
+ORDER BY name
+`
+
+func (q *Queries) ListNotebooksByUserId(ctx context.Context, userID uuid.UUID) ([]Notebook, error) {
+	rows, err := q.db.QueryContext(ctx, listNotebooksByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notebook
+	for rows.Next() {
+		var i Notebook
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ParentID,
+			&i.Name,
+			&i.CreateTime,
+			&i.UpdateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotesByUserIdCreateTimeAsc = `-- name: ListNotesByUserIdCreateTimeAsc :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NULL
+  AND ($2::boolean OR notes.archive_time IS NULL)
+  AND (NOT $3::boolean OR (notes.pinned_time IS NULL, notes.create_time, notes.id) > (NOT $4::boolean, $5::timestamp, $6::uuid))
+  AND ($7::timestamp IS NULL OR notes.create_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.create_time < $8::timestamp)
+  AND ($9::timestamp IS NULL OR notes.update_time >= $9::timestamp)
+  AND ($10::timestamp IS NULL OR notes.update_time < $10::timestamp)
+  AND ($11::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $11::uuid
+  ))
+  AND ($12::uuid IS NULL OR notes.notebook_id = $12::uuid)
+ORDER BY notes.pinned_time IS NULL, notes.create_time, notes.id
+LIMIT $13
+`
+
+type ListNotesByUserIdCreateTimeAscParams struct {
+	UserID          uuid.UUID
+	IncludeArchived bool
+	HasCursor       bool
+	CursorPinned    bool
+	CursorTime      time.Time
+	CursorID        uuid.UUID
+	CreatedAfter    sql.NullTime
+	CreatedBefore   sql.NullTime
+	UpdatedAfter    sql.NullTime
+	UpdatedBefore   sql.NullTime
+	TagID           uuid.NullUUID
+	NotebookID      uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) ListNotesByUserIdCreateTimeAsc(ctx context.Context, arg ListNotesByUserIdCreateTimeAscParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNotesByUserIdCreateTimeAsc,
+		arg.UserID,
+		arg.IncludeArchived,
+		arg.HasCursor,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotesByUserIdCreateTimeDesc = `-- name: ListNotesByUserIdCreateTimeDesc :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NULL
+  AND ($2::boolean OR notes.archive_time IS NULL)
+  AND (NOT $3::boolean OR (notes.pinned_time IS NOT NULL, notes.create_time, notes.id) < ($4::boolean, $5::timestamp, $6::uuid))
+  AND ($7::timestamp IS NULL OR notes.create_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.create_time < $8::timestamp)
+  AND ($9::timestamp IS NULL OR notes.update_time >= $9::timestamp)
+  AND ($10::timestamp IS NULL OR notes.update_time < $10::timestamp)
+  AND ($11::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $11::uuid
+  ))
+  AND ($12::uuid IS NULL OR notes.notebook_id = $12::uuid)
+ORDER BY notes.pinned_time IS NOT NULL DESC, notes.create_time DESC, notes.id DESC
+LIMIT $13
+`
+
+type ListNotesByUserIdCreateTimeDescParams struct {
+	UserID          uuid.UUID
+	IncludeArchived bool
+	HasCursor       bool
+	CursorPinned    bool
+	CursorTime      time.Time
+	CursorID        uuid.UUID
+	CreatedAfter    sql.NullTime
+	CreatedBefore   sql.NullTime
+	UpdatedAfter    sql.NullTime
+	UpdatedBefore   sql.NullTime
+	TagID           uuid.NullUUID
+	NotebookID      uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) ListNotesByUserIdCreateTimeDesc(ctx context.Context, arg ListNotesByUserIdCreateTimeDescParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNotesByUserIdCreateTimeDesc,
+		arg.UserID,
+		arg.IncludeArchived,
+		arg.HasCursor,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotesByUserIdTitleAsc = `-- name: ListNotesByUserIdTitleAsc :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NULL
+  AND ($2::boolean OR notes.archive_time IS NULL)
+  AND (NOT $3::boolean OR (notes.pinned_time IS NULL, COALESCE(notes.title, ''), notes.id) > (NOT $4::boolean, $5::text, $6::uuid))
+  AND ($7::timestamp IS NULL OR notes.create_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.create_time < $8::timestamp)
+  AND ($9::timestamp IS NULL OR notes.update_time >= $9::timestamp)
+  AND ($10::timestamp IS NULL OR notes.update_time < $10::timestamp)
+  AND ($11::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $11::uuid
+  ))
+  AND ($12::uuid IS NULL OR notes.notebook_id = $12::uuid)
+ORDER BY notes.pinned_time IS NULL, COALESCE(notes.title, ''), notes.id
+LIMIT $13
+`
+
+type ListNotesByUserIdTitleAscParams struct {
+	UserID          uuid.UUID
+	IncludeArchived bool
+	HasCursor       bool
+	CursorPinned    bool
+	CursorTitle     string
+	CursorID        uuid.UUID
+	CreatedAfter    sql.NullTime
+	CreatedBefore   sql.NullTime
+	UpdatedAfter    sql.NullTime
+	UpdatedBefore   sql.NullTime
+	TagID           uuid.NullUUID
+	NotebookID      uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) ListNotesByUserIdTitleAsc(ctx context.Context, arg ListNotesByUserIdTitleAscParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNotesByUserIdTitleAsc,
+		arg.UserID,
+		arg.IncludeArchived,
+		arg.HasCursor,
+		arg.CursorPinned,
+		arg.CursorTitle,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotesByUserIdTitleDesc = `-- name: ListNotesByUserIdTitleDesc :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NULL
+  AND ($2::boolean OR notes.archive_time IS NULL)
+  AND (NOT $3::boolean OR (notes.pinned_time IS NOT NULL, COALESCE(notes.title, ''), notes.id) < ($4::boolean, $5::text, $6::uuid))
+  AND ($7::timestamp IS NULL OR notes.create_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.create_time < $8::timestamp)
+  AND ($9::timestamp IS NULL OR notes.update_time >= $9::timestamp)
+  AND ($10::timestamp IS NULL OR notes.update_time < $10::timestamp)
+  AND ($11::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $11::uuid
+  ))
+  AND ($12::uuid IS NULL OR notes.notebook_id = $12::uuid)
+ORDER BY notes.pinned_time IS NOT NULL DESC, COALESCE(notes.title, '') DESC, notes.id DESC
+LIMIT $13
+`
+
+type ListNotesByUserIdTitleDescParams struct {
+	UserID          uuid.UUID
+	IncludeArchived bool
+	HasCursor       bool
+	CursorPinned    bool
+	CursorTitle     string
+	CursorID        uuid.UUID
+	CreatedAfter    sql.NullTime
+	CreatedBefore   sql.NullTime
+	UpdatedAfter    sql.NullTime
+	UpdatedBefore   sql.NullTime
+	TagID           uuid.NullUUID
+	NotebookID      uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) ListNotesByUserIdTitleDesc(ctx context.Context, arg ListNotesByUserIdTitleDescParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNotesByUserIdTitleDesc,
+		arg.UserID,
+		arg.IncludeArchived,
+		arg.HasCursor,
+		arg.CursorPinned,
+		arg.CursorTitle,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotesByUserIdUpdateTimeAsc = `-- name: ListNotesByUserIdUpdateTimeAsc :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NULL
+  AND ($2::boolean OR notes.archive_time IS NULL)
+  AND (NOT $3::boolean OR (notes.pinned_time IS NULL, notes.update_time, notes.id) > (NOT $4::boolean, $5::timestamp, $6::uuid))
+  AND ($7::timestamp IS NULL OR notes.create_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.create_time < $8::timestamp)
+  AND ($9::timestamp IS NULL OR notes.update_time >= $9::timestamp)
+  AND ($10::timestamp IS NULL OR notes.update_time < $10::timestamp)
+  AND ($11::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $11::uuid
+  ))
+  AND ($12::uuid IS NULL OR notes.notebook_id = $12::uuid)
+ORDER BY notes.pinned_time IS NULL, notes.update_time, notes.id
+LIMIT $13
+`
+
+type ListNotesByUserIdUpdateTimeAscParams struct {
+	UserID          uuid.UUID
+	IncludeArchived bool
+	HasCursor       bool
+	CursorPinned    bool
+	CursorTime      time.Time
+	CursorID        uuid.UUID
+	CreatedAfter    sql.NullTime
+	CreatedBefore   sql.NullTime
+	UpdatedAfter    sql.NullTime
+	UpdatedBefore   sql.NullTime
+	TagID           uuid.NullUUID
+	NotebookID      uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) ListNotesByUserIdUpdateTimeAsc(ctx context.Context, arg ListNotesByUserIdUpdateTimeAscParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNotesByUserIdUpdateTimeAsc,
+		arg.UserID,
+		arg.IncludeArchived,
+		arg.HasCursor,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotesByUserIdUpdateTimeDesc = `-- name: ListNotesByUserIdUpdateTimeDesc :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NULL
+  AND ($2::boolean OR notes.archive_time IS NULL)
+  AND (NOT $3::boolean OR (notes.pinned_time IS NOT NULL, notes.update_time, notes.id) < ($4::boolean, $5::timestamp, $6::uuid))
+  AND ($7::timestamp IS NULL OR notes.create_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.create_time < $8::timestamp)
+  AND ($9::timestamp IS NULL OR notes.update_time >= $9::timestamp)
+  AND ($10::timestamp IS NULL OR notes.update_time < $10::timestamp)
+  AND ($11::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $11::uuid
+  ))
+  AND ($12::uuid IS NULL OR notes.notebook_id = $12::uuid)
+ORDER BY notes.pinned_time IS NOT NULL DESC, notes.update_time DESC, notes.id DESC
+LIMIT $13
+`
+
+type ListNotesByUserIdUpdateTimeDescParams struct {
+	UserID          uuid.UUID
+	IncludeArchived bool
+	HasCursor       bool
+	CursorPinned    bool
+	CursorTime      time.Time
+	CursorID        uuid.UUID
+	CreatedAfter    sql.NullTime
+	CreatedBefore   sql.NullTime
+	UpdatedAfter    sql.NullTime
+	UpdatedBefore   sql.NullTime
+	TagID           uuid.NullUUID
+	NotebookID      uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) ListNotesByUserIdUpdateTimeDesc(ctx context.Context, arg ListNotesByUserIdUpdateTimeDescParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNotesByUserIdUpdateTimeDesc,
+		arg.UserID,
+		arg.IncludeArchived,
+		arg.HasCursor,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotesChangedSince = `-- name: ListNotesChangedSince :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE user_id = $1
+	AND (change_xid, change_seq) > ($2::bigint, $3::bigint)
+	AND change_xid < $4::bigint
+ORDER BY change_xid, change_seq
+LIMIT $5
+`
+
+type ListNotesChangedSinceParams struct {
+	UserID    uuid.UUID
+	ChangeXid int64
+	ChangeSeq int64
+	Horizon   int64
+	PageLimit int32
+}
+
+func (q *Queries) ListNotesChangedSince(ctx context.Context, arg ListNotesChangedSinceParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNotesChangedSince,
+		arg.UserID,
+		arg.ChangeXid,
+		arg.ChangeSeq,
+		arg.Horizon,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSessionsByUserId = `-- name: ListSessionsByUserId :many
+SELECT
+  refresh_tokens.id, refresh_tokens.user_id, refresh_tokens.family_id, refresh_tokens.device_name, refresh_tokens.user_agent, refresh_tokens.ip_address, refresh_tokens.create_time, refresh_tokens.update_time, refresh_tokens.rotate_time,
+  (SELECT MIN(family.create_time) FROM refresh_tokens family WHERE family.family_id = refresh_tokens.family_id)::timestamp AS sign_in_time
+FROM refresh_tokens
+WHERE refresh_tokens.user_id = $1 AND refresh_tokens.rotate_time IS NULL AND refresh_tokens.create_time > $2
+ORDER BY refresh_tokens.create_time DESC
+`
+
+type ListSessionsByUserIdParams struct {
+	UserID     uuid.UUID
+	CreateTime time.Time
+}
+
+type ListSessionsByUserIdRow struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	FamilyID   uuid.UUID
+	DeviceName sql.NullString
+	UserAgent  sql.NullString
+	IpAddress  sql.NullString
+	CreateTime time.Time
+	UpdateTime sql.NullTime
+	RotateTime sql.NullTime
+	SignInTime time.Time
+}
+
+func (q *Queries) ListSessionsByUserId(ctx context.Context, arg ListSessionsByUserIdParams) ([]ListSessionsByUserIdRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionsByUserId, arg.UserID, arg.CreateTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSessionsByUserIdRow
+	for rows.Next() {
+		var i ListSessionsByUserIdRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.FamilyID,
+			&i.DeviceName,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.RotateTime,
+			&i.SignInTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsByIds = `-- name: ListTagsByIds :many
+SELECT id, user_id, name, create_time, update_time FROM tags
+WHERE user_id = $1 AND id = ANY($2::uuid[])
+`
+
+type ListTagsByIdsParams struct {
+	UserID  uuid.UUID
+	Column2 []uuid.UUID
+}
+
+func (q *Queries) ListTagsByIds(ctx context.Context, arg ListTagsByIdsParams) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, listTagsByIds, arg.UserID, pq.Array(arg.Column2))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreateTime,
+			&i.UpdateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsByNoteId = `-- name: ListTagsByNoteId :many
+SELECT tags.id, tags.user_id, tags.name, tags.create_time, tags.update_time FROM tags
+JOIN note_tags ON note_tags.tag_id = tags.id
+WHERE note_tags.note_id = $1
+ORDER BY tags.name
+`
+
+func (q *Queries) ListTagsByNoteId(ctx context.Context, noteID uuid.UUID) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, listTagsByNoteId, noteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreateTime,
+			&i.UpdateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsByNotesIds = `-- name: ListTagsByNotesIds :many
+SELECT note_tags.note_id, tags.id, tags.user_id, tags.name, tags.create_time, tags.update_time
+FROM note_tags
+JOIN tags ON tags.id = note_tags.tag_id
+WHERE note_tags.note_id = ANY($1::uuid[])
+ORDER BY tags.name
+`
+
+type ListTagsByNotesIdsRow struct {
+	NoteID     uuid.UUID
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+func (q *Queries) ListTagsByNotesIds(ctx context.Context, dollar_1 []uuid.UUID) ([]ListTagsByNotesIdsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTagsByNotesIds, pq.Array(dollar_1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagsByNotesIdsRow
+	for rows.Next() {
+		var i ListTagsByNotesIdsRow
+		if err := rows.Scan(
+			&i.NoteID,
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreateTime,
+			&i.UpdateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsByUserId = `-- name: ListTagsByUserId :many
+SELECT id, user_id, name, create_time, update_time FROM tags
+WHERE user_id = $1
+ORDER BY name
+`
+
+func (q *Queries) ListTagsByUserId(ctx context.Context, userID uuid.UUID) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, listTagsByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreateTime,
+			&i.UpdateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTombstonesSince = `-- name: ListTombstonesSince :many
+SELECT id, user_id, entity_type, entity_id, note_id, change_seq, change_xid, delete_time FROM tombstones
+WHERE user_id = $1
+	AND (change_xid, change_seq) > ($2::bigint, $3::bigint)
+	AND change_xid < $4::bigint
+ORDER BY change_xid, change_seq
+LIMIT $5
+`
+
+type ListTombstonesSinceParams struct {
+	UserID    uuid.UUID
+	ChangeXid int64
+	ChangeSeq int64
+	Horizon   int64
+	PageLimit int32
+}
+
+func (q *Queries) ListTombstonesSince(ctx context.Context, arg ListTombstonesSinceParams) ([]Tombstone, error) {
+	rows, err := q.db.QueryContext(ctx, listTombstonesSince,
+		arg.UserID,
+		arg.ChangeXid,
+		arg.ChangeSeq,
+		arg.Horizon,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tombstone
+	for rows.Next() {
+		var i Tombstone
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EntityType,
+			&i.EntityID,
+			&i.NoteID,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.DeleteTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashNoteIdsByUserId = `-- name: ListTrashNoteIdsByUserId :many
+SELECT id FROM notes
+WHERE user_id = $1 AND delete_time IS NOT NULL
+ORDER BY delete_time, id
+LIMIT $2
+FOR UPDATE
+`
+
+type ListTrashNoteIdsByUserIdParams struct {
+	UserID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) ListTrashNoteIdsByUserId(ctx context.Context, arg ListTrashNoteIdsByUserIdParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashNoteIdsByUserId, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashNotesByUserId = `-- name: ListTrashNotesByUserId :many
+SELECT id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector FROM notes
+WHERE notes.user_id = $1 AND notes.delete_time IS NOT NULL
+  AND (NOT $2::boolean OR (notes.delete_time, notes.id) < ($3::timestamp, $4::uuid))
+  AND ($5::timestamp IS NULL OR notes.create_time >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR notes.create_time < $6::timestamp)
+  AND ($7::timestamp IS NULL OR notes.update_time >= $7::timestamp)
+  AND ($8::timestamp IS NULL OR notes.update_time < $8::timestamp)
+  AND ($9::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = $9::uuid
+  ))
+  AND ($10::uuid IS NULL OR notes.notebook_id = $10::uuid)
+ORDER BY notes.delete_time DESC, notes.id DESC
+LIMIT $11
+`
+
+type ListTrashNotesByUserIdParams struct {
+	UserID        uuid.UUID
+	HasCursor     bool
+	CursorTime    time.Time
+	CursorID      uuid.UUID
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	UpdatedAfter  sql.NullTime
+	UpdatedBefore sql.NullTime
+	TagID         uuid.NullUUID
+	NotebookID    uuid.NullUUID
+	PageLimit     int32
+}
+
+func (q *Queries) ListTrashNotesByUserId(ctx context.Context, arg ListTrashNotesByUserIdParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashNotesByUserId,
+		arg.UserID,
+		arg.HasCursor,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.TagID,
+		arg.NotebookID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockFilesByNotesIds = `-- name: LockFilesByNotesIds :many
+SELECT id, processed_file, original_file, note_id, create_time, update_time, delete_time, change_seq, change_xid, processing_status, duration, sample_rate, waveform FROM files
+WHERE note_id = ANY($1::uuid[])
+FOR UPDATE
+`
+
+func (q *Queries) LockFilesByNotesIds(ctx context.Context, dollar_1 []uuid.UUID) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, lockFilesByNotesIds, pq.Array(dollar_1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProcessedFile,
+			&i.OriginalFile,
+			&i.NoteID,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.ProcessingStatus,
+			&i.Duration,
+			&i.SampleRate,
+			pq.Array(&i.Waveform),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockNotebooksByUserId = `-- name: LockNotebooksByUserId :exec
+SELECT id FROM notebooks
+WHERE user_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockNotebooksByUserId(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, lockNotebooksByUserId, userID)
+	return err
+}
+
+const moveNoteById = `-- name: MoveNoteById :one
+UPDATE notes SET
+  notebook_id = $3, update_time = $4, version = version + 1
+WHERE id = $1 AND user_id = $2 AND delete_time IS NULL
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type MoveNoteByIdParams struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	NotebookID uuid.NullUUID
+	UpdateTime time.Time
+}
+
+func (q *Queries) MoveNoteById(ctx context.Context, arg MoveNoteByIdParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, moveNoteById,
+		arg.ID,
+		arg.UserID,
+		arg.NotebookID,
+		arg.UpdateTime,
+	)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.NotebookID,
+		&i.Title,
+		&i.Content,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const moveNotebookById = `-- name: MoveNotebookById :one
+UPDATE notebooks SET
+  parent_id = $3, update_time = $4
+WHERE id = $1 AND user_id = $2 RETURNING id, user_id, parent_id, name, create_time, update_time
+`
+
+type MoveNotebookByIdParams struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	ParentID   uuid.NullUUID
+	UpdateTime time.Time
+}
+
+func (q *Queries) MoveNotebookById(ctx context.Context, arg MoveNotebookByIdParams) (Notebook, error) {
+	row := q.db.QueryRowContext(ctx, moveNotebookById,
+		arg.ID,
+		arg.UserID,
+		arg.ParentID,
+		arg.UpdateTime,
+	)
+	var i Notebook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const pruneNoteRevisions = `-- name: PruneNoteRevisions :exec
+DELETE FROM note_revisions
+WHERE note_revisions.note_id = $1 AND note_revisions.id NOT IN (
+  SELECT kept.id FROM note_revisions kept
+  WHERE kept.note_id = $1
+  ORDER BY kept.create_time DESC, kept.id DESC
+  LIMIT $2
+)
+`
+
+type PruneNoteRevisionsParams struct {
+	NoteID uuid.UUID
+	Keep   int32
+}
+
+func (q *Queries) PruneNoteRevisions(ctx context.Context, arg PruneNoteRevisionsParams) error {
+	_, err := q.db.ExecContext(ctx, pruneNoteRevisions, arg.NoteID, arg.Keep)
+	return err
+}
+
+const removeNotesFromNotebooks = `-- name: RemoveNotesFromNotebooks :many
+UPDATE notes SET
+  notebook_id = NULL,
+  delete_time = CASE WHEN $1::boolean THEN COALESCE(delete_time, $2::timestamp) ELSE delete_time END,
+  update_time = $3, version = version + 1
+WHERE user_id = $4 AND notebook_id = ANY($5::uuid[])
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type RemoveNotesFromNotebooksParams struct {
+	Trash       bool
+	DeleteTime  time.Time
+	UpdateTime  time.Time
+	UserID      uuid.UUID
+	NotebookIds []uuid.UUID
+}
+
+func (q *Queries) RemoveNotesFromNotebooks(ctx context.Context, arg RemoveNotesFromNotebooksParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, removeNotesFromNotebooks,
+		arg.Trash,
+		arg.DeleteTime,
+		arg.UpdateTime,
+		arg.UserID,
+		pq.Array(arg.NotebookIds),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
 	for rows.Next() {
-		var i File
+		var i Note
 		if err := rows.Scan(
 			&i.ID,
-			&i.ProcessedFile,
-			&i.OriginalFile,
-			&i.NoteID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
 			&i.CreateTime,
 			&i.UpdateTime,
 			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -386,20 +2838,146 @@ func (q *Queries) ListFilesByNotesIds(ctx context.Context, dollar_1 []uuid.UUID)
 	return items, nil
 }
 
-const listNotesByUserId = `-- name: ListNotesByUserId :many
-SELECT id, user_id, title, content, create_time, update_time, delete_time FROM notes
-WHERE user_id = $1 AND update_time < $2 AND delete_time IS NULL
-ORDER BY update_time DESC
-LIMIT 10
+const renameNotebookById = `-- name: RenameNotebookById :one
+UPDATE notebooks SET
+  name = $3, update_time = $4
+WHERE id = $1 AND user_id = $2 RETURNING id, user_id, parent_id, name, create_time, update_time
 `
 
-type ListNotesByUserIdParams struct {
+type RenameNotebookByIdParams struct {
+	ID         uuid.UUID
 	UserID     uuid.UUID
+	Name       string
 	UpdateTime time.Time
 }
 
-func (q *Queries) ListNotesByUserId(ctx context.Context, arg ListNotesByUserIdParams) ([]Note, error) {
-	rows, err := q.db.QueryContext(ctx, listNotesByUserId, arg.UserID, arg.UpdateTime)
+func (q *Queries) RenameNotebookById(ctx context.Context, arg RenameNotebookByIdParams) (Notebook, error) {
+	row := q.db.QueryRowContext(ctx, renameNotebookById,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.UpdateTime,
+	)
+	var i Notebook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreateTime,
+		&i.UpdateTime,
+	)
+	return i, err
+}
+
+const replaceProcessedFileById = `-- name: ReplaceProcessedFileById :one
+UPDATE files SET
+  processed_file = $1, processing_status = 'succeeded', update_time = $2,
+  duration = $3, sample_rate = $4, waveform = $5
+WHERE id = $6 RETURNING id, processed_file, original_file, note_id, create_time, update_time, delete_time, change_seq, change_xid, processing_status, duration, sample_rate, waveform
+`
+
+type ReplaceProcessedFileByIdParams struct {
+	ProcessedFile sql.NullString
+	UpdateTime    time.Time
+	Duration      sql.NullFloat64
+	SampleRate    sql.NullInt32
+	Waveform      []float32
+	ID            uuid.UUID
+}
+
+func (q *Queries) ReplaceProcessedFileById(ctx context.Context, arg ReplaceProcessedFileByIdParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, replaceProcessedFileById,
+		arg.ProcessedFile,
+		arg.UpdateTime,
+		arg.Duration,
+		arg.SampleRate,
+		pq.Array(arg.Waveform),
+		arg.ID,
+	)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.ProcessedFile,
+		&i.OriginalFile,
+		&i.NoteID,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.DeleteTime,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.ProcessingStatus,
+		&i.Duration,
+		&i.SampleRate,
+		pq.Array(&i.Waveform),
+	)
+	return i, err
+}
+
+const requeueDeadObjectDeletions = `-- name: RequeueDeadObjectDeletions :execrows
+UPDATE object_deletions SET
+  attempts = 0,
+  next_attempt_time = CURRENT_TIMESTAMP,
+  dead_time = NULL
+WHERE dead_time IS NOT NULL
+`
+
+func (q *Queries) RequeueDeadObjectDeletions(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requeueDeadObjectDeletions)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreNoteById = `-- name: RestoreNoteById :one
+UPDATE notes SET
+  delete_time = NULL, version = version + 1, update_time = now()
+WHERE id = $1 AND user_id = $2 AND delete_time IS NOT NULL
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type RestoreNoteByIdParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) RestoreNoteById(ctx context.Context, arg RestoreNoteByIdParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, restoreNoteById, arg.ID, arg.UserID)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.NotebookID,
+		&i.Title,
+		&i.Content,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const restoreNotesByIds = `-- name: RestoreNotesByIds :many
+UPDATE notes SET
+  delete_time = NULL, version = version + 1, update_time = now()
+WHERE id = ANY($1::uuid[]) AND user_id = $2 AND delete_time IS NOT NULL
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type RestoreNotesByIdsParams struct {
+	Ids    []uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) RestoreNotesByIds(ctx context.Context, arg RestoreNotesByIdsParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, restoreNotesByIds, pq.Array(arg.Ids), arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -410,11 +2988,18 @@ func (q *Queries) ListNotesByUserId(ctx context.Context, arg ListNotesByUserIdPa
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.NotebookID,
 			&i.Title,
 			&i.Content,
 			&i.CreateTime,
 			&i.UpdateTime,
 			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -429,35 +3014,106 @@ func (q *Queries) ListNotesByUserId(ctx context.Context, arg ListNotesByUserIdPa
 	return items, nil
 }
 
-const listTrashNotesByUserId = `-- name: ListTrashNotesByUserId :many
-SELECT id, user_id, title, content, create_time, update_time, delete_time FROM notes
-WHERE user_id = $1 AND delete_time < $2 AND delete_time IS NOT NULL
-ORDER BY delete_time DESC
-LIMIT 10
+const rotateRefreshTokenById = `-- name: RotateRefreshTokenById :one
+UPDATE refresh_tokens SET
+  rotate_time = $2, update_time = $2
+WHERE id = $1 AND rotate_time IS NULL
+RETURNING id, user_id, family_id, device_name, user_agent, ip_address, create_time, update_time, rotate_time
 `
 
-type ListTrashNotesByUserIdParams struct {
-	UserID     uuid.UUID
-	DeleteTime sql.NullTime
+type RotateRefreshTokenByIdParams struct {
+	ID         uuid.UUID
+	RotateTime sql.NullTime
 }
 
-func (q *Queries) ListTrashNotesByUserId(ctx context.Context, arg ListTrashNotesByUserIdParams) ([]Note, error) {
-	rows, err := q.db.QueryContext(ctx, listTrashNotesByUserId, arg.UserID, arg.DeleteTime)
+func (q *Queries) RotateRefreshTokenById(ctx context.Context, arg RotateRefreshTokenByIdParams) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, rotateRefreshTokenById, arg.ID, arg.RotateTime)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.DeviceName,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.RotateTime,
+	)
+	return i, err
+}
+
+const searchNotes = `-- name: SearchNotes :many
+SELECT
+  notes.id, notes.user_id, notes.notebook_id, notes.title, notes.content, notes.create_time, notes.update_time, notes.delete_time, notes.pinned_time, notes.archive_time, notes.version, notes.change_seq, notes.change_xid, notes.search_vector,
+  ts_rank(notes.search_vector, query)::float8 AS rank,
+  ts_headline('english', COALESCE(notes.title, ''), query, 'HighlightAll=true')::text AS title_snippet,
+  ts_headline('english', COALESCE(notes.content, ''), query, 'MaxFragments=2, MaxWords=20, MinWords=5')::text AS content_snippet
+FROM notes, websearch_to_tsquery('english', $1::text) query
+WHERE notes.user_id = $2
+  AND notes.search_vector @@ query
+  AND ($3::boolean OR notes.delete_time IS NULL)
+  AND (
+    NOT $4::boolean
+    OR ts_rank(notes.search_vector, query)::float8 < $5::float8
+    OR (ts_rank(notes.search_vector, query)::float8 = $5::float8 AND notes.id < $6::uuid)
+  )
+ORDER BY rank DESC, notes.id DESC
+LIMIT $7
+`
+
+type SearchNotesParams struct {
+	Query        string
+	UserID       uuid.UUID
+	IncludeTrash bool
+	HasCursor    bool
+	CursorRank   float64
+	CursorID     uuid.UUID
+	PageLimit    int32
+}
+
+type SearchNotesRow struct {
+	Note           Note
+	Rank           float64
+	TitleSnippet   string
+	ContentSnippet string
+}
+
+func (q *Queries) SearchNotes(ctx context.Context, arg SearchNotesParams) ([]SearchNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchNotes,
+		arg.Query,
+		arg.UserID,
+		arg.IncludeTrash,
+		arg.HasCursor,
+		arg.CursorRank,
+		arg.CursorID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Note
+	var items []SearchNotesRow
 	for rows.Next() {
-		var i Note
+		var i SearchNotesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.Title,
-			&i.Content,
-			&i.CreateTime,
-			&i.UpdateTime,
-			&i.DeleteTime,
+			&i.Note.ID,
+			&i.Note.UserID,
+			&i.Note.NotebookID,
+			&i.Note.Title,
+			&i.Note.Content,
+			&i.Note.CreateTime,
+			&i.Note.UpdateTime,
+			&i.Note.DeleteTime,
+			&i.Note.PinnedTime,
+			&i.Note.ArchiveTime,
+			&i.Note.Version,
+			&i.Note.ChangeSeq,
+			&i.Note.ChangeXid,
+			&i.Note.SearchVector,
+			&i.Rank,
+			&i.TitleSnippet,
+			&i.ContentSnippet,
 		); err != nil {
 			return nil, err
 		}
@@ -472,69 +3128,220 @@ func (q *Queries) ListTrashNotesByUserId(ctx context.Context, arg ListTrashNotes
 	return items, nil
 }
 
-const restoreNoteById = `-- name: RestoreNoteById :one
+const setFilesProcessingStatus = `-- name: SetFilesProcessingStatus :exec
+UPDATE files SET
+  processing_status = $1
+WHERE id = ANY($2::uuid[])
+`
+
+type SetFilesProcessingStatusParams struct {
+	ProcessingStatus string
+	Ids              []uuid.UUID
+}
+
+func (q *Queries) SetFilesProcessingStatus(ctx context.Context, arg SetFilesProcessingStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setFilesProcessingStatus, arg.ProcessingStatus, pq.Array(arg.Ids))
+	return err
+}
+
+const setNoteArchivedById = `-- name: SetNoteArchivedById :one
+UPDATE notes SET
+  archive_time = CASE WHEN $1::boolean THEN COALESCE(archive_time, $2::timestamp) ELSE NULL END,
+  update_time = $3, version = version + 1
+WHERE id = $4 AND user_id = $5 AND delete_time IS NULL
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type SetNoteArchivedByIdParams struct {
+	Archived    bool
+	ArchiveTime time.Time
+	UpdateTime  time.Time
+	ID          uuid.UUID
+	UserID      uuid.UUID
+}
+
+func (q *Queries) SetNoteArchivedById(ctx context.Context, arg SetNoteArchivedByIdParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, setNoteArchivedById,
+		arg.Archived,
+		arg.ArchiveTime,
+		arg.UpdateTime,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.NotebookID,
+		&i.Title,
+		&i.Content,
+		&i.CreateTime,
+		&i.UpdateTime,
+		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const setNotePinnedById = `-- name: SetNotePinnedById :one
 UPDATE notes SET
-  delete_time = NULL
-WHERE id = $1 AND delete_time IS NOT NULL
-RETURNING id, user_id, title, content, create_time, update_time, delete_time
+  pinned_time = CASE WHEN $1::boolean THEN COALESCE(pinned_time, $2::timestamp) ELSE NULL END,
+  update_time = $3, version = version + 1
+WHERE id = $4 AND user_id = $5 AND delete_time IS NULL
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
 `
 
-func (q *Queries) RestoreNoteById(ctx context.Context, id uuid.UUID) (Note, error) {
-	row := q.db.QueryRowContext(ctx, restoreNoteById, id)
+type SetNotePinnedByIdParams struct {
+	Pinned     bool
+	PinnedTime time.Time
+	UpdateTime time.Time
+	ID         uuid.UUID
+	UserID     uuid.UUID
+}
+
+func (q *Queries) SetNotePinnedById(ctx context.Context, arg SetNotePinnedByIdParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, setNotePinnedById,
+		arg.Pinned,
+		arg.PinnedTime,
+		arg.UpdateTime,
+		arg.ID,
+		arg.UserID,
+	)
 	var i Note
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.NotebookID,
 		&i.Title,
 		&i.Content,
 		&i.CreateTime,
 		&i.UpdateTime,
 		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const softDeleteNoteById = `-- name: SoftDeleteNoteById :one
 UPDATE notes SET
-  delete_time = $2
-WHERE id = $1 AND delete_time IS NULL
-RETURNING id, user_id, title, content, create_time, update_time, delete_time
+  delete_time = $2, version = version + 1, update_time = now()
+WHERE id = $1 AND user_id = $3 AND delete_time IS NULL
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
 `
 
 type SoftDeleteNoteByIdParams struct {
 	ID         uuid.UUID
 	DeleteTime sql.NullTime
+	UserID     uuid.UUID
 }
 
 func (q *Queries) SoftDeleteNoteById(ctx context.Context, arg SoftDeleteNoteByIdParams) (Note, error) {
-	row := q.db.QueryRowContext(ctx, softDeleteNoteById, arg.ID, arg.DeleteTime)
+	row := q.db.QueryRowContext(ctx, softDeleteNoteById, arg.ID, arg.DeleteTime, arg.UserID)
 	var i Note
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.NotebookID,
 		&i.Title,
 		&i.Content,
 		&i.CreateTime,
 		&i.UpdateTime,
 		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
+const softDeleteNotesByIds = `-- name: SoftDeleteNotesByIds :many
+UPDATE notes SET
+  delete_time = $1, version = version + 1, update_time = now()
+WHERE id = ANY($2::uuid[]) AND user_id = $3 AND delete_time IS NULL
+RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
+`
+
+type SoftDeleteNotesByIdsParams struct {
+	DeleteTime sql.NullTime
+	Ids        []uuid.UUID
+	UserID     uuid.UUID
+}
+
+func (q *Queries) SoftDeleteNotesByIds(ctx context.Context, arg SoftDeleteNotesByIdsParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, softDeleteNotesByIds, arg.DeleteTime, pq.Array(arg.Ids), arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Note
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotebookID,
+			&i.Title,
+			&i.Content,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.DeleteTime,
+			&i.PinnedTime,
+			&i.ArchiveTime,
+			&i.Version,
+			&i.ChangeSeq,
+			&i.ChangeXid,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateFileByOriginalId = `-- name: UpdateFileByOriginalId :one
 UPDATE files SET
-  processed_file = $2, update_time = $3
-WHERE original_file = $1 AND (processed_file IS NULL OR processed_file = '') RETURNING id, processed_file, original_file, note_id, create_time, update_time, delete_time
+  processed_file = $2, update_time = $3, duration = $4, sample_rate = $5, waveform = $6
+WHERE original_file = $1 AND (processed_file IS NULL OR processed_file = '') RETURNING id, processed_file, original_file, note_id, create_time, update_time, delete_time, change_seq, change_xid, processing_status, duration, sample_rate, waveform
 `
 
 type UpdateFileByOriginalIdParams struct {
 	OriginalFile  string
 	ProcessedFile sql.NullString
 	UpdateTime    time.Time
+	Duration      sql.NullFloat64
+	SampleRate    sql.NullInt32
+	Waveform      []float32
 }
 
 func (q *Queries) UpdateFileByOriginalId(ctx context.Context, arg UpdateFileByOriginalIdParams) (File, error) {
-	row := q.db.QueryRowContext(ctx, updateFileByOriginalId, arg.OriginalFile, arg.ProcessedFile, arg.UpdateTime)
+	row := q.db.QueryRowContext(ctx, updateFileByOriginalId,
+		arg.OriginalFile,
+		arg.ProcessedFile,
+		arg.UpdateTime,
+		arg.Duration,
+		arg.SampleRate,
+		pq.Array(arg.Waveform),
+	)
 	var i File
 	err := row.Scan(
 		&i.ID,
@@ -544,14 +3351,20 @@ func (q *Queries) UpdateFileByOriginalId(ctx context.Context, arg UpdateFileByOr
 		&i.CreateTime,
 		&i.UpdateTime,
 		&i.DeleteTime,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.ProcessingStatus,
+		&i.Duration,
+		&i.SampleRate,
+		pq.Array(&i.Waveform),
 	)
 	return i, err
 }
 
 const updateNoteById = `-- name: UpdateNoteById :one
 UPDATE notes SET
-  title = COALESCE(NULLIF($2, ''), title), content = COALESCE(NULLIF($3, ''), content), update_time = $4
-WHERE id = $1 RETURNING id, user_id, title, content, create_time, update_time, delete_time
+  title = COALESCE(NULLIF($2, ''), title), content = COALESCE(NULLIF($3, ''), content), update_time = $4, version = version + 1
+WHERE id = $1 AND user_id = $5 AND version = $6 RETURNING id, user_id, notebook_id, title, content, create_time, update_time, delete_time, pinned_time, archive_time, version, change_seq, change_xid, search_vector
 `
 
 type UpdateNoteByIdParams struct {
@@ -559,6 +3372,8 @@ type UpdateNoteByIdParams struct {
 	Column2    interface{}
 	Column3    interface{}
 	UpdateTime time.Time
+	UserID     uuid.UUID
+	Version    int32
 }
 
 func (q *Queries) UpdateNoteById(ctx context.Context, arg UpdateNoteByIdParams) (Note, error) {
@@ -567,16 +3382,56 @@ func (q *Queries) UpdateNoteById(ctx context.Context, arg UpdateNoteByIdParams)
 		arg.Column2,
 		arg.Column3,
 		arg.UpdateTime,
+		arg.UserID,
+		arg.Version,
 	)
 	var i Note
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.NotebookID,
 		&i.Title,
 		&i.Content,
 		&i.CreateTime,
 		&i.UpdateTime,
 		&i.DeleteTime,
+		&i.PinnedTime,
+		&i.ArchiveTime,
+		&i.Version,
+		&i.ChangeSeq,
+		&i.ChangeXid,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const updateTagById = `-- name: UpdateTagById :one
+UPDATE tags SET
+  name = $3, update_time = $4
+WHERE id = $1 AND user_id = $2 RETURNING id, user_id, name, create_time, update_time
+`
+
+type UpdateTagByIdParams struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	UpdateTime time.Time
+}
+
+func (q *Queries) UpdateTagById(ctx context.Context, arg UpdateTagByIdParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, updateTagById,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.UpdateTime,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreateTime,
+		&i.UpdateTime,
 	)
 	return i, err
 }
@@ -6,6 +6,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// AccessTokenLifetime is how long an access token is valid
+const AccessTokenLifetime = 60 * time.Minute
+
 type AccessToken struct {
 	Id             uuid.UUID `json:"id"`
 	UserId         uuid.UUID `json:"user_id"`
@@ -10,5 +10,6 @@ import (
 type AccessTokenDatabaseDs interface {
 	GetAccessTokenById(ctx context.Context, id uuid.UUID) (*AccessToken, error)
 	CreateAccessToken(ctx context.Context, tx *sql.Tx, accessToken *AccessToken) (*AccessToken, error)
-	DeleteAccessTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (*uuid.UUID, error)
+	DeleteAccessTokensByRefreshTokenId(ctx context.Context, tx *sql.Tx, refreshTokenId uuid.UUID) (*[]uuid.UUID, error)
+	DeleteAccessTokensByRefreshTokenFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) (*[]uuid.UUID, error)
 }
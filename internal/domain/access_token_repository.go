@@ -13,7 +13,8 @@ import (
 type AccessTokenRepository interface {
 	GetAccessToken(ctx context.Context, id uuid.UUID) (*AccessToken, error)
 	CreateAccessToken(ctx context.Context, tx *sql.Tx, userId uuid.UUID, refreshTokenId uuid.UUID) (*AccessToken, error)
-	DeleteAccessTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	DeleteAccessTokensByRefreshTokenId(ctx context.Context, tx *sql.Tx, refreshTokenId uuid.UUID) error
+	DeleteAccessTokensByRefreshTokenFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) error
 }
 
 type accessTokenRepository struct {
@@ -97,16 +98,32 @@ func (r *accessTokenRepository) CreateAccessToken(ctx context.Context, tx *sql.T
 	return accessToken, nil
 }
 
-func (r *accessTokenRepository) DeleteAccessTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
-	// Delete the refresh token on the database
-	id, err := r.AccessTokenDatabaseDs.DeleteAccessTokenByUserId(ctx, tx, userId)
+func (r *accessTokenRepository) DeleteAccessTokensByRefreshTokenId(ctx context.Context, tx *sql.Tx, refreshTokenId uuid.UUID) error {
+	// Delete the access tokens on the database
+	ids, err := r.AccessTokenDatabaseDs.DeleteAccessTokensByRefreshTokenId(ctx, tx, refreshTokenId)
 	if err != nil {
 		return err
 	}
-	// Delete the refresh token on the cache
-	err = r.AccessTokenCacheDs.DeleteAccessToken(ctx, *id)
+	// Delete the access tokens on the cache
+	return r.deleteCachedAccessTokens(ctx, ids)
+}
+
+func (r *accessTokenRepository) DeleteAccessTokensByRefreshTokenFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) error {
+	// Delete the access tokens on the database
+	ids, err := r.AccessTokenDatabaseDs.DeleteAccessTokensByRefreshTokenFamilyId(ctx, tx, familyId)
 	if err != nil {
 		return err
 	}
+	// Delete the access tokens on the cache
+	return r.deleteCachedAccessTokens(ctx, ids)
+}
+
+// deleteCachedAccessTokens removes the given access tokens from the cache
+func (r *accessTokenRepository) deleteCachedAccessTokens(ctx context.Context, ids *[]uuid.UUID) error {
+	for _, id := range *ids {
+		if err := r.AccessTokenCacheDs.DeleteAccessToken(ctx, id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Codecs of the processed audio files, the original files of every supported format are encoded with the configured one
+const (
+	AudioAac    = "aac"
+	AudioMp3    = "mp3"
+	AudioOpus   = "opus"
+	AudioVorbis = "vorbis"
+)
+
+// Waveform of the processed audio files
+const (
+	// waveformPeaks is the number of peaks of the waveform
+	waveformPeaks = 100
+	// waveformSampleRate is the sample rate the audio is decoded at to find the peaks
+	waveformSampleRate = 8000
+	// waveformBlockSize is the number of samples of a block, the peak of every block is kept while decoding
+	waveformBlockSize = 80
+)
+
+// audioCodec is how the audio files are encoded with a codec
+type audioCodec struct {
+	// encoder of ffmpeg
+	encoder string
+	// extension of the container of the processed audio file
+	extension string
+}
+
+// audioCodecs are the supported codecs of the processed audio files
+var audioCodecs = map[string]audioCodec{
+	AudioAac:    {encoder: "aac", extension: ".m4a"},
+	AudioMp3:    {encoder: "libmp3lame", extension: ".mp3"},
+	AudioOpus:   {encoder: "libopus", extension: ".ogg"},
+	AudioVorbis: {encoder: "libvorbis", extension: ".ogg"},
+}
+
+// CompressAudio encodes the first audio stream of the file with the codec and the bitrate using ffmpeg.
+// The cover art and the other streams are dropped, it returns the path of the compressed audio.
+func CompressAudio(path string, codecName string, bitrate string) (string, error) {
+	codec, ok := audioCodecs[codecName]
+	if !ok {
+		return "", fmt.Errorf("the audio codec %s is not supported", codecName)
+	}
+
+	// Define the output path for the compressed audio
+	outputPath := fmt.Sprintf("/tmp/%s%s", uuid.New(), codec.extension)
+	if err := runFfmpeg("-i", path, "-map", "0:a:0", "-c:a", codec.encoder, "-b:a", bitrate, outputPath); err != nil {
+		// Remove the output file if the compression fails
+		os.Remove(outputPath)
+		return "", fmt.Errorf("error compressing audio: %v", err)
+	}
+	return outputPath, nil
+}
+
+// ReadAudioMetadata probes the duration and the sample rate of the audio file with ffprobe, and decodes it to find the peaks of its waveform
+func ReadAudioMetadata(path string) (*AudioMetadata, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("ffprobe", "-v", "error", "-select_streams", "a:0", "-show_entries", "stream=sample_rate:format=duration", "-of", "json", path)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var probe struct {
+		Streams []struct {
+			SampleRate string `json:"sample_rate"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse the ffprobe output: %v", err)
+	}
+	if len(probe.Streams) == 0 {
+		return nil, errors.New("the file has no audio stream")
+	}
+	duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audio duration: %s", probe.Format.Duration)
+	}
+	sampleRate, err := strconv.Atoi(probe.Streams[0].SampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audio sample rate: %s", probe.Streams[0].SampleRate)
+	}
+
+	peaks, err := decodeWaveform(path)
+	if err != nil {
+		return nil, err
+	}
+	return &AudioMetadata{Duration: duration, SampleRate: int32(sampleRate), Peaks: peaks}, nil
+}
+
+// decodeWaveform decodes the audio file to mono samples with ffmpeg and returns the peaks of its waveform.
+// The samples are read from the output of ffmpeg as they are decoded, so the decoded audio isn't held in memory.
+func decodeWaveform(path string) ([]float32, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("ffmpeg", "-loglevel", "error", "-i", path, "-map", "0:a:0", "-ac", "1", "-ar", fmt.Sprint(waveformSampleRate), "-f", "s16le", "-")
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %v", err)
+	}
+
+	peaks, readErr := WaveformPeaks(stdout, waveformPeaks)
+	// Drain the output, so ffmpeg isn't blocked writing it after a failed read
+	io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read the decoded audio: %v", readErr)
+	}
+	return peaks, nil
+}
+
+// WaveformPeaks reads 16 bit little endian mono samples and returns the given number of peaks, evenly spread over the samples.
+// Every peak is the highest absolute sample of its span from 0 to 1, rounded to 3 decimals. The audio shorter than the peaks is stretched.
+func WaveformPeaks(r io.Reader, n int) ([]float32, error) {
+	// Keep the peak of every block of samples, a long audio is reduced to a few values per second
+	var blocks []int32
+	buf := make([]byte, 2*waveformBlockSize)
+	for {
+		read, err := io.ReadFull(r, buf)
+		if read >= 2 {
+			var peak int32
+			for i := 0; i+1 < read; i += 2 {
+				sample := int32(int16(binary.LittleEndian.Uint16(buf[i:])))
+				if sample < 0 {
+					sample = -sample
+				}
+				peak = max(peak, sample)
+			}
+			blocks = append(blocks, peak)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	peaks := make([]float32, n)
+	if len(blocks) == 0 {
+		return peaks, nil
+	}
+	for i := range peaks {
+		start := i * len(blocks) / n
+		end := max((i+1)*len(blocks)/n, start+1)
+		var peak int32
+		for _, block := range blocks[start:end] {
+			peak = max(peak, block)
+		}
+		peaks[i] = float32(math.Round(float64(peak)/math.MaxInt16*1000) / 1000)
+	}
+	return peaks, nil
+}
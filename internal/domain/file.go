@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Processing statuses of a file, they follow the status of its processing job
+const (
+	FileProcessingQueued    = "queued"
+	FileProcessingRunning   = "running"
+	FileProcessingSucceeded = "succeeded"
+	FileProcessingFailed    = "failed"
+)
+
 type File struct {
 	Id            uuid.UUID `json:"id"`
 	NoteId        uuid.UUID `json:"note_id"`
@@ -15,4 +23,75 @@ type File struct {
 	CreateTime    time.Time `json:"create_time"`
 	UpdateTime    time.Time `json:"update_time"`
 	DeleteTime    time.Time `json:"delete_time"`
+	// ProcessingStatus is queued, running, succeeded or failed
+	ProcessingStatus string `json:"processing_status"`
+	// Renditions are the resized copies of a processed image
+	Renditions []FileRendition `json:"-"`
+	// RenditionUrls are the urls of the renditions by their size
+	RenditionUrls map[string]string `json:"renditions"`
+	// Audio is the metadata of a processed audio file
+	Audio *AudioMetadata `json:"audio"`
+	// ChangeSeq and ChangeXid are the position of the last change of the file in the order of the delta sync
+	ChangeSeq int64 `json:"-"`
+	ChangeXid int64 `json:"-"`
+}
+
+// FileFilter selects the files to reprocess, a nil id, an empty type or a zero time disables the filter.
+// The after time is inclusive and the before time is exclusive.
+type FileFilter struct {
+	NoteId uuid.UUID
+	UserId uuid.UUID
+	// Type is picture or audio
+	Type          string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Unprocessed leaves out the files that were processed successfully
+	Unprocessed bool
+}
+
+// AudioMetadata describes a processed audio file, so the players are rendered without downloading it
+type AudioMetadata struct {
+	// Duration in seconds
+	Duration   float64 `json:"duration"`
+	SampleRate int32   `json:"sample_rate"`
+	// Peaks are the peaks of the samples from 0 to 1, evenly spread over the duration
+	Peaks []float32 `json:"peaks"`
+}
+
+// FileRendition is a resized copy of a processed image, its longest side is the size
+type FileRendition struct {
+	Id         uuid.UUID `json:"id"`
+	FileId     uuid.UUID `json:"file_id"`
+	Size       int32     `json:"size"`
+	ObjectName string    `json:"object_name"`
+	Width      int32     `json:"width"`
+	Height     int32     `json:"height"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// ProcessedObjectNames returns the objects of the processed copy of the file and its renditions
+func (f *File) ProcessedObjectNames() []string {
+	objectNames := make([]string, 0, 1+len(f.Renditions))
+	if f.ProcessedFile != "" {
+		objectNames = append(objectNames, f.ProcessedFile)
+	}
+	for _, rendition := range f.Renditions {
+		objectNames = append(objectNames, rendition.ObjectName)
+	}
+	return objectNames
+}
+
+// UnsavedObjectsError is returned when the processed copies of a file were uploaded but are not saved on the file.
+// No file references the objects once the transaction is rolled back, so they are discarded by the caller.
+type UnsavedObjectsError struct {
+	ObjectNames []string
+	Err         error
+}
+
+func (e *UnsavedObjectsError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UnsavedObjectsError) Unwrap() error {
+	return e.Err
 }
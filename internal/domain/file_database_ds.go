@@ -10,7 +10,13 @@ import (
 type FileDatabaseDs interface {
 	ListFilesByNotesIds(ctx context.Context, noteId []uuid.UUID) (*[]File, error)
 	ListFilesByNoteId(ctx context.Context, noteId uuid.UUID) (*[]File, error)
+	LockFilesByNotesIds(ctx context.Context, tx *sql.Tx, noteIds []uuid.UUID) (*[]File, error)
 	CreateFile(ctx context.Context, tx *sql.Tx, file *File) (*File, error)
-	UpdateFileByOriginalId(ctx context.Context, tx *sql.Tx, originalFileId, processFileId string) (*File, error)
+	UpdateFileByOriginalId(ctx context.Context, tx *sql.Tx, originalFileId, processFileId string, audio *AudioMetadata) (*File, error)
 	HardDeleteFilesByNoteId(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) (*[]File, error)
+	ListFilesForReprocessing(ctx context.Context, filter FileFilter, cursor uuid.UUID, limit int32) (*[]File, error)
+	CountFilesForReprocessing(ctx context.Context, filter FileFilter) (int64, error)
+	ReplaceProcessedFile(ctx context.Context, tx *sql.Tx, id uuid.UUID, processedFileId string, audio *AudioMetadata) (*File, error)
+	ListFileRenditionsByFileIds(ctx context.Context, fileIds []uuid.UUID) (*[]FileRendition, error)
+	ReplaceFileRenditions(ctx context.Context, tx *sql.Tx, fileId uuid.UUID, renditions []FileRendition) (replaced *[]FileRendition, created *[]FileRendition, err error)
 }
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of a file processing job
+const (
+	FileJobQueued    = "queued"
+	FileJobRunning   = "running"
+	FileJobSucceeded = "succeeded"
+	FileJobFailed    = "failed"
+)
+
+// FileJob is a queued processing of a file. A worker leases the job while it processes the file,
+// a job whose lease expires is claimed again by another worker.
+type FileJob struct {
+	Id              uuid.UUID `json:"id"`
+	FileId          uuid.UUID `json:"file_id"`
+	OriginalFile    string    `json:"original_file"`
+	Status          string    `json:"status"`
+	Attempts        int32     `json:"attempts"`
+	LastError       string    `json:"last_error"`
+	RunTime         time.Time `json:"run_time"`
+	LeaseOwner      string    `json:"lease_owner"`
+	LeaseExpireTime time.Time `json:"lease_expire_time"`
+	CreateTime      time.Time `json:"create_time"`
+	UpdateTime      time.Time `json:"update_time"`
+}
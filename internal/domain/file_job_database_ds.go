@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileJobDatabaseDs interface {
+	EnqueueFileJobs(ctx context.Context, tx *sql.Tx, fileIds []uuid.UUID) error
+	ClaimFileJobs(ctx context.Context, tx *sql.Tx, worker string, now time.Time, leaseExpireTime time.Time, limit int32) (*[]FileJob, error)
+	CompleteFileJob(ctx context.Context, tx *sql.Tx, job *FileJob, worker string) error
+	FailFileJob(ctx context.Context, tx *sql.Tx, job *FileJob, worker string, lastError string, runTime time.Time, dead bool) error
+	CompleteFileJobsByFileId(ctx context.Context, tx *sql.Tx, fileId uuid.UUID) error
+}
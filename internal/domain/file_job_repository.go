@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileJobRepository interface {
+	EnqueueFileJobs(ctx context.Context, tx *sql.Tx, fileIds []uuid.UUID) error
+	ClaimFileJobs(ctx context.Context, tx *sql.Tx, worker string, lease time.Duration, limit int32) (*[]FileJob, error)
+	CompleteFileJob(ctx context.Context, tx *sql.Tx, job *FileJob, worker string) error
+	FailFileJob(ctx context.Context, tx *sql.Tx, job *FileJob, worker string, lastError string, runTime time.Time, dead bool) error
+	CompleteFileJobsByFileId(ctx context.Context, tx *sql.Tx, fileId uuid.UUID) error
+}
+
+type fileJobRepository struct {
+	FileJobDatabaseDs FileJobDatabaseDs
+}
+
+func NewFileJobRepository(fileJobDatabaseDs FileJobDatabaseDs) FileJobRepository {
+	return &fileJobRepository{
+		FileJobDatabaseDs: fileJobDatabaseDs,
+	}
+}
+
+func (r *fileJobRepository) EnqueueFileJobs(ctx context.Context, tx *sql.Tx, fileIds []uuid.UUID) error {
+	if len(fileIds) == 0 {
+		return nil
+	}
+	// Queue the processing of the files, the jobs are claimed once the transaction is committed
+	return r.FileJobDatabaseDs.EnqueueFileJobs(ctx, tx, fileIds)
+}
+
+func (r *fileJobRepository) ClaimFileJobs(ctx context.Context, tx *sql.Tx, worker string, lease time.Duration, limit int32) (*[]FileJob, error) {
+	// Lease the due jobs and the jobs whose lease expired, the jobs locked by another worker are skipped
+	now := time.Now().UTC()
+	return r.FileJobDatabaseDs.ClaimFileJobs(ctx, tx, worker, now, now.Add(lease), limit)
+}
+
+func (r *fileJobRepository) CompleteFileJob(ctx context.Context, tx *sql.Tx, job *FileJob, worker string) error {
+	return r.FileJobDatabaseDs.CompleteFileJob(ctx, tx, job, worker)
+}
+
+func (r *fileJobRepository) FailFileJob(ctx context.Context, tx *sql.Tx, job *FileJob, worker string, lastError string, runTime time.Time, dead bool) error {
+	return r.FileJobDatabaseDs.FailFileJob(ctx, tx, job, worker, lastError, runTime, dead)
+}
+
+func (r *fileJobRepository) CompleteFileJobsByFileId(ctx context.Context, tx *sql.Tx, fileId uuid.UUID) error {
+	// The jobs of a file that is processed out of the queue are done with, a running job is left to its worker
+	return r.FileJobDatabaseDs.CompleteFileJobsByFileId(ctx, tx, fileId)
+}
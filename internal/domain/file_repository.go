@@ -5,15 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"image/jpeg"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
 
 	"github.com/daniarmas/clogg"
 	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/oss"
 	"github.com/google/uuid"
 )
@@ -21,20 +20,23 @@ import (
 var pictureExtensions = map[string]bool{
 	".jpg":  true,
 	".jpeg": true,
-	// ".png":  true,
-	// ".gif":  true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+	".heic": true,
+	".heif": true,
 	// ".bmp":  true,
 	// ".tiff": true,
 	// ".svg":  true,
 }
 
 var audioExtensions = map[string]bool{
-	// ".mp3":  true,
-	// ".wav":  true,
-	// ".flac": true,
-	// ".aac":  true,
-	// ".ogg":  true,
-	".m4a": true,
+	".mp3":  true,
+	".wav":  true,
+	".flac": true,
+	".aac":  true,
+	".ogg":  true,
+	".m4a":  true,
 	// ".wma":  true,
 }
 
@@ -50,27 +52,57 @@ func fileType(path string) string {
 	}
 }
 
+// FileTypeExtensions returns the extensions of the supported files of the type, picture or audio
+func FileTypeExtensions(fileType string) []string {
+	var extensions map[string]bool
+	switch fileType {
+	case "picture":
+		extensions = pictureExtensions
+	case "audio":
+		extensions = audioExtensions
+	default:
+		return nil
+	}
+	response := make([]string, 0, len(extensions))
+	for ext := range extensions {
+		response = append(response, ext)
+	}
+	sort.Strings(response)
+	return response
+}
+
+// ErrFileAlreadyProcessed reports a file whose processed copy was already saved by a reprocess or a previous lease owner
+var ErrFileAlreadyProcessed = errors.New("the file is already processed")
+
 type FileRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, ossFileId, path string, noteID uuid.UUID) (*File, error)
 	Update() error
 	HardDeleteFiles(ctx context.Context, tx *sql.Tx, files *[]File) error
 	ListFilesByNoteId(ctx context.Context, noteId uuid.UUID) (*[]File, error)
 	ListFilesByNotesIds(ctx context.Context, noteId []uuid.UUID) (*[]File, error)
+	LockFilesByNotesIds(ctx context.Context, tx *sql.Tx, noteIds []uuid.UUID) (*[]File, error)
 	Move() error
-	Process(ctx context.Context, tx *sql.Tx, ossFileId string) error
+	Process(ctx context.Context, tx *sql.Tx, ossFileId string) (*File, error)
+	ListFilesForReprocessing(ctx context.Context, filter FileFilter, cursor uuid.UUID, limit int32) (*[]File, error)
+	CountFilesForReprocessing(ctx context.Context, filter FileFilter) (int64, error)
+	Reprocess(ctx context.Context, tx *sql.Tx, file *File) (*File, error)
+	DiscardObjects(ctx context.Context, tx *sql.Tx, objectNames []string) error
+	IncludeRenditions(ctx context.Context, files []File) error
 }
 
 type fileCloudRepository struct {
-	FileDatabaseDs       FileDatabaseDs
-	ObjectStorageService oss.ObjectStorageService
-	Config               *config.Configuration
+	FileDatabaseDs           FileDatabaseDs
+	ObjectDeletionDatabaseDs ObjectDeletionDatabaseDs
+	ObjectStorageService     oss.ObjectStorageService
+	Config                   *config.Configuration
 }
 
-func NewFileRepository(fileDatabaseDs FileDatabaseDs, objectStorageService oss.ObjectStorageService, cfg *config.Configuration) FileRepository {
+func NewFileRepository(fileDatabaseDs FileDatabaseDs, objectDeletionDatabaseDs ObjectDeletionDatabaseDs, objectStorageService oss.ObjectStorageService, cfg *config.Configuration) FileRepository {
 	return &fileCloudRepository{
-		FileDatabaseDs:       fileDatabaseDs,
-		ObjectStorageService: objectStorageService,
-		Config:               cfg,
+		FileDatabaseDs:           fileDatabaseDs,
+		ObjectDeletionDatabaseDs: objectDeletionDatabaseDs,
+		ObjectStorageService:     objectStorageService,
+		Config:                   cfg,
 	}
 }
 
@@ -94,44 +126,38 @@ func (r *fileCloudRepository) Create(ctx context.Context, tx *sql.Tx, ossFileId,
 
 func (r *fileCloudRepository) Update() error { return nil }
 
+// HardDeleteFiles records the objects of the files in the outbox of the object storage service deletions.
+// They are removed by the object deletion worker once the transaction is committed, so a rolled back transaction keeps them.
 func (r *fileCloudRepository) HardDeleteFiles(ctx context.Context, tx *sql.Tx, files *[]File) error {
-	processedFilesNames := make([]string, 0, len(*files))
-	originalFilesNames := make([]string, 0, len(*files))
-	// Get the processed and original files names
+	objectNames := make([]string, 0, 2*len(*files))
 	for _, file := range *files {
-		processedFilesNames = append(processedFilesNames, file.ProcessedFile)
-		originalFilesNames = append(originalFilesNames, file.OriginalFile)
-	}
-	// Delete the files from the cloud
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(processedFilesNames))
-	for i, _ := range processedFilesNames {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			processedFile := processedFilesNames[i]
-			originalFile := originalFilesNames[i]
-			err := r.ObjectStorageService.RemoveObject(ctx, r.Config.ObjectStorageServiceBucket, processedFile)
-			if err != nil {
-				errChan <- errors.New(fmt.Sprintf("error removing processed file %s from the cloud", processedFile))
-				return
-			}
-			err = r.ObjectStorageService.RemoveObject(ctx, r.Config.ObjectStorageServiceBucket, originalFile)
-			if err != nil {
-				errChan <- errors.New(fmt.Sprintf("error removing original file %s from the cloud", originalFile))
-				return
-			}
-		}()
-	}
-
-	wg.Wait()
-	close(errChan)
-
-	if len(errChan) > 0 {
-		return errors.New("error removing files from the cloud")
+		// A file that was never processed has no processed copy to remove
+		if file.ProcessedFile != "" {
+			objectNames = append(objectNames, file.ProcessedFile)
+		}
+		objectNames = append(objectNames, file.OriginalFile)
+		for _, rendition := range file.Renditions {
+			objectNames = append(objectNames, rendition.ObjectName)
+		}
 	}
+	if len(objectNames) == 0 {
+		return nil
+	}
+	return r.ObjectDeletionDatabaseDs.CreateObjectDeletions(ctx, tx, r.Config.ObjectStorageServiceBucket, objectNames)
+}
 
-	return nil
+// LockFilesByNotesIds reads and locks the files of the notes in the transaction, so they can't change
+// between the read and the deletion of the notes
+func (r *fileCloudRepository) LockFilesByNotesIds(ctx context.Context, tx *sql.Tx, noteIds []uuid.UUID) (*[]File, error) {
+	// Fetch and lock the files from the database
+	files, err := r.FileDatabaseDs.LockFilesByNotesIds(ctx, tx, noteIds)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.IncludeRenditions(ctx, *files); err != nil {
+		return nil, err
+	}
+	return files, nil
 }
 
 func (r *fileCloudRepository) ListFilesByNotesIds(ctx context.Context, noteId []uuid.UUID) (*[]File, error) {
@@ -140,6 +166,9 @@ func (r *fileCloudRepository) ListFilesByNotesIds(ctx context.Context, noteId []
 	if err != nil {
 		return nil, err
 	}
+	if err := r.IncludeRenditions(ctx, *files); err != nil {
+		return nil, err
+	}
 	return files, nil
 }
 
@@ -149,113 +178,245 @@ func (r *fileCloudRepository) ListFilesByNoteId(ctx context.Context, noteId uuid
 	if err != nil {
 		return nil, err
 	}
+	if err := r.IncludeRenditions(ctx, *files); err != nil {
+		return nil, err
+	}
 	return files, nil
 }
 
+// IncludeRenditions fetches the renditions of the files in a single query and includes them in the files
+func (r *fileCloudRepository) IncludeRenditions(ctx context.Context, files []File) error {
+	if len(files) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(files))
+	for _, file := range files {
+		ids = append(ids, file.Id)
+	}
+	renditions, err := r.FileDatabaseDs.ListFileRenditionsByFileIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	// Group the renditions by file id
+	renditionMap := make(map[uuid.UUID][]FileRendition)
+	for _, rendition := range *renditions {
+		renditionMap[rendition.FileId] = append(renditionMap[rendition.FileId], rendition)
+	}
+	for i := range files {
+		files[i].Renditions = renditionMap[files[i].Id]
+	}
+	return nil
+}
+
 func (r *fileCloudRepository) Move() error { return nil }
 
-func (r *fileCloudRepository) Process(ctx context.Context, tx *sql.Tx, ossFileId string) error {
-	// Declare the processed file id
-	var processedFileId string
+func (r *fileCloudRepository) Process(ctx context.Context, tx *sql.Tx, ossFileId string) (*File, error) {
+	processed, err := r.processObject(ctx, ossFileId)
+	if err != nil {
+		return nil, err
+	}
 
-	// Download the file from the cloud
-	path, err := r.ObjectStorageService.GetObject(ctx, r.Config.ObjectStorageServiceBucket, ossFileId)
+	// Update the file on the database
+	file, err := r.FileDatabaseDs.UpdateFileByOriginalId(ctx, tx, ossFileId, processed.name, processed.audio)
 	if err != nil {
-		return err
+		clogg.Error(ctx, "error updating file by original id", clogg.String("error", err.Error()))
+		// Only a file without a processed copy is updated, a reprocess or a previous lease owner already saved one
+		if _, ok := err.(*customerrors.RecordNotFound); ok {
+			err = ErrFileAlreadyProcessed
+		}
+		return nil, &UnsavedObjectsError{ObjectNames: processed.objectNames(), Err: err}
 	}
 
-	// Remove the file from tmp after the process
-	defer os.Remove(path)
+	if err := r.replaceRenditions(ctx, tx, file, processed.renditions); err != nil {
+		return nil, &UnsavedObjectsError{ObjectNames: processed.objectNames(), Err: err}
+	}
 
-	// Process the file based on the type
-	switch fileType(path) {
-	case "picture":
-		if path, err = CompressJpegImage(path); err != nil {
-			return err
-		}
-		processedFileId = fmt.Sprintf("processed-photos/%s", filepath.Base(path))
-	case "audio":
-		if path, err = CompressAudio(path); err != nil {
-			return err
+	return file, nil
+}
+
+// DiscardObjects records the uploaded objects that no file references in the outbox of the object storage service deletions
+func (r *fileCloudRepository) DiscardObjects(ctx context.Context, tx *sql.Tx, objectNames []string) error {
+	if len(objectNames) == 0 {
+		return nil
+	}
+	return r.ObjectDeletionDatabaseDs.CreateObjectDeletions(ctx, tx, r.Config.ObjectStorageServiceBucket, objectNames)
+}
+
+func (r *fileCloudRepository) ListFilesForReprocessing(ctx context.Context, filter FileFilter, cursor uuid.UUID, limit int32) (*[]File, error) {
+	return r.FileDatabaseDs.ListFilesForReprocessing(ctx, filter, cursor, limit)
+}
+
+func (r *fileCloudRepository) CountFilesForReprocessing(ctx context.Context, filter FileFilter) (int64, error) {
+	return r.FileDatabaseDs.CountFilesForReprocessing(ctx, filter)
+}
+
+// Reprocess processes the original file again and replaces the processed copy of the file.
+// The replaced copy is recorded in the outbox of the object storage service deletions, so it is kept if the transaction is rolled back.
+func (r *fileCloudRepository) Reprocess(ctx context.Context, tx *sql.Tx, file *File) (*File, error) {
+	processed, err := r.processObject(ctx, file.OriginalFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the processed file on the database
+	res, err := r.FileDatabaseDs.ReplaceProcessedFile(ctx, tx, file.Id, processed.name, processed.audio)
+	if err != nil {
+		clogg.Error(ctx, "error replacing processed file", clogg.String("error", err.Error()))
+		return nil, &UnsavedObjectsError{ObjectNames: processed.objectNames(), Err: err}
+	}
+
+	if file.ProcessedFile != "" && file.ProcessedFile != processed.name {
+		if err := r.ObjectDeletionDatabaseDs.CreateObjectDeletions(ctx, tx, r.Config.ObjectStorageServiceBucket, []string{file.ProcessedFile}); err != nil {
+			return nil, &UnsavedObjectsError{ObjectNames: processed.objectNames(), Err: err}
 		}
-		processedFileId = fmt.Sprintf("processed-audio/%s", filepath.Base(path))
-	default:
-		clogg.Error(ctx, "file not supported")
-		return fmt.Errorf("the file is not supported")
 	}
 
-	// Remove the processed file from tmp after the upload
-	defer os.Remove(path)
+	if err := r.replaceRenditions(ctx, tx, res, processed.renditions); err != nil {
+		return nil, &UnsavedObjectsError{ObjectNames: processed.objectNames(), Err: err}
+	}
 
-	// Upload the processed file to the cloud
-	if err := r.ObjectStorageService.PutObject(ctx, r.Config.ObjectStorageServiceBucket, processedFileId, path); err != nil {
-		clogg.Error(ctx, "error uploading processed file to the cloud", clogg.String("error", err.Error()))
+	return res, nil
+}
+
+// replaceRenditions replaces the renditions of the file on the database and includes the new ones in the file.
+// The replaced renditions are recorded in the outbox of the object storage service deletions.
+func (r *fileCloudRepository) replaceRenditions(ctx context.Context, tx *sql.Tx, file *File, renditions []FileRendition) error {
+	replaced, created, err := r.FileDatabaseDs.ReplaceFileRenditions(ctx, tx, file.Id, renditions)
+	if err != nil {
+		clogg.Error(ctx, "error replacing file renditions", clogg.String("error", err.Error()))
 		return err
 	}
 
-	// Update the file on the database
-	if _, err := r.FileDatabaseDs.UpdateFileByOriginalId(ctx, tx, ossFileId, processedFileId); err != nil {
-		clogg.Error(ctx, "error updating file by original id", clogg.String("error", err.Error()))
-		return err
+	objectNames := make([]string, 0, len(*replaced))
+	for _, rendition := range *replaced {
+		objectNames = append(objectNames, rendition.ObjectName)
+	}
+	if len(objectNames) > 0 {
+		if err := r.ObjectDeletionDatabaseDs.CreateObjectDeletions(ctx, tx, r.Config.ObjectStorageServiceBucket, objectNames); err != nil {
+			return err
+		}
 	}
 
+	file.Renditions = *created
 	return nil
 }
 
-// CompressAudio compresses the audio file using ffmpeg
-func CompressAudio(path string) (string, error) {
-	// Define the output path for the compressed audio
-	id := uuid.New()
-	ext := filepath.Ext(path)
-	outputPath := fmt.Sprintf("/tmp/%s%s", id, ext)
-
-	// Compress the audio using ffmpeg
-	cmd := exec.Command("ffmpeg", "-i", path, "-b:a", "128k", outputPath)
+// processedObject is the processed copy of a file uploaded to the cloud
+type processedObject struct {
+	// name of the processed copy
+	name string
+	// renditions of the processed image
+	renditions []FileRendition
+	// audio is the metadata of the processed audio file
+	audio *AudioMetadata
+}
 
-	// Run the command
-	if err := cmd.Run(); err != nil {
-		clogg.Error(context.Background(), "error compressing audio", clogg.String("error", err.Error()))
-		return "", fmt.Errorf("error compressing audio: %v", err)
+// objectNames returns the uploaded processed copy and its renditions
+func (p *processedObject) objectNames() []string {
+	objectNames := make([]string, 0, 1+len(p.renditions))
+	objectNames = append(objectNames, p.name)
+	for _, rendition := range p.renditions {
+		objectNames = append(objectNames, rendition.ObjectName)
 	}
-
-	return outputPath, nil
+	return objectNames
 }
 
-// CompressJPEGImage compresses the image file using "image/jpeg"
-func CompressJpegImage(path string) (string, error) {
-	// Define the output path for the compressed audio
-	id := uuid.New()
-	ext := filepath.Ext(path)
-	outputPath := fmt.Sprintf("/tmp/%s%s", id, ext)
+// processObject downloads the original file, compresses it based on the type and uploads the processed copy.
+// The renditions of the images are uploaded next to the processed copy, the metadata of the audio files is read from it.
+func (r *fileCloudRepository) processObject(ctx context.Context, ossFileId string) (*processedObject, error) {
+	processed := &processedObject{}
 
-	// Open the input file
-	file, err := os.Open(path)
+	// Download the file from the cloud
+	path, err := r.ObjectStorageService.GetObject(ctx, r.Config.ObjectStorageServiceBucket, ossFileId)
 	if err != nil {
-		return "", fmt.Errorf("failed to open input file: %v", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	// Decode the image
-	img, err := jpeg.Decode(file)
+	// Remove the file from tmp after the process
+	defer os.Remove(path)
+
+	// The images are told apart by their content, so a misnamed image is processed with the encoder of its format
+	imageFormat, err := imageFormatOf(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode JPEG image: %v", err)
+		return nil, err
 	}
 
-	// Create the output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create output file: %v", err)
+	// Process the file based on the type
+	switch {
+	case imageFormat != "":
+		if path, err = CompressImage(path); err != nil {
+			return nil, err
+		}
+		processed.name = fmt.Sprintf("processed-photos/%s", filepath.Base(path))
+	case fileType(path) == "audio":
+		if path, err = CompressAudio(path, r.Config.AudioCodec, r.Config.AudioBitrate); err != nil {
+			return nil, err
+		}
+		processed.name = fmt.Sprintf("processed-audio/%s", filepath.Base(path))
+	default:
+		clogg.Error(ctx, "file not supported")
+		return nil, fmt.Errorf("the file is not supported")
+	}
+
+	// Remove the processed file from tmp after the upload
+	defer os.Remove(path)
+
+	// The metadata is read from the processed audio, which is the one played by the clients
+	if imageFormat == "" {
+		if processed.audio, err = ReadAudioMetadata(path); err != nil {
+			clogg.Error(ctx, "error reading audio metadata", clogg.String("error", err.Error()))
+			return nil, err
+		}
 	}
-	defer outFile.Close()
 
-	// Encode the image with the specified quality
-	options := &jpeg.Options{Quality: 50}
-	if err := jpeg.Encode(outFile, img, options); err != nil {
-		// Remove the output file if the encoding fails
-		os.Remove(outputPath)
+	// Upload the processed file to the cloud
+	if err := r.ObjectStorageService.PutObject(ctx, r.Config.ObjectStorageServiceBucket, processed.name, path); err != nil {
+		clogg.Error(ctx, "error uploading processed file to the cloud", clogg.String("error", err.Error()))
+		return nil, err
+	}
 
-		return "", fmt.Errorf("failed to encode JPEG image: %v", err)
+	// Only the images have renditions
+	if imageFormat != "" {
+		if processed.renditions, err = r.uploadRenditions(ctx, path, processed.name); err != nil {
+			// The processed copy and the renditions uploaded so far are referenced by no file
+			return nil, &UnsavedObjectsError{ObjectNames: processed.objectNames(), Err: err}
+		}
 	}
 
-	return outputPath, nil
+	return processed, nil
+}
+
+// uploadRenditions resizes the processed image to the configured sizes and uploads the copies named after the processed copy.
+// On a failed upload it returns the renditions uploaded so far along the error, so they can be discarded.
+func (r *fileCloudRepository) uploadRenditions(ctx context.Context, path string, processedFileId string) ([]FileRendition, error) {
+	images, err := CreateImageRenditions(path, r.Config.ImageRenditionSizes)
+	if err != nil {
+		clogg.Error(ctx, "error creating image renditions", clogg.String("error", err.Error()))
+		return nil, err
+	}
+
+	// Remove the renditions from tmp after the upload
+	defer func() {
+		for _, image := range images {
+			os.Remove(image.Path)
+		}
+	}()
+
+	ext := filepath.Ext(processedFileId)
+	renditions := make([]FileRendition, 0, len(images))
+	for _, image := range images {
+		objectName := fmt.Sprintf("%s-%d%s", strings.TrimSuffix(processedFileId, ext), image.Size, ext)
+		if err := r.ObjectStorageService.PutObject(ctx, r.Config.ObjectStorageServiceBucket, objectName, image.Path); err != nil {
+			clogg.Error(ctx, "error uploading image rendition to the cloud", clogg.String("error", err.Error()))
+			return renditions, err
+		}
+		renditions = append(renditions, FileRendition{
+			Size:       int32(image.Size),
+			ObjectName: objectName,
+			Width:      int32(image.Width),
+			Height:     int32(image.Height),
+		})
+	}
+	return renditions, nil
 }
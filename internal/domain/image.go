@@ -0,0 +1,445 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Formats of the images, they are detected from the magic bytes of the files
+const (
+	ImageJpeg = "jpeg"
+	ImagePng  = "png"
+	ImageGif  = "gif"
+	ImageWebp = "webp"
+	ImageHeic = "heic"
+)
+
+// Quality of the lossy encoders
+const (
+	jpegQuality = 50
+	webpQuality = 50
+	// ffmpegJpegQuality is the qscale of the ffmpeg jpeg encoder, from 2 (best) to 31 (worst)
+	ffmpegJpegQuality = 5
+)
+
+// maxImagePixels caps the size of the decoded images, a small file can declare a size that takes gigabytes to decode
+const maxImagePixels = 50_000_000
+
+// imageFormat is how the images of a format are processed
+type imageFormat struct {
+	// extension of the processed image
+	extension string
+	// compress writes the processed image to the output path
+	compress func(path string, outputPath string) error
+}
+
+// imageFormats are the supported formats of the images.
+// The HEIC images are processed to JPEG, so every browser can display them.
+var imageFormats = map[string]imageFormat{
+	ImageJpeg: {extension: ".jpg", compress: compressJpeg},
+	ImagePng:  {extension: ".png", compress: compressPng},
+	ImageGif:  {extension: ".gif", compress: compressGif},
+	ImageWebp: {extension: ".webp", compress: compressWebp},
+	ImageHeic: {extension: ".jpg", compress: compressHeic},
+}
+
+// heicBrands are the brands of the ftyp box of the HEIC and HEIF images
+var heicBrands = map[string]bool{
+	"heic": true,
+	"heix": true,
+	"hevc": true,
+	"hevx": true,
+	"heim": true,
+	"heis": true,
+	"mif1": true,
+	"msf1": true,
+}
+
+// DetectImageFormat returns the format of the image from its first bytes, an empty format when it isn't a supported image
+func DetectImageFormat(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, []byte{0xff, 0xd8, 0xff}):
+		return ImageJpeg
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")):
+		return ImagePng
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return ImageGif
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return ImageWebp
+	case len(header) >= 12 && string(header[4:8]) == "ftyp" && heicBrands[string(header[8:12])]:
+		return ImageHeic
+	}
+	return ""
+}
+
+// imageFormatOf returns the format of the image file, an empty format when it isn't a supported image
+func imageFormatOf(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	header := make([]byte, 16)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return DetectImageFormat(header[:n]), nil
+}
+
+// CompressImage compresses the image with the encoder of its format, detected from the content of the file.
+// The transparency is kept and the EXIF orientation is applied to the pixels, so the image is displayed upright without it.
+// It returns the path of the compressed image.
+func CompressImage(path string) (string, error) {
+	formatName, err := imageFormatOf(path)
+	if err != nil {
+		return "", err
+	}
+	format, ok := imageFormats[formatName]
+	if !ok {
+		return "", errors.New("the image format is not supported")
+	}
+
+	// Define the output path for the compressed image
+	outputPath := fmt.Sprintf("/tmp/%s%s", uuid.New(), format.extension)
+	if err := format.compress(path, outputPath); err != nil {
+		// Remove the output file if the compression fails
+		os.Remove(outputPath)
+		return "", fmt.Errorf("error compressing %s image: %v", formatName, err)
+	}
+	return outputPath, nil
+}
+
+// compressJpeg encodes the upright image with the jpeg quality
+func compressJpeg(path string, outputPath string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := checkImagePixels(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode JPEG image: %v", err)
+	}
+	return writeImage(outputPath, func(w io.Writer) error {
+		return jpeg.Encode(w, orient(img, exifOrientation(jpegExif(data))), &jpeg.Options{Quality: jpegQuality})
+	})
+}
+
+// compressPng encodes the upright image with the best compression, the alpha channel is kept
+func compressPng(path string, outputPath string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := checkImagePixels(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode PNG image: %v", err)
+	}
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	return writeImage(outputPath, func(w io.Writer) error {
+		return encoder.Encode(w, orient(img, exifOrientation(pngExif(data))))
+	})
+}
+
+// compressGif encodes the frames again, the delays, the disposals and the transparent color of the palettes are kept
+func compressGif(path string, outputPath string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := checkImagePixels(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	img, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode GIF image: %v", err)
+	}
+	return writeImage(outputPath, func(w io.Writer) error {
+		return gif.EncodeAll(w, img)
+	})
+}
+
+// compressWebp encodes the upright image with ffmpeg, the alpha channel is kept by the libwebp encoder
+func compressWebp(path string, outputPath string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	width, height, err := webpSize(data)
+	if err != nil {
+		return err
+	}
+	if err := checkImageSize(width, height); err != nil {
+		return err
+	}
+	exif, animated := webpChunks(data)
+	// The webp decoder of ffmpeg doesn't decode the animations
+	if animated {
+		return errors.New("the animated WebP images are not supported")
+	}
+	args := []string{"-i", path}
+	if filter := orientationFilter(exifOrientation(exif)); filter != "" {
+		args = append(args, "-vf", filter)
+	}
+	args = append(args, "-c:v", "libwebp", "-quality", fmt.Sprint(webpQuality), "-frames:v", "1", outputPath)
+	return runFfmpeg(args...)
+}
+
+// compressHeic converts the image to JPEG with ffmpeg, which applies the rotation and the mirroring of the HEIC container.
+// The HEIC images are only demuxed from ffmpeg 7.1 on, see CheckFfmpeg.
+func compressHeic(path string, outputPath string) error {
+	return runFfmpeg("-i", path, "-frames:v", "1", "-q:v", fmt.Sprint(ffmpegJpegQuality), outputPath)
+}
+
+// checkImagePixels reads the size of the image from its header, so an image over the cap is rejected before it is decoded
+func checkImagePixels(r io.Reader) error {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return fmt.Errorf("failed to decode image config: %v", err)
+	}
+	return checkImageSize(config.Width, config.Height)
+}
+
+// checkImageSize rejects the images over the cap of pixels
+func checkImageSize(width int, height int) error {
+	if int64(width)*int64(height) > maxImagePixels {
+		return fmt.Errorf("the image of %dx%d pixels is too large", width, height)
+	}
+	return nil
+}
+
+// ffmpegVersion matches the release of the first line of ffmpeg -version, the builds from git have no release
+var ffmpegVersion = regexp.MustCompile(`^ffmpeg version n?(\d+)\.(\d+)`)
+
+// CheckFfmpeg returns an error when ffmpeg is missing or older than 7.1, the first release that demuxes the HEIC images.
+// The builds from git don't tell their release, so they are accepted.
+func CheckFfmpeg() error {
+	output, err := exec.Command("ffmpeg", "-version").Output()
+	if err != nil {
+		return fmt.Errorf("ffmpeg is not available: %v", err)
+	}
+	match := ffmpegVersion.FindSubmatch(output)
+	if match == nil {
+		return nil
+	}
+	major, _ := strconv.Atoi(string(match[1]))
+	minor, _ := strconv.Atoi(string(match[2]))
+	if major < 7 || major == 7 && minor < 1 {
+		return fmt.Errorf("ffmpeg %d.%d is older than 7.1, the HEIC images can't be processed", major, minor)
+	}
+	return nil
+}
+
+// runFfmpeg runs ffmpeg with the arguments and returns its output on failure
+func runFfmpeg(args ...string) error {
+	cmd := exec.Command("ffmpeg", append([]string{"-y", "-loglevel", "error"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %v: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// writeImage creates the output file and encodes the image to it
+func writeImage(outputPath string, encode func(w io.Writer) error) error {
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer outFile.Close()
+
+	if err := encode(outFile); err != nil {
+		return fmt.Errorf("failed to encode image: %v", err)
+	}
+	return outFile.Close()
+}
+
+// jpegExif returns the EXIF data of the APP1 segment of the JPEG image
+func jpegExif(data []byte) []byte {
+	// Skip the start of image marker
+	offset := 2
+	for offset+4 <= len(data) && data[offset] == 0xff {
+		marker := data[offset+1]
+		// The image data follows the start of scan, there is no EXIF data after it
+		if marker == 0xda {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		end := offset + 2 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+		segment := data[offset+4 : end]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		offset = end
+	}
+	return nil
+}
+
+// pngExif returns the EXIF data of the eXIf chunk of the PNG image
+func pngExif(data []byte) []byte {
+	// Skip the signature
+	offset := 8
+	for offset+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		chunk := string(data[offset+4 : offset+8])
+		end := offset + 8 + length
+		if length < 0 || end+4 > len(data) {
+			return nil
+		}
+		if chunk == "eXIf" {
+			return data[offset+8 : end]
+		}
+		if chunk == "IEND" {
+			return nil
+		}
+		// Skip the data and the crc
+		offset = end + 4
+	}
+	return nil
+}
+
+// webpChunks returns the EXIF data of the EXIF chunk of the WebP image and if it is animated
+func webpChunks(data []byte) (exif []byte, animated bool) {
+	// Skip the RIFF header
+	offset := 12
+	for offset+8 <= len(data) {
+		chunk := string(data[offset : offset+4])
+		length := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		end := offset + 8 + length
+		if length < 0 || end > len(data) {
+			break
+		}
+		switch chunk {
+		case "EXIF":
+			// Some encoders keep the header of the JPEG APP1 segment
+			exif = bytes.TrimPrefix(data[offset+8:end], []byte("Exif\x00\x00"))
+		case "ANIM":
+			animated = true
+		}
+		// The chunks are padded to an even size
+		offset = end + length%2
+	}
+	return exif, animated
+}
+
+// exifOrientation returns the orientation tag of the first IFD of the EXIF data, 1 (upright) when there is none
+func exifOrientation(exif []byte) int {
+	if len(exif) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(exif[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	if order.Uint16(exif[2:]) != 42 {
+		return 1
+	}
+	ifd := int(order.Uint32(exif[4:]))
+	if ifd < 8 || ifd+2 > len(exif) {
+		return 1
+	}
+	entries := int(order.Uint16(exif[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(exif) {
+			return 1
+		}
+		// The orientation is a SHORT stored in the value of the entry
+		if order.Uint16(exif[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(exif[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// orient returns the upright image of the EXIF orientation, the mirrored orientations are flipped back too
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	// The orientations from 5 to 8 swap the width and the height
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	// The non-premultiplied colors keep the transparent pixels as they are
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)))
+		}
+	}
+	return dst
+}
+
+// orientationFilter returns the ffmpeg filter that applies the EXIF orientation, an empty filter for the upright images
+func orientationFilter(orientation int) string {
+	switch orientation {
+	case 2:
+		return "hflip"
+	case 3:
+		return "hflip,vflip"
+	case 4:
+		return "vflip"
+	case 5:
+		return "transpose=cclock_flip"
+	case 6:
+		return "transpose=clock"
+	case 7:
+		return "transpose=clock_flip"
+	case 8:
+		return "transpose=cclock"
+	}
+	return ""
+}
@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// ImageRendition is a resized copy of an image, its longest side is the size
+type ImageRendition struct {
+	Size   int
+	Path   string
+	Width  int
+	Height int
+}
+
+// CreateImageRenditions writes a resized copy of the processed image for every size smaller than its longest side.
+// The copies keep the format of the image, the caller removes them once they are uploaded.
+func CreateImageRenditions(path string, sizes []int) ([]ImageRendition, error) {
+	formatName, err := imageFormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	resize, width, height, err := imageResizer(path, formatName)
+	if err != nil {
+		return nil, err
+	}
+
+	renditions := make([]ImageRendition, 0, len(sizes))
+	for _, size := range sizes {
+		// The image isn't enlarged, the processed image is used instead
+		if size >= max(width, height) {
+			continue
+		}
+		w, h := fitSize(width, height, size)
+		outputPath := fmt.Sprintf("/tmp/%s%s", uuid.New(), filepath.Ext(path))
+		if err := resize(outputPath, w, h); err != nil {
+			// Remove the renditions written so far
+			os.Remove(outputPath)
+			for _, rendition := range renditions {
+				os.Remove(rendition.Path)
+			}
+			return nil, fmt.Errorf("error resizing %s image to %d: %v", formatName, size, err)
+		}
+		renditions = append(renditions, ImageRendition{Size: size, Path: outputPath, Width: w, Height: h})
+	}
+	return renditions, nil
+}
+
+// imageResizer decodes the image once and returns the func that writes it resized, along its size
+func imageResizer(path string, formatName string) (resize func(outputPath string, w, h int) error, width int, height int, err error) {
+	switch formatName {
+	case ImageJpeg, ImagePng:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if err := checkImagePixels(bytes.NewReader(data)); err != nil {
+			return nil, 0, 0, err
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to decode image: %v", err)
+		}
+		resize = func(outputPath string, w, h int) error {
+			return writeImage(outputPath, func(out io.Writer) error {
+				if formatName == ImageJpeg {
+					return jpeg.Encode(out, resizeBox(img, w, h), &jpeg.Options{Quality: jpegQuality})
+				}
+				encoder := png.Encoder{CompressionLevel: png.BestCompression}
+				return encoder.Encode(out, resizeBox(img, w, h))
+			})
+		}
+		return resize, img.Bounds().Dx(), img.Bounds().Dy(), nil
+	case ImageGif:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if err := checkImagePixels(bytes.NewReader(data)); err != nil {
+			return nil, 0, 0, err
+		}
+		img, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to decode GIF image: %v", err)
+		}
+		resize = func(outputPath string, w, h int) error {
+			return writeImage(outputPath, func(out io.Writer) error {
+				return gif.EncodeAll(out, resizeGif(img, w, h))
+			})
+		}
+		return resize, img.Config.Width, img.Config.Height, nil
+	case ImageWebp:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		width, height, err := webpSize(data)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if err := checkImageSize(width, height); err != nil {
+			return nil, 0, 0, err
+		}
+		resize = func(outputPath string, w, h int) error {
+			return runFfmpeg("-i", path, "-vf", fmt.Sprintf("scale=%d:%d", w, h), "-c:v", "libwebp", "-quality", fmt.Sprint(webpQuality), "-frames:v", "1", outputPath)
+		}
+		return resize, width, height, nil
+	}
+	// The HEIC images are processed to JPEG, so there are no processed HEIC images to resize
+	return nil, 0, 0, errors.New("the image format has no renditions")
+}
+
+// fitSize returns the size of the image whose longest side is the size, keeping the aspect ratio
+func fitSize(width int, height int, size int) (int, int) {
+	if width >= height {
+		return size, max(1, (height*size+width/2)/width)
+	}
+	return max(1, (width*size+height/2)/height), size
+}
+
+// resizeBox downscales the image averaging the pixels covered by every pixel of the resized image.
+// The colors are averaged premultiplied, so the transparent pixels don't darken the edges.
+func resizeBox(img image.Image, w int, h int) *image.RGBA {
+	bounds := img.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		y0 := dy * sh / h
+		y1 := max((dy+1)*sh/h, y0+1)
+		for dx := 0; dx < w; dx++ {
+			x0 := dx * sw / w
+			x1 := max((dx+1)*sw/w, x0+1)
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					cr, cg, cb, ca := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(dx, dy, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// resizeGif downscales every frame picking the nearest pixel, so the palettes and the transparent color are kept
+func resizeGif(img *gif.GIF, w int, h int) *gif.GIF {
+	sw, sh := img.Config.Width, img.Config.Height
+	res := &gif.GIF{
+		Delay:           img.Delay,
+		LoopCount:       img.LoopCount,
+		Disposal:        img.Disposal,
+		BackgroundIndex: img.BackgroundIndex,
+		Config:          image.Config{ColorModel: img.Config.ColorModel, Width: w, Height: h},
+		Image:           make([]*image.Paletted, 0, len(img.Image)),
+	}
+	for _, frame := range img.Image {
+		// Scale the position of the frame in the image along its pixels
+		r := frame.Bounds()
+		x0, y0 := r.Min.X*w/sw, r.Min.Y*h/sh
+		dr := image.Rect(x0, y0, max(r.Max.X*w/sw, x0+1), max(r.Max.Y*h/sh, y0+1))
+		dst := image.NewPaletted(dr, frame.Palette)
+		for y := dr.Min.Y; y < dr.Max.Y; y++ {
+			sy := min(max(y*sh/h, r.Min.Y), r.Max.Y-1)
+			for x := dr.Min.X; x < dr.Max.X; x++ {
+				sx := min(max(x*sw/w, r.Min.X), r.Max.X-1)
+				dst.SetColorIndex(x, y, frame.ColorIndexAt(sx, sy))
+			}
+		}
+		res.Image = append(res.Image, dst)
+	}
+	return res
+}
+
+// webpSize returns the size of the WebP image from the header of its first chunk
+func webpSize(data []byte) (int, int, error) {
+	if len(data) < 30 {
+		return 0, 0, errors.New("the WebP image is too short")
+	}
+	chunk := data[20:]
+	switch string(data[12:16]) {
+	case "VP8X":
+		// The canvas size minus one in 24 bits
+		width := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		height := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return width + 1, height + 1, nil
+	case "VP8L":
+		// The size minus one in 14 bits each, after the signature
+		if chunk[0] != 0x2f {
+			return 0, 0, errors.New("invalid lossless WebP signature")
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, nil
+	case "VP8 ":
+		// The size in 14 bits each, after the frame tag and the start code
+		if !bytes.Equal(chunk[3:6], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0, errors.New("invalid lossy WebP start code")
+		}
+		return int(binary.LittleEndian.Uint16(chunk[6:]) & 0x3fff), int(binary.LittleEndian.Uint16(chunk[8:]) & 0x3fff), nil
+	}
+	return 0, 0, errors.New("invalid WebP image")
+}
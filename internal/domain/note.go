@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,10 +13,159 @@ import (
 type Note struct {
 	Id           uuid.UUID `json:"id"`
 	UserId       uuid.UUID `json:"user_id"`
+	NotebookId   *uuid.UUID `json:"notebook_id"`
 	Title        string    `json:"title"`
 	Content      string    `json:"content"`
 	Files        []*File   `json:"files"`
+	Tags         []*Tag    `json:"tags"`
 	CreateTime   time.Time `json:"create_time"`
 	UpdateTime   time.Time `json:"update_time"`
 	DeleteTime   time.Time `json:"delete_time"`
+	// The pinned notes are listed first, a zero time means the note is not pinned
+	PinnedTime   time.Time `json:"pinned_time"`
+	// The archived notes are left out of the listing, a zero time means the note is not archived
+	ArchiveTime  time.Time `json:"archive_time"`
+	Version      int32     `json:"version"`
+	// ChangeSeq and ChangeXid are the position of the last change of the note in the order of the delta sync
+	ChangeSeq    int64     `json:"-"`
+	ChangeXid    int64     `json:"-"`
+}
+
+// NoteFilter narrows the notes returned by the listings, a nil id or a zero time disables the filter.
+// The after times are inclusive and the before times are exclusive.
+type NoteFilter struct {
+	TagId         uuid.UUID
+	NotebookId    uuid.UUID
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	// IncludeArchived lists the archived notes along the others
+	IncludeArchived bool
+}
+
+// Keys the note listing can be sorted by
+const (
+	NoteSortCreateTime = "create_time"
+	NoteSortUpdateTime = "update_time"
+	NoteSortTitle      = "title"
+)
+
+// NoteSort is the order of the note listing, the id breaks the ties in the same direction
+type NoteSort struct {
+	Key       string
+	Ascending bool
+}
+
+// DefaultNoteSort lists the last updated notes first
+var DefaultNoteSort = NoteSort{Key: NoteSortUpdateTime}
+
+// ParseNoteSort parses the sort key and the direction (asc or desc) of the note listing, empty values use the default
+func ParseNoteSort(key string, direction string) (NoteSort, error) {
+	sort := DefaultNoteSort
+	switch key {
+	case "":
+	case NoteSortCreateTime, NoteSortUpdateTime, NoteSortTitle:
+		sort.Key = key
+	default:
+		return sort, errors.New("invalid sort")
+	}
+	switch strings.ToLower(direction) {
+	case "", "desc":
+	case "asc":
+		sort.Ascending = true
+	default:
+		return sort, errors.New("invalid sort direction")
+	}
+	return sort, nil
+}
+
+// Cursor returns the position of the note in the listing sorted by s, the pinned notes go first
+func (s NoteSort) Cursor(note Note) NoteListCursor {
+	cursor := NoteListCursor{Id: note.Id, Pinned: !note.PinnedTime.IsZero(), Sort: s}
+	switch s.Key {
+	case NoteSortCreateTime:
+		cursor.Time = note.CreateTime
+	case NoteSortTitle:
+		cursor.Title = note.Title
+	default:
+		cursor.Time = note.UpdateTime
+	}
+	return cursor
+}
+
+// Sizes of the pages of the note listings
+const (
+	DefaultNotesPageSize = 10
+	MaxNotesPageSize     = 100
+)
+
+// MaxBulkNotes is the maximum number of notes of a bulk operation
+const MaxBulkNotes = 100
+
+// NoteListCursor is the position of the last note of a listing page.
+// The notes are ordered by the sort key and the id, so the notes sharing a key are not skipped.
+// Only the field of the sort key is set, the time or the title.
+// Sort is the sort of the listing, a cursor can't continue a listing with another sort.
+type NoteListCursor struct {
+	Time   time.Time
+	Title  string
+	Id     uuid.UUID
+	Pinned bool
+	Sort   NoteSort
+}
+
+// Encode returns the cursor as an opaque string for the clients
+func (c NoteListCursor) Encode() string {
+	// The title goes last because it may contain commas
+	direction := "desc"
+	if c.Sort.Ascending {
+		direction = "asc"
+	}
+	value := c.Time.UTC().Format(time.RFC3339Nano) + "," + c.Id.String() + "," + strconv.FormatBool(c.Pinned) + "," + c.Sort.Key + "," + direction + "," + c.Title
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// DecodeNoteListCursor parses a cursor returned by NoteListCursor.Encode
+func DecodeNoteListCursor(cursor string) (*NoteListCursor, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	parts := strings.SplitN(string(value), ",", 6)
+	if len(parts) != 6 {
+		return nil, errors.New("invalid cursor")
+	}
+	listTime, id, title := parts[0], parts[1], parts[5]
+	// The listings without a sort key are only listed in descending order
+	var sort NoteSort
+	switch parts[3] {
+	case "", NoteSortCreateTime, NoteSortUpdateTime, NoteSortTitle:
+		sort.Key = parts[3]
+	default:
+		return nil, errors.New("invalid cursor")
+	}
+	switch parts[4] {
+	case "desc":
+	case "asc":
+		if sort.Key == "" {
+			return nil, errors.New("invalid cursor")
+		}
+		sort.Ascending = true
+	default:
+		return nil, errors.New("invalid cursor")
+	}
+	pinned, err := strconv.ParseBool(parts[2])
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	parsedTime, err := time.Parse(time.RFC3339Nano, listTime)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &NoteListCursor{Time: parsedTime, Title: title, Id: parsedId, Pinned: pinned, Sort: sort}, nil
 }
@@ -9,12 +9,24 @@ import (
 )
 
 type NoteDatabaseDs interface {
-	ListNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]Note, error)
-	ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]Note, error)
+	ListNotesByUser(ctx context.Context, user_id uuid.UUID, sort NoteSort, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error)
+	ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error)
+	ListArchivedNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error)
+	ListExpiredTrashNotes(ctx context.Context, tx *sql.Tx, before time.Time, cursor *NoteListCursor, limit int32) (*[]Note, error)
+	HardDeleteTrashNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) (*[]Note, error)
+	SearchNotes(ctx context.Context, userId uuid.UUID, query string, includeTrash bool, cursor *NoteSearchCursor, limit int32) (*[]NoteSearchResult, error)
 	GetNote(ctx context.Context, id uuid.UUID) (*Note, error)
 	CreateNote(ctx context.Context, tx *sql.Tx, note *Note) (*Note, error)
 	UpdateNote(ctx context.Context, tx *sql.Tx, note *Note) (*Note, error)
-	RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*Note, error)
-	HardDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) error
-	SoftDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) error
+	MoveNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, notebookId *uuid.UUID) (*Note, error)
+	RemoveNotesFromNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID, notebookIds []uuid.UUID, trash bool) (*[]Note, error)
+	SetNotePinned(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, pinned bool) (*Note, error)
+	SetNoteArchived(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, archived bool) (*Note, error)
+	RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) (*Note, error)
+	HardDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error
+	SoftDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error
+	SoftDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error)
+	RestoreNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error)
+	HardDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error)
+	ListTrashNoteIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID, limit int32) ([]uuid.UUID, error)
 }
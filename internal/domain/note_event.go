@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of the note events
+const (
+	NoteCreated  = "created"
+	NoteUpdated  = "updated"
+	NoteDeleted  = "deleted"
+	NoteRestored = "restored"
+	// FileProcessed is published once the processed copy of a file of the note is uploaded
+	FileProcessed = "file_processed"
+	// NoteEventsReset is sent when the events after the last received one were trimmed from the stream,
+	// the client must fetch the notes again
+	NoteEventsReset = "reset"
+)
+
+// NoteEvent is a change of a note, it is published to the subscribers of the user
+type NoteEvent struct {
+	// Id is the id of the event in the stream of the user, it is used to resume the stream
+	Id     string    `json:"id"`
+	Type   string    `json:"type"`
+	NoteId uuid.UUID `json:"note_id"`
+	UserId uuid.UUID `json:"user_id"`
+	// Note is the note after the change, it is nil for the deleted notes
+	Note *Note `json:"note"`
+	// File is the processed file for the file_processed events
+	File *File `json:"file"`
+	// Hard is true if a deleted note was removed for good instead of moved to the trash
+	Hard bool      `json:"hard"`
+	Time time.Time `json:"time"`
+}
+
+// NewNoteEvent returns an event of the given type with the note after the change
+func NewNoteEvent(eventType string, note *Note) *NoteEvent {
+	return &NoteEvent{
+		Type:   eventType,
+		NoteId: note.Id,
+		UserId: note.UserId,
+		Note:   note,
+		Time:   time.Now().UTC(),
+	}
+}
+
+// NewFileProcessedEvent returns the event of a processed file of a note of the user
+func NewFileProcessedEvent(userId uuid.UUID, file *File) *NoteEvent {
+	return &NoteEvent{
+		Type:   FileProcessed,
+		NoteId: file.NoteId,
+		UserId: userId,
+		File:   file,
+		Time:   time.Now().UTC(),
+	}
+}
+
+// ParseNoteEventId splits an event id in the "<milliseconds>-<sequence>" format of the redis streams
+func ParseNoteEventId(id string) (uint64, uint64, bool) {
+	milliseconds, sequence, found := strings.Cut(id, "-")
+	ms, err := strconv.ParseUint(milliseconds, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !found {
+		return ms, 0, true
+	}
+	seq, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
+
+// NoteEventIdBefore reports whether the event a was published before the event b, both ids must be valid
+func NoteEventIdBefore(a string, b string) bool {
+	aMs, aSeq, _ := ParseNoteEventId(a)
+	bMs, bSeq, _ := ParseNoteEventId(b)
+	return aMs < bMs || (aMs == bMs && aSeq < bSeq)
+}
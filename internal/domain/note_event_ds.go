@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type NoteEventDs interface {
+	PublishNoteEvent(ctx context.Context, event *NoteEvent, streamLength int64) error
+	SubscribeNoteEvents(ctx context.Context, userId uuid.UUID) (<-chan NoteEvent, error)
+	ReadNoteEvents(ctx context.Context, userId uuid.UUID, lastEventId string) (<-chan NoteEvent, error)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type NoteEventRepository interface {
+	PublishNoteEvent(ctx context.Context, event *NoteEvent, streamLength int64) error
+	SubscribeNoteEvents(ctx context.Context, userId uuid.UUID) (<-chan NoteEvent, error)
+	ReadNoteEvents(ctx context.Context, userId uuid.UUID, lastEventId string) (<-chan NoteEvent, error)
+}
+
+type noteEventRepository struct {
+	NoteEventDs NoteEventDs
+}
+
+func NewNoteEventRepository(noteEventDs NoteEventDs) NoteEventRepository {
+	return &noteEventRepository{
+		NoteEventDs: noteEventDs,
+	}
+}
+
+func (r *noteEventRepository) PublishNoteEvent(ctx context.Context, event *NoteEvent, streamLength int64) error {
+	// Append the event to the stream of the user, keeping about the last streamLength events,
+	// and publish it to the subscribers of the user in every replica
+	return r.NoteEventDs.PublishNoteEvent(ctx, event, streamLength)
+}
+
+func (r *noteEventRepository) SubscribeNoteEvents(ctx context.Context, userId uuid.UUID) (<-chan NoteEvent, error) {
+	// The channel is closed when the context is done
+	return r.NoteEventDs.SubscribeNoteEvents(ctx, userId)
+}
+
+func (r *noteEventRepository) ReadNoteEvents(ctx context.Context, userId uuid.UUID, lastEventId string) (<-chan NoteEvent, error) {
+	// The events after lastEventId are read from the stream, only the new events are read for an empty id
+	return r.NoteEventDs.ReadNoteEvents(ctx, userId, lastEventId)
+}
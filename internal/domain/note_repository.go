@@ -6,17 +6,31 @@ import (
 	"time"
 
 	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/google/uuid"
 )
 
 type NoteRepository interface {
-	ListNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]Note, error)
-	ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]Note, error)
-	// GetNote(ctx context.Context, id uuid.UUID) (*Note, error)
+	ListNotesByUser(ctx context.Context, user_id uuid.UUID, sort NoteSort, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error)
+	ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error)
+	ListArchivedNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error)
+	ListExpiredTrashNotes(ctx context.Context, tx *sql.Tx, before time.Time, cursor *NoteListCursor, limit int32) (*[]Note, error)
+	HardDeleteTrashNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) (*[]Note, error)
+	SearchNotes(ctx context.Context, userId uuid.UUID, query string, includeTrash bool, cursor *NoteSearchCursor, limit int32) (*[]NoteSearchResult, error)
+	GetNote(ctx context.Context, id uuid.UUID) (*Note, error)
 	CreateNote(ctx context.Context, tx *sql.Tx, note *Note) (*Note, error)
-	RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*Note, error)
+	MoveNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, notebookId *uuid.UUID) (*Note, error)
+	RemoveNotesFromNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID, notebookIds []uuid.UUID, trash bool) (*[]Note, error)
+	SetNotePinned(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, pinned bool) (*Note, error)
+	SetNoteArchived(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, archived bool) (*Note, error)
+	RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) (*Note, error)
 	UpdateNote(ctx context.Context, tx *sql.Tx, note *Note) (*Note, error)
-	DeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, isHard bool) error
+	DeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, isHard bool) error
+	SoftDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error)
+	RestoreNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error)
+	HardDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error)
+	ListTrashNoteIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID, limit int32) ([]uuid.UUID, error)
+	DeleteCachedNotes(ctx context.Context, ids []uuid.UUID)
 }
 
 type noteRepository struct {
@@ -32,12 +46,32 @@ func NewNoteRepository(noteCacheDs *NoteCacheDs, noteDatabaseDs *NoteDatabaseDs)
 }
 
 func (n *noteRepository) CreateNote(ctx context.Context, tx *sql.Tx, note *Note) (*Note, error) {
-	// Save the note on the database
+	// Save the note on the database, it is cached on its first read once the transaction is committed
 	note, err := n.NoteDatabaseDs.CreateNote(ctx, tx, note)
 	if err != nil {
 		return nil, err
 	}
-	// Cache the note
+	return note, nil
+}
+
+func (n *noteRepository) GetNote(ctx context.Context, id uuid.UUID) (*Note, error) {
+	// Get the note from the cache
+	note, err := n.NoteCacheDs.GetNote(ctx, id)
+	if err == nil {
+		return note, nil
+	}
+	if _, ok := err.(*customerrors.RecordNotFound); !ok {
+		clogg.Info(
+			ctx,
+			err.Error(),
+		)
+	}
+	// Get the note from the database
+	note, err = n.NoteDatabaseDs.GetNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Cache the note for the next reads
 	err = n.NoteCacheDs.CreateNote(ctx, note)
 	if err != nil {
 		clogg.Info(
@@ -48,24 +82,33 @@ func (n *noteRepository) CreateNote(ctx context.Context, tx *sql.Tx, note *Note)
 	return note, nil
 }
 
-func (n *noteRepository) ListNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]Note, error) {
+func (n *noteRepository) ListNotesByUser(ctx context.Context, user_id uuid.UUID, sort NoteSort, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error) {
 	// Fetch the notes from the database
-	notes, err := n.NoteDatabaseDs.ListNotesByUser(ctx, user_id, cursor)
+	notes, err := n.NoteDatabaseDs.ListNotesByUser(ctx, user_id, sort, cursor, limit, filter)
 	if err != nil {
 		return nil, err
 	}
 	return notes, nil
 }
 
-func (n *noteRepository) ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor time.Time) (*[]Note, error) {
+func (n *noteRepository) ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error) {
 	// Fetch the notes from the database
-	notes, err := n.NoteDatabaseDs.ListTrashNotesByUser(ctx, user_id, cursor)
+	notes, err := n.NoteDatabaseDs.ListTrashNotesByUser(ctx, user_id, cursor, limit, filter)
 	if err != nil {
 		return nil, err
 	}
 	return notes, nil
 }
 
+func (n *noteRepository) SearchNotes(ctx context.Context, userId uuid.UUID, query string, includeTrash bool, cursor *NoteSearchCursor, limit int32) (*[]NoteSearchResult, error) {
+	// Search the notes on the database
+	results, err := n.NoteDatabaseDs.SearchNotes(ctx, userId, query, includeTrash, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (n *noteRepository) UpdateNote(ctx context.Context, tx *sql.Tx, note *Note) (*Note, error) {
 	// Update the note on the database
 	note, err := n.NoteDatabaseDs.UpdateNote(ctx, tx, note)
@@ -75,28 +118,137 @@ func (n *noteRepository) UpdateNote(ctx context.Context, tx *sql.Tx, note *Note)
 	return note, nil
 }
 
-func (n *noteRepository) RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*Note, error) {
+func (n *noteRepository) MoveNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, notebookId *uuid.UUID) (*Note, error) {
+	// Move the note on the database
+	note, err := n.NoteDatabaseDs.MoveNote(ctx, tx, id, userId, notebookId)
+	if err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (n *noteRepository) ListArchivedNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *NoteListCursor, limit int32, filter NoteFilter) (*[]Note, error) {
+	// Fetch the notes from the database
+	notes, err := n.NoteDatabaseDs.ListArchivedNotesByUser(ctx, user_id, cursor, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (n *noteRepository) ListExpiredTrashNotes(ctx context.Context, tx *sql.Tx, before time.Time, cursor *NoteListCursor, limit int32) (*[]Note, error) {
+	// Fetch and lock the notes from the database
+	notes, err := n.NoteDatabaseDs.ListExpiredTrashNotes(ctx, tx, before, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (n *noteRepository) HardDeleteTrashNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) (*[]Note, error) {
+	// Hard delete the notes still in the trash from the database
+	notes, err := n.NoteDatabaseDs.HardDeleteTrashNotes(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (n *noteRepository) SetNotePinned(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, pinned bool) (*Note, error) {
+	// Pin or unpin the note on the database
+	note, err := n.NoteDatabaseDs.SetNotePinned(ctx, tx, id, userId, pinned)
+	if err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (n *noteRepository) SetNoteArchived(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, archived bool) (*Note, error) {
+	// Archive or unarchive the note on the database
+	note, err := n.NoteDatabaseDs.SetNoteArchived(ctx, tx, id, userId, archived)
+	if err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (n *noteRepository) RemoveNotesFromNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID, notebookIds []uuid.UUID, trash bool) (*[]Note, error) {
+	// Take the notes out of the notebooks on the database, moving them to the trash if requested
+	notes, err := n.NoteDatabaseDs.RemoveNotesFromNotebooks(ctx, tx, userId, notebookIds, trash)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (n *noteRepository) RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) (*Note, error) {
 	// Update the note on the database
-	note, err := n.NoteDatabaseDs.RestoreNote(ctx, tx, id)
+	note, err := n.NoteDatabaseDs.RestoreNote(ctx, tx, id, userId)
 	if err != nil {
 		return nil, err
 	}
 	return note, nil
 }
 
-func (n *noteRepository) DeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, isHard bool) error {
+func (n *noteRepository) DeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, isHard bool) error {
 	if isHard {
 		// Hard delete the note from the database
-		err := n.NoteDatabaseDs.HardDeleteNote(ctx, tx, id)
+		err := n.NoteDatabaseDs.HardDeleteNote(ctx, tx, id, userId)
 		if err != nil {
 			return err
 		}
 	} else {
 		// Soft delete the note from the database
-		err := n.NoteDatabaseDs.SoftDeleteNote(ctx, tx, id)
+		err := n.NoteDatabaseDs.SoftDeleteNote(ctx, tx, id, userId)
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+func (n *noteRepository) SoftDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error) {
+	// Soft delete the notes of the user from the database
+	notes, err := n.NoteDatabaseDs.SoftDeleteNotes(ctx, tx, ids, userId)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (n *noteRepository) RestoreNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error) {
+	// Restore the notes of the user from the trash
+	notes, err := n.NoteDatabaseDs.RestoreNotes(ctx, tx, ids, userId)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (n *noteRepository) HardDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]Note, error) {
+	// Hard delete the notes of the user from the database
+	notes, err := n.NoteDatabaseDs.HardDeleteNotes(ctx, tx, ids, userId)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (n *noteRepository) ListTrashNoteIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID, limit int32) ([]uuid.UUID, error) {
+	return n.NoteDatabaseDs.ListTrashNoteIds(ctx, tx, userId, limit)
+}
+
+// DeleteCachedNotes removes the notes from the cache so the next read gets them from the database.
+// It is called once the transaction that changed the notes is committed, otherwise a concurrent read
+// could cache the row as it was before the commit. A failure is only logged, the cached notes expire anyway.
+func (n *noteRepository) DeleteCachedNotes(ctx context.Context, ids []uuid.UUID) {
+	for _, id := range ids {
+		err := n.NoteCacheDs.DeleteNote(ctx, id)
+		if err != nil {
+			clogg.Info(
+				ctx,
+				err.Error(),
+			)
+		}
+	}
+}
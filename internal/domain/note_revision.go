@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteRevision is a copy of the title and the content of a note before an update
+type NoteRevision struct {
+	Id         uuid.UUID `json:"id"`
+	NoteId     uuid.UUID `json:"note_id"`
+	UserId     uuid.UUID `json:"user_id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// Types of the lines of a diff
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine is a line of a diff, the type says if the line is kept, inserted or deleted
+type DiffLine struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// NoteRevisionDiff is the line diff of the title and the content between two revisions
+type NoteRevisionDiff struct {
+	From    uuid.UUID  `json:"from"`
+	To      uuid.UUID  `json:"to"`
+	Title   []DiffLine `json:"title"`
+	Content []DiffLine `json:"content"`
+}
+
+// DiffLines returns the line diff to go from the from text to the to text,
+// using the longest common subsequence of the lines
+func DiffLines(from string, to string) []DiffLine {
+	a := splitLines(from)
+	b := splitLines(to)
+
+	// The common prefix and suffix are kept as they are, so the table is only
+	// built for the lines that changed
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma := a[prefix : len(a)-suffix]
+	mb := b[prefix : len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of ma[i:] and mb[j:]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		diff = append(diff, DiffLine{Type: DiffEqual, Text: line})
+	}
+	i, j := 0, 0
+	for i < len(ma) && j < len(mb) {
+		switch {
+		case ma[i] == mb[j]:
+			diff = append(diff, DiffLine{Type: DiffEqual, Text: ma[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Type: DiffDelete, Text: ma[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Type: DiffInsert, Text: mb[j]})
+			j++
+		}
+	}
+	for ; i < len(ma); i++ {
+		diff = append(diff, DiffLine{Type: DiffDelete, Text: ma[i]})
+	}
+	for ; j < len(mb); j++ {
+		diff = append(diff, DiffLine{Type: DiffInsert, Text: mb[j]})
+	}
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, DiffLine{Type: DiffEqual, Text: line})
+	}
+	return diff
+}
+
+// splitLines splits a text in lines, an empty text has no lines
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
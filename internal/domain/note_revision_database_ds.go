@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+type NoteRevisionDatabaseDs interface {
+	CreateNoteRevision(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID) error
+	ListNoteRevisions(ctx context.Context, noteId uuid.UUID, userId uuid.UUID) (*[]NoteRevision, error)
+	GetNoteRevision(ctx context.Context, id uuid.UUID, noteId uuid.UUID, userId uuid.UUID) (*NoteRevision, error)
+	PruneNoteRevisions(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, keep int) error
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+type NoteRevisionRepository interface {
+	CreateNoteRevision(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, keep int) error
+	ListNoteRevisions(ctx context.Context, noteId uuid.UUID, userId uuid.UUID) (*[]NoteRevision, error)
+	GetNoteRevision(ctx context.Context, id uuid.UUID, noteId uuid.UUID, userId uuid.UUID) (*NoteRevision, error)
+}
+
+type noteRevisionRepository struct {
+	NoteRevisionDatabaseDs NoteRevisionDatabaseDs
+}
+
+func NewNoteRevisionRepository(noteRevisionDatabaseDs NoteRevisionDatabaseDs) NoteRevisionRepository {
+	return &noteRevisionRepository{
+		NoteRevisionDatabaseDs: noteRevisionDatabaseDs,
+	}
+}
+
+func (r *noteRevisionRepository) CreateNoteRevision(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, keep int) error {
+	// Copy the current title and content of the note before it is updated
+	if err := r.NoteRevisionDatabaseDs.CreateNoteRevision(ctx, tx, noteId, userId); err != nil {
+		return err
+	}
+	// Only the last revisions of the note are kept, a zero keep disables the retention
+	if keep > 0 {
+		if err := r.NoteRevisionDatabaseDs.PruneNoteRevisions(ctx, tx, noteId, keep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *noteRevisionRepository) ListNoteRevisions(ctx context.Context, noteId uuid.UUID, userId uuid.UUID) (*[]NoteRevision, error) {
+	// Fetch the revisions of the note from the database
+	revisions, err := r.NoteRevisionDatabaseDs.ListNoteRevisions(ctx, noteId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *noteRevisionRepository) GetNoteRevision(ctx context.Context, id uuid.UUID, noteId uuid.UUID, userId uuid.UUID) (*NoteRevision, error) {
+	// Fetch the revision from the database
+	revision, err := r.NoteRevisionDatabaseDs.GetNoteRevision(ctx, id, noteId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// NoteSearchResult is a note matching a full-text search with its rank and the
+// highlighted fragments of the title and the content
+type NoteSearchResult struct {
+	Note           Note    `json:"note"`
+	Rank           float64 `json:"rank"`
+	TitleSnippet   string  `json:"title_snippet"`
+	ContentSnippet string  `json:"content_snippet"`
+}
+
+// NoteSearchCursor is the position of the last result of a search page.
+// The results are ordered by rank and id, both descending.
+type NoteSearchCursor struct {
+	Rank float64
+	Id   uuid.UUID
+}
+
+// Encode returns the cursor as an opaque string for the clients
+func (c NoteSearchCursor) Encode() string {
+	value := strconv.FormatFloat(c.Rank, 'g', -1, 64) + "," + c.Id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// DecodeNoteSearchCursor parses a cursor returned by NoteSearchCursor.Encode
+func DecodeNoteSearchCursor(cursor string) (*NoteSearchCursor, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	rank, id, ok := strings.Cut(string(value), ",")
+	if !ok {
+		return nil, errors.New("invalid cursor")
+	}
+	parsedRank, err := strconv.ParseFloat(rank, 64)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &NoteSearchCursor{Rank: parsedRank, Id: parsedId}, nil
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notebook groups the notes of a user, a notebook without parent is at the root
+type Notebook struct {
+	Id         uuid.UUID  `json:"id"`
+	UserId     uuid.UUID  `json:"user_id"`
+	ParentId   *uuid.UUID `json:"parent_id"`
+	Name       string     `json:"name"`
+	CreateTime time.Time  `json:"create_time"`
+	UpdateTime time.Time  `json:"update_time"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+type NotebookDatabaseDs interface {
+	GetNotebook(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*Notebook, error)
+	ListNotebooksByUser(ctx context.Context, userId uuid.UUID) (*[]Notebook, error)
+	LockNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	ListNotebookTreeIds(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error)
+	CreateNotebook(ctx context.Context, tx *sql.Tx, notebook *Notebook) (*Notebook, error)
+	RenameNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, name string) (*Notebook, error)
+	MoveNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, parentId *uuid.UUID) (*Notebook, error)
+	DeleteNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error
+}
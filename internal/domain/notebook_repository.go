@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+type NotebookRepository interface {
+	GetNotebook(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*Notebook, error)
+	ListNotebooksByUser(ctx context.Context, userId uuid.UUID) (*[]Notebook, error)
+	LockNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	ListNotebookTreeIds(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error)
+	CreateNotebook(ctx context.Context, tx *sql.Tx, notebook *Notebook) (*Notebook, error)
+	RenameNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, name string) (*Notebook, error)
+	MoveNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, parentId *uuid.UUID) (*Notebook, error)
+	DeleteNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error
+}
+
+type notebookRepository struct {
+	NotebookDatabaseDs NotebookDatabaseDs
+}
+
+func NewNotebookRepository(notebookDatabaseDs NotebookDatabaseDs) NotebookRepository {
+	return &notebookRepository{
+		NotebookDatabaseDs: notebookDatabaseDs,
+	}
+}
+
+func (r *notebookRepository) GetNotebook(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*Notebook, error) {
+	// Fetch the notebook from the database
+	notebook, err := r.NotebookDatabaseDs.GetNotebook(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return notebook, nil
+}
+
+func (r *notebookRepository) ListNotebooksByUser(ctx context.Context, userId uuid.UUID) (*[]Notebook, error) {
+	// Fetch the notebooks from the database
+	notebooks, err := r.NotebookDatabaseDs.ListNotebooksByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return notebooks, nil
+}
+
+func (r *notebookRepository) LockNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	// Lock the notebooks of the user on the database until the transaction ends
+	return r.NotebookDatabaseDs.LockNotebooks(ctx, tx, userId)
+}
+
+func (r *notebookRepository) ListNotebookTreeIds(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error) {
+	// Fetch the ids of the notebook and all its descendants from the database
+	ids, err := r.NotebookDatabaseDs.ListNotebookTreeIds(ctx, tx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *notebookRepository) CreateNotebook(ctx context.Context, tx *sql.Tx, notebook *Notebook) (*Notebook, error) {
+	// Save the notebook on the database
+	notebook, err := r.NotebookDatabaseDs.CreateNotebook(ctx, tx, notebook)
+	if err != nil {
+		return nil, err
+	}
+	return notebook, nil
+}
+
+func (r *notebookRepository) RenameNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, name string) (*Notebook, error) {
+	// Rename the notebook on the database
+	notebook, err := r.NotebookDatabaseDs.RenameNotebook(ctx, tx, id, userId, name)
+	if err != nil {
+		return nil, err
+	}
+	return notebook, nil
+}
+
+func (r *notebookRepository) MoveNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, parentId *uuid.UUID) (*Notebook, error) {
+	// Move the notebook on the database
+	notebook, err := r.NotebookDatabaseDs.MoveNotebook(ctx, tx, id, userId, parentId)
+	if err != nil {
+		return nil, err
+	}
+	return notebook, nil
+}
+
+func (r *notebookRepository) DeleteNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	// Delete the notebook from the database, its descendants are deleted by cascade
+	return r.NotebookDatabaseDs.DeleteNotebook(ctx, tx, id, userId)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ObjectDeletion is an object of a hard deleted file waiting to be removed from the object storage service.
+// A deletion that keeps failing is dead-lettered once it runs out of attempts, it is kept with its last error.
+type ObjectDeletion struct {
+	Id              uuid.UUID `json:"id"`
+	Bucket          string    `json:"bucket"`
+	ObjectName      string    `json:"object_name"`
+	Attempts        int32     `json:"attempts"`
+	LastError       string    `json:"last_error"`
+	NextAttemptTime time.Time `json:"next_attempt_time"`
+	DeadTime        time.Time `json:"dead_time"`
+	CreateTime      time.Time `json:"create_time"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ObjectDeletionDatabaseDs interface {
+	CreateObjectDeletions(ctx context.Context, tx *sql.Tx, bucket string, objectNames []string) error
+	ListDueObjectDeletions(ctx context.Context, tx *sql.Tx, now time.Time, limit int32) (*[]ObjectDeletion, error)
+	DeleteObjectDeletions(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) error
+	FailObjectDeletion(ctx context.Context, tx *sql.Tx, id uuid.UUID, lastError string, nextAttemptTime time.Time, dead bool) error
+	RequeueDeadObjectDeletions(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/daniarmas/notes/internal/oss"
+	"github.com/google/uuid"
+)
+
+type ObjectDeletionRepository interface {
+	ListDueObjectDeletions(ctx context.Context, tx *sql.Tx, limit int32) (*[]ObjectDeletion, error)
+	RemoveObject(ctx context.Context, deletion *ObjectDeletion) error
+	DeleteObjectDeletions(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) error
+	FailObjectDeletion(ctx context.Context, tx *sql.Tx, id uuid.UUID, lastError string, nextAttemptTime time.Time, dead bool) error
+	RequeueDeadObjectDeletions(ctx context.Context) (int64, error)
+}
+
+type objectDeletionRepository struct {
+	ObjectDeletionDatabaseDs ObjectDeletionDatabaseDs
+	ObjectStorageService     oss.ObjectStorageService
+}
+
+func NewObjectDeletionRepository(objectDeletionDatabaseDs ObjectDeletionDatabaseDs, objectStorageService oss.ObjectStorageService) ObjectDeletionRepository {
+	return &objectDeletionRepository{
+		ObjectDeletionDatabaseDs: objectDeletionDatabaseDs,
+		ObjectStorageService:     objectStorageService,
+	}
+}
+
+func (r *objectDeletionRepository) ListDueObjectDeletions(ctx context.Context, tx *sql.Tx, limit int32) (*[]ObjectDeletion, error) {
+	// Lock the deletions due for an attempt, the deletions locked by another worker are skipped
+	return r.ObjectDeletionDatabaseDs.ListDueObjectDeletions(ctx, tx, time.Now().UTC(), limit)
+}
+
+func (r *objectDeletionRepository) RemoveObject(ctx context.Context, deletion *ObjectDeletion) error {
+	// Removing an object that is already gone succeeds, so a deletion retried after a crash is idempotent
+	return r.ObjectStorageService.RemoveObject(ctx, deletion.Bucket, deletion.ObjectName)
+}
+
+func (r *objectDeletionRepository) DeleteObjectDeletions(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) error {
+	return r.ObjectDeletionDatabaseDs.DeleteObjectDeletions(ctx, tx, ids)
+}
+
+func (r *objectDeletionRepository) FailObjectDeletion(ctx context.Context, tx *sql.Tx, id uuid.UUID, lastError string, nextAttemptTime time.Time, dead bool) error {
+	return r.ObjectDeletionDatabaseDs.FailObjectDeletion(ctx, tx, id, lastError, nextAttemptTime, dead)
+}
+
+func (r *objectDeletionRepository) RequeueDeadObjectDeletions(ctx context.Context) (int64, error) {
+	return r.ObjectDeletionDatabaseDs.RequeueDeadObjectDeletions(ctx)
+}
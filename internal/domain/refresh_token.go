@@ -6,9 +6,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// RefreshTokenLifetime is how long a refresh token is valid, a session expires when its latest token does
+const RefreshTokenLifetime = 30 * 24 * time.Hour
+
 type RefreshToken struct {
 	Id         uuid.UUID `json:"id"`
 	UserId     uuid.UUID `json:"user_id"`
+	FamilyId   uuid.UUID `json:"family_id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IpAddress  string    `json:"ip_address"`
 	CreateTime time.Time `json:"create_time"`
 	UpdateTime time.Time `json:"update_time"`
+	RotateTime time.Time `json:"rotate_time"`
 }
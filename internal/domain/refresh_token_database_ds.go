@@ -10,5 +10,7 @@ import (
 type RefreshTokenDatabaseDs interface {
 	GetRefreshTokenById(ctx context.Context, id uuid.UUID) (*RefreshToken, error)
 	CreateRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken *RefreshToken) (*RefreshToken, error)
-	DeleteRefreshTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (*uuid.UUID, error)
+	RotateRefreshToken(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*RefreshToken, error)
+	ListSessionsByUserId(ctx context.Context, userId uuid.UUID) (*[]Session, error)
+	DeleteRefreshTokensByFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) (*[]uuid.UUID, error)
 }
@@ -12,7 +12,9 @@ import (
 type RefreshTokenRepository interface {
 	GetRefreshToken(ctx context.Context, id uuid.UUID) (*RefreshToken, error)
 	CreateRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken *RefreshToken) (*RefreshToken, error)
-	DeleteRefreshTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	RotateRefreshToken(ctx context.Context, tx *sql.Tx, id uuid.UUID) error
+	DeleteRefreshTokensByFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) error
+	ListSessionsByUserId(ctx context.Context, userId uuid.UUID) (*[]Session, error)
 }
 
 type refreshTokenRepository struct {
@@ -71,16 +73,37 @@ func (r *refreshTokenRepository) CreateRefreshToken(ctx context.Context, tx *sql
 	return user, nil
 }
 
-func (r *refreshTokenRepository) DeleteRefreshTokenByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
-	// Delete the refresh token on the database
-	id, err := r.RefreshTokenDatabaseDs.DeleteRefreshTokenByUserId(ctx, tx, userId)
+func (r *refreshTokenRepository) RotateRefreshToken(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	// Mark the refresh token as rotated on the database
+	_, err := r.RefreshTokenDatabaseDs.RotateRefreshToken(ctx, tx, id)
 	if err != nil {
 		return err
 	}
-	// Delete the refresh token on the cache
-	err = r.RefreshTokenCacheDs.DeleteRefreshToken(ctx, *id)
+	// Delete the refresh token on the cache so the next reads see the rotation
+	err = r.RefreshTokenCacheDs.DeleteRefreshToken(ctx, id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *refreshTokenRepository) DeleteRefreshTokensByFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) error {
+	// Delete the refresh tokens of the family on the database
+	ids, err := r.RefreshTokenDatabaseDs.DeleteRefreshTokensByFamilyId(ctx, tx, familyId)
+	if err != nil {
+		return err
+	}
+	// Delete the refresh tokens on the cache
+	for _, id := range *ids {
+		err = r.RefreshTokenCacheDs.DeleteRefreshToken(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) ListSessionsByUserId(ctx context.Context, userId uuid.UUID) (*[]Session, error) {
+	// The sessions are listed from the database, the cache only holds single refresh tokens
+	return r.RefreshTokenDatabaseDs.ListSessionsByUserId(ctx, userId)
+}
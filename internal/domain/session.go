@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a signed in device of a user. It is identified by the family id of its refresh tokens
+type Session struct {
+	Id             uuid.UUID `json:"id"`
+	DeviceName     string    `json:"device_name"`
+	UserAgent      string    `json:"user_agent"`
+	IpAddress      string    `json:"ip_address"`
+	Current        bool      `json:"current"`
+	SignInTime     time.Time `json:"sign_in_time"`
+	LastActiveTime time.Time `json:"last_active_time"`
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of the hard deleted entities
+const (
+	TombstoneNote = "note"
+	TombstoneFile = "file"
+)
+
+// Tombstone is a hard deleted note or file, it is kept so the delta sync can report the deletion
+type Tombstone struct {
+	EntityType string    `json:"entity_type"`
+	EntityId   uuid.UUID `json:"entity_id"`
+	NoteId     uuid.UUID `json:"note_id"`
+	DeleteTime time.Time `json:"delete_time"`
+	ChangeSeq  int64     `json:"-"`
+	ChangeXid  int64     `json:"-"`
+}
+
+// SyncChanges are the notes, files and tombstones changed after a sync token, in the order of the changes.
+// The soft deleted and restored notes are returned as notes with or without the delete time.
+type SyncChanges struct {
+	Notes      []Note      `json:"notes"`
+	Files      []File      `json:"files"`
+	Tombstones []Tombstone `json:"tombstones"`
+	// Token is sent in the next sync to receive the changes after these ones
+	Token string `json:"token"`
+	// HasMore is true if there are more changes after the token
+	HasMore bool `json:"has_more"`
+}
+
+// SyncPosition is a position in the order of the changes of the delta sync. The transactions commit out of the order
+// of the change sequence, so the changes are ordered by the transaction that made them and then by the change sequence.
+type SyncPosition struct {
+	ChangeXid int64
+	ChangeSeq int64
+}
+
+// After reports whether the position comes after the other one
+func (p SyncPosition) After(other SyncPosition) bool {
+	if p.ChangeXid != other.ChangeXid {
+		return p.ChangeXid > other.ChangeXid
+	}
+	return p.ChangeSeq > other.ChangeSeq
+}
+
+// EncodeSyncToken returns the opaque token of a position in the order of the changes
+func EncodeSyncToken(position SyncPosition) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(position.ChangeXid, 10) + "." + strconv.FormatInt(position.ChangeSeq, 10)))
+}
+
+// DecodeSyncToken returns the position in the order of the changes of a token, an empty token is the beginning.
+// A token with the change sequence alone can't be placed in the order of the transactions, it starts over from the beginning.
+func DecodeSyncToken(token string) (SyncPosition, error) {
+	if token == "" {
+		return SyncPosition{}, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return SyncPosition{}, errors.New("invalid sync token")
+	}
+	xid, seq, found := strings.Cut(string(decoded), ".")
+	if !found {
+		if changeSeq, err := strconv.ParseInt(xid, 10, 64); err != nil || changeSeq < 0 {
+			return SyncPosition{}, errors.New("invalid sync token")
+		}
+		return SyncPosition{}, nil
+	}
+	changeXid, err := strconv.ParseInt(xid, 10, 64)
+	if err != nil || changeXid < 0 {
+		return SyncPosition{}, errors.New("invalid sync token")
+	}
+	changeSeq, err := strconv.ParseInt(seq, 10, 64)
+	if err != nil || changeSeq < 0 {
+		return SyncPosition{}, errors.New("invalid sync token")
+	}
+	return SyncPosition{ChangeXid: changeXid, ChangeSeq: changeSeq}, nil
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+type SyncDatabaseDs interface {
+	GetSyncHorizon(ctx context.Context, tx *sql.Tx) (int64, error)
+	ListNotesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position SyncPosition, horizon int64, limit int32) (*[]Note, error)
+	ListFilesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position SyncPosition, horizon int64, limit int32) (*[]File, error)
+	ListTombstonesSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position SyncPosition, horizon int64, limit int32) (*[]Tombstone, error)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type SyncRepository interface {
+	ListChangesSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position SyncPosition, limit int) (*SyncChanges, error)
+}
+
+type syncRepository struct {
+	SyncDatabaseDs SyncDatabaseDs
+}
+
+func NewSyncRepository(syncDatabaseDs SyncDatabaseDs) SyncRepository {
+	return &syncRepository{
+		SyncDatabaseDs: syncDatabaseDs,
+	}
+}
+
+// ListChangesSince returns the changes after the position. The transaction must be repeatable read, so the horizon
+// and the changes come from the same snapshot. Only the changes of the transactions older than the horizon are
+// returned, a transaction still in progress could commit a change before the last returned one otherwise.
+func (r *syncRepository) ListChangesSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position SyncPosition, limit int) (*SyncChanges, error) {
+	horizon, err := r.SyncDatabaseDs.GetSyncHorizon(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each kind of change is read up to one past the limit, the first changes of the merge are always among them
+	notes, err := r.SyncDatabaseDs.ListNotesChangedSince(ctx, tx, userId, position, horizon, int32(limit+1))
+	if err != nil {
+		return nil, err
+	}
+	files, err := r.SyncDatabaseDs.ListFilesChangedSince(ctx, tx, userId, position, horizon, int32(limit+1))
+	if err != nil {
+		return nil, err
+	}
+	tombstones, err := r.SyncDatabaseDs.ListTombstonesSince(ctx, tx, userId, position, horizon, int32(limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the last change of the page
+	positions := make([]SyncPosition, 0, len(*notes)+len(*files)+len(*tombstones))
+	for _, note := range *notes {
+		positions = append(positions, SyncPosition{ChangeXid: note.ChangeXid, ChangeSeq: note.ChangeSeq})
+	}
+	for _, file := range *files {
+		positions = append(positions, SyncPosition{ChangeXid: file.ChangeXid, ChangeSeq: file.ChangeSeq})
+	}
+	for _, tombstone := range *tombstones {
+		positions = append(positions, SyncPosition{ChangeXid: tombstone.ChangeXid, ChangeSeq: tombstone.ChangeSeq})
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[j].After(positions[i]) })
+
+	last := position
+	hasMore := len(positions) > limit
+	if hasMore {
+		last = positions[limit-1]
+	} else if len(positions) > 0 {
+		last = positions[len(positions)-1]
+	}
+
+	// Keep the changes up to the last change of the page
+	res := &SyncChanges{
+		Notes:      make([]Note, 0, len(*notes)),
+		Files:      make([]File, 0, len(*files)),
+		Tombstones: make([]Tombstone, 0, len(*tombstones)),
+		Token:      EncodeSyncToken(last),
+		HasMore:    hasMore,
+	}
+	for _, note := range *notes {
+		if !(SyncPosition{ChangeXid: note.ChangeXid, ChangeSeq: note.ChangeSeq}).After(last) {
+			res.Notes = append(res.Notes, note)
+		}
+	}
+	for _, file := range *files {
+		if !(SyncPosition{ChangeXid: file.ChangeXid, ChangeSeq: file.ChangeSeq}).After(last) {
+			res.Files = append(res.Files, file)
+		}
+	}
+	for _, tombstone := range *tombstones {
+		if !(SyncPosition{ChangeXid: tombstone.ChangeXid, ChangeSeq: tombstone.ChangeSeq}).After(last) {
+			res.Tombstones = append(res.Tombstones, tombstone)
+		}
+	}
+
+	return res, nil
+}
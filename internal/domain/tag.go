@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	Id         uuid.UUID `json:"id"`
+	UserId     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"create_time"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// NoteTag is a tag attached to a note, used to batch-load the tags of several notes
+type NoteTag struct {
+	NoteId uuid.UUID
+	Tag    Tag
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+type TagDatabaseDs interface {
+	ListTagsByUser(ctx context.Context, userId uuid.UUID) (*[]Tag, error)
+	ListTagsByIds(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (*[]Tag, error)
+	ListTagsByNotesIds(ctx context.Context, noteIds []uuid.UUID) (*[]NoteTag, error)
+	ListTagsByNoteId(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) (*[]Tag, error)
+	CreateTag(ctx context.Context, tx *sql.Tx, tag *Tag) (*Tag, error)
+	UpdateTag(ctx context.Context, tx *sql.Tx, tag *Tag) (*Tag, error)
+	DeleteTag(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error
+	AttachTagsToNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error
+	DetachTagsFromNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error
+}
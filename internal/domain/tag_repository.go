@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/google/uuid"
+)
+
+type TagRepository interface {
+	ListTagsByUser(ctx context.Context, userId uuid.UUID) (*[]Tag, error)
+	ListTagsByNotesIds(ctx context.Context, noteIds []uuid.UUID) (*[]NoteTag, error)
+	ListTagsByNoteId(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) (*[]Tag, error)
+	CreateTag(ctx context.Context, tx *sql.Tx, tag *Tag) (*Tag, error)
+	UpdateTag(ctx context.Context, tx *sql.Tx, tag *Tag) (*Tag, error)
+	DeleteTag(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error
+	AttachTagsToNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error
+	DetachTagsFromNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error
+}
+
+type tagRepository struct {
+	TagDatabaseDs TagDatabaseDs
+}
+
+func NewTagRepository(tagDatabaseDs TagDatabaseDs) TagRepository {
+	return &tagRepository{
+		TagDatabaseDs: tagDatabaseDs,
+	}
+}
+
+func (r *tagRepository) ListTagsByUser(ctx context.Context, userId uuid.UUID) (*[]Tag, error) {
+	// Fetch the tags from the database
+	tags, err := r.TagDatabaseDs.ListTagsByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) ListTagsByNotesIds(ctx context.Context, noteIds []uuid.UUID) (*[]NoteTag, error) {
+	// Fetch the tags of the notes from the database
+	tags, err := r.TagDatabaseDs.ListTagsByNotesIds(ctx, noteIds)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) ListTagsByNoteId(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) (*[]Tag, error) {
+	// Fetch the tags of the note from the database inside the transaction,
+	// so the tags attached or detached by it are included
+	tags, err := r.TagDatabaseDs.ListTagsByNoteId(ctx, tx, noteId)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) CreateTag(ctx context.Context, tx *sql.Tx, tag *Tag) (*Tag, error) {
+	// Save the tag on the database
+	tag, err := r.TagDatabaseDs.CreateTag(ctx, tx, tag)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (r *tagRepository) UpdateTag(ctx context.Context, tx *sql.Tx, tag *Tag) (*Tag, error) {
+	// Update the tag on the database
+	tag, err := r.TagDatabaseDs.UpdateTag(ctx, tx, tag)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (r *tagRepository) DeleteTag(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	// Delete the tag from the database, the notes lose the tag by cascade
+	return r.TagDatabaseDs.DeleteTag(ctx, tx, id, userId)
+}
+
+func (r *tagRepository) AttachTagsToNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+	// Only the tags of the user can be attached to the note
+	tags, err := r.TagDatabaseDs.ListTagsByIds(ctx, userId, tagIds)
+	if err != nil {
+		return err
+	}
+	if len(*tags) != len(uniqueIds(tagIds)) {
+		return &customerrors.RecordNotFound{}
+	}
+	// Attach the tags, the tags already attached are kept
+	return r.TagDatabaseDs.AttachTagsToNote(ctx, tx, noteId, userId, tagIds)
+}
+
+func (r *tagRepository) DetachTagsFromNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+	// Detach the tags, the tags not attached to the note are ignored
+	return r.TagDatabaseDs.DetachTagsFromNote(ctx, tx, noteId, userId, tagIds)
+}
+
+// uniqueIds returns the ids without duplicates
+func uniqueIds(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
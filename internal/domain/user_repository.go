@@ -2,13 +2,14 @@ package domain
 
 import (
 	"context"
+	"database/sql"
 	"log"
 
 	"github.com/google/uuid"
 )
 
 type UserRepository interface {
-	CreateUser(ctx context.Context, user *User) (*User, error)
+	CreateUser(ctx context.Context, tx *sql.Tx, user *User) (*User, error)
 	GetUserById(ctx context.Context, id uuid.UUID) (*User, error)
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 }
@@ -25,9 +26,9 @@ func NewUserRepository(userCacheDs *UserCacheDs, userDatabaseDs *UserDatabaseDs)
 	}
 }
 
-func (d *userRepo) CreateUser(ctx context.Context, user *User) (*User, error) {
+func (d *userRepo) CreateUser(ctx context.Context, tx *sql.Tx, user *User) (*User, error) {
 	// Save the user on the database
-	user, err := d.UserDatabaseDs.CreateUser(ctx, user)
+	user, err := d.UserDatabaseDs.CreateUser(ctx, tx, user)
 	if err != nil {
 		return nil, err
 	}
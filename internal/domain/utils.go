@@ -22,3 +22,33 @@ func GetUserIdFromContext(ctx context.Context) uuid.UUID {
 	}
 	return uuid.Nil
 }
+
+// SetAccessTokenIdInContext sets the access token used by the request in the context
+func SetAccessTokenIdInContext(ctx context.Context, accessTokenId uuid.UUID) context.Context {
+	if accessTokenId == uuid.Nil {
+		return ctx
+	} else {
+		return context.WithValue(ctx, "accessTokenId", accessTokenId.String())
+	}
+}
+
+// Get access token from context
+func GetAccessTokenIdFromContext(ctx context.Context) uuid.UUID {
+	if accessToken, ok := ctx.Value("accessTokenId").(string); ok {
+		return uuid.MustParse(accessToken)
+	}
+	return uuid.Nil
+}
+
+// SetClientInContext sets the user agent and ip address of the request client in the context
+func SetClientInContext(ctx context.Context, userAgent string, ipAddress string) context.Context {
+	ctx = context.WithValue(ctx, "userAgent", userAgent)
+	return context.WithValue(ctx, "ipAddress", ipAddress)
+}
+
+// Get the user agent and ip address of the request client from context
+func GetClientFromContext(ctx context.Context) (userAgent string, ipAddress string) {
+	userAgent, _ = ctx.Value("userAgent").(string)
+	ipAddress, _ = ctx.Value("ipAddress").(string)
+	return userAgent, ipAddress
+}
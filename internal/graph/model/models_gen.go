@@ -10,47 +10,137 @@ type AccessToken struct {
 	UpdateTime     *string `json:"updateTime,omitempty"`
 }
 
+type AudioMetadata struct {
+	Duration   float64   `json:"duration"`
+	SampleRate int32     `json:"sampleRate"`
+	Peaks      []float64 `json:"peaks"`
+}
+
+type BulkNoteResult struct {
+	ID    string  `json:"id"`
+	Ok    bool    `json:"ok"`
+	Error *string `json:"error,omitempty"`
+}
+
+type BulkNotesResponse struct {
+	Results []*BulkNoteResult `json:"results"`
+	HasMore bool              `json:"hasMore"`
+}
+
 type CreateNoteInput struct {
 	Title       *string   `json:"title,omitempty"`
 	Content     *string   `json:"content,omitempty"`
 	ObjectNames []*string `json:"objectNames,omitempty"`
+	TagIds      []string  `json:"tagIds,omitempty"`
+	NotebookID  *string   `json:"notebookId,omitempty"`
+}
+
+type CreateNotebookInput struct {
+	Name     string  `json:"name"`
+	ParentID *string `json:"parentId,omitempty"`
 }
 
 type CreatePresignedUrlsResponse struct {
 	Urls []*PresignedURL `json:"Urls,omitempty"`
 }
 
+type DiffLine struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
 type File struct {
-	ID            string  `json:"id"`
-	NoteID        string  `json:"noteId"`
-	OriginalFile  string  `json:"originalFile"`
-	ProcessedFile *string `json:"processedFile,omitempty"`
-	URL           string  `json:"url"`
-	CreateTime    string  `json:"createTime"`
-	UpdateTime    *string `json:"updateTime,omitempty"`
+	ID               string         `json:"id"`
+	NoteID           string         `json:"noteId"`
+	OriginalFile     string         `json:"originalFile"`
+	ProcessedFile    *string        `json:"processedFile,omitempty"`
+	ProcessingStatus string         `json:"processingStatus"`
+	URL              string         `json:"url"`
+	Renditions       map[string]any `json:"renditions"`
+	Audio            *AudioMetadata `json:"audio,omitempty"`
+	CreateTime       string         `json:"createTime"`
+	UpdateTime       *string        `json:"updateTime,omitempty"`
 }
 
 type Mutation struct {
 }
 
 type Note struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"userId"`
+	NotebookID  *string `json:"notebookId,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	Content     *string `json:"content,omitempty"`
+	Files       []*File `json:"files,omitempty"`
+	Tags        []*Tag  `json:"tags,omitempty"`
+	CreateTime  string  `json:"createTime"`
+	UpdateTime  *string `json:"updateTime,omitempty"`
+	DeleteTime  *string `json:"deleteTime,omitempty"`
+	PinnedTime  *string `json:"pinnedTime,omitempty"`
+	ArchiveTime *string `json:"archiveTime,omitempty"`
+	Version     int32   `json:"version"`
+}
+
+type NoteChangedEvent struct {
+	Type   string `json:"type"`
+	NoteID string `json:"noteId"`
+	Note   *Note  `json:"note,omitempty"`
+	File   *File  `json:"file,omitempty"`
+	Hard   bool   `json:"hard"`
+	Time   string `json:"time"`
+}
+
+type NoteRevision struct {
 	ID         string  `json:"id"`
-	UserID     string  `json:"userId"`
+	NoteID     string  `json:"noteId"`
 	Title      *string `json:"title,omitempty"`
 	Content    *string `json:"content,omitempty"`
-	Files      []*File `json:"files,omitempty"`
+	CreateTime string  `json:"createTime"`
+}
+
+type NoteRevisionDiff struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Title   []*DiffLine `json:"title"`
+	Content []*DiffLine `json:"content"`
+}
+
+type NoteSearchResult struct {
+	Note           *Note   `json:"note"`
+	Rank           float64 `json:"rank"`
+	TitleSnippet   *string `json:"titleSnippet,omitempty"`
+	ContentSnippet *string `json:"contentSnippet,omitempty"`
+}
+
+type Notebook struct {
+	ID         string  `json:"id"`
+	UserID     string  `json:"userId"`
+	ParentID   *string `json:"parentId,omitempty"`
+	Name       string  `json:"name"`
 	CreateTime string  `json:"createTime"`
 	UpdateTime *string `json:"updateTime,omitempty"`
 }
 
 type NotesInput struct {
-	Cursor *string `json:"cursor,omitempty"`
-	Trash  *bool   `json:"trash,omitempty"`
+	Cursor          *string `json:"cursor,omitempty"`
+	Limit           *int32  `json:"limit,omitempty"`
+	Trash           *bool   `json:"trash,omitempty"`
+	Archived        *bool   `json:"archived,omitempty"`
+	IncludeArchived *bool   `json:"includeArchived,omitempty"`
+	TagID           *string `json:"tagId,omitempty"`
+	NotebookID      *string `json:"notebookId,omitempty"`
+	Sort            *string `json:"sort,omitempty"`
+	Direction       *string `json:"direction,omitempty"`
+	CreatedAfter    *string `json:"createdAfter,omitempty"`
+	CreatedBefore   *string `json:"createdBefore,omitempty"`
+	UpdatedAfter    *string `json:"updatedAfter,omitempty"`
+	UpdatedBefore   *string `json:"updatedBefore,omitempty"`
 }
 
 type NotesResponse struct {
-	Notes  []*Note `json:"notes,omitempty"`
-	Cursor string  `json:"cursor"`
+	Notes       []*Note `json:"notes,omitempty"`
+	HasNextPage bool    `json:"hasNextPage"`
+	NextCursor  *string `json:"nextCursor,omitempty"`
 }
 
 type PresignedURL struct {
@@ -69,9 +159,36 @@ type RefreshToken struct {
 	UpdateTime *string `json:"updateTime,omitempty"`
 }
 
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type SearchNotesInput struct {
+	Query  string  `json:"query"`
+	Cursor *string `json:"cursor,omitempty"`
+	Limit  *int32  `json:"limit,omitempty"`
+	Trash  *bool   `json:"trash,omitempty"`
+}
+
+type SearchNotesResponse struct {
+	Results []*NoteSearchResult `json:"results"`
+	Cursor  *string             `json:"cursor,omitempty"`
+}
+
+type Session struct {
+	ID             string  `json:"id"`
+	DeviceName     *string `json:"deviceName,omitempty"`
+	UserAgent      *string `json:"userAgent,omitempty"`
+	IPAddress      *string `json:"ipAddress,omitempty"`
+	Current        bool    `json:"current"`
+	SignInTime     string  `json:"signInTime"`
+	LastActiveTime string  `json:"lastActiveTime"`
+}
+
 type SignInInput struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string  `json:"email"`
+	Password   string  `json:"password"`
+	DeviceName *string `json:"deviceName,omitempty"`
 }
 
 type SignInResponse struct {
@@ -80,9 +197,49 @@ type SignInResponse struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
+type SignUpInput struct {
+	Name       string  `json:"name"`
+	Email      string  `json:"email"`
+	Password   string  `json:"password"`
+	DeviceName *string `json:"deviceName,omitempty"`
+}
+
+type Subscription struct {
+}
+
+type SyncResponse struct {
+	Notes      []*Note      `json:"notes"`
+	Files      []*File      `json:"files"`
+	Tombstones []*Tombstone `json:"tombstones"`
+	Token      string       `json:"token"`
+	HasMore    bool         `json:"hasMore"`
+}
+
+type Tag struct {
+	ID         string  `json:"id"`
+	UserID     string  `json:"userId"`
+	Name       string  `json:"name"`
+	CreateTime string  `json:"createTime"`
+	UpdateTime *string `json:"updateTime,omitempty"`
+}
+
+type TagInput struct {
+	Name string `json:"name"`
+}
+
+type Tombstone struct {
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityId"`
+	NoteID     string `json:"noteId"`
+	DeleteTime string `json:"deleteTime"`
+}
+
 type UpdateNoteInput struct {
-	Title   *string `json:"title,omitempty"`
-	Content *string `json:"content,omitempty"`
+	ExpectedVersion int32    `json:"expectedVersion"`
+	Title           *string  `json:"title,omitempty"`
+	Content         *string  `json:"content,omitempty"`
+	AddTagIds       []string `json:"addTagIds,omitempty"`
+	RemoveTagIds    []string `json:"removeTagIds,omitempty"`
 }
 
 type User struct {
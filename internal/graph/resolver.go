@@ -9,6 +9,8 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	AuthSrv service.AuthenticationService
-	NoteSrv service.NoteService
+	AuthSrv     service.AuthenticationService
+	NoteSrv     service.NoteService
+	TagSrv      service.TagService
+	NotebookSrv service.NotebookService
 }
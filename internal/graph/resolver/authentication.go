@@ -5,9 +5,11 @@ import (
 	"errors"
 	"time"
 
+	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/domain"
 	"github.com/daniarmas/notes/internal/graph/model"
 	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/internal/validate"
 	"github.com/google/uuid"
 )
 
@@ -26,9 +28,63 @@ func mapUser(user domain.User) *model.User {
 	}
 }
 
+// map from domain to graphql model session
+func mapSession(session domain.Session) *model.Session {
+	return &model.Session{
+		ID:             session.Id.String(),
+		DeviceName:     &session.DeviceName,
+		UserAgent:      &session.UserAgent,
+		IPAddress:      &session.IpAddress,
+		Current:        session.Current,
+		SignInTime:     session.SignInTime.Format(time.RFC3339),
+		LastActiveTime: session.LastActiveTime.Format(time.RFC3339),
+	}
+}
+
+// SignUp is the resolver for the signUp field.
+func SignUp(ctx context.Context, input model.SignUpInput, srv service.AuthenticationService) (*model.SignInResponse, error) {
+	// Validate the input
+	if input.Name == "" {
+		return nil, errors.New("field 'name' is required")
+	}
+	validationErrors := make(map[string]string)
+	validate.ValidateEmail(&validationErrors, input.Email)
+	if _, ok := validationErrors["email"]; ok {
+		return nil, errors.New("invalid email format")
+	}
+	if msg, ok := validate.ValidatePassword(input.Password)["password"]; ok {
+		return nil, errors.New(msg)
+	}
+
+	var deviceName string
+	if input.DeviceName != nil {
+		deviceName = *input.DeviceName
+	}
+
+	res, err := srv.SignUp(ctx, input.Name, input.Email, input.Password, deviceName)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.DuplicateRecord:
+			return nil, errors.New("a user with this email already exists")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+	return &model.SignInResponse{
+		User:         mapUser(res.User),
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+	}, nil
+}
+
 // SignIn is the resolver for the signIn field.
 func SignIn(ctx context.Context, input model.SignInInput, srv service.AuthenticationService) (*model.SignInResponse, error) {
-	res, err := srv.SignIn(ctx, input.Email, input.Password)
+	var deviceName string
+	if input.DeviceName != nil {
+		deviceName = *input.DeviceName
+	}
+
+	res, err := srv.SignIn(ctx, input.Email, input.Password, deviceName)
 	if err != nil {
 		switch err.Error() {
 		case "invalid credentials":
@@ -44,6 +100,28 @@ func SignIn(ctx context.Context, input model.SignInInput, srv service.Authentica
 	}, nil
 }
 
+// RefreshToken is the resolver for the refreshToken field.
+func RefreshToken(ctx context.Context, input model.RefreshTokenInput, srv service.AuthenticationService) (*model.SignInResponse, error) {
+	if input.RefreshToken == "" {
+		return nil, errors.New("field 'refreshToken' is required")
+	}
+
+	res, err := srv.RefreshToken(ctx, input.RefreshToken)
+	if err != nil {
+		switch err.Error() {
+		case "invalid refresh token", "refresh token expired", "refresh token reused":
+			return nil, err
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+	return &model.SignInResponse{
+		User:         mapUser(res.User),
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+	}, nil
+}
+
 // Me is the resolver for the me field.
 func Me(ctx context.Context, srv service.AuthenticationService) (*model.User, error) {
 	// Check if the user is authenticated
@@ -78,3 +156,68 @@ func SignOut(ctx context.Context, srv service.AuthenticationService) (bool, erro
 	}
 	return true, nil
 }
+
+// Sessions is the resolver for the sessions field.
+func Sessions(ctx context.Context, srv service.AuthenticationService) ([]*model.Session, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	res, err := srv.ListSessions(ctx)
+	if err != nil {
+		switch err.Error() {
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+	sessions := make([]*model.Session, len(res.Sessions))
+	for i, session := range res.Sessions {
+		sessions[i] = mapSession(session)
+	}
+	return sessions, nil
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func RevokeSession(ctx context.Context, id string, srv service.AuthenticationService) (bool, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return false, errors.New("unauthenticated")
+	}
+
+	sessionId, err := uuid.Parse(id)
+	if err != nil {
+		return false, errors.New("invalid session id")
+	}
+
+	err = srv.RevokeSession(ctx, sessionId)
+	if err != nil {
+		switch err.Error() {
+		case "session not found":
+			return false, errors.New("session not found")
+		default:
+			return false, errors.New("internal server error")
+		}
+	}
+	return true, nil
+}
+
+// RevokeOtherSessions is the resolver for the revokeOtherSessions field.
+func RevokeOtherSessions(ctx context.Context, srv service.AuthenticationService) (bool, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return false, errors.New("unauthenticated")
+	}
+
+	err := srv.RevokeOtherSessions(ctx)
+	if err != nil {
+		switch err.Error() {
+		default:
+			return false, errors.New("internal server error")
+		}
+	}
+	return true, nil
+}
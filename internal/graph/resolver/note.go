@@ -3,13 +3,16 @@ package resolver
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/daniarmas/notes/internal/domain"
 	"github.com/daniarmas/notes/internal/graph/model"
 	"github.com/daniarmas/notes/internal/service"
-	"github.com/daniarmas/notes/internal/utils"
 	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 func mapFile(file domain.File) *model.File {
@@ -18,14 +21,39 @@ func mapFile(file domain.File) *model.File {
 		updateTime = file.UpdateTime.Format(time.RFC3339)
 
 	}
+	renditions := make(map[string]any, len(file.RenditionUrls))
+	for size, url := range file.RenditionUrls {
+		renditions[size] = url
+	}
 	return &model.File{
-		ID:            file.Id.String(),
-		NoteID:        file.NoteId.String(),
-		OriginalFile:  file.OriginalFile,
-		ProcessedFile: &file.ProcessedFile,
-		URL:           file.Url,
-		CreateTime:    file.CreateTime.Format(time.RFC3339),
-		UpdateTime:    &updateTime,
+		ID:               file.Id.String(),
+		NoteID:           file.NoteId.String(),
+		OriginalFile:     file.OriginalFile,
+		ProcessedFile:    &file.ProcessedFile,
+		ProcessingStatus: file.ProcessingStatus,
+		URL:              file.Url,
+		Renditions:       renditions,
+		Audio:            mapAudioMetadata(file.Audio),
+		CreateTime:       file.CreateTime.Format(time.RFC3339),
+		UpdateTime:       &updateTime,
+	}
+}
+
+// func to map domain.AudioMetadata to model.AudioMetadata, the files without audio metadata have none
+func mapAudioMetadata(audio *domain.AudioMetadata) *model.AudioMetadata {
+	if audio == nil {
+		return nil
+	}
+	peaks := make([]float64, 0, len(audio.Peaks))
+	for _, peak := range audio.Peaks {
+		// Format the peak as a float32, so it keeps its shortest representation
+		value, _ := strconv.ParseFloat(strconv.FormatFloat(float64(peak), 'f', -1, 32), 64)
+		peaks = append(peaks, value)
+	}
+	return &model.AudioMetadata{
+		Duration:   audio.Duration,
+		SampleRate: audio.SampleRate,
+		Peaks:      peaks,
 	}
 }
 
@@ -40,17 +68,88 @@ func mapNote(note domain.Note) *model.Note {
 	for i, file := range note.Files {
 		files[i] = mapFile(*file)
 	}
+	// map domain.Tag to model.Tag
+	tags := make([]*model.Tag, len(note.Tags))
+	for i, tag := range note.Tags {
+		tags[i] = mapTag(*tag)
+	}
+	// Only the notes in the trash have a delete time
+	var deleteTime *string
+	if !note.DeleteTime.IsZero() {
+		formatted := note.DeleteTime.Format(time.RFC3339)
+		deleteTime = &formatted
+	}
+	// Only the pinned and the archived notes have a pinned and an archive time
+	var pinnedTime, archiveTime *string
+	if !note.PinnedTime.IsZero() {
+		formatted := note.PinnedTime.Format(time.RFC3339)
+		pinnedTime = &formatted
+	}
+	if !note.ArchiveTime.IsZero() {
+		formatted := note.ArchiveTime.Format(time.RFC3339)
+		archiveTime = &formatted
+	}
+	// A note outside of any notebook has no notebook id
+	var notebookId *string
+	if note.NotebookId != nil {
+		id := note.NotebookId.String()
+		notebookId = &id
+	}
 	return &model.Note{
-		ID:         note.Id.String(),
-		UserID:     note.UserId.String(),
-		Title:      &note.Title,
-		Content:    &note.Content,
-		Files:      files,
-		CreateTime: note.CreateTime.Format(time.RFC3339),
-		UpdateTime: &updateTime,
+		ID:          note.Id.String(),
+		UserID:      note.UserId.String(),
+		NotebookID:  notebookId,
+		Title:       &note.Title,
+		Content:     &note.Content,
+		Files:       files,
+		Tags:        tags,
+		CreateTime:  note.CreateTime.Format(time.RFC3339),
+		UpdateTime:  &updateTime,
+		DeleteTime:  deleteTime,
+		PinnedTime:  pinnedTime,
+		ArchiveTime: archiveTime,
+		Version:     note.Version,
 	}
 }
 
+// noteConflictError returns the error of a stale update, the current note is in its extensions
+// so the client can merge the changes
+func noteConflictError(current *domain.Note) error {
+	return &gqlerror.Error{
+		Message: "the note was modified by another request",
+		Extensions: map[string]interface{}{
+			"code":        "CONFLICT",
+			"currentNote": mapNote(*current),
+		},
+	}
+}
+
+// GetNote is the resolver for the note field.
+func GetNote(ctx context.Context, id string, srv service.NoteService) (*model.Note, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid note id")
+	}
+
+	res, err := srv.GetNote(ctx, noteId)
+	if err != nil {
+		switch err.Error() {
+		case "note not found":
+			return nil, errors.New("note not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapNote(*res), nil
+}
+
 // ListNotes is the resolver for the notes field.
 func ListNotes(ctx context.Context, input *model.NotesInput, srv service.NoteService) (*model.NotesResponse, error) {
 	// Check if the user is authenticated
@@ -59,56 +158,118 @@ func ListNotes(ctx context.Context, input *model.NotesInput, srv service.NoteSer
 		return nil, errors.New("unauthenticated")
 	}
 
-	// Get the cursor from the query parameters
-	var cursorQueryParam string
-	if input != nil && input.Cursor != nil {
-		cursorQueryParam = *input.Cursor
+	// Parse the cursor returned by the previous page
+	var cursor *domain.NoteListCursor
+	if input != nil && input.Cursor != nil && *input.Cursor != "" {
+		c, err := domain.DecodeNoteListCursor(*input.Cursor)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		cursor = c
+	}
+
+	// Parse the page size
+	limit := domain.DefaultNotesPageSize
+	if input != nil && input.Limit != nil {
+		if *input.Limit < 1 || *input.Limit > domain.MaxNotesPageSize {
+			return nil, fmt.Errorf("limit must be between 1 and %d", domain.MaxNotesPageSize)
+		}
+		limit = int(*input.Limit)
+	}
+
+	// Parse the tag and notebook filters
+	var (
+		filter domain.NoteFilter
+		err    error
+	)
+	if input != nil && input.TagID != nil && *input.TagID != "" {
+		filter.TagId, err = uuid.Parse(*input.TagID)
+		if err != nil {
+			return nil, errors.New("invalid tag id")
+		}
+	}
+	if input != nil && input.NotebookID != nil && *input.NotebookID != "" {
+		filter.NotebookId, err = uuid.Parse(*input.NotebookID)
+		if err != nil {
+			return nil, errors.New("invalid notebook id")
+		}
+	}
+
+	// Parse the date range filters
+	if input != nil {
+		dateRange := []struct {
+			name  string
+			input *string
+			value *time.Time
+		}{
+			{"createdAfter", input.CreatedAfter, &filter.CreatedAfter},
+			{"createdBefore", input.CreatedBefore, &filter.CreatedBefore},
+			{"updatedAfter", input.UpdatedAfter, &filter.UpdatedAfter},
+			{"updatedBefore", input.UpdatedBefore, &filter.UpdatedBefore},
+		}
+		for _, field := range dateRange {
+			if field.input == nil || *field.input == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, *field.input)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time format for %s, must use RFC3339 format", field.name)
+			}
+			*field.value = t.UTC()
+		}
+	}
+
+	// Parse the sort key and the direction
+	var sortKey, direction string
+	if input != nil && input.Sort != nil {
+		sortKey = *input.Sort
 	}
-	// parse the cursor query parameter
-	if cursorQueryParam == "" {
-		cursorQueryParam = time.Now().UTC().Format(time.RFC3339)
+	if input != nil && input.Direction != nil {
+		direction = *input.Direction
 	}
-	cursor, err := utils.ParseTime(cursorQueryParam)
-	if err != nil && cursorQueryParam != "" {
-		msg := "Invalid time format for the cursor query parameter. Must use RFC3339 format"
-		return nil, errors.New(msg)
+	sort, err := domain.ParseNoteSort(sortKey, direction)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if trash is true
-	var notes *[]domain.Note
+	// Include the archived notes if requested
+	filter.IncludeArchived = input != nil && input.IncludeArchived != nil && *input.IncludeArchived
+
+	// Check if trash or archived is true
+	var res *service.ListNotesResponse
 
 	if input != nil && input.Trash != nil && *input.Trash {
-		notes, err = srv.ListTrashNotesByUser(ctx, cursor)
+		res, err = srv.ListTrashNotesByUser(ctx, cursor, limit, filter)
+	} else if input != nil && input.Archived != nil && *input.Archived {
+		res, err = srv.ListArchivedNotesByUser(ctx, cursor, limit, filter)
 	} else {
-		notes, err = srv.ListNotesByUser(ctx, cursor)
+		res, err = srv.ListNotesByUser(ctx, sort, cursor, limit, filter)
 	}
 
 	if err != nil {
 		switch err.Error() {
+		case "cursor does not match the sort":
+			return nil, err
 		default:
 			return nil, errors.New("internal server error")
 		}
 	}
 
-	// Get the next cursor
-	notesSlice := *notes
-	var nextCursor time.Time
-	if len(notesSlice) > 0 {
-		nextCursor = notesSlice[len(notesSlice)-1].UpdateTime
-	} else {
-		// Handle the case where notesSlice is empty
-		nextCursor = time.Now().UTC()
-	}
-
 	// Parse []domain.Note to []*model.Note
-	notesRes := make([]*model.Note, len(notesSlice))
-	for i, note := range notesSlice {
+	notesRes := make([]*model.Note, len(res.Notes))
+	for i, note := range res.Notes {
 		notesRes[i] = mapNote(note)
 	}
 
+	var nextCursor *string
+	if res.NextCursor != "" {
+		nextCursor = &res.NextCursor
+	}
+
 	return &model.NotesResponse{
-		Notes:  notesRes,
-		Cursor: nextCursor.Format(time.RFC3339),
+		Notes:       notesRes,
+		HasNextPage: res.HasNextPage,
+		NextCursor:  nextCursor,
 	}, nil
 }
 
@@ -138,17 +299,31 @@ func CreateNote(ctx context.Context, input model.CreateNoteInput, srv service.No
 		objectNames[i] = *objectName
 	}
 
+	tagIds, err := parseIds(input.TagIds)
+	if err != nil {
+		return nil, errors.New("invalid tag id")
+	}
+
+	notebookId, err := parseOptionalId(input.NotebookID)
+	if err != nil {
+		return nil, errors.New("invalid notebook id")
+	}
+
 	// Validate the input
 	if input.Title == nil || *input.Title == "" {
 		return nil, errors.New("field 'title' is required")
 	}
 
-	res, err := srv.CreateNote(ctx, title, content, objectNames)
+	res, err := srv.CreateNote(ctx, title, content, objectNames, tagIds, notebookId)
 	if err != nil {
 		switch err.Error() {
+		case "notebook not found":
+			return nil, errors.New("notebook not found")
 		case "objects not found":
 			msg := "One or more objects not found in the object storage service"
 			return nil, errors.New(msg)
+		case "tag not found":
+			return nil, errors.New("one or more tags not found")
 		default:
 			return nil, errors.New("internal server error")
 		}
@@ -268,6 +443,127 @@ func RestoreNote(ctx context.Context, id string, srv service.NoteService) (bool,
 	return true, nil
 }
 
+// SoftDeleteNotes is the resolver for the softDeleteNotes field.
+func SoftDeleteNotes(ctx context.Context, ids []string, srv service.NoteService) (*model.BulkNotesResponse, error) {
+	return bulkNotes(ctx, ids, srv.SoftDeleteNotes)
+}
+
+// RestoreNotes is the resolver for the restoreNotes field.
+func RestoreNotes(ctx context.Context, ids []string, srv service.NoteService) (*model.BulkNotesResponse, error) {
+	return bulkNotes(ctx, ids, srv.RestoreNotes)
+}
+
+// DeleteNotes is the resolver for the deleteNotes field.
+func DeleteNotes(ctx context.Context, ids []string, srv service.NoteService) (*model.BulkNotesResponse, error) {
+	return bulkNotes(ctx, ids, srv.HardDeleteNotes)
+}
+
+// bulkNotes resolves the bulk note operations, apply is the service method of the operation
+func bulkNotes(ctx context.Context, ids []string, apply func(ctx context.Context, ids []uuid.UUID) (*service.BulkNotesResponse, error)) (*model.BulkNotesResponse, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteIds := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		noteId, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errors.New("invalid note id")
+		}
+		noteIds = append(noteIds, noteId)
+	}
+
+	res, err := apply(ctx, noteIds)
+	if err != nil {
+		switch err.Error() {
+		case "ids required", "too many ids":
+			return nil, fmt.Errorf("between 1 and %d note ids are required", domain.MaxBulkNotes)
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapBulkNotesResponse(res), nil
+}
+
+// EmptyTrash is the resolver for the emptyTrash field.
+func EmptyTrash(ctx context.Context, srv service.NoteService) (*model.BulkNotesResponse, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	res, err := srv.EmptyTrash(ctx)
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	return mapBulkNotesResponse(res), nil
+}
+
+// mapBulkNotesResponse maps the results of a bulk note operation to the graphql model
+func mapBulkNotesResponse(res *service.BulkNotesResponse) *model.BulkNotesResponse {
+	results := make([]*model.BulkNoteResult, 0, len(res.Results))
+	for _, result := range res.Results {
+		item := &model.BulkNoteResult{ID: result.Id.String(), Ok: result.Ok}
+		if result.Error != "" {
+			msg := result.Error
+			item.Error = &msg
+		}
+		results = append(results, item)
+	}
+	return &model.BulkNotesResponse{Results: results, HasMore: res.HasMore}
+}
+
+// PinNote is the resolver for the pinNote field.
+func PinNote(ctx context.Context, id string, srv service.NoteService) (*model.Note, error) {
+	return setNoteState(ctx, id, srv.PinNote)
+}
+
+// UnpinNote is the resolver for the unpinNote field.
+func UnpinNote(ctx context.Context, id string, srv service.NoteService) (*model.Note, error) {
+	return setNoteState(ctx, id, srv.UnpinNote)
+}
+
+// ArchiveNote is the resolver for the archiveNote field.
+func ArchiveNote(ctx context.Context, id string, srv service.NoteService) (*model.Note, error) {
+	return setNoteState(ctx, id, srv.ArchiveNote)
+}
+
+// UnarchiveNote is the resolver for the unarchiveNote field.
+func UnarchiveNote(ctx context.Context, id string, srv service.NoteService) (*model.Note, error) {
+	return setNoteState(ctx, id, srv.UnarchiveNote)
+}
+
+// setNoteState resolves the mutations changing the pinned or archived state of a note, set is the service method of the change
+func setNoteState(ctx context.Context, id string, set func(ctx context.Context, id uuid.UUID) (*domain.Note, error)) (*model.Note, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid note id")
+	}
+
+	res, err := set(ctx, noteId)
+	if err != nil {
+		switch err.Error() {
+		case "note not found":
+			return nil, errors.New("note not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapNote(*res), nil
+}
+
 // UpdateNote is the resolver for the updateNote field.
 func UpdateNote(ctx context.Context, id string, input model.UpdateNoteInput, srv service.NoteService) (*model.Note, error) {
 	// Check if the user is authenticated
@@ -294,22 +590,70 @@ func UpdateNote(ctx context.Context, id string, input model.UpdateNoteInput, srv
 		content = *input.Content
 	}
 
+	addTagIds, err := parseIds(input.AddTagIds)
+	if err != nil {
+		return nil, errors.New("invalid tag id")
+	}
+	removeTagIds, err := parseIds(input.RemoveTagIds)
+	if err != nil {
+		return nil, errors.New("invalid tag id")
+	}
+
 	// Validate the input
-	if (input.Title == nil || *input.Title == "") && (input.Content == nil || *input.Content == "") {
-		return nil, errors.New("field 'title' or 'content' is required")
+	if (input.Title == nil || *input.Title == "") && (input.Content == nil || *input.Content == "") && len(addTagIds) == 0 && len(removeTagIds) == 0 {
+		return nil, errors.New("field 'title', 'content', 'addTagIds' or 'removeTagIds' is required")
 	}
 
 	note := &domain.Note{
 		Id:      noteId,
 		Title:   title,
 		Content: content,
+		Version: input.ExpectedVersion,
+	}
+
+	res, err := srv.UpdateNote(ctx, note, addTagIds, removeTagIds)
+	if err != nil {
+		switch err.Error() {
+		case "note not found":
+			return nil, errors.New("note not found")
+		case "note version conflict":
+			return nil, noteConflictError(res)
+		case "tag not found":
+			return nil, errors.New("one or more tags not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
 	}
 
-	res, err := srv.UpdateNote(ctx, note)
+	return mapNote(*res), nil
+}
+
+// MoveNote is the resolver for the moveNote field.
+func MoveNote(ctx context.Context, id string, notebookID *string, srv service.NoteService) (*model.Note, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid note id")
+	}
+
+	// A null notebook takes the note out of its notebook
+	notebookId, err := parseOptionalId(notebookID)
+	if err != nil {
+		return nil, errors.New("invalid notebook id")
+	}
+
+	res, err := srv.MoveNote(ctx, noteId, notebookId)
 	if err != nil {
 		switch err.Error() {
 		case "note not found":
 			return nil, errors.New("note not found")
+		case "notebook not found":
+			return nil, errors.New("notebook not found")
 		default:
 			return nil, errors.New("internal server error")
 		}
@@ -317,3 +661,70 @@ func UpdateNote(ctx context.Context, id string, input model.UpdateNoteInput, srv
 
 	return mapNote(*res), nil
 }
+
+// SearchNotes is the resolver for the searchNotes field.
+func SearchNotes(ctx context.Context, input model.SearchNotesInput, srv service.NoteService) (*model.SearchNotesResponse, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	// Validate the input
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, errors.New("field 'query' is required")
+	}
+
+	// Parse the cursor returned by the previous page
+	var cursor *domain.NoteSearchCursor
+	if input.Cursor != nil && *input.Cursor != "" {
+		c, err := domain.DecodeNoteSearchCursor(*input.Cursor)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		cursor = c
+	}
+
+	// Parse the page size
+	limit := domain.DefaultNotesPageSize
+	if input.Limit != nil {
+		if *input.Limit < 1 || *input.Limit > domain.MaxNotesPageSize {
+			return nil, fmt.Errorf("limit must be between 1 and %d", domain.MaxNotesPageSize)
+		}
+		limit = int(*input.Limit)
+	}
+
+	includeTrash := input.Trash != nil && *input.Trash
+
+	res, err := srv.SearchNotes(ctx, query, includeTrash, cursor, limit)
+	if err != nil {
+		switch err.Error() {
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	// Parse []domain.NoteSearchResult to []*model.NoteSearchResult
+	results := make([]*model.NoteSearchResult, len(res.Results))
+	for i, result := range res.Results {
+		titleSnippet := result.TitleSnippet
+		contentSnippet := result.ContentSnippet
+		results[i] = &model.NoteSearchResult{
+			Note:           mapNote(result.Note),
+			Rank:           result.Rank,
+			TitleSnippet:   &titleSnippet,
+			ContentSnippet: &contentSnippet,
+		}
+	}
+
+	var nextCursor *string
+	if res.NextCursor != "" {
+		nextCursor = &res.NextCursor
+	}
+
+	return &model.SearchNotesResponse{
+		Results: results,
+		Cursor:  nextCursor,
+	}, nil
+}
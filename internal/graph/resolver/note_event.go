@@ -0,0 +1,61 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/graph/model"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// func to map domain.NoteEvent to model.NoteChangedEvent
+func mapNoteEvent(event domain.NoteEvent) *model.NoteChangedEvent {
+	var note *model.Note
+	if event.Note != nil {
+		note = mapNote(*event.Note)
+	}
+	var file *model.File
+	if event.File != nil {
+		file = mapFile(*event.File)
+	}
+	return &model.NoteChangedEvent{
+		Type:   event.Type,
+		NoteID: event.NoteId.String(),
+		Note:   note,
+		File:   file,
+		Hard:   event.Hard,
+		Time:   event.Time.Format(time.RFC3339),
+	}
+}
+
+// NoteChanged is the resolver for the noteChanged field.
+func NoteChanged(ctx context.Context, srv service.NoteService) (<-chan *model.NoteChangedEvent, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	events, err := srv.SubscribeNoteEvents(ctx)
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	// Map the events until the subscription is closed
+	res := make(chan *model.NoteChangedEvent)
+	go func() {
+		defer close(res)
+		for event := range events {
+			select {
+			case res <- mapNoteEvent(event):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return res, nil
+}
@@ -0,0 +1,167 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/graph/model"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// func to map domain.NoteRevision to model.NoteRevision
+func mapNoteRevision(revision domain.NoteRevision) *model.NoteRevision {
+	return &model.NoteRevision{
+		ID:         revision.Id.String(),
+		NoteID:     revision.NoteId.String(),
+		Title:      &revision.Title,
+		Content:    &revision.Content,
+		CreateTime: revision.CreateTime.Format(time.RFC3339),
+	}
+}
+
+// func to map []domain.DiffLine to []*model.DiffLine
+func mapDiffLines(lines []domain.DiffLine) []*model.DiffLine {
+	res := make([]*model.DiffLine, len(lines))
+	for i, line := range lines {
+		res[i] = &model.DiffLine{Type: line.Type, Text: line.Text}
+	}
+	return res
+}
+
+// NoteRevisions is the resolver for the noteRevisions field.
+func NoteRevisions(ctx context.Context, noteID string, srv service.NoteService) ([]*model.NoteRevision, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteId, err := uuid.Parse(noteID)
+	if err != nil {
+		return nil, errors.New("invalid note id")
+	}
+
+	revisions, err := srv.ListNoteRevisions(ctx, noteId)
+	if err != nil {
+		switch err.Error() {
+		case "note not found":
+			return nil, errors.New("note not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	// Parse []domain.NoteRevision to []*model.NoteRevision
+	res := make([]*model.NoteRevision, len(*revisions))
+	for i, revision := range *revisions {
+		res[i] = mapNoteRevision(revision)
+	}
+
+	return res, nil
+}
+
+// NoteRevision is the resolver for the noteRevision field.
+func NoteRevision(ctx context.Context, noteID string, id string, srv service.NoteService) (*model.NoteRevision, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteId, err := uuid.Parse(noteID)
+	if err != nil {
+		return nil, errors.New("invalid note id")
+	}
+	revisionId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid revision id")
+	}
+
+	res, err := srv.GetNoteRevision(ctx, noteId, revisionId)
+	if err != nil {
+		switch err.Error() {
+		case "revision not found":
+			return nil, errors.New("revision not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapNoteRevision(*res), nil
+}
+
+// NoteRevisionDiff is the resolver for the noteRevisionDiff field.
+func NoteRevisionDiff(ctx context.Context, noteID string, from string, to string, srv service.NoteService) (*model.NoteRevisionDiff, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteId, err := uuid.Parse(noteID)
+	if err != nil {
+		return nil, errors.New("invalid note id")
+	}
+	fromId, err := uuid.Parse(from)
+	if err != nil {
+		return nil, errors.New("invalid revision id")
+	}
+	toId, err := uuid.Parse(to)
+	if err != nil {
+		return nil, errors.New("invalid revision id")
+	}
+
+	res, err := srv.DiffNoteRevisions(ctx, noteId, fromId, toId)
+	if err != nil {
+		switch err.Error() {
+		case "revision not found":
+			return nil, errors.New("revision not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return &model.NoteRevisionDiff{
+		From:    res.From.String(),
+		To:      res.To.String(),
+		Title:   mapDiffLines(res.Title),
+		Content: mapDiffLines(res.Content),
+	}, nil
+}
+
+// RestoreNoteRevision is the resolver for the restoreNoteRevision field.
+func RestoreNoteRevision(ctx context.Context, noteID string, id string, srv service.NoteService) (*model.Note, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	noteId, err := uuid.Parse(noteID)
+	if err != nil {
+		return nil, errors.New("invalid note id")
+	}
+	revisionId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid revision id")
+	}
+
+	res, err := srv.RestoreNoteRevision(ctx, noteId, revisionId)
+	if err != nil {
+		switch err.Error() {
+		case "revision not found":
+			return nil, errors.New("revision not found")
+		case "note not found":
+			return nil, errors.New("note not found")
+		case "note version conflict":
+			return nil, noteConflictError(res)
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapNote(*res), nil
+}
@@ -0,0 +1,197 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/graph/model"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// func to map domain.Notebook to model.Notebook
+func mapNotebook(notebook domain.Notebook) *model.Notebook {
+	var updateTime string
+	if !notebook.UpdateTime.IsZero() {
+		updateTime = notebook.UpdateTime.Format(time.RFC3339)
+	}
+	var parentId *string
+	if notebook.ParentId != nil {
+		id := notebook.ParentId.String()
+		parentId = &id
+	}
+	return &model.Notebook{
+		ID:         notebook.Id.String(),
+		UserID:     notebook.UserId.String(),
+		ParentID:   parentId,
+		Name:       notebook.Name,
+		CreateTime: notebook.CreateTime.Format(time.RFC3339),
+		UpdateTime: &updateTime,
+	}
+}
+
+// parseOptionalId parses an optional id from the input, a nil or empty id is returned as nil
+func parseOptionalId(id *string) (*uuid.UUID, error) {
+	if id == nil || *id == "" {
+		return nil, nil
+	}
+	parsed, err := uuid.Parse(*id)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// ListNotebooks is the resolver for the notebooks field.
+func ListNotebooks(ctx context.Context, srv service.NotebookService) ([]*model.Notebook, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	notebooks, err := srv.ListNotebooks(ctx)
+	if err != nil {
+		switch err.Error() {
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	// Parse []domain.Notebook to []*model.Notebook
+	res := make([]*model.Notebook, len(*notebooks))
+	for i, notebook := range *notebooks {
+		res[i] = mapNotebook(notebook)
+	}
+
+	return res, nil
+}
+
+// CreateNotebook is the resolver for the createNotebook field.
+func CreateNotebook(ctx context.Context, input model.CreateNotebookInput, srv service.NotebookService) (*model.Notebook, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	// Validate the input
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("field 'name' is required")
+	}
+
+	parentId, err := parseOptionalId(input.ParentID)
+	if err != nil {
+		return nil, errors.New("invalid parent notebook id")
+	}
+
+	res, err := srv.CreateNotebook(ctx, name, parentId)
+	if err != nil {
+		switch err.Error() {
+		case "parent notebook not found":
+			return nil, errors.New("parent notebook not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapNotebook(*res), nil
+}
+
+// RenameNotebook is the resolver for the renameNotebook field.
+func RenameNotebook(ctx context.Context, id string, name string, srv service.NotebookService) (*model.Notebook, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	notebookId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid notebook id")
+	}
+
+	// Validate the input
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("field 'name' is required")
+	}
+
+	res, err := srv.RenameNotebook(ctx, notebookId, name)
+	if err != nil {
+		switch err.Error() {
+		case "notebook not found":
+			return nil, errors.New("notebook not found")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapNotebook(*res), nil
+}
+
+// MoveNotebook is the resolver for the moveNotebook field.
+func MoveNotebook(ctx context.Context, id string, parentID *string, srv service.NotebookService) (*model.Notebook, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	notebookId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid notebook id")
+	}
+
+	// A null parent moves the notebook to the root
+	parentId, err := parseOptionalId(parentID)
+	if err != nil {
+		return nil, errors.New("invalid parent notebook id")
+	}
+
+	res, err := srv.MoveNotebook(ctx, notebookId, parentId)
+	if err != nil {
+		switch err.Error() {
+		case "notebook not found":
+			return nil, errors.New("notebook not found")
+		case "parent notebook not found":
+			return nil, errors.New("parent notebook not found")
+		case "invalid parent notebook":
+			return nil, errors.New("a notebook can't be moved inside itself or one of its descendants")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapNotebook(*res), nil
+}
+
+// DeleteNotebook is the resolver for the deleteNotebook field.
+func DeleteNotebook(ctx context.Context, id string, trashNotes *bool, srv service.NotebookService) (bool, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return false, errors.New("unauthenticated")
+	}
+
+	notebookId, err := uuid.Parse(id)
+	if err != nil {
+		return false, errors.New("invalid notebook id")
+	}
+
+	err = srv.DeleteNotebook(ctx, notebookId, trashNotes != nil && *trashNotes)
+	if err != nil {
+		switch err.Error() {
+		case "notebook not found":
+			return false, errors.New("notebook not found")
+		default:
+			return false, errors.New("internal server error")
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,68 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/graph/model"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// func to map domain.Tombstone to model.Tombstone
+func mapTombstone(tombstone domain.Tombstone) *model.Tombstone {
+	return &model.Tombstone{
+		EntityType: tombstone.EntityType,
+		EntityID:   tombstone.EntityId.String(),
+		NoteID:     tombstone.NoteId.String(),
+		DeleteTime: tombstone.DeleteTime.Format(time.RFC3339),
+	}
+}
+
+// Sync is the resolver for the sync field.
+func Sync(ctx context.Context, since *string, srv service.NoteService) (*model.SyncResponse, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	var token string
+	if since != nil {
+		token = *since
+	}
+
+	changes, err := srv.Sync(ctx, token)
+	if err != nil {
+		switch err.Error() {
+		case "invalid sync token":
+			return nil, errors.New("invalid sync token")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	// Parse the changes to the model
+	notes := make([]*model.Note, len(changes.Notes))
+	for i, note := range changes.Notes {
+		notes[i] = mapNote(note)
+	}
+	files := make([]*model.File, len(changes.Files))
+	for i, file := range changes.Files {
+		files[i] = mapFile(file)
+	}
+	tombstones := make([]*model.Tombstone, len(changes.Tombstones))
+	for i, tombstone := range changes.Tombstones {
+		tombstones[i] = mapTombstone(tombstone)
+	}
+
+	return &model.SyncResponse{
+		Notes:      notes,
+		Files:      files,
+		Tombstones: tombstones,
+		Token:      changes.Token,
+		HasMore:    changes.HasMore,
+	}, nil
+}
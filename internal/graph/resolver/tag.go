@@ -0,0 +1,153 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/graph/model"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// func to map domain.Tag to model.Tag
+func mapTag(tag domain.Tag) *model.Tag {
+	var updateTime string
+	if !tag.UpdateTime.IsZero() {
+		updateTime = tag.UpdateTime.Format(time.RFC3339)
+	}
+	return &model.Tag{
+		ID:         tag.Id.String(),
+		UserID:     tag.UserId.String(),
+		Name:       tag.Name,
+		CreateTime: tag.CreateTime.Format(time.RFC3339),
+		UpdateTime: &updateTime,
+	}
+}
+
+// parseIds parses a list of ids from the input
+func parseIds(ids []string) ([]uuid.UUID, error) {
+	res := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = parsed
+	}
+	return res, nil
+}
+
+// ListTags is the resolver for the tags field.
+func ListTags(ctx context.Context, srv service.TagService) ([]*model.Tag, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	tags, err := srv.ListTags(ctx)
+	if err != nil {
+		switch err.Error() {
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	// Parse []domain.Tag to []*model.Tag
+	res := make([]*model.Tag, len(*tags))
+	for i, tag := range *tags {
+		res[i] = mapTag(tag)
+	}
+
+	return res, nil
+}
+
+// CreateTag is the resolver for the createTag field.
+func CreateTag(ctx context.Context, input model.TagInput, srv service.TagService) (*model.Tag, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	// Validate the input
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("field 'name' is required")
+	}
+
+	res, err := srv.CreateTag(ctx, name)
+	if err != nil {
+		switch err.Error() {
+		case "tag already exists":
+			return nil, errors.New("a tag with this name already exists")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapTag(*res), nil
+}
+
+// UpdateTag is the resolver for the updateTag field.
+func UpdateTag(ctx context.Context, id string, input model.TagInput, srv service.TagService) (*model.Tag, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return nil, errors.New("unauthenticated")
+	}
+
+	tagId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid tag id")
+	}
+
+	// Validate the input
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("field 'name' is required")
+	}
+
+	res, err := srv.UpdateTag(ctx, tagId, name)
+	if err != nil {
+		switch err.Error() {
+		case "tag not found":
+			return nil, errors.New("tag not found")
+		case "tag already exists":
+			return nil, errors.New("a tag with this name already exists")
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return mapTag(*res), nil
+}
+
+// DeleteTag is the resolver for the deleteTag field.
+func DeleteTag(ctx context.Context, id string, srv service.TagService) (bool, error) {
+	// Check if the user is authenticated
+	userId := domain.GetUserIdFromContext(ctx)
+	if userId == uuid.Nil {
+		return false, errors.New("unauthenticated")
+	}
+
+	tagId, err := uuid.Parse(id)
+	if err != nil {
+		return false, errors.New("invalid tag id")
+	}
+
+	err = srv.DeleteTag(ctx, tagId)
+	if err != nil {
+		switch err.Error() {
+		case "tag not found":
+			return false, errors.New("tag not found")
+		default:
+			return false, errors.New("internal server error")
+		}
+	}
+
+	return true, nil
+}
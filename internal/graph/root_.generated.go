@@ -37,6 +37,7 @@ type Config struct {
 type ResolverRoot interface {
 	Mutation() MutationResolver
 	Query() QueryResolver
+	Subscription() SubscriptionResolver
 }
 
 type DirectiveRoot struct {
@@ -51,44 +52,137 @@ type ComplexityRoot struct {
 		UserID         func(childComplexity int) int
 	}
 
+	AudioMetadata struct {
+		Duration   func(childComplexity int) int
+		Peaks      func(childComplexity int) int
+		SampleRate func(childComplexity int) int
+	}
+
+	BulkNoteResult struct {
+		Error func(childComplexity int) int
+		ID    func(childComplexity int) int
+		Ok    func(childComplexity int) int
+	}
+
+	BulkNotesResponse struct {
+		HasMore func(childComplexity int) int
+		Results func(childComplexity int) int
+	}
+
 	CreatePresignedUrlsResponse struct {
 		Urls func(childComplexity int) int
 	}
 
+	DiffLine struct {
+		Text func(childComplexity int) int
+		Type func(childComplexity int) int
+	}
+
 	File struct {
-		CreateTime    func(childComplexity int) int
-		ID            func(childComplexity int) int
-		NoteID        func(childComplexity int) int
-		OriginalFile  func(childComplexity int) int
-		ProcessedFile func(childComplexity int) int
-		URL           func(childComplexity int) int
-		UpdateTime    func(childComplexity int) int
+		Audio            func(childComplexity int) int
+		CreateTime       func(childComplexity int) int
+		ID               func(childComplexity int) int
+		NoteID           func(childComplexity int) int
+		OriginalFile     func(childComplexity int) int
+		ProcessedFile    func(childComplexity int) int
+		ProcessingStatus func(childComplexity int) int
+		Renditions       func(childComplexity int) int
+		URL              func(childComplexity int) int
+		UpdateTime       func(childComplexity int) int
 	}
 
 	Mutation struct {
-		CreateNote         func(childComplexity int, input model.CreateNoteInput) int
-		CreatePresignedURL func(childComplexity int, objectName []string) int
-		DeleteNote         func(childComplexity int, id string) int
-		RestoreNote        func(childComplexity int, id string) int
-		SignIn             func(childComplexity int, input model.SignInInput) int
-		SignOut            func(childComplexity int) int
-		SoftDeleteNote     func(childComplexity int, id string) int
-		UpdateNote         func(childComplexity int, id string, input model.UpdateNoteInput) int
+		ArchiveNote         func(childComplexity int, id string) int
+		CreateNote          func(childComplexity int, input model.CreateNoteInput) int
+		CreateNotebook      func(childComplexity int, input model.CreateNotebookInput) int
+		CreatePresignedURL  func(childComplexity int, objectName []string) int
+		CreateTag           func(childComplexity int, input model.TagInput) int
+		DeleteNote          func(childComplexity int, id string) int
+		DeleteNotebook      func(childComplexity int, id string, trashNotes *bool) int
+		DeleteNotes         func(childComplexity int, ids []string) int
+		DeleteTag           func(childComplexity int, id string) int
+		EmptyTrash          func(childComplexity int) int
+		MoveNote            func(childComplexity int, id string, notebookID *string) int
+		MoveNotebook        func(childComplexity int, id string, parentID *string) int
+		PinNote             func(childComplexity int, id string) int
+		RefreshToken        func(childComplexity int, input model.RefreshTokenInput) int
+		RenameNotebook      func(childComplexity int, id string, name string) int
+		RestoreNote         func(childComplexity int, id string) int
+		RestoreNoteRevision func(childComplexity int, noteID string, id string) int
+		RestoreNotes        func(childComplexity int, ids []string) int
+		RevokeOtherSessions func(childComplexity int) int
+		RevokeSession       func(childComplexity int, id string) int
+		SignIn              func(childComplexity int, input model.SignInInput) int
+		SignOut             func(childComplexity int) int
+		SignUp              func(childComplexity int, input model.SignUpInput) int
+		SoftDeleteNote      func(childComplexity int, id string) int
+		SoftDeleteNotes     func(childComplexity int, ids []string) int
+		UnarchiveNote       func(childComplexity int, id string) int
+		UnpinNote           func(childComplexity int, id string) int
+		UpdateNote          func(childComplexity int, id string, input model.UpdateNoteInput) int
+		UpdateTag           func(childComplexity int, id string, input model.TagInput) int
 	}
 
 	Note struct {
+		ArchiveTime func(childComplexity int) int
+		Content     func(childComplexity int) int
+		CreateTime  func(childComplexity int) int
+		DeleteTime  func(childComplexity int) int
+		Files       func(childComplexity int) int
+		ID          func(childComplexity int) int
+		NotebookID  func(childComplexity int) int
+		PinnedTime  func(childComplexity int) int
+		Tags        func(childComplexity int) int
+		Title       func(childComplexity int) int
+		UpdateTime  func(childComplexity int) int
+		UserID      func(childComplexity int) int
+		Version     func(childComplexity int) int
+	}
+
+	NoteChangedEvent struct {
+		File   func(childComplexity int) int
+		Hard   func(childComplexity int) int
+		Note   func(childComplexity int) int
+		NoteID func(childComplexity int) int
+		Time   func(childComplexity int) int
+		Type   func(childComplexity int) int
+	}
+
+	NoteRevision struct {
 		Content    func(childComplexity int) int
 		CreateTime func(childComplexity int) int
-		Files      func(childComplexity int) int
 		ID         func(childComplexity int) int
+		NoteID     func(childComplexity int) int
 		Title      func(childComplexity int) int
+	}
+
+	NoteRevisionDiff struct {
+		Content func(childComplexity int) int
+		From    func(childComplexity int) int
+		Title   func(childComplexity int) int
+		To      func(childComplexity int) int
+	}
+
+	NoteSearchResult struct {
+		ContentSnippet func(childComplexity int) int
+		Note           func(childComplexity int) int
+		Rank           func(childComplexity int) int
+		TitleSnippet   func(childComplexity int) int
+	}
+
+	Notebook struct {
+		CreateTime func(childComplexity int) int
+		ID         func(childComplexity int) int
+		Name       func(childComplexity int) int
+		ParentID   func(childComplexity int) int
 		UpdateTime func(childComplexity int) int
 		UserID     func(childComplexity int) int
 	}
 
 	NotesResponse struct {
-		Cursor func(childComplexity int) int
-		Notes  func(childComplexity int) int
+		HasNextPage func(childComplexity int) int
+		NextCursor  func(childComplexity int) int
+		Notes       func(childComplexity int) int
 	}
 
 	PresignedUrl struct {
@@ -98,8 +192,17 @@ type ComplexityRoot struct {
 	}
 
 	Query struct {
-		ListNotes func(childComplexity int, input *model.NotesInput) int
-		Me        func(childComplexity int) int
+		ListNotes        func(childComplexity int, input *model.NotesInput) int
+		Me               func(childComplexity int) int
+		Note             func(childComplexity int, id string) int
+		NoteRevision     func(childComplexity int, noteID string, id string) int
+		NoteRevisionDiff func(childComplexity int, noteID string, from string, to string) int
+		NoteRevisions    func(childComplexity int, noteID string) int
+		Notebooks        func(childComplexity int) int
+		SearchNotes      func(childComplexity int, input model.SearchNotesInput) int
+		Sessions         func(childComplexity int) int
+		Sync             func(childComplexity int, since *string) int
+		Tags             func(childComplexity int) int
 	}
 
 	RefreshToken struct {
@@ -109,12 +212,54 @@ type ComplexityRoot struct {
 		UserID     func(childComplexity int) int
 	}
 
+	SearchNotesResponse struct {
+		Cursor  func(childComplexity int) int
+		Results func(childComplexity int) int
+	}
+
+	Session struct {
+		Current        func(childComplexity int) int
+		DeviceName     func(childComplexity int) int
+		ID             func(childComplexity int) int
+		IPAddress      func(childComplexity int) int
+		LastActiveTime func(childComplexity int) int
+		SignInTime     func(childComplexity int) int
+		UserAgent      func(childComplexity int) int
+	}
+
 	SignInResponse struct {
 		AccessToken  func(childComplexity int) int
 		RefreshToken func(childComplexity int) int
 		User         func(childComplexity int) int
 	}
 
+	Subscription struct {
+		NoteChanged func(childComplexity int) int
+	}
+
+	SyncResponse struct {
+		Files      func(childComplexity int) int
+		HasMore    func(childComplexity int) int
+		Notes      func(childComplexity int) int
+		Token      func(childComplexity int) int
+		Tombstones func(childComplexity int) int
+	}
+
+	Tag struct {
+		CreateTime func(childComplexity int) int
+		ID         func(childComplexity int) int
+		Name       func(childComplexity int) int
+		UpdateTime func(childComplexity int) int
+		UserID     func(childComplexity int) int
+	}
+
+	Tombstone struct {
+		DeleteTime func(childComplexity int) int
+		EntityID   func(childComplexity int) int
+		EntityType func(childComplexity int) int
+		NoteID     func(childComplexity int) int
+	}
+
 	User struct {
 		CreateTime func(childComplexity int) int
 		Email      func(childComplexity int) int
@@ -178,6 +323,62 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.AccessToken.UserID(childComplexity), true
 
+	case "AudioMetadata.duration":
+		if e.complexity.AudioMetadata.Duration == nil {
+			break
+		}
+
+		return e.complexity.AudioMetadata.Duration(childComplexity), true
+
+	case "AudioMetadata.peaks":
+		if e.complexity.AudioMetadata.Peaks == nil {
+			break
+		}
+
+		return e.complexity.AudioMetadata.Peaks(childComplexity), true
+
+	case "AudioMetadata.sampleRate":
+		if e.complexity.AudioMetadata.SampleRate == nil {
+			break
+		}
+
+		return e.complexity.AudioMetadata.SampleRate(childComplexity), true
+
+	case "BulkNoteResult.error":
+		if e.complexity.BulkNoteResult.Error == nil {
+			break
+		}
+
+		return e.complexity.BulkNoteResult.Error(childComplexity), true
+
+	case "BulkNoteResult.id":
+		if e.complexity.BulkNoteResult.ID == nil {
+			break
+		}
+
+		return e.complexity.BulkNoteResult.ID(childComplexity), true
+
+	case "BulkNoteResult.ok":
+		if e.complexity.BulkNoteResult.Ok == nil {
+			break
+		}
+
+		return e.complexity.BulkNoteResult.Ok(childComplexity), true
+
+	case "BulkNotesResponse.hasMore":
+		if e.complexity.BulkNotesResponse.HasMore == nil {
+			break
+		}
+
+		return e.complexity.BulkNotesResponse.HasMore(childComplexity), true
+
+	case "BulkNotesResponse.results":
+		if e.complexity.BulkNotesResponse.Results == nil {
+			break
+		}
+
+		return e.complexity.BulkNotesResponse.Results(childComplexity), true
+
 	case "CreatePresignedUrlsResponse.Urls":
 		if e.complexity.CreatePresignedUrlsResponse.Urls == nil {
 			break
@@ -185,6 +386,27 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.CreatePresignedUrlsResponse.Urls(childComplexity), true
 
+	case "DiffLine.text":
+		if e.complexity.DiffLine.Text == nil {
+			break
+		}
+
+		return e.complexity.DiffLine.Text(childComplexity), true
+
+	case "DiffLine.type":
+		if e.complexity.DiffLine.Type == nil {
+			break
+		}
+
+		return e.complexity.DiffLine.Type(childComplexity), true
+
+	case "File.audio":
+		if e.complexity.File.Audio == nil {
+			break
+		}
+
+		return e.complexity.File.Audio(childComplexity), true
+
 	case "File.createTime":
 		if e.complexity.File.CreateTime == nil {
 			break
@@ -220,6 +442,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.File.ProcessedFile(childComplexity), true
 
+	case "File.processingStatus":
+		if e.complexity.File.ProcessingStatus == nil {
+			break
+		}
+
+		return e.complexity.File.ProcessingStatus(childComplexity), true
+
+	case "File.renditions":
+		if e.complexity.File.Renditions == nil {
+			break
+		}
+
+		return e.complexity.File.Renditions(childComplexity), true
+
 	case "File.url":
 		if e.complexity.File.URL == nil {
 			break
@@ -234,6 +470,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.File.UpdateTime(childComplexity), true
 
+	case "Mutation.archiveNote":
+		if e.complexity.Mutation.ArchiveNote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_archiveNote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ArchiveNote(childComplexity, args["id"].(string)), true
+
 	case "Mutation.createNote":
 		if e.complexity.Mutation.CreateNote == nil {
 			break
@@ -246,6 +494,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.CreateNote(childComplexity, args["input"].(model.CreateNoteInput)), true
 
+	case "Mutation.createNotebook":
+		if e.complexity.Mutation.CreateNotebook == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createNotebook_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreateNotebook(childComplexity, args["input"].(model.CreateNotebookInput)), true
+
 	case "Mutation.createPresignedUrl":
 		if e.complexity.Mutation.CreatePresignedURL == nil {
 			break
@@ -258,6 +518,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.CreatePresignedURL(childComplexity, args["objectName"].([]string)), true
 
+	case "Mutation.createTag":
+		if e.complexity.Mutation.CreateTag == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createTag_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreateTag(childComplexity, args["input"].(model.TagInput)), true
+
 	case "Mutation.deleteNote":
 		if e.complexity.Mutation.DeleteNote == nil {
 			break
@@ -270,212 +542,903 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.DeleteNote(childComplexity, args["id"].(string)), true
 
-	case "Mutation.restoreNote":
-		if e.complexity.Mutation.RestoreNote == nil {
+	case "Mutation.deleteNotebook":
+		if e.complexity.Mutation.DeleteNotebook == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_restoreNote_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_deleteNotebook_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Mutation.RestoreNote(childComplexity, args["id"].(string)), true
+		return e.complexity.Mutation.DeleteNotebook(childComplexity, args["id"].(string), args["trashNotes"].(*bool)), true
 
-	case "Mutation.signIn":
-		if e.complexity.Mutation.SignIn == nil {
+	case "Mutation.deleteNotes":
+		if e.complexity.Mutation.DeleteNotes == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_signIn_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_deleteNotes_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Mutation.SignIn(childComplexity, args["input"].(model.SignInInput)), true
+		return e.complexity.Mutation.DeleteNotes(childComplexity, args["ids"].([]string)), true
 
-	case "Mutation.signOut":
-		if e.complexity.Mutation.SignOut == nil {
+	case "Mutation.deleteTag":
+		if e.complexity.Mutation.DeleteTag == nil {
 			break
 		}
 
-		return e.complexity.Mutation.SignOut(childComplexity), true
+		args, err := ec.field_Mutation_deleteTag_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
 
-	case "Mutation.softDeleteNote":
-		if e.complexity.Mutation.SoftDeleteNote == nil {
+		return e.complexity.Mutation.DeleteTag(childComplexity, args["id"].(string)), true
+
+	case "Mutation.emptyTrash":
+		if e.complexity.Mutation.EmptyTrash == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_softDeleteNote_args(context.TODO(), rawArgs)
+		return e.complexity.Mutation.EmptyTrash(childComplexity), true
+
+	case "Mutation.moveNote":
+		if e.complexity.Mutation.MoveNote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_moveNote_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Mutation.SoftDeleteNote(childComplexity, args["id"].(string)), true
+		return e.complexity.Mutation.MoveNote(childComplexity, args["id"].(string), args["notebookId"].(*string)), true
 
-	case "Mutation.updateNote":
-		if e.complexity.Mutation.UpdateNote == nil {
+	case "Mutation.moveNotebook":
+		if e.complexity.Mutation.MoveNotebook == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_updateNote_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_moveNotebook_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Mutation.UpdateNote(childComplexity, args["id"].(string), args["input"].(model.UpdateNoteInput)), true
+		return e.complexity.Mutation.MoveNotebook(childComplexity, args["id"].(string), args["parentId"].(*string)), true
 
-	case "Note.content":
-		if e.complexity.Note.Content == nil {
+	case "Mutation.pinNote":
+		if e.complexity.Mutation.PinNote == nil {
 			break
 		}
 
-		return e.complexity.Note.Content(childComplexity), true
-
-	case "Note.createTime":
-		if e.complexity.Note.CreateTime == nil {
-			break
+		args, err := ec.field_Mutation_pinNote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.Note.CreateTime(childComplexity), true
+		return e.complexity.Mutation.PinNote(childComplexity, args["id"].(string)), true
 
-	case "Note.files":
-		if e.complexity.Note.Files == nil {
+	case "Mutation.refreshToken":
+		if e.complexity.Mutation.RefreshToken == nil {
 			break
 		}
 
-		return e.complexity.Note.Files(childComplexity), true
-
-	case "Note.id":
-		if e.complexity.Note.ID == nil {
-			break
+		args, err := ec.field_Mutation_refreshToken_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.Note.ID(childComplexity), true
+		return e.complexity.Mutation.RefreshToken(childComplexity, args["input"].(model.RefreshTokenInput)), true
 
-	case "Note.title":
-		if e.complexity.Note.Title == nil {
+	case "Mutation.renameNotebook":
+		if e.complexity.Mutation.RenameNotebook == nil {
 			break
 		}
 
-		return e.complexity.Note.Title(childComplexity), true
-
-	case "Note.updateTime":
-		if e.complexity.Note.UpdateTime == nil {
-			break
+		args, err := ec.field_Mutation_renameNotebook_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.Note.UpdateTime(childComplexity), true
+		return e.complexity.Mutation.RenameNotebook(childComplexity, args["id"].(string), args["name"].(string)), true
 
-	case "Note.userId":
-		if e.complexity.Note.UserID == nil {
+	case "Mutation.restoreNote":
+		if e.complexity.Mutation.RestoreNote == nil {
 			break
 		}
 
-		return e.complexity.Note.UserID(childComplexity), true
-
-	case "NotesResponse.cursor":
-		if e.complexity.NotesResponse.Cursor == nil {
-			break
+		args, err := ec.field_Mutation_restoreNote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.NotesResponse.Cursor(childComplexity), true
+		return e.complexity.Mutation.RestoreNote(childComplexity, args["id"].(string)), true
 
-	case "NotesResponse.notes":
-		if e.complexity.NotesResponse.Notes == nil {
+	case "Mutation.restoreNoteRevision":
+		if e.complexity.Mutation.RestoreNoteRevision == nil {
 			break
 		}
 
-		return e.complexity.NotesResponse.Notes(childComplexity), true
-
-	case "PresignedUrl.File":
-		if e.complexity.PresignedUrl.File == nil {
-			break
+		args, err := ec.field_Mutation_restoreNoteRevision_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.PresignedUrl.File(childComplexity), true
+		return e.complexity.Mutation.RestoreNoteRevision(childComplexity, args["noteId"].(string), args["id"].(string)), true
 
-	case "PresignedUrl.ObjectId":
-		if e.complexity.PresignedUrl.ObjectID == nil {
+	case "Mutation.restoreNotes":
+		if e.complexity.Mutation.RestoreNotes == nil {
 			break
 		}
 
-		return e.complexity.PresignedUrl.ObjectID(childComplexity), true
+		args, err := ec.field_Mutation_restoreNotes_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
 
-	case "PresignedUrl.Url":
-		if e.complexity.PresignedUrl.URL == nil {
+		return e.complexity.Mutation.RestoreNotes(childComplexity, args["ids"].([]string)), true
+
+	case "Mutation.revokeOtherSessions":
+		if e.complexity.Mutation.RevokeOtherSessions == nil {
 			break
 		}
 
-		return e.complexity.PresignedUrl.URL(childComplexity), true
+		return e.complexity.Mutation.RevokeOtherSessions(childComplexity), true
 
-	case "Query.listNotes":
-		if e.complexity.Query.ListNotes == nil {
+	case "Mutation.revokeSession":
+		if e.complexity.Mutation.RevokeSession == nil {
 			break
 		}
 
-		args, err := ec.field_Query_listNotes_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_revokeSession_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Query.ListNotes(childComplexity, args["input"].(*model.NotesInput)), true
+		return e.complexity.Mutation.RevokeSession(childComplexity, args["id"].(string)), true
 
-	case "Query.me":
-		if e.complexity.Query.Me == nil {
+	case "Mutation.signIn":
+		if e.complexity.Mutation.SignIn == nil {
 			break
 		}
 
-		return e.complexity.Query.Me(childComplexity), true
-
-	case "RefreshToken.createTime":
-		if e.complexity.RefreshToken.CreateTime == nil {
-			break
+		args, err := ec.field_Mutation_signIn_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.RefreshToken.CreateTime(childComplexity), true
+		return e.complexity.Mutation.SignIn(childComplexity, args["input"].(model.SignInInput)), true
 
-	case "RefreshToken.id":
-		if e.complexity.RefreshToken.ID == nil {
+	case "Mutation.signOut":
+		if e.complexity.Mutation.SignOut == nil {
 			break
 		}
 
-		return e.complexity.RefreshToken.ID(childComplexity), true
+		return e.complexity.Mutation.SignOut(childComplexity), true
 
-	case "RefreshToken.updateTime":
-		if e.complexity.RefreshToken.UpdateTime == nil {
+	case "Mutation.signUp":
+		if e.complexity.Mutation.SignUp == nil {
 			break
 		}
 
-		return e.complexity.RefreshToken.UpdateTime(childComplexity), true
-
-	case "RefreshToken.userId":
-		if e.complexity.RefreshToken.UserID == nil {
-			break
+		args, err := ec.field_Mutation_signUp_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.RefreshToken.UserID(childComplexity), true
+		return e.complexity.Mutation.SignUp(childComplexity, args["input"].(model.SignUpInput)), true
 
-	case "SignInResponse.accessToken":
-		if e.complexity.SignInResponse.AccessToken == nil {
+	case "Mutation.softDeleteNote":
+		if e.complexity.Mutation.SoftDeleteNote == nil {
 			break
 		}
 
-		return e.complexity.SignInResponse.AccessToken(childComplexity), true
-
-	case "SignInResponse.refreshToken":
-		if e.complexity.SignInResponse.RefreshToken == nil {
-			break
+		args, err := ec.field_Mutation_softDeleteNote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
 
-		return e.complexity.SignInResponse.RefreshToken(childComplexity), true
+		return e.complexity.Mutation.SoftDeleteNote(childComplexity, args["id"].(string)), true
 
-	case "SignInResponse.user":
-		if e.complexity.SignInResponse.User == nil {
+	case "Mutation.softDeleteNotes":
+		if e.complexity.Mutation.SoftDeleteNotes == nil {
 			break
 		}
 
-		return e.complexity.SignInResponse.User(childComplexity), true
+		args, err := ec.field_Mutation_softDeleteNotes_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.SoftDeleteNotes(childComplexity, args["ids"].([]string)), true
+
+	case "Mutation.unarchiveNote":
+		if e.complexity.Mutation.UnarchiveNote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_unarchiveNote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.UnarchiveNote(childComplexity, args["id"].(string)), true
+
+	case "Mutation.unpinNote":
+		if e.complexity.Mutation.UnpinNote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_unpinNote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.UnpinNote(childComplexity, args["id"].(string)), true
+
+	case "Mutation.updateNote":
+		if e.complexity.Mutation.UpdateNote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_updateNote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.UpdateNote(childComplexity, args["id"].(string), args["input"].(model.UpdateNoteInput)), true
+
+	case "Mutation.updateTag":
+		if e.complexity.Mutation.UpdateTag == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_updateTag_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.UpdateTag(childComplexity, args["id"].(string), args["input"].(model.TagInput)), true
+
+	case "Note.archiveTime":
+		if e.complexity.Note.ArchiveTime == nil {
+			break
+		}
+
+		return e.complexity.Note.ArchiveTime(childComplexity), true
+
+	case "Note.content":
+		if e.complexity.Note.Content == nil {
+			break
+		}
+
+		return e.complexity.Note.Content(childComplexity), true
+
+	case "Note.createTime":
+		if e.complexity.Note.CreateTime == nil {
+			break
+		}
+
+		return e.complexity.Note.CreateTime(childComplexity), true
+
+	case "Note.deleteTime":
+		if e.complexity.Note.DeleteTime == nil {
+			break
+		}
+
+		return e.complexity.Note.DeleteTime(childComplexity), true
+
+	case "Note.files":
+		if e.complexity.Note.Files == nil {
+			break
+		}
+
+		return e.complexity.Note.Files(childComplexity), true
+
+	case "Note.id":
+		if e.complexity.Note.ID == nil {
+			break
+		}
+
+		return e.complexity.Note.ID(childComplexity), true
+
+	case "Note.notebookId":
+		if e.complexity.Note.NotebookID == nil {
+			break
+		}
+
+		return e.complexity.Note.NotebookID(childComplexity), true
+
+	case "Note.pinnedTime":
+		if e.complexity.Note.PinnedTime == nil {
+			break
+		}
+
+		return e.complexity.Note.PinnedTime(childComplexity), true
+
+	case "Note.tags":
+		if e.complexity.Note.Tags == nil {
+			break
+		}
+
+		return e.complexity.Note.Tags(childComplexity), true
+
+	case "Note.title":
+		if e.complexity.Note.Title == nil {
+			break
+		}
+
+		return e.complexity.Note.Title(childComplexity), true
+
+	case "Note.updateTime":
+		if e.complexity.Note.UpdateTime == nil {
+			break
+		}
+
+		return e.complexity.Note.UpdateTime(childComplexity), true
+
+	case "Note.userId":
+		if e.complexity.Note.UserID == nil {
+			break
+		}
+
+		return e.complexity.Note.UserID(childComplexity), true
+
+	case "Note.version":
+		if e.complexity.Note.Version == nil {
+			break
+		}
+
+		return e.complexity.Note.Version(childComplexity), true
+
+	case "NoteChangedEvent.file":
+		if e.complexity.NoteChangedEvent.File == nil {
+			break
+		}
+
+		return e.complexity.NoteChangedEvent.File(childComplexity), true
+
+	case "NoteChangedEvent.hard":
+		if e.complexity.NoteChangedEvent.Hard == nil {
+			break
+		}
+
+		return e.complexity.NoteChangedEvent.Hard(childComplexity), true
+
+	case "NoteChangedEvent.note":
+		if e.complexity.NoteChangedEvent.Note == nil {
+			break
+		}
+
+		return e.complexity.NoteChangedEvent.Note(childComplexity), true
+
+	case "NoteChangedEvent.noteId":
+		if e.complexity.NoteChangedEvent.NoteID == nil {
+			break
+		}
+
+		return e.complexity.NoteChangedEvent.NoteID(childComplexity), true
+
+	case "NoteChangedEvent.time":
+		if e.complexity.NoteChangedEvent.Time == nil {
+			break
+		}
+
+		return e.complexity.NoteChangedEvent.Time(childComplexity), true
+
+	case "NoteChangedEvent.type":
+		if e.complexity.NoteChangedEvent.Type == nil {
+			break
+		}
+
+		return e.complexity.NoteChangedEvent.Type(childComplexity), true
+
+	case "NoteRevision.content":
+		if e.complexity.NoteRevision.Content == nil {
+			break
+		}
+
+		return e.complexity.NoteRevision.Content(childComplexity), true
+
+	case "NoteRevision.createTime":
+		if e.complexity.NoteRevision.CreateTime == nil {
+			break
+		}
+
+		return e.complexity.NoteRevision.CreateTime(childComplexity), true
+
+	case "NoteRevision.id":
+		if e.complexity.NoteRevision.ID == nil {
+			break
+		}
+
+		return e.complexity.NoteRevision.ID(childComplexity), true
+
+	case "NoteRevision.noteId":
+		if e.complexity.NoteRevision.NoteID == nil {
+			break
+		}
+
+		return e.complexity.NoteRevision.NoteID(childComplexity), true
+
+	case "NoteRevision.title":
+		if e.complexity.NoteRevision.Title == nil {
+			break
+		}
+
+		return e.complexity.NoteRevision.Title(childComplexity), true
+
+	case "NoteRevisionDiff.content":
+		if e.complexity.NoteRevisionDiff.Content == nil {
+			break
+		}
+
+		return e.complexity.NoteRevisionDiff.Content(childComplexity), true
+
+	case "NoteRevisionDiff.from":
+		if e.complexity.NoteRevisionDiff.From == nil {
+			break
+		}
+
+		return e.complexity.NoteRevisionDiff.From(childComplexity), true
+
+	case "NoteRevisionDiff.title":
+		if e.complexity.NoteRevisionDiff.Title == nil {
+			break
+		}
+
+		return e.complexity.NoteRevisionDiff.Title(childComplexity), true
+
+	case "NoteRevisionDiff.to":
+		if e.complexity.NoteRevisionDiff.To == nil {
+			break
+		}
+
+		return e.complexity.NoteRevisionDiff.To(childComplexity), true
+
+	case "NoteSearchResult.contentSnippet":
+		if e.complexity.NoteSearchResult.ContentSnippet == nil {
+			break
+		}
+
+		return e.complexity.NoteSearchResult.ContentSnippet(childComplexity), true
+
+	case "NoteSearchResult.note":
+		if e.complexity.NoteSearchResult.Note == nil {
+			break
+		}
+
+		return e.complexity.NoteSearchResult.Note(childComplexity), true
+
+	case "NoteSearchResult.rank":
+		if e.complexity.NoteSearchResult.Rank == nil {
+			break
+		}
+
+		return e.complexity.NoteSearchResult.Rank(childComplexity), true
+
+	case "NoteSearchResult.titleSnippet":
+		if e.complexity.NoteSearchResult.TitleSnippet == nil {
+			break
+		}
+
+		return e.complexity.NoteSearchResult.TitleSnippet(childComplexity), true
+
+	case "Notebook.createTime":
+		if e.complexity.Notebook.CreateTime == nil {
+			break
+		}
+
+		return e.complexity.Notebook.CreateTime(childComplexity), true
+
+	case "Notebook.id":
+		if e.complexity.Notebook.ID == nil {
+			break
+		}
+
+		return e.complexity.Notebook.ID(childComplexity), true
+
+	case "Notebook.name":
+		if e.complexity.Notebook.Name == nil {
+			break
+		}
+
+		return e.complexity.Notebook.Name(childComplexity), true
+
+	case "Notebook.parentId":
+		if e.complexity.Notebook.ParentID == nil {
+			break
+		}
+
+		return e.complexity.Notebook.ParentID(childComplexity), true
+
+	case "Notebook.updateTime":
+		if e.complexity.Notebook.UpdateTime == nil {
+			break
+		}
+
+		return e.complexity.Notebook.UpdateTime(childComplexity), true
+
+	case "Notebook.userId":
+		if e.complexity.Notebook.UserID == nil {
+			break
+		}
+
+		return e.complexity.Notebook.UserID(childComplexity), true
+
+	case "NotesResponse.hasNextPage":
+		if e.complexity.NotesResponse.HasNextPage == nil {
+			break
+		}
+
+		return e.complexity.NotesResponse.HasNextPage(childComplexity), true
+
+	case "NotesResponse.nextCursor":
+		if e.complexity.NotesResponse.NextCursor == nil {
+			break
+		}
+
+		return e.complexity.NotesResponse.NextCursor(childComplexity), true
+
+	case "NotesResponse.notes":
+		if e.complexity.NotesResponse.Notes == nil {
+			break
+		}
+
+		return e.complexity.NotesResponse.Notes(childComplexity), true
+
+	case "PresignedUrl.File":
+		if e.complexity.PresignedUrl.File == nil {
+			break
+		}
+
+		return e.complexity.PresignedUrl.File(childComplexity), true
+
+	case "PresignedUrl.ObjectId":
+		if e.complexity.PresignedUrl.ObjectID == nil {
+			break
+		}
+
+		return e.complexity.PresignedUrl.ObjectID(childComplexity), true
+
+	case "PresignedUrl.Url":
+		if e.complexity.PresignedUrl.URL == nil {
+			break
+		}
+
+		return e.complexity.PresignedUrl.URL(childComplexity), true
+
+	case "Query.listNotes":
+		if e.complexity.Query.ListNotes == nil {
+			break
+		}
+
+		args, err := ec.field_Query_listNotes_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.ListNotes(childComplexity, args["input"].(*model.NotesInput)), true
+
+	case "Query.me":
+		if e.complexity.Query.Me == nil {
+			break
+		}
+
+		return e.complexity.Query.Me(childComplexity), true
+
+	case "Query.note":
+		if e.complexity.Query.Note == nil {
+			break
+		}
+
+		args, err := ec.field_Query_note_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Note(childComplexity, args["id"].(string)), true
+
+	case "Query.noteRevision":
+		if e.complexity.Query.NoteRevision == nil {
+			break
+		}
+
+		args, err := ec.field_Query_noteRevision_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.NoteRevision(childComplexity, args["noteId"].(string), args["id"].(string)), true
+
+	case "Query.noteRevisionDiff":
+		if e.complexity.Query.NoteRevisionDiff == nil {
+			break
+		}
+
+		args, err := ec.field_Query_noteRevisionDiff_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.NoteRevisionDiff(childComplexity, args["noteId"].(string), args["from"].(string), args["to"].(string)), true
+
+	case "Query.noteRevisions":
+		if e.complexity.Query.NoteRevisions == nil {
+			break
+		}
+
+		args, err := ec.field_Query_noteRevisions_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.NoteRevisions(childComplexity, args["noteId"].(string)), true
+
+	case "Query.notebooks":
+		if e.complexity.Query.Notebooks == nil {
+			break
+		}
+
+		return e.complexity.Query.Notebooks(childComplexity), true
+
+	case "Query.searchNotes":
+		if e.complexity.Query.SearchNotes == nil {
+			break
+		}
+
+		args, err := ec.field_Query_searchNotes_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.SearchNotes(childComplexity, args["input"].(model.SearchNotesInput)), true
+
+	case "Query.sessions":
+		if e.complexity.Query.Sessions == nil {
+			break
+		}
+
+		return e.complexity.Query.Sessions(childComplexity), true
+
+	case "Query.sync":
+		if e.complexity.Query.Sync == nil {
+			break
+		}
+
+		args, err := ec.field_Query_sync_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Sync(childComplexity, args["since"].(*string)), true
+
+	case "Query.tags":
+		if e.complexity.Query.Tags == nil {
+			break
+		}
+
+		return e.complexity.Query.Tags(childComplexity), true
+
+	case "RefreshToken.createTime":
+		if e.complexity.RefreshToken.CreateTime == nil {
+			break
+		}
+
+		return e.complexity.RefreshToken.CreateTime(childComplexity), true
+
+	case "RefreshToken.id":
+		if e.complexity.RefreshToken.ID == nil {
+			break
+		}
+
+		return e.complexity.RefreshToken.ID(childComplexity), true
+
+	case "RefreshToken.updateTime":
+		if e.complexity.RefreshToken.UpdateTime == nil {
+			break
+		}
+
+		return e.complexity.RefreshToken.UpdateTime(childComplexity), true
+
+	case "RefreshToken.userId":
+		if e.complexity.RefreshToken.UserID == nil {
+			break
+		}
+
+		return e.complexity.RefreshToken.UserID(childComplexity), true
+
+	case "SearchNotesResponse.cursor":
+		if e.complexity.SearchNotesResponse.Cursor == nil {
+			break
+		}
+
+		return e.complexity.SearchNotesResponse.Cursor(childComplexity), true
+
+	case "SearchNotesResponse.results":
+		if e.complexity.SearchNotesResponse.Results == nil {
+			break
+		}
+
+		return e.complexity.SearchNotesResponse.Results(childComplexity), true
+
+	case "Session.current":
+		if e.complexity.Session.Current == nil {
+			break
+		}
+
+		return e.complexity.Session.Current(childComplexity), true
+
+	case "Session.deviceName":
+		if e.complexity.Session.DeviceName == nil {
+			break
+		}
+
+		return e.complexity.Session.DeviceName(childComplexity), true
+
+	case "Session.id":
+		if e.complexity.Session.ID == nil {
+			break
+		}
+
+		return e.complexity.Session.ID(childComplexity), true
+
+	case "Session.ipAddress":
+		if e.complexity.Session.IPAddress == nil {
+			break
+		}
+
+		return e.complexity.Session.IPAddress(childComplexity), true
+
+	case "Session.lastActiveTime":
+		if e.complexity.Session.LastActiveTime == nil {
+			break
+		}
+
+		return e.complexity.Session.LastActiveTime(childComplexity), true
+
+	case "Session.signInTime":
+		if e.complexity.Session.SignInTime == nil {
+			break
+		}
+
+		return e.complexity.Session.SignInTime(childComplexity), true
+
+	case "Session.userAgent":
+		if e.complexity.Session.UserAgent == nil {
+			break
+		}
+
+		return e.complexity.Session.UserAgent(childComplexity), true
+
+	case "SignInResponse.accessToken":
+		if e.complexity.SignInResponse.AccessToken == nil {
+			break
+		}
+
+		return e.complexity.SignInResponse.AccessToken(childComplexity), true
+
+	case "SignInResponse.refreshToken":
+		if e.complexity.SignInResponse.RefreshToken == nil {
+			break
+		}
+
+		return e.complexity.SignInResponse.RefreshToken(childComplexity), true
+
+	case "SignInResponse.user":
+		if e.complexity.SignInResponse.User == nil {
+			break
+		}
+
+		return e.complexity.SignInResponse.User(childComplexity), true
+
+	case "Subscription.noteChanged":
+		if e.complexity.Subscription.NoteChanged == nil {
+			break
+		}
+
+		return e.complexity.Subscription.NoteChanged(childComplexity), true
+
+	case "SyncResponse.files":
+		if e.complexity.SyncResponse.Files == nil {
+			break
+		}
+
+		return e.complexity.SyncResponse.Files(childComplexity), true
+
+	case "SyncResponse.hasMore":
+		if e.complexity.SyncResponse.HasMore == nil {
+			break
+		}
+
+		return e.complexity.SyncResponse.HasMore(childComplexity), true
+
+	case "SyncResponse.notes":
+		if e.complexity.SyncResponse.Notes == nil {
+			break
+		}
+
+		return e.complexity.SyncResponse.Notes(childComplexity), true
+
+	case "SyncResponse.token":
+		if e.complexity.SyncResponse.Token == nil {
+			break
+		}
+
+		return e.complexity.SyncResponse.Token(childComplexity), true
+
+	case "SyncResponse.tombstones":
+		if e.complexity.SyncResponse.Tombstones == nil {
+			break
+		}
+
+		return e.complexity.SyncResponse.Tombstones(childComplexity), true
+
+	case "Tag.createTime":
+		if e.complexity.Tag.CreateTime == nil {
+			break
+		}
+
+		return e.complexity.Tag.CreateTime(childComplexity), true
+
+	case "Tag.id":
+		if e.complexity.Tag.ID == nil {
+			break
+		}
+
+		return e.complexity.Tag.ID(childComplexity), true
+
+	case "Tag.name":
+		if e.complexity.Tag.Name == nil {
+			break
+		}
+
+		return e.complexity.Tag.Name(childComplexity), true
+
+	case "Tag.updateTime":
+		if e.complexity.Tag.UpdateTime == nil {
+			break
+		}
+
+		return e.complexity.Tag.UpdateTime(childComplexity), true
+
+	case "Tag.userId":
+		if e.complexity.Tag.UserID == nil {
+			break
+		}
+
+		return e.complexity.Tag.UserID(childComplexity), true
+
+	case "Tombstone.deleteTime":
+		if e.complexity.Tombstone.DeleteTime == nil {
+			break
+		}
+
+		return e.complexity.Tombstone.DeleteTime(childComplexity), true
+
+	case "Tombstone.entityId":
+		if e.complexity.Tombstone.EntityID == nil {
+			break
+		}
+
+		return e.complexity.Tombstone.EntityID(childComplexity), true
+
+	case "Tombstone.entityType":
+		if e.complexity.Tombstone.EntityType == nil {
+			break
+		}
+
+		return e.complexity.Tombstone.EntityType(childComplexity), true
+
+	case "Tombstone.noteId":
+		if e.complexity.Tombstone.NoteID == nil {
+			break
+		}
+
+		return e.complexity.Tombstone.NoteID(childComplexity), true
 
 	case "User.createTime":
 		if e.complexity.User.CreateTime == nil {
@@ -521,8 +1484,13 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	ec := executionContext{opCtx, e, 0, 0, make(chan graphql.DeferredResult)}
 	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
 		ec.unmarshalInputCreateNoteInput,
+		ec.unmarshalInputCreateNotebookInput,
 		ec.unmarshalInputNotesInput,
+		ec.unmarshalInputRefreshTokenInput,
+		ec.unmarshalInputSearchNotesInput,
 		ec.unmarshalInputSignInInput,
+		ec.unmarshalInputSignUpInput,
+		ec.unmarshalInputTagInput,
 		ec.unmarshalInputUpdateNoteInput,
 	)
 	first := true
@@ -569,6 +1537,23 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			var buf bytes.Buffer
 			data.MarshalGQL(&buf)
 
+			return &graphql.Response{
+				Data: buf.Bytes(),
+			}
+		}
+	case ast.Subscription:
+		next := ec._Subscription(ctx, opCtx.Operation.SelectionSet)
+
+		var buf bytes.Buffer
+		return func(ctx context.Context) *graphql.Response {
+			buf.Reset()
+			data := next(ctx)
+
+			if data == nil {
+				return nil
+			}
+			data.MarshalGQL(&buf)
+
 			return &graphql.Response{
 				Data: buf.Bytes(),
 			}
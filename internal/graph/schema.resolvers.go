@@ -11,16 +11,36 @@ import (
 	"github.com/daniarmas/notes/internal/graph/resolver"
 )
 
+// SignUp is the resolver for the signUp field.
+func (r *mutationResolver) SignUp(ctx context.Context, input model.SignUpInput) (*model.SignInResponse, error) {
+	return resolver.SignUp(ctx, input, r.AuthSrv)
+}
+
 // SignIn is the resolver for the signIn field.
 func (r *mutationResolver) SignIn(ctx context.Context, input model.SignInInput) (*model.SignInResponse, error) {
 	return resolver.SignIn(ctx, input, r.AuthSrv)
 }
 
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, input model.RefreshTokenInput) (*model.SignInResponse, error) {
+	return resolver.RefreshToken(ctx, input, r.AuthSrv)
+}
+
 // SignOut is the resolver for the signOut field.
 func (r *mutationResolver) SignOut(ctx context.Context) (bool, error) {
 	return resolver.SignOut(ctx, r.AuthSrv)
 }
 
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) (bool, error) {
+	return resolver.RevokeSession(ctx, id, r.AuthSrv)
+}
+
+// RevokeOtherSessions is the resolver for the revokeOtherSessions field.
+func (r *mutationResolver) RevokeOtherSessions(ctx context.Context) (bool, error) {
+	return resolver.RevokeOtherSessions(ctx, r.AuthSrv)
+}
+
 // CreateNote is the resolver for the createNote field.
 func (r *mutationResolver) CreateNote(ctx context.Context, input model.CreateNoteInput) (*model.Note, error) {
 	return resolver.CreateNote(ctx, input, r.NoteSrv)
@@ -46,26 +66,165 @@ func (r *mutationResolver) RestoreNote(ctx context.Context, id string) (bool, er
 	return resolver.RestoreNote(ctx, id, r.NoteSrv)
 }
 
+// SoftDeleteNotes is the resolver for the softDeleteNotes field.
+func (r *mutationResolver) SoftDeleteNotes(ctx context.Context, ids []string) (*model.BulkNotesResponse, error) {
+	return resolver.SoftDeleteNotes(ctx, ids, r.NoteSrv)
+}
+
+// RestoreNotes is the resolver for the restoreNotes field.
+func (r *mutationResolver) RestoreNotes(ctx context.Context, ids []string) (*model.BulkNotesResponse, error) {
+	return resolver.RestoreNotes(ctx, ids, r.NoteSrv)
+}
+
+// DeleteNotes is the resolver for the deleteNotes field.
+func (r *mutationResolver) DeleteNotes(ctx context.Context, ids []string) (*model.BulkNotesResponse, error) {
+	return resolver.DeleteNotes(ctx, ids, r.NoteSrv)
+}
+
+// EmptyTrash is the resolver for the emptyTrash field.
+func (r *mutationResolver) EmptyTrash(ctx context.Context) (*model.BulkNotesResponse, error) {
+	return resolver.EmptyTrash(ctx, r.NoteSrv)
+}
+
 // UpdateNote is the resolver for the updateNote field.
 func (r *mutationResolver) UpdateNote(ctx context.Context, id string, input model.UpdateNoteInput) (*model.Note, error) {
 	return resolver.UpdateNote(ctx, id, input, r.NoteSrv)
 }
 
+// MoveNote is the resolver for the moveNote field.
+func (r *mutationResolver) MoveNote(ctx context.Context, id string, notebookID *string) (*model.Note, error) {
+	return resolver.MoveNote(ctx, id, notebookID, r.NoteSrv)
+}
+
+// PinNote is the resolver for the pinNote field.
+func (r *mutationResolver) PinNote(ctx context.Context, id string) (*model.Note, error) {
+	return resolver.PinNote(ctx, id, r.NoteSrv)
+}
+
+// UnpinNote is the resolver for the unpinNote field.
+func (r *mutationResolver) UnpinNote(ctx context.Context, id string) (*model.Note, error) {
+	return resolver.UnpinNote(ctx, id, r.NoteSrv)
+}
+
+// ArchiveNote is the resolver for the archiveNote field.
+func (r *mutationResolver) ArchiveNote(ctx context.Context, id string) (*model.Note, error) {
+	return resolver.ArchiveNote(ctx, id, r.NoteSrv)
+}
+
+// UnarchiveNote is the resolver for the unarchiveNote field.
+func (r *mutationResolver) UnarchiveNote(ctx context.Context, id string) (*model.Note, error) {
+	return resolver.UnarchiveNote(ctx, id, r.NoteSrv)
+}
+
+// RestoreNoteRevision is the resolver for the restoreNoteRevision field.
+func (r *mutationResolver) RestoreNoteRevision(ctx context.Context, noteID string, id string) (*model.Note, error) {
+	return resolver.RestoreNoteRevision(ctx, noteID, id, r.NoteSrv)
+}
+
+// CreateTag is the resolver for the createTag field.
+func (r *mutationResolver) CreateTag(ctx context.Context, input model.TagInput) (*model.Tag, error) {
+	return resolver.CreateTag(ctx, input, r.TagSrv)
+}
+
+// UpdateTag is the resolver for the updateTag field.
+func (r *mutationResolver) UpdateTag(ctx context.Context, id string, input model.TagInput) (*model.Tag, error) {
+	return resolver.UpdateTag(ctx, id, input, r.TagSrv)
+}
+
+// DeleteTag is the resolver for the deleteTag field.
+func (r *mutationResolver) DeleteTag(ctx context.Context, id string) (bool, error) {
+	return resolver.DeleteTag(ctx, id, r.TagSrv)
+}
+
+// CreateNotebook is the resolver for the createNotebook field.
+func (r *mutationResolver) CreateNotebook(ctx context.Context, input model.CreateNotebookInput) (*model.Notebook, error) {
+	return resolver.CreateNotebook(ctx, input, r.NotebookSrv)
+}
+
+// RenameNotebook is the resolver for the renameNotebook field.
+func (r *mutationResolver) RenameNotebook(ctx context.Context, id string, name string) (*model.Notebook, error) {
+	return resolver.RenameNotebook(ctx, id, name, r.NotebookSrv)
+}
+
+// MoveNotebook is the resolver for the moveNotebook field.
+func (r *mutationResolver) MoveNotebook(ctx context.Context, id string, parentID *string) (*model.Notebook, error) {
+	return resolver.MoveNotebook(ctx, id, parentID, r.NotebookSrv)
+}
+
+// DeleteNotebook is the resolver for the deleteNotebook field.
+func (r *mutationResolver) DeleteNotebook(ctx context.Context, id string, trashNotes *bool) (bool, error) {
+	return resolver.DeleteNotebook(ctx, id, trashNotes, r.NotebookSrv)
+}
+
 // Me is the resolver for the me field.
 func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
 	return resolver.Me(ctx, r.AuthSrv)
 }
 
+// Sessions is the resolver for the sessions field.
+func (r *queryResolver) Sessions(ctx context.Context) ([]*model.Session, error) {
+	return resolver.Sessions(ctx, r.AuthSrv)
+}
+
 // ListNotes is the resolver for the listNotes field.
 func (r *queryResolver) ListNotes(ctx context.Context, input *model.NotesInput) (*model.NotesResponse, error) {
 	return resolver.ListNotes(ctx, input, r.NoteSrv)
 }
 
+// Note is the resolver for the note field.
+func (r *queryResolver) Note(ctx context.Context, id string) (*model.Note, error) {
+	return resolver.GetNote(ctx, id, r.NoteSrv)
+}
+
+// SearchNotes is the resolver for the searchNotes field.
+func (r *queryResolver) SearchNotes(ctx context.Context, input model.SearchNotesInput) (*model.SearchNotesResponse, error) {
+	return resolver.SearchNotes(ctx, input, r.NoteSrv)
+}
+
+// NoteRevisions is the resolver for the noteRevisions field.
+func (r *queryResolver) NoteRevisions(ctx context.Context, noteID string) ([]*model.NoteRevision, error) {
+	return resolver.NoteRevisions(ctx, noteID, r.NoteSrv)
+}
+
+// NoteRevision is the resolver for the noteRevision field.
+func (r *queryResolver) NoteRevision(ctx context.Context, noteID string, id string) (*model.NoteRevision, error) {
+	return resolver.NoteRevision(ctx, noteID, id, r.NoteSrv)
+}
+
+// NoteRevisionDiff is the resolver for the noteRevisionDiff field.
+func (r *queryResolver) NoteRevisionDiff(ctx context.Context, noteID string, from string, to string) (*model.NoteRevisionDiff, error) {
+	return resolver.NoteRevisionDiff(ctx, noteID, from, to, r.NoteSrv)
+}
+
+// Sync is the resolver for the sync field.
+func (r *queryResolver) Sync(ctx context.Context, since *string) (*model.SyncResponse, error) {
+	return resolver.Sync(ctx, since, r.NoteSrv)
+}
+
+// Tags is the resolver for the tags field.
+func (r *queryResolver) Tags(ctx context.Context) ([]*model.Tag, error) {
+	return resolver.ListTags(ctx, r.TagSrv)
+}
+
+// Notebooks is the resolver for the notebooks field.
+func (r *queryResolver) Notebooks(ctx context.Context) ([]*model.Notebook, error) {
+	return resolver.ListNotebooks(ctx, r.NotebookSrv)
+}
+
+// NoteChanged is the resolver for the noteChanged field.
+func (r *subscriptionResolver) NoteChanged(ctx context.Context) (<-chan *model.NoteChangedEvent, error) {
+	return resolver.NoteChanged(ctx, r.NoteSrv)
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -8,6 +9,31 @@ import (
 	"github.com/daniarmas/http/response"
 )
 
+// conflictResponse mirrors the envelope used by the response package
+type conflictResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details"`
+	Data    any    `json:"data"`
+}
+
+// Conflict writes a 409 response with the same envelope as the response package.
+// The data argument is returned to the client as is, it can be nil.
+func Conflict(w http.ResponseWriter, r *http.Request, message string, data any) {
+	if data == nil {
+		data = &struct{}{}
+	}
+	res := conflictResponse{
+		Code:    http.StatusConflict,
+		Message: message,
+		Details: &struct{}{},
+		Data:    data,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(res)
+}
+
 // OpenApiHanlder handles requests for the OpenAPI specification.
 func OpenApiHanlder(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, "api/openapi-spec/swagger.json")
@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/daniarmas/notes/internal/domain"
@@ -19,21 +24,36 @@ type GetPresignedUrlsRequest struct {
 
 // Represents the structure of the create note request
 type CreateNoteRequest struct {
-	Title       string   `json:"title"`
-	Content     string   `json:"content"`
-	ObjectNames []string `json:"object_names"`
+	Title       string      `json:"title"`
+	Content     string      `json:"content"`
+	ObjectNames []string    `json:"object_names"`
+	TagIds      []uuid.UUID `json:"tag_ids"`
+	NotebookId  *uuid.UUID  `json:"notebook_id"`
 }
 
 // Represents the structure of the update note request
 type UpdateNoteRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title        string      `json:"title"`
+	Content      string      `json:"content"`
+	AddTagIds    []uuid.UUID `json:"add_tag_ids"`
+	RemoveTagIds []uuid.UUID `json:"remove_tag_ids"`
+}
+
+// Represents the structure of the move note request
+type MoveNoteRequest struct {
+	NotebookId *uuid.UUID `json:"notebook_id"`
+}
+
+// Represents the structure of the bulk note operations request
+type BulkNotesRequest struct {
+	Ids []uuid.UUID `json:"ids"`
 }
 
 // Represent the structure of the list notes response
 type ListNotesResponse struct {
-	Notes  *[]domain.Note `json:"notes"`
-	Cursor time.Time      `json:"cursor"`
+	Notes       []domain.Note `json:"notes"`
+	HasNextPage bool          `json:"has_next_page"`
+	NextCursor  string        `json:"next_cursor"`
 }
 
 // Validates the get presigned urls request
@@ -47,6 +67,17 @@ func (r GetPresignedUrlsRequest) Validate() map[string]string {
 	return errors
 }
 
+// Validates the bulk note operations request
+func (r BulkNotesRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if len(r.Ids) == 0 {
+		errors["ids"] = "field required"
+	} else if len(r.Ids) > domain.MaxBulkNotes {
+		errors["ids"] = fmt.Sprintf("maximum of %d notes allowed", domain.MaxBulkNotes)
+	}
+	return errors
+}
+
 // Validates the create note request
 func (r CreateNoteRequest) Validate() map[string]string {
 	errors := make(map[string]string)
@@ -59,6 +90,10 @@ func (r CreateNoteRequest) Validate() map[string]string {
 // Validates the update note request
 func (r UpdateNoteRequest) Validate() map[string]string {
 	errors := make(map[string]string)
+	// A request that only attaches or detaches tags keeps the title and the content
+	if r.Title == "" && r.Content == "" && (len(r.AddTagIds) > 0 || len(r.RemoveTagIds) > 0) {
+		return errors
+	}
 	if r.Title == "" {
 		errors["title"] = "field required"
 	}
@@ -68,6 +103,83 @@ func (r UpdateNoteRequest) Validate() map[string]string {
 	return errors
 }
 
+// Parses the tag, notebook and date range filters of the list notes endpoints, a non empty message means an invalid filter
+func parseNoteFilter(r *http.Request) (domain.NoteFilter, string) {
+	var filter domain.NoteFilter
+	if tagQueryParam := r.URL.Query().Get("tag"); tagQueryParam != "" {
+		tagId, err := uuid.Parse(tagQueryParam)
+		if err != nil {
+			return filter, "Invalid tag query parameter. It must be a valid UUID."
+		}
+		filter.TagId = tagId
+	}
+	if notebookQueryParam := r.URL.Query().Get("notebook"); notebookQueryParam != "" {
+		notebookId, err := uuid.Parse(notebookQueryParam)
+		if err != nil {
+			return filter, "Invalid notebook query parameter. It must be a valid UUID."
+		}
+		filter.NotebookId = notebookId
+	}
+	dateRange := []struct {
+		name  string
+		value *time.Time
+	}{
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+		{"updated_after", &filter.UpdatedAfter},
+		{"updated_before", &filter.UpdatedBefore},
+	}
+	for _, param := range dateRange {
+		if queryParam := r.URL.Query().Get(param.name); queryParam != "" {
+			t, err := utils.ParseTime(queryParam)
+			if err != nil {
+				return filter, fmt.Sprintf("Invalid time format for the %s query parameter. Must use RFC3339 format", param.name)
+			}
+			*param.value = t.UTC()
+		}
+	}
+	return filter, ""
+}
+
+// Parses the cursor and the limit of the list notes endpoints, a non empty message means an invalid page
+func parseNoteListPage(r *http.Request) (*domain.NoteListCursor, int, string) {
+	var cursor *domain.NoteListCursor
+	if cursorQueryParam := r.URL.Query().Get("cursor"); cursorQueryParam != "" {
+		c, err := domain.DecodeNoteListCursor(cursorQueryParam)
+		if err != nil {
+			return nil, 0, "Invalid cursor query parameter. Use the cursor returned by the previous page."
+		}
+		cursor = c
+	}
+	limit := domain.DefaultNotesPageSize
+	if limitQueryParam := r.URL.Query().Get("limit"); limitQueryParam != "" {
+		l, err := strconv.Atoi(limitQueryParam)
+		if err != nil || l < 1 || l > domain.MaxNotesPageSize {
+			return nil, 0, fmt.Sprintf("Invalid limit query parameter. It must be a number between 1 and %d.", domain.MaxNotesPageSize)
+		}
+		limit = l
+	}
+	return cursor, limit, ""
+}
+
+// Sets the ETag header with the version of the note, it is sent back in the If-Match header of the updates
+func setNoteETag(w http.ResponseWriter, note *domain.Note) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(int64(note.Version), 10)))
+}
+
+// Parses the version of the note from an If-Match header with the value of a previous ETag header
+func parseIfMatch(header string) (int32, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	version, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || version < 1 {
+		return 0, errors.New("invalid If-Match header")
+	}
+	return int32(version), nil
+}
+
 // Handler for the get presigned urls endpoint
 func GetPresignedUrls(srv service.NoteService) http.HandlerFunc {
 	return http.HandlerFunc(
@@ -122,13 +234,21 @@ func CreateNote(srv service.NoteService) http.HandlerFunc {
 				return
 			}
 
-			res, err := srv.CreateNote(r.Context(), req.Title, req.Content, req.ObjectNames)
+			res, err := srv.CreateNote(r.Context(), req.Title, req.Content, req.ObjectNames, req.TagIds, req.NotebookId)
 			if err != nil {
 				switch err.Error() {
+				case "notebook not found":
+					msg := "Notebook not found"
+					response.BadRequest(w, r, &msg, nil)
+					return
 				case "objects not found":
 					msg := "One or more objects not found in the object storage service"
 					response.BadRequest(w, r, &msg, nil)
 					return
+				case "tag not found":
+					msg := "One or more tags not found"
+					response.BadRequest(w, r, &msg, nil)
+					return
 				default:
 					response.InternalServerError(w, r)
 					return
@@ -144,43 +264,54 @@ func CreateNote(srv service.NoteService) http.HandlerFunc {
 func ListNotesByUser(srv service.NoteService) http.HandlerFunc {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
-			// Get the cursor from the query parameters
-			cursorQueryParam := r.URL.Query().Get("cursor")
-			// parse the cursor query parameter
-			cursor, err := utils.ParseTime(cursorQueryParam)
-			if err != nil && cursorQueryParam != "" {
-				msg := "Invalid time format for the cursor query parameter. Must use RFC3339 format"
+			// Get the cursor and the limit from the query parameters
+			cursor, limit, msg := parseNoteListPage(r)
+			if msg != "" {
 				response.BadRequest(w, r, &msg, nil)
 				return
 			}
 
-			// If the cursor is zero, set it to the current time
-			if cursor.IsZero() {
-				cursor = time.Now().UTC()
+			// Get the sort key and the direction from the query parameters
+			sort, err := domain.ParseNoteSort(r.URL.Query().Get("sort"), r.URL.Query().Get("direction"))
+			if err != nil {
+				var msg string
+				switch err.Error() {
+				case "invalid sort direction":
+					msg = "Invalid direction query parameter. It must be asc or desc."
+				default:
+					msg = "Invalid sort query parameter. It must be create_time, update_time or title."
+				}
+				response.BadRequest(w, r, &msg, nil)
+				return
 			}
 
-			notes, err := srv.ListNotesByUser(r.Context(), cursor)
+			// Get the tag, notebook and date range filters from the query parameters
+			filter, msg := parseNoteFilter(r)
+			if msg != "" {
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			// Include the archived notes if requested
+			filter.IncludeArchived = r.URL.Query().Get("archived") == "true"
+
+			notes, err := srv.ListNotesByUser(r.Context(), sort, cursor, limit, filter)
 			if err != nil {
 				switch err.Error() {
+				case "cursor does not match the sort":
+					msg := "Invalid cursor query parameter. The cursor belongs to a listing with another sort."
+					response.BadRequest(w, r, &msg, nil)
+					return
 				default:
 					response.InternalServerError(w, r)
 					return
 				}
 			}
 
-			// Get the next cursor
-			notesSlice := *notes
-			var nextCursor time.Time
-			if len(notesSlice) > 0 {
-				nextCursor = notesSlice[len(notesSlice)-1].UpdateTime
-			} else {
-				// Handle the case where notesSlice is empty
-				nextCursor = time.Now().UTC()
-			}
-
 			res := ListNotesResponse{
-				Notes:  notes,
-				Cursor: nextCursor,
+				Notes:       notes.Notes,
+				HasNextPage: notes.HasNextPage,
+				NextCursor:  notes.NextCursor,
 			}
 			response.OK(w, r, res)
 		},
@@ -191,44 +322,162 @@ func ListNotesByUser(srv service.NoteService) http.HandlerFunc {
 func ListTrashNotesByUser(srv service.NoteService) http.HandlerFunc {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
-			// Get the cursor from the query parameters
-			cursorQueryParam := r.URL.Query().Get("cursor")
-			// parse the cursor query parameter
-			cursor, err := utils.ParseTime(cursorQueryParam)
-			if err != nil && cursorQueryParam != "" {
-				msg := "Invalid time format for the cursor query parameter. Must use RFC3339 format"
+			// Get the cursor and the limit from the query parameters
+			cursor, limit, msg := parseNoteListPage(r)
+			if msg != "" {
 				response.BadRequest(w, r, &msg, nil)
 				return
 			}
 
-			// If the cursor is zero, set it to the current time
-			if cursor.IsZero() {
-				cursor = time.Now().UTC()
+			// Get the tag, notebook and date range filters from the query parameters
+			filter, msg := parseNoteFilter(r)
+			if msg != "" {
+				response.BadRequest(w, r, &msg, nil)
+				return
 			}
 
-			notes, err := srv.ListTrashNotesByUser(r.Context(), cursor)
+			notes, err := srv.ListTrashNotesByUser(r.Context(), cursor, limit, filter)
 			if err != nil {
 				switch err.Error() {
+				case "cursor does not match the sort":
+					msg := "Invalid cursor query parameter. The cursor belongs to a listing with another sort."
+					response.BadRequest(w, r, &msg, nil)
+					return
 				default:
 					response.InternalServerError(w, r)
 					return
 				}
 			}
 
-			// Get the next cursor
-			notesSlice := *notes
-			var nextCursor time.Time
-			if len(notesSlice) > 0 {
-				nextCursor = notesSlice[len(notesSlice)-1].DeleteTime
-			} else {
-				// Handle the case where notesSlice is empty
-				nextCursor = time.Now().UTC()
+			res := ListNotesResponse{
+				Notes:       notes.Notes,
+				HasNextPage: notes.HasNextPage,
+				NextCursor:  notes.NextCursor,
+			}
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the list archived notes endpoint
+func ListArchivedNotesByUser(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the cursor and the limit from the query parameters
+			cursor, limit, msg := parseNoteListPage(r)
+			if msg != "" {
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			// Get the tag, notebook and date range filters from the query parameters
+			filter, msg := parseNoteFilter(r)
+			if msg != "" {
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			notes, err := srv.ListArchivedNotesByUser(r.Context(), cursor, limit, filter)
+			if err != nil {
+				switch err.Error() {
+				case "cursor does not match the sort":
+					msg := "Invalid cursor query parameter. The cursor belongs to a listing with another sort."
+					response.BadRequest(w, r, &msg, nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
 			}
 
 			res := ListNotesResponse{
-				Notes:  notes,
-				Cursor: nextCursor,
+				Notes:       notes.Notes,
+				HasNextPage: notes.HasNextPage,
+				NextCursor:  notes.NextCursor,
+			}
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the search notes endpoint
+func SearchNotes(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the search query from the query parameters
+			query := strings.TrimSpace(r.URL.Query().Get("q"))
+			if query == "" {
+				response.BadRequest(w, r, nil, map[string]string{"q": "field required"})
+				return
+			}
+
+			// Parse the cursor query parameter, it is returned by the previous page
+			var cursor *domain.NoteSearchCursor
+			if cursorQueryParam := r.URL.Query().Get("cursor"); cursorQueryParam != "" {
+				c, err := domain.DecodeNoteSearchCursor(cursorQueryParam)
+				if err != nil {
+					msg := "Invalid cursor query parameter. Use the cursor returned by the previous page."
+					response.BadRequest(w, r, &msg, nil)
+					return
+				}
+				cursor = c
+			}
+
+			// Parse the page size
+			limit := domain.DefaultNotesPageSize
+			if limitQueryParam := r.URL.Query().Get("limit"); limitQueryParam != "" {
+				l, err := strconv.Atoi(limitQueryParam)
+				if err != nil || l < 1 || l > domain.MaxNotesPageSize {
+					msg := fmt.Sprintf("Invalid limit query parameter. It must be a number between 1 and %d.", domain.MaxNotesPageSize)
+					response.BadRequest(w, r, &msg, nil)
+					return
+				}
+				limit = l
+			}
+
+			// Include the notes in the trash if requested
+			includeTrash := r.URL.Query().Get("trash") == "true"
+
+			res, err := srv.SearchNotes(r.Context(), query, includeTrash, cursor, limit)
+			if err != nil {
+				switch err.Error() {
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
 			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the get note endpoint
+func GetNote(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the note ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			res, err := srv.GetNote(r.Context(), id)
+			if err != nil {
+				switch err.Error() {
+				case "note not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			setNoteETag(w, res)
 			response.OK(w, r, res)
 		},
 	)
@@ -247,6 +496,19 @@ func UpdateNote(srv service.NoteService) http.HandlerFunc {
 				return
 			}
 
+			// The If-Match header is required, it has the version of the note the client last saw
+			ifMatch := r.Header.Get("If-Match")
+			if ifMatch == "" {
+				response.BadRequest(w, r, nil, map[string]string{"If-Match": "header required"})
+				return
+			}
+			version, err := parseIfMatch(ifMatch)
+			if err != nil {
+				msg := "Invalid If-Match header. Use the ETag header returned by the last read or update of the note."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
 			// Parse the request body into a UpdateNoteRequest struct
 			var req UpdateNoteRequest
 			err = json.NewDecoder(r.Body).Decode(&req)
@@ -267,14 +529,69 @@ func UpdateNote(srv service.NoteService) http.HandlerFunc {
 				Id:      id,
 				Title:   req.Title,
 				Content: req.Content,
+				Version: version,
+			}
+
+			res, err := srv.UpdateNote(r.Context(), note, req.AddTagIds, req.RemoveTagIds)
+			if err != nil {
+				switch err.Error() {
+				case "note not found":
+					response.NotFound(w, r, "")
+					return
+				case "note version conflict":
+					// The current copy of the note is returned so the client can merge the changes
+					setNoteETag(w, res)
+					Conflict(w, r, "The note was modified by another request. The current note is returned in the data field.", res)
+					return
+				case "tag not found":
+					msg := "One or more tags not found"
+					response.BadRequest(w, r, &msg, nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			setNoteETag(w, res)
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the move note endpoint
+func MoveNote(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the note ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
 			}
 
-			res, err := srv.UpdateNote(r.Context(), note)
+			// Parse the request body into a MoveNoteRequest struct, a null notebook takes the note out of its notebook
+			var req MoveNoteRequest
+			err = json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			res, err := srv.MoveNote(r.Context(), id, req.NotebookId)
 			if err != nil {
 				switch err.Error() {
 				case "note not found":
 					response.NotFound(w, r, "")
 					return
+				case "notebook not found":
+					msg := "Notebook not found"
+					response.BadRequest(w, r, &msg, nil)
+					return
 				default:
 					response.InternalServerError(w, r)
 					return
@@ -286,6 +603,57 @@ func UpdateNote(srv service.NoteService) http.HandlerFunc {
 	)
 }
 
+// Handler for the pin note endpoint
+func PinNote(srv service.NoteService) http.HandlerFunc {
+	return setNoteState(srv.PinNote)
+}
+
+// Handler for the unpin note endpoint
+func UnpinNote(srv service.NoteService) http.HandlerFunc {
+	return setNoteState(srv.UnpinNote)
+}
+
+// Handler for the archive note endpoint
+func ArchiveNote(srv service.NoteService) http.HandlerFunc {
+	return setNoteState(srv.ArchiveNote)
+}
+
+// Handler for the unarchive note endpoint
+func UnarchiveNote(srv service.NoteService) http.HandlerFunc {
+	return setNoteState(srv.UnarchiveNote)
+}
+
+// Handles the endpoints changing the pinned or archived state of a note, set is the service method of the change
+func setNoteState(set func(ctx context.Context, id uuid.UUID) (*domain.Note, error)) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the note ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			res, err := set(r.Context(), id)
+			if err != nil {
+				switch err.Error() {
+				case "note not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			setNoteETag(w, res)
+			response.OK(w, r, res)
+		},
+	)
+}
+
 // Handler for the update note endpoint
 func RestoreNote(srv service.NoteService) http.HandlerFunc {
 	return http.HandlerFunc(
@@ -375,3 +743,71 @@ func SoftDeleteNote(srv service.NoteService) http.HandlerFunc {
 		},
 	)
 }
+
+// Handler for the bulk soft delete notes endpoint
+func SoftDeleteNotes(srv service.NoteService) http.HandlerFunc {
+	return bulkNotes(srv.SoftDeleteNotes)
+}
+
+// Handler for the bulk restore notes endpoint
+func RestoreNotes(srv service.NoteService) http.HandlerFunc {
+	return bulkNotes(srv.RestoreNotes)
+}
+
+// Handler for the bulk hard delete notes endpoint
+func HardDeleteNotes(srv service.NoteService) http.HandlerFunc {
+	return bulkNotes(srv.HardDeleteNotes)
+}
+
+// Handles the bulk note operations endpoints, apply is the service method of the operation
+func bulkNotes(apply func(ctx context.Context, ids []uuid.UUID) (*service.BulkNotesResponse, error)) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Parse the request body into a BulkNotesRequest struct
+			var req BulkNotesRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			// Validate the request and return an BadRequest if there are any errors
+			if errors := req.Validate(); len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := apply(r.Context(), req.Ids)
+			if err != nil {
+				switch err.Error() {
+				case "ids required", "too many ids":
+					msg := fmt.Sprintf("Between 1 and %d note ids are required.", domain.MaxBulkNotes)
+					response.BadRequest(w, r, &msg, nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the empty trash endpoint
+func EmptyTrash(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			res, err := srv.EmptyTrash(r.Context())
+			if err != nil {
+				response.InternalServerError(w, r)
+				return
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/httpserver/middleware"
+	"github.com/daniarmas/notes/internal/service"
+)
+
+// Handler for the note events endpoint, the events are streamed as Server-Sent Events
+func NoteEvents(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// The browsers send the id of the last received event when they reconnect
+			lastEventId := r.Header.Get("Last-Event-ID")
+
+			events, err := srv.StreamNoteEvents(r.Context(), lastEventId)
+			if err != nil {
+				switch err.Error() {
+				case "invalid last event id":
+					msg := "Last-Event-ID header is invalid. It must be the id of a received event."
+					response.BadRequest(w, r, &msg, nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			// The stream is kept open beyond the write timeout of the server
+			rc := middleware.ResponseController(w, r)
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+				response.InternalServerError(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.Header().Set("X-Accel-Buffering", "no")
+			w.WriteHeader(http.StatusOK)
+			rc.Flush()
+
+			// Send a comment periodically so the proxies don't close an idle stream
+			keepAlive := time.NewTicker(15 * time.Second)
+			defer keepAlive.Stop()
+
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case <-keepAlive.C:
+					fmt.Fprint(w, ": keep-alive\n\n")
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					data, err := json.Marshal(event)
+					if err != nil {
+						return
+					}
+					if event.Id != "" {
+						fmt.Fprintf(w, "id: %s\n", event.Id)
+					}
+					fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+				}
+				if err := rc.Flush(); err != nil {
+					return
+				}
+			}
+		},
+	)
+}
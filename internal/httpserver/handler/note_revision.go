@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// Represent the structure of the list note revisions response
+type ListNoteRevisionsResponse struct {
+	Revisions *[]domain.NoteRevision `json:"revisions"`
+}
+
+// Handler for the list note revisions endpoint
+func ListNoteRevisions(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the note ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			revisions, err := srv.ListNoteRevisions(r.Context(), id)
+			if err != nil {
+				switch err.Error() {
+				case "note not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, ListNoteRevisionsResponse{Revisions: revisions})
+		},
+	)
+}
+
+// Handler for the get note revision endpoint
+func GetNoteRevision(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the note and revision IDs from the URL path
+			id, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			revisionId, err := uuid.Parse(r.PathValue("revisionId"))
+			if err != nil {
+				msg := "Provided revision ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			res, err := srv.GetNoteRevision(r.Context(), id, revisionId)
+			if err != nil {
+				switch err.Error() {
+				case "revision not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the diff note revisions endpoint
+func DiffNoteRevisions(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the note ID from the URL path
+			id, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			// Get the revisions to compare from the query parameters
+			errors := make(map[string]string)
+			from, err := uuid.Parse(r.URL.Query().Get("from"))
+			if err != nil {
+				errors["from"] = "field required, it must be a valid UUID"
+			}
+			to, err := uuid.Parse(r.URL.Query().Get("to"))
+			if err != nil {
+				errors["to"] = "field required, it must be a valid UUID"
+			}
+			if len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := srv.DiffNoteRevisions(r.Context(), id, from, to)
+			if err != nil {
+				switch err.Error() {
+				case "revision not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the restore note revision endpoint
+func RestoreNoteRevision(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the note and revision IDs from the URL path
+			id, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			revisionId, err := uuid.Parse(r.PathValue("revisionId"))
+			if err != nil {
+				msg := "Provided revision ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			res, err := srv.RestoreNoteRevision(r.Context(), id, revisionId)
+			if err != nil {
+				switch err.Error() {
+				case "revision not found", "note not found":
+					response.NotFound(w, r, "")
+					return
+				case "note version conflict":
+					setNoteETag(w, res)
+					Conflict(w, r, "The note was modified by another request. The current note is returned in the data field.", res)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			setNoteETag(w, res)
+			response.OK(w, r, res)
+		},
+	)
+}
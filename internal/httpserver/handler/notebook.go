@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// Represents the structure of the create notebook request
+type CreateNotebookRequest struct {
+	Name     string     `json:"name"`
+	ParentId *uuid.UUID `json:"parent_id"`
+}
+
+// Represents the structure of the rename notebook request
+type RenameNotebookRequest struct {
+	Name string `json:"name"`
+}
+
+// Represents the structure of the move notebook request
+type MoveNotebookRequest struct {
+	ParentId *uuid.UUID `json:"parent_id"`
+}
+
+// Represent the structure of the list notebooks response
+type ListNotebooksResponse struct {
+	Notebooks *[]domain.Notebook `json:"notebooks"`
+}
+
+// Validates the create notebook request
+func (r CreateNotebookRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if strings.TrimSpace(r.Name) == "" {
+		errors["name"] = "field required"
+	}
+	return errors
+}
+
+// Validates the rename notebook request
+func (r RenameNotebookRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if strings.TrimSpace(r.Name) == "" {
+		errors["name"] = "field required"
+	}
+	return errors
+}
+
+// Handler for the list notebooks endpoint
+func ListNotebooks(srv service.NotebookService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			notebooks, err := srv.ListNotebooks(r.Context())
+			if err != nil {
+				switch err.Error() {
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, ListNotebooksResponse{Notebooks: notebooks})
+		},
+	)
+}
+
+// Handler for the create notebook endpoint
+func CreateNotebook(srv service.NotebookService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Parse the request body into a CreateNotebookRequest struct
+			var req CreateNotebookRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			// Validate the request and return an BadRequest if there are any errors
+			if errors := req.Validate(); len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := srv.CreateNotebook(r.Context(), strings.TrimSpace(req.Name), req.ParentId)
+			if err != nil {
+				switch err.Error() {
+				case "parent notebook not found":
+					msg := "Parent notebook not found"
+					response.BadRequest(w, r, &msg, nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the rename notebook endpoint
+func RenameNotebook(srv service.NotebookService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the notebook ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			// Parse the request body into a RenameNotebookRequest struct
+			var req RenameNotebookRequest
+			err = json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			// Validate the request and return an BadRequest if there are any errors
+			if errors := req.Validate(); len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := srv.RenameNotebook(r.Context(), id, strings.TrimSpace(req.Name))
+			if err != nil {
+				switch err.Error() {
+				case "notebook not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the move notebook endpoint
+func MoveNotebook(srv service.NotebookService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the notebook ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			// Parse the request body into a MoveNotebookRequest struct, a null parent moves the notebook to the root
+			var req MoveNotebookRequest
+			err = json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			res, err := srv.MoveNotebook(r.Context(), id, req.ParentId)
+			if err != nil {
+				switch err.Error() {
+				case "notebook not found":
+					response.NotFound(w, r, "")
+					return
+				case "parent notebook not found":
+					msg := "Parent notebook not found"
+					response.BadRequest(w, r, &msg, nil)
+					return
+				case "invalid parent notebook":
+					msg := "A notebook can't be moved inside itself or one of its descendants"
+					response.BadRequest(w, r, &msg, nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the delete notebook endpoint
+func DeleteNotebook(srv service.NotebookService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the notebook ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			// Move the notes of the deleted notebooks to the trash if requested
+			trashNotes := r.URL.Query().Get("trash") == "true"
+
+			err = srv.DeleteNotebook(r.Context(), id, trashNotes)
+			if err != nil {
+				switch err.Error() {
+				case "notebook not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.NoContent(w, r)
+		},
+	)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/service"
+)
+
+// Represents the structure of the refresh token request
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Validates the refresh token request
+func (r RefreshTokenRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if r.RefreshToken == "" {
+		errors["refresh_token"] = "field required"
+	}
+	return errors
+}
+
+// Handler for the refresh token endpoint
+func RefreshToken(srv service.AuthenticationService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Parse the request body into a RefreshTokenRequest struct
+			var req RefreshTokenRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			// Validate the request and return an BadRequest if there are any errors
+			if errors := req.Validate(); len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := srv.RefreshToken(r.Context(), req.RefreshToken)
+			if err != nil {
+				switch err.Error() {
+				case "refresh token expired":
+					response.Unauthorized(w, r, "Refresh token has expired. Please log in again to continue.", nil)
+					return
+				case "invalid refresh token":
+					response.Unauthorized(w, r, "Refresh token provided is invalid. Please provide a valid token.", nil)
+					return
+				case "refresh token reused":
+					response.Unauthorized(w, r, "Refresh token was already used. The session has been revoked, please log in again.", nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// Handler for the list sessions endpoint
+func ListSessions(srv service.AuthenticationService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			res, err := srv.ListSessions(r.Context())
+			if err != nil {
+				switch err.Error() {
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the revoke session endpoint
+func RevokeSession(srv service.AuthenticationService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the session ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			err = srv.RevokeSession(r.Context(), id)
+			if err != nil {
+				switch err.Error() {
+				case "session not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.NoContent(w, r)
+		},
+	)
+}
+
+// Handler for the revoke other sessions endpoint
+func RevokeOtherSessions(srv service.AuthenticationService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			err := srv.RevokeOtherSessions(r.Context())
+			if err != nil {
+				switch err.Error() {
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.NoContent(w, r)
+		},
+	)
+}
@@ -11,8 +11,9 @@ import (
 
 // Represents the structure of the sign-in request
 type SignInRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name"`
 }
 
 // Validates the sign-in request
@@ -49,7 +50,7 @@ func SignIn(srv service.AuthenticationService) http.HandlerFunc {
 				return
 			}
 
-			res, err := srv.SignIn(r.Context(), req.Email, req.Password)
+			res, err := srv.SignIn(r.Context(), req.Email, req.Password, req.DeviceName)
 			if err != nil {
 				switch err.Error() {
 				case "invalid credentials":
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/internal/validate"
+)
+
+// Represents the structure of the sign-up request
+type SignUpRequest struct {
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name"`
+}
+
+// Validates the sign-up request
+func (r SignUpRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if r.Name == "" {
+		errors["name"] = "field required"
+	}
+	if r.Email == "" {
+		errors["email"] = "field required"
+	} else {
+		validate.ValidateEmail(&errors, r.Email)
+	}
+	if r.Password == "" {
+		errors["password"] = "field required"
+	} else {
+		for field, msg := range validate.ValidatePassword(r.Password) {
+			errors[field] = msg
+		}
+	}
+	return errors
+}
+
+// Handler for the sign-up endpoint
+func SignUp(srv service.AuthenticationService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Parse the request body into a SignUpRequest struct
+			var req SignUpRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			// Validate the request and return an BadRequest if there are any errors
+			if errors := req.Validate(); len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := srv.SignUp(r.Context(), req.Name, req.Email, req.Password, req.DeviceName)
+			if err != nil {
+				switch err.(type) {
+				case *customerrors.DuplicateRecord:
+					Conflict(w, r, "A user with this email already exists", nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/service"
+)
+
+// Handler for the delta sync endpoint
+func Sync(srv service.NoteService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// The token of the previous sync, a missing token returns all the changes
+			since := r.URL.Query().Get("since")
+
+			changes, err := srv.Sync(r.Context(), since)
+			if err != nil {
+				switch err.Error() {
+				case "invalid sync token":
+					msg := "Provided since query parameter is invalid. It must be the token of a previous sync."
+					response.BadRequest(w, r, &msg, nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, changes)
+		},
+	)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+)
+
+// Represents the structure of the create and update tag requests
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+// Represent the structure of the list tags response
+type ListTagsResponse struct {
+	Tags *[]domain.Tag `json:"tags"`
+}
+
+// Validates the tag request
+func (r TagRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if strings.TrimSpace(r.Name) == "" {
+		errors["name"] = "field required"
+	}
+	return errors
+}
+
+// Handler for the list tags endpoint
+func ListTags(srv service.TagService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			tags, err := srv.ListTags(r.Context())
+			if err != nil {
+				switch err.Error() {
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, ListTagsResponse{Tags: tags})
+		},
+	)
+}
+
+// Handler for the create tag endpoint
+func CreateTag(srv service.TagService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Parse the request body into a TagRequest struct
+			var req TagRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			// Validate the request and return an BadRequest if there are any errors
+			if errors := req.Validate(); len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := srv.CreateTag(r.Context(), strings.TrimSpace(req.Name))
+			if err != nil {
+				switch err.Error() {
+				case "tag already exists":
+					Conflict(w, r, "A tag with this name already exists", nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the update tag endpoint
+func UpdateTag(srv service.TagService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the tag ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			// Parse the request body into a TagRequest struct
+			var req TagRequest
+			err = json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				msg := "Invalid JSON request"
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+			defer r.Body.Close()
+
+			// Validate the request and return an BadRequest if there are any errors
+			if errors := req.Validate(); len(errors) > 0 {
+				response.BadRequest(w, r, nil, errors)
+				return
+			}
+
+			res, err := srv.UpdateTag(r.Context(), id, strings.TrimSpace(req.Name))
+			if err != nil {
+				switch err.Error() {
+				case "tag not found":
+					response.NotFound(w, r, "")
+					return
+				case "tag already exists":
+					Conflict(w, r, "A tag with this name already exists", nil)
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.OK(w, r, res)
+		},
+	)
+}
+
+// Handler for the delete tag endpoint
+func DeleteTag(srv service.TagService) http.HandlerFunc {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Get the tag ID from the URL path
+			idPathParam := r.PathValue("id")
+			id, err := uuid.Parse(idPathParam)
+			if err != nil {
+				msg := "Provided ID path parameter is invalid. It must be a valid UUID."
+				response.BadRequest(w, r, &msg, nil)
+				return
+			}
+
+			err = srv.DeleteTag(r.Context(), id)
+			if err != nil {
+				switch err.Error() {
+				case "tag not found":
+					response.NotFound(w, r, "")
+					return
+				default:
+					response.InternalServerError(w, r)
+					return
+				}
+			}
+
+			response.NoContent(w, r)
+		},
+	)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/daniarmas/notes/internal/config"
@@ -30,15 +31,23 @@ type Server struct {
 }
 
 // NewGraphQLServer creates and configures a new GraphQL server with the specified address.
-func NewGraphQLServer(authenticationService service.AuthenticationService, noteService service.NoteService, cfg config.Configuration, jwtDatasource domain.JwtDatasource) *Server {
+func NewGraphQLServer(authenticationService service.AuthenticationService, noteService service.NoteService, tagService service.TagService, notebookService service.NotebookService, cfg config.Configuration, jwtDatasource domain.JwtDatasource, accessTokenRepository domain.AccessTokenRepository) *Server {
 	// Create a new ServeMux
 	mux := http.NewServeMux()
 
 	// Create the GraphQL server
-	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{AuthSrv: authenticationService, NoteSrv: noteService}}))
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{AuthSrv: authenticationService, NoteSrv: noteService, TagSrv: tagService, NotebookSrv: notebookService}}))
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
+	// Subscriptions are served over websockets
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: middleware.CheckOrigin(cfg.AllowedOrigins),
+		},
+		InitFunc: middleware.WebsocketInit(jwtDatasource, accessTokenRepository),
+	})
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 	srv.Use(extension.Introspection{})
 	srv.Use(extension.AutomaticPersistedQuery{
@@ -53,7 +62,8 @@ func NewGraphQLServer(authenticationService service.AuthenticationService, noteS
 
 	var handler http.Handler = mux
 	// Add middlewares
-	handler = middleware.SetUserInContext(jwtDatasource)(handler)
+	handler = middleware.SetUserInContext(jwtDatasource, accessTokenRepository)(handler)
+	handler = middleware.SetClientInContext(cfg.TrustedProxies)(handler)
 
 	// Create the HTTP server
 	readTimeOut := 10 * time.Second
@@ -1,11 +1,16 @@
 package middleware
 
 import (
+	"context"
+	"net"
 	"net/http"
+	"net/netip"
+	"slices"
 	"strings"
 
 	cmiddleware "github.com/daniarmas/http/middleware"
 	"github.com/daniarmas/http/response"
+	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/domain"
 	"github.com/google/uuid"
 )
@@ -22,8 +27,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// SetUserInContext is a middleware that sets the user in the context
-func SetUserInContext(jwtDatasource domain.JwtDatasource) cmiddleware.Middleware {
+// SetUserInContext is a middleware that sets the user in the context.
+// The token id is checked against the stored access tokens so revoked tokens are rejected before they expire.
+func SetUserInContext(jwtDatasource domain.JwtDatasource, accessTokenRepository domain.AccessTokenRepository) cmiddleware.Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get the Authorization header from the request
@@ -55,8 +61,26 @@ func SetUserInContext(jwtDatasource domain.JwtDatasource) cmiddleware.Middleware
 					}
 				}
 
-				// Set the user in the context
+				// Check that the access token was not revoked
+				accessToken, err := accessTokenRepository.GetAccessToken(r.Context(), jwtMetadata.TokenId)
+				if err != nil {
+					switch err.(type) {
+					case *customerrors.RecordNotFound:
+						response.Unauthorized(w, r, "Authorization token has been revoked. Please log in again to continue.", nil)
+						return
+					default:
+						response.InternalServerError(w, r)
+						return
+					}
+				}
+				if accessToken.UserId != jwtMetadata.UserId {
+					response.Unauthorized(w, r, "Authorization token provided is invalid. Please provide a valid token.", nil)
+					return
+				}
+
+				// Set the user and the access token in the context
 				ctx := domain.SetUserInContext(r.Context(), jwtMetadata.UserId)
+				ctx = domain.SetAccessTokenIdInContext(ctx, jwtMetadata.TokenId)
 
 				// Call the next handler with the modified context
 				next.ServeHTTP(w, r.WithContext(ctx))
@@ -68,6 +92,90 @@ func SetUserInContext(jwtDatasource domain.JwtDatasource) cmiddleware.Middleware
 	}
 }
 
+// SetClientInContext is a middleware that sets the user agent and ip address of the client in the context.
+// The proxy headers are only honoured when the request comes from one of the trusted proxies.
+func SetClientInContext(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := domain.SetClientInContext(r.Context(), r.UserAgent(), clientIp(r, trustedProxies))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// clientIp returns the ip address of the client. Behind a trusted proxy it is the nearest untrusted
+// address of X-Forwarded-For, the addresses on its left are set by the client and can't be trusted.
+func clientIp(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+	if forwardedFor := r.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		addrs := strings.Split(strings.Join(forwardedFor, ","), ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			addr := strings.TrimSpace(addrs[i])
+			if !isTrustedProxy(addr, trustedProxies) {
+				return addr
+			}
+		}
+		return strings.TrimSpace(addrs[0])
+	}
+	if realIp := r.Header.Get("X-Real-Ip"); realIp != "" {
+		return realIp
+	}
+	return host
+}
+
+// isTrustedProxy reports whether the ip address is in one of the trusted proxies
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// corsAllowedMethods and corsAllowedHeaders are the methods and request headers the browsers can send from an allowed origin.
+// Last-Event-ID resumes the feed of note events and If-Match sends the version of the updated note.
+var (
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowedHeaders = "Content-Type, Authorization, Last-Event-ID, If-Match"
+	corsExposedHeaders = "ETag"
+)
+
+// Cors is a middleware that allows the browsers on the allowed origins to call the server.
+// The preflight requests of an allowed origin are answered without calling the next handler.
+func Cors(allowedOrigins []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+			origin := r.Header.Get("Origin")
+			if origin == "" || !slices.Contains(allowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			// Handle preflight requests
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func LoggedOnly(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		userId := domain.GetUserIdFromContext(r.Context())
@@ -79,3 +187,23 @@ func LoggedOnly(h http.Handler) http.Handler {
 		h.ServeHTTP(w, r)
 	})
 }
+
+type responseWriterKey struct{}
+
+// SetResponseWriterInContext keeps the response writer of the server in the context.
+// The writers of the other middlewares don't implement http.Flusher, so streaming handlers flush and
+// extend the write deadline through the ResponseController of this writer.
+func SetResponseWriterInContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), responseWriterKey{}, w)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ResponseController returns the controller of the response writer of the server, or of w if it is not in the context
+func ResponseController(w http.ResponseWriter, r *http.Request) *http.ResponseController {
+	if rw, ok := r.Context().Value(responseWriterKey{}).(http.ResponseWriter); ok {
+		return http.NewResponseController(rw)
+	}
+	return http.NewResponseController(w)
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// accessTokenCheckInterval is how often a websocket connection checks that its access token was not revoked
+const accessTokenCheckInterval = 30 * time.Second
+
+// WebsocketInit authenticates a websocket connection with the Authorization field of the connection_init payload.
+// Browsers can't set headers on a websocket, so the token is sent in the payload instead of the Authorization header.
+// An authenticated connection is closed once its access token expires or is revoked.
+func WebsocketInit(jwtDatasource domain.JwtDatasource, accessTokenRepository domain.AccessTokenRepository) transport.WebsocketInitFunc {
+	return func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		// The user was already set from the Authorization header of the upgrade request
+		if domain.GetUserIdFromContext(ctx) != uuid.Nil {
+			accessToken, err := accessTokenRepository.GetAccessToken(ctx, domain.GetAccessTokenIdFromContext(ctx))
+			if err != nil {
+				switch err.(type) {
+				case *customerrors.RecordNotFound:
+					return nil, nil, errors.New("authorization token has been revoked")
+				default:
+					return nil, nil, errors.New("internal server error")
+				}
+			}
+			return watchAccessToken(ctx, accessTokenRepository, accessToken), &initPayload, nil
+		}
+
+		authHeader := initPayload.Authorization()
+		if authHeader == "" {
+			return ctx, &initPayload, nil
+		}
+
+		// Split the header to get the token
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, nil, errors.New("authorization format is invalid")
+		}
+		jwtMetadata := domain.JWTMetadata{Token: parts[1]}
+		if err := jwtDatasource.ParseJWT(&jwtMetadata); err != nil {
+			switch err.Error() {
+			case "Token is expired":
+				return nil, nil, errors.New("authorization token has expired")
+			default:
+				return nil, nil, errors.New("authorization token is invalid")
+			}
+		}
+
+		// Check that the access token was not revoked
+		accessToken, err := accessTokenRepository.GetAccessToken(ctx, jwtMetadata.TokenId)
+		if err != nil {
+			switch err.(type) {
+			case *customerrors.RecordNotFound:
+				return nil, nil, errors.New("authorization token has been revoked")
+			default:
+				return nil, nil, errors.New("internal server error")
+			}
+		}
+		if accessToken.UserId != jwtMetadata.UserId {
+			return nil, nil, errors.New("authorization token is invalid")
+		}
+
+		// Set the user and the access token in the context
+		ctx = domain.SetUserInContext(ctx, jwtMetadata.UserId)
+		ctx = domain.SetAccessTokenIdInContext(ctx, jwtMetadata.TokenId)
+		return watchAccessToken(ctx, accessTokenRepository, accessToken), &initPayload, nil
+	}
+}
+
+// CheckOrigin allows the websocket upgrades of the allowed origins, the requests of the same origin and the ones without an Origin header.
+// Any other origin is rejected, so a page of another site can't open the subscriptions from the browser of the user.
+func CheckOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || slices.Contains(allowedOrigins, origin) {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// watchAccessToken returns a context that is done once the access token expires or is revoked,
+// the websocket connection of the subscriptions is closed along with it
+func watchAccessToken(ctx context.Context, accessTokenRepository domain.AccessTokenRepository, accessToken *domain.AccessToken) context.Context {
+	ctx = transport.AppendCloseReason(ctx, "authorization token has expired or has been revoked")
+	ctx, cancel := context.WithDeadline(ctx, accessToken.CreateTime.Add(domain.AccessTokenLifetime))
+	go func() {
+		defer cancel()
+		ticker := time.NewTicker(accessTokenCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// A failure to reach the store keeps the connection, only a deleted token closes it
+				if _, err := accessTokenRepository.GetAccessToken(ctx, accessToken.Id); err != nil {
+					if _, ok := err.(*customerrors.RecordNotFound); ok {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return ctx
+}
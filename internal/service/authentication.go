@@ -8,6 +8,7 @@ import (
 
 	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
 )
 
 type SignInResponse struct {
@@ -20,9 +21,18 @@ type MeResponse struct {
 	User domain.User `json:"user"`
 }
 
+type ListSessionsResponse struct {
+	Sessions []domain.Session `json:"sessions"`
+}
+
 type AuthenticationService interface {
-	SignIn(ctx context.Context, email string, password string) (*SignInResponse, error)
+	SignUp(ctx context.Context, name string, email string, password string, deviceName string) (*SignInResponse, error)
+	SignIn(ctx context.Context, email string, password string, deviceName string) (*SignInResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*SignInResponse, error)
 	SignOut(ctx context.Context) error
+	ListSessions(ctx context.Context) (*ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, id uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context) error
 	Me(ctx context.Context) (*MeResponse, error)
 }
 
@@ -46,7 +56,57 @@ func NewAuthenticationService(jwtDatasource domain.JwtDatasource, hashDatasource
 	}
 }
 
-func (s *authenticationService) SignIn(ctx context.Context, email string, password string) (*SignInResponse, error) {
+func (s *authenticationService) SignUp(ctx context.Context, name string, email string, password string, deviceName string) (*SignInResponse, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Hash the user password
+	hashedPassword, err := s.HashDatasource.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the user
+	user, err := s.UserRepository.CreateUser(ctx, tx, &domain.User{
+		Name:     name,
+		Email:    email,
+		Password: hashedPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the session tokens
+	userAgent, ipAddress := domain.GetClientFromContext(ctx)
+	res, err := s.createSession(ctx, tx, user, &domain.RefreshToken{
+		FamilyId:   uuid.New(),
+		DeviceName: deviceName,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *authenticationService) SignIn(ctx context.Context, email string, password string, deviceName string) (*SignInResponse, error) {
 	// Start the sql transaction
 	tx, err := s.Db.BeginTx(ctx, nil)
 	if err != nil {
@@ -86,31 +146,29 @@ func (s *authenticationService) SignIn(ctx context.Context, email string, passwo
 		return nil, errors.New("invalid credentials")
 	}
 
-	// Delete the existing access token
-	err = s.AccessTokenRepository.DeleteAccessTokenByUserId(ctx, tx, user.Id)
+	// Create the session tokens
+	userAgent, ipAddress := domain.GetClientFromContext(ctx)
+	res, err := s.createSession(ctx, tx, user, &domain.RefreshToken{
+		FamilyId:   uuid.New(),
+		DeviceName: deviceName,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+	})
 	if err != nil {
-		switch err.(type) {
-		case *customerrors.RecordNotFound:
-			// Do nothing
-		default:
-			return nil, err
-		}
+		return nil, err
 	}
 
-	// Delete the existing refresh token
-	err = s.RefreshTokenRepository.DeleteRefreshTokenByUserId(ctx, tx, user.Id)
-	if err != nil {
-		switch err.(type) {
-		case *customerrors.RecordNotFound:
-			// Do nothing
-		default:
-			return nil, err
-		}
-	}
+	return res, nil
+}
+
+// createSession creates the refresh and access tokens of a session for the user
+// and returns them signed as jwt. The session argument holds the family id and the
+// device of the session, every refresh token issued by rotation keeps the family id
+// of the sign in that started the session.
+func (s *authenticationService) createSession(ctx context.Context, tx *sql.Tx, user *domain.User, session *domain.RefreshToken) (*SignInResponse, error) {
 	// Create a new refresh token
-	refreshToken, err := s.RefreshTokenRepository.CreateRefreshToken(ctx, tx, &domain.RefreshToken{
-		UserId: user.Id,
-	})
+	session.UserId = user.Id
+	refreshToken, err := s.RefreshTokenRepository.CreateRefreshToken(ctx, tx, session)
 	if err != nil {
 		return nil, err
 	}
@@ -121,14 +179,14 @@ func (s *authenticationService) SignIn(ctx context.Context, email string, passwo
 	}
 	// Create jwt
 	now := time.Now()
-	accessTokenExpiration := now.Add(60 * time.Minute)
-	refreshTokenExpiration := now.Add(30 * 24 * time.Hour)
+	accessTokenExpiration := now.Add(domain.AccessTokenLifetime)
+	refreshTokenExpiration := now.Add(domain.RefreshTokenLifetime)
 	// Refresh token jwt
 	refreshTokenJWT, err := s.JwtDatasource.CreateJWT(&domain.JWTMetadata{TokenId: refreshToken.Id, UserId: user.Id}, refreshTokenExpiration)
 	if err != nil {
 		return nil, err
 	}
-	// Refresh token jwt
+	// Access token jwt
 	accessTokenJWT, err := s.JwtDatasource.CreateJWT(&domain.JWTMetadata{TokenId: accessToken.Id, UserId: user.Id}, accessTokenExpiration)
 	if err != nil {
 		return nil, err
@@ -141,6 +199,148 @@ func (s *authenticationService) SignIn(ctx context.Context, email string, passwo
 	}, nil
 }
 
+// errRefreshTokenReused reports the exchange of a refresh token that was already rotated
+var errRefreshTokenReused = errors.New("refresh token reused")
+
+func (s *authenticationService) RefreshToken(ctx context.Context, refreshToken string) (*SignInResponse, error) {
+	res, token, err := s.rotateRefreshToken(ctx, refreshToken)
+	if err == errRefreshTokenReused {
+		// The rotation is rolled back, so the session family is revoked in a transaction of its own
+		if revokeErr := s.revokeReusedSessionFamily(ctx, token.FamilyId); revokeErr != nil {
+			return nil, revokeErr
+		}
+	}
+	return res, err
+}
+
+// rotateRefreshToken exchanges the refresh token for new session tokens in the same family.
+// A reused refresh token is reported with errRefreshTokenReused along the stored token, every error rolls the transaction back.
+func (s *authenticationService) rotateRefreshToken(ctx context.Context, refreshToken string) (res *SignInResponse, token *domain.RefreshToken, err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Parse the refresh token jwt
+	jwtMetadata := domain.JWTMetadata{Token: refreshToken}
+	if err = s.JwtDatasource.ParseJWT(&jwtMetadata); err != nil {
+		switch err.Error() {
+		case "Token is expired":
+			return nil, nil, errors.New("refresh token expired")
+		default:
+			return nil, nil, errors.New("invalid refresh token")
+		}
+	}
+
+	// Get the refresh token
+	token, err = s.RefreshTokenRepository.GetRefreshToken(ctx, jwtMetadata.TokenId)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, nil, errors.New("invalid refresh token")
+		default:
+			return nil, nil, err
+		}
+	}
+	if token.UserId != jwtMetadata.UserId {
+		return nil, nil, errors.New("invalid refresh token")
+	}
+
+	// The refresh token was already exchanged
+	if !token.RotateTime.IsZero() {
+		return nil, token, errRefreshTokenReused
+	}
+
+	// Mark the refresh token as rotated. If it was rotated concurrently it is a reuse as well
+	if err = s.RefreshTokenRepository.RotateRefreshToken(ctx, tx, token.Id); err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, token, errRefreshTokenReused
+		default:
+			return nil, nil, err
+		}
+	}
+
+	// Delete the access tokens issued with the rotated refresh token
+	if err = s.AccessTokenRepository.DeleteAccessTokensByRefreshTokenId(ctx, tx, token.Id); err != nil {
+		return nil, nil, err
+	}
+
+	// Get the user
+	user, err := s.UserRepository.GetUserById(ctx, token.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create the new session tokens in the same family, keeping the device of the session
+	userAgent, ipAddress := domain.GetClientFromContext(ctx)
+	if userAgent == "" {
+		userAgent = token.UserAgent
+	}
+	if ipAddress == "" {
+		ipAddress = token.IpAddress
+	}
+	res, err = s.createSession(ctx, tx, user, &domain.RefreshToken{
+		FamilyId:   token.FamilyId,
+		DeviceName: token.DeviceName,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, token, nil
+}
+
+// revokeReusedSessionFamily revokes the session family of a reused refresh token in its own transaction
+func (s *authenticationService) revokeReusedSessionFamily(ctx context.Context, familyId uuid.UUID) (err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	return s.revokeSessionFamily(ctx, tx, familyId)
+}
+
+// revokeSessionFamily deletes every refresh and access token of a session family
+func (s *authenticationService) revokeSessionFamily(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) error {
+	// The access tokens must be deleted first, they are found through the refresh tokens
+	if err := s.AccessTokenRepository.DeleteAccessTokensByRefreshTokenFamilyId(ctx, tx, familyId); err != nil {
+		return err
+	}
+	if err := s.RefreshTokenRepository.DeleteRefreshTokensByFamilyId(ctx, tx, familyId); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *authenticationService) SignOut(ctx context.Context) error {
 	// Start the sql transaction
 	tx, err := s.Db.BeginTx(ctx, nil)
@@ -160,20 +360,145 @@ func (s *authenticationService) SignOut(ctx context.Context) error {
 		}
 	}()
 
+	// Get the session of the request
+	sessionId, err := s.currentSessionId(ctx)
+	if err != nil {
+		return err
+	}
+
+	// End only the current session, the other devices stay signed in
+	err = s.revokeSessionFamily(ctx, tx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// currentSessionId returns the session of the access token that authenticated the request
+func (s *authenticationService) currentSessionId(ctx context.Context) (uuid.UUID, error) {
+	accessToken, err := s.AccessTokenRepository.GetAccessToken(ctx, domain.GetAccessTokenIdFromContext(ctx))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	refreshToken, err := s.RefreshTokenRepository.GetRefreshToken(ctx, accessToken.RefreshTokenId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return refreshToken.FamilyId, nil
+}
+
+func (s *authenticationService) ListSessions(ctx context.Context) (*ListSessionsResponse, error) {
+	// Get the user from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Get the session of the request
+	currentSessionId, err := s.currentSessionId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.RefreshTokenRepository.ListSessionsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *sessions {
+		(*sessions)[i].Current = (*sessions)[i].Id == currentSessionId
+	}
+
+	return &ListSessionsResponse{
+		Sessions: *sessions,
+	}, nil
+}
+
+func (s *authenticationService) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Get the user from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Check that the session belongs to the user
+	sessions, err := s.RefreshTokenRepository.ListSessionsByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, session := range *sessions {
+		if session.Id == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		err = errors.New("session not found")
+		return err
+	}
+
+	err = s.revokeSessionFamily(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authenticationService) RevokeOtherSessions(ctx context.Context) error {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
 	// Get the user from the context
 	userId := domain.GetUserIdFromContext(ctx)
 
-	// Delete the existing access token
-	err = s.AccessTokenRepository.DeleteAccessTokenByUserId(ctx, tx, userId)
+	// Get the session of the request
+	currentSessionId, err := s.currentSessionId(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Delete the existing refresh token
-	err = s.RefreshTokenRepository.DeleteRefreshTokenByUserId(ctx, tx, userId)
+	sessions, err := s.RefreshTokenRepository.ListSessionsByUserId(ctx, userId)
 	if err != nil {
 		return err
 	}
+	for _, session := range *sessions {
+		if session.Id == currentSessionId {
+			continue
+		}
+		err = s.revokeSessionFamily(ctx, tx, session.Id)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+)
+
+// maxFileJobBackoff caps the wait between the attempts of a failing file job
+const maxFileJobBackoff = time.Hour
+
+// ProcessFileJobsOptions configures a run of the file processing worker
+type ProcessFileJobsOptions struct {
+	// Worker identifies the lease owner, it must be unique among the running workers
+	Worker string
+	// Concurrency is the number of jobs claimed and processed at once
+	Concurrency int
+	// Lease is how long a job is held by the worker before another worker can claim it
+	Lease time.Duration
+	// MaxAttempts is the number of attempts before a job is failed
+	MaxAttempts int
+	// RetryBackoff is the wait after the first failed attempt, it doubles with every attempt
+	RetryBackoff time.Duration
+}
+
+// ProcessFileJobsSummary reports what a run of the file processing worker did
+type ProcessFileJobsSummary struct {
+	Claimed   int
+	Succeeded int
+	Retried   int
+	Failed    int
+	// Lost are the jobs whose lease expired before they were done, another worker claims them again
+	Lost int
+}
+
+type FileJobService interface {
+	ProcessFileJobs(ctx context.Context, opts ProcessFileJobsOptions) (*ProcessFileJobsSummary, error)
+	ReprocessFiles(ctx context.Context, opts ReprocessFilesOptions) (*ReprocessFilesSummary, error)
+}
+
+type fileJobService struct {
+	Config            config.Configuration
+	FileJobRepository domain.FileJobRepository
+	FileRepository    domain.FileRepository
+	NoteRepository    domain.NoteRepository
+	EventRepository   domain.NoteEventRepository
+	Db                *sql.DB
+}
+
+func NewFileJobService(fileJobRepository domain.FileJobRepository, fileRepository domain.FileRepository, noteRepository domain.NoteRepository, eventRepository domain.NoteEventRepository, cfg config.Configuration, db *sql.DB) FileJobService {
+	return &fileJobService{
+		Config:            cfg,
+		FileJobRepository: fileJobRepository,
+		FileRepository:    fileRepository,
+		NoteRepository:    noteRepository,
+		EventRepository:   eventRepository,
+		Db:                db,
+	}
+}
+
+// ProcessFileJobs claims the due jobs up to the concurrency and processes their files concurrently.
+// Every job is processed in its own transaction, a failed job is queued again with a backoff until it runs out of attempts.
+func (s *fileJobService) ProcessFileJobs(ctx context.Context, opts ProcessFileJobsOptions) (*ProcessFileJobsSummary, error) {
+	// A zero concurrency would never claim a job, a zero lease would let another worker claim the jobs again at once
+	switch {
+	case opts.Concurrency < 1:
+		return nil, errors.New("concurrency must be positive")
+	case opts.Lease <= 0:
+		return nil, errors.New("lease must be positive")
+	case opts.MaxAttempts < 1:
+		return nil, errors.New("max attempts must be positive")
+	case opts.RetryBackoff <= 0:
+		return nil, errors.New("retry backoff must be positive")
+	}
+
+	jobs, err := s.claimJobs(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ProcessFileJobsSummary{Claimed: len(*jobs)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := range *jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := s.runJob(ctx, &(*jobs)[i], opts)
+			mu.Lock()
+			defer mu.Unlock()
+			switch status {
+			case domain.FileJobSucceeded:
+				summary.Succeeded++
+			case domain.FileJobQueued:
+				summary.Retried++
+			case domain.FileJobFailed:
+				summary.Failed++
+			default:
+				summary.Lost++
+			}
+		}()
+	}
+	wg.Wait()
+
+	return summary, nil
+}
+
+// claimJobs leases the due jobs to the worker in a transaction
+func (s *fileJobService) claimJobs(ctx context.Context, opts ProcessFileJobsOptions) (jobs *[]domain.FileJob, err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	return s.FileJobRepository.ClaimFileJobs(ctx, tx, opts.Worker, opts.Lease, int32(opts.Concurrency))
+}
+
+// runJob processes the file of a claimed job and returns the status the job is left in, an empty status means the lease was lost
+func (s *fileJobService) runJob(ctx context.Context, job *domain.FileJob, opts ProcessFileJobsOptions) string {
+	var file *domain.File
+	var err error
+	// The attempts are counted when the job is claimed, so a job whose worker keeps crashing runs out of them too
+	if int(job.Attempts) > opts.MaxAttempts {
+		err = errors.New("the lease of the job expired on every attempt")
+	} else {
+		file, err = s.processJob(ctx, job, opts.Worker)
+	}
+
+	if err == nil {
+		s.publishFileProcessed(ctx, file)
+		return domain.FileJobSucceeded
+	}
+
+	s.discardUnsavedObjects(ctx, err, job.OriginalFile)
+	var notFound *customerrors.RecordNotFound
+	if errors.As(err, &notFound) {
+		clogg.Error(ctx, "file job lease lost", clogg.String("file", job.OriginalFile))
+		return ""
+	}
+	// A reprocess or a previous lease owner saved the processed copy of the file, so there is nothing left to do
+	if errors.Is(err, domain.ErrFileAlreadyProcessed) {
+		if completeErr := s.completeJob(ctx, job, opts.Worker); completeErr != nil {
+			clogg.Error(ctx, "error completing the file job", clogg.String("file", job.OriginalFile), clogg.String("error", completeErr.Error()))
+			return ""
+		}
+		clogg.Info(ctx, "file job completed, the file is already processed", clogg.String("file", job.OriginalFile))
+		return domain.FileJobSucceeded
+	}
+
+	// Retry the job later, or fail it once it runs out of attempts
+	isDead := int(job.Attempts) >= opts.MaxAttempts
+	runTime := time.Now().UTC().Add(retryBackoff(opts.RetryBackoff, int(job.Attempts), maxFileJobBackoff))
+	if failErr := s.failJob(ctx, job, opts.Worker, err.Error(), runTime, isDead); failErr != nil {
+		clogg.Error(ctx, "error failing the file job", clogg.String("file", job.OriginalFile), clogg.String("error", failErr.Error()))
+		return ""
+	}
+	if isDead {
+		clogg.Error(ctx, "file job failed", clogg.String("file", job.OriginalFile), clogg.Int("attempts", int(job.Attempts)), clogg.String("error", err.Error()))
+		return domain.FileJobFailed
+	}
+	clogg.Info(ctx, "file job retried", clogg.String("file", job.OriginalFile), clogg.Int("attempts", int(job.Attempts)), clogg.String("error", err.Error()))
+	return domain.FileJobQueued
+}
+
+// processJob processes the file and completes the job in a transaction, so the processed file is only saved by the lease owner
+func (s *fileJobService) processJob(ctx context.Context, job *domain.FileJob, worker string) (file *domain.File, err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if file, err = s.FileRepository.Process(ctx, tx, job.OriginalFile); err != nil {
+		return nil, err
+	}
+	if err = s.FileJobRepository.CompleteFileJob(ctx, tx, job, worker); err != nil {
+		return nil, &domain.UnsavedObjectsError{ObjectNames: file.ProcessedObjectNames(), Err: err}
+	}
+	file.ProcessingStatus = domain.FileProcessingSucceeded
+	return file, nil
+}
+
+// completeJob completes the job of a file that is already processed in a transaction
+func (s *fileJobService) completeJob(ctx context.Context, job *domain.FileJob, worker string) (err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	return s.FileJobRepository.CompleteFileJob(ctx, tx, job, worker)
+}
+
+// discardUnsavedObjects discards the processed copies uploaded by a rolled back transaction, they are referenced by no file.
+// A failure is only logged, the error of the processing is the one reported.
+func (s *fileJobService) discardUnsavedObjects(ctx context.Context, err error, originalFile string) {
+	var unsaved *domain.UnsavedObjectsError
+	if !errors.As(err, &unsaved) || len(unsaved.ObjectNames) == 0 {
+		return
+	}
+	if discardErr := s.discardObjects(ctx, unsaved.ObjectNames); discardErr != nil {
+		clogg.Error(ctx, "error discarding the processed objects", clogg.String("file", originalFile), clogg.String("error", discardErr.Error()))
+	}
+}
+
+// discardObjects queues the uploaded objects that no file references for deletion in a transaction
+func (s *fileJobService) discardObjects(ctx context.Context, objectNames []string) (err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	return s.FileRepository.DiscardObjects(ctx, tx, objectNames)
+}
+
+// failJob queues the job again or fails it in a transaction
+func (s *fileJobService) failJob(ctx context.Context, job *domain.FileJob, worker string, lastError string, runTime time.Time, dead bool) (err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	return s.FileJobRepository.FailFileJob(ctx, tx, job, worker, lastError, runTime, dead)
+}
+
+// publishFileProcessed notifies the owner of the note of the processed file, a failure is only logged because the file is committed
+func (s *fileJobService) publishFileProcessed(ctx context.Context, file *domain.File) {
+	note, err := s.NoteRepository.GetNote(ctx, file.NoteId)
+	if err != nil {
+		clogg.Error(ctx, "error getting the note of the processed file", clogg.String("error", err.Error()))
+		return
+	}
+	if err := s.EventRepository.PublishNoteEvent(ctx, domain.NewFileProcessedEvent(note.UserId, file), s.Config.NoteEventsStreamLength); err != nil {
+		clogg.Error(ctx, "error publishing the note event", clogg.String("error", err.Error()))
+	}
+}
@@ -6,17 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
-
 	"github.com/daniarmas/clogg"
 	"github.com/daniarmas/notes/internal/config"
 	"github.com/daniarmas/notes/internal/customerrors"
 	"github.com/daniarmas/notes/internal/domain"
-	"github.com/daniarmas/notes/internal/k8sc"
 	"github.com/daniarmas/notes/internal/oss"
 	"github.com/google/uuid"
 )
@@ -36,43 +33,102 @@ type GetPresignedUrlsResponse struct {
 	Urls []PresignedUrl `json:"urls"`
 }
 
+// ListNotesResponse represents the structure of a page of the list notes response
+type ListNotesResponse struct {
+	Notes       []domain.Note `json:"notes"`
+	HasNextPage bool          `json:"has_next_page"`
+	NextCursor  string        `json:"next_cursor"`
+}
+
+// SearchNotesResponse represents the structure of the search notes response
+type SearchNotesResponse struct {
+	Results    []domain.NoteSearchResult `json:"results"`
+	NextCursor string                    `json:"next_cursor"`
+}
+
+// BulkNoteResult is the outcome of a bulk operation for one of its notes
+type BulkNoteResult struct {
+	Id    uuid.UUID `json:"id"`
+	Ok    bool      `json:"ok"`
+	Error string    `json:"error,omitempty"`
+}
+
+// BulkNotesResponse represents the structure of the response of the bulk note operations
+type BulkNotesResponse struct {
+	Results []BulkNoteResult `json:"results"`
+	// HasMore is true if the trash still has notes after emptying it, it is emptied in batches
+	HasMore bool `json:"has_more"`
+}
+
 type NoteService interface {
-	CreateNote(ctx context.Context, title string, content string, objectNames []string) (*CreateNoteResponse, error)
-	ListTrashNotesByUser(ctx context.Context, cursor time.Time) (*[]domain.Note, error)
-	ListNotesByUser(ctx context.Context, cursor time.Time) (*[]domain.Note, error)
+	CreateNote(ctx context.Context, title string, content string, objectNames []string, tagIds []uuid.UUID, notebookId *uuid.UUID) (*CreateNoteResponse, error)
+	ListTrashNotesByUser(ctx context.Context, cursor *domain.NoteListCursor, limit int, filter domain.NoteFilter) (*ListNotesResponse, error)
+	ListNotesByUser(ctx context.Context, sort domain.NoteSort, cursor *domain.NoteListCursor, limit int, filter domain.NoteFilter) (*ListNotesResponse, error)
+	ListArchivedNotesByUser(ctx context.Context, cursor *domain.NoteListCursor, limit int, filter domain.NoteFilter) (*ListNotesResponse, error)
+	GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error)
+	SearchNotes(ctx context.Context, query string, includeTrash bool, cursor *domain.NoteSearchCursor, limit int) (*SearchNotesResponse, error)
+	MoveNote(ctx context.Context, id uuid.UUID, notebookId *uuid.UUID) (*domain.Note, error)
 	RestoreNote(ctx context.Context, id uuid.UUID) (*domain.Note, error)
+	PinNote(ctx context.Context, id uuid.UUID) (*domain.Note, error)
+	UnpinNote(ctx context.Context, id uuid.UUID) (*domain.Note, error)
+	ArchiveNote(ctx context.Context, id uuid.UUID) (*domain.Note, error)
+	UnarchiveNote(ctx context.Context, id uuid.UUID) (*domain.Note, error)
 	DeleteNote(ctx context.Context, id uuid.UUID, hard bool) error
-	UpdateNote(ctx context.Context, note *domain.Note) (*domain.Note, error)
+	SoftDeleteNotes(ctx context.Context, ids []uuid.UUID) (*BulkNotesResponse, error)
+	RestoreNotes(ctx context.Context, ids []uuid.UUID) (*BulkNotesResponse, error)
+	HardDeleteNotes(ctx context.Context, ids []uuid.UUID) (*BulkNotesResponse, error)
+	EmptyTrash(ctx context.Context) (*BulkNotesResponse, error)
+	UpdateNote(ctx context.Context, note *domain.Note, addTagIds []uuid.UUID, removeTagIds []uuid.UUID) (*domain.Note, error)
+	ListNoteRevisions(ctx context.Context, noteId uuid.UUID) (*[]domain.NoteRevision, error)
+	GetNoteRevision(ctx context.Context, noteId uuid.UUID, id uuid.UUID) (*domain.NoteRevision, error)
+	DiffNoteRevisions(ctx context.Context, noteId uuid.UUID, fromId uuid.UUID, toId uuid.UUID) (*domain.NoteRevisionDiff, error)
+	RestoreNoteRevision(ctx context.Context, noteId uuid.UUID, id uuid.UUID) (*domain.Note, error)
+	SubscribeNoteEvents(ctx context.Context) (<-chan domain.NoteEvent, error)
+	StreamNoteEvents(ctx context.Context, lastEventId string) (<-chan domain.NoteEvent, error)
+	Sync(ctx context.Context, token string) (*domain.SyncChanges, error)
 	GetPresignedUrls(ctx context.Context, objectNames []string) (*GetPresignedUrlsResponse, error)
 }
 
 type noteService struct {
-	Config         config.Configuration
-	FileRepository domain.FileRepository
-	NoteRepository domain.NoteRepository
-	Oss            oss.ObjectStorageService
-	K8sClient      k8sc.K8sC
-	Db             *sql.DB
+	Config             config.Configuration
+	FileRepository     domain.FileRepository
+	NoteRepository     domain.NoteRepository
+	TagRepository      domain.TagRepository
+	NotebookRepository domain.NotebookRepository
+	RevisionRepository domain.NoteRevisionRepository
+	EventRepository    domain.NoteEventRepository
+	SyncRepository     domain.SyncRepository
+	Oss                oss.ObjectStorageService
+	FileJobRepository  domain.FileJobRepository
+	Db                 *sql.DB
 }
 
-func NewNoteService(noteRepository domain.NoteRepository, oss oss.ObjectStorageService, fileRepository domain.FileRepository, cfg config.Configuration, k8sClient k8sc.K8sC, db *sql.DB) NoteService {
+func NewNoteService(noteRepository domain.NoteRepository, oss oss.ObjectStorageService, fileRepository domain.FileRepository, tagRepository domain.TagRepository, notebookRepository domain.NotebookRepository, revisionRepository domain.NoteRevisionRepository, eventRepository domain.NoteEventRepository, syncRepository domain.SyncRepository, cfg config.Configuration, fileJobRepository domain.FileJobRepository, db *sql.DB) NoteService {
 	return &noteService{
-		NoteRepository: noteRepository,
-		Oss:            oss,
-		FileRepository: fileRepository,
-		Config:         cfg,
-		K8sClient:      k8sClient,
-		Db:             db,
+		NoteRepository:     noteRepository,
+		TagRepository:      tagRepository,
+		NotebookRepository: notebookRepository,
+		RevisionRepository: revisionRepository,
+		EventRepository:    eventRepository,
+		SyncRepository:     syncRepository,
+		Oss:                oss,
+		FileRepository:     fileRepository,
+		Config:             cfg,
+		FileJobRepository:  fileJobRepository,
+		Db:                 db,
 	}
 }
 
-func (s *noteService) CreateNote(ctx context.Context, title string, content string, objectNames []string) (*CreateNoteResponse, error) {
+func (s *noteService) CreateNote(ctx context.Context, title string, content string, objectNames []string, tagIds []uuid.UUID, notebookId *uuid.UUID) (*CreateNoteResponse, error) {
 	// Start the sql transaction
 	tx, err := s.Db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
 	// Defer the transaction rollback or commit
 	defer func() {
 		if p := recover(); p != nil {
@@ -82,13 +138,30 @@ func (s *noteService) CreateNote(ctx context.Context, title string, content stri
 			tx.Rollback()
 		} else {
 			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
 		}
 	}()
 
 	note := &domain.Note{
-		UserId:  domain.GetUserIdFromContext(ctx),
-		Title:   title,
-		Content: content,
+		UserId:     domain.GetUserIdFromContext(ctx),
+		NotebookId: notebookId,
+		Title:      title,
+		Content:    content,
+	}
+
+	// Only a notebook of the user can hold the note
+	if notebookId != nil {
+		if _, err = s.NotebookRepository.GetNotebook(ctx, *notebookId, note.UserId); err != nil {
+			switch err.(type) {
+			case *customerrors.RecordNotFound:
+				return nil, errors.New("notebook not found")
+			default:
+				return nil, err
+			}
+		}
 	}
 
 	// Check concurrently if the objects exists in the oss
@@ -118,6 +191,16 @@ func (s *noteService) CreateNote(ctx context.Context, title string, content stri
 	if err != nil {
 		return nil, err
 	}
+
+	// Attach the tags, only the tags of the user can be attached
+	if err = s.TagRepository.AttachTagsToNote(ctx, tx, note.Id, note.UserId, tagIds); err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("tag not found")
+		default:
+			return nil, err
+		}
+	}
 	// Create the files concurrently
 	var files []*domain.File
 	var mu2 sync.Mutex
@@ -145,41 +228,13 @@ func (s *noteService) CreateNote(ctx context.Context, title string, content stri
 		return nil, errors.New("error creating files")
 	}
 
-	// Create a k8s job to process the files
-	if s.Config.InK8s {
-		jobName := fmt.Sprintf("process-note-files-job-%s", note.Id)
-		namespace := "default"
-		imageName := s.Config.DockerImageName
-		args := []string{
-			"process-files",
-			"--files",
-		}
-		// Append the slice of object names as a comma-separated string
-		args = append(args, strings.Join(objectNames, ","))
-
-		// Define the environment variables for the job
-		envs := []corev1.EnvFromSource{
-			{
-				SecretRef: &corev1.SecretEnvSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "note-secrets",
-					},
-				},
-			},
-		}
-
-		err := s.K8sClient.CreateJob(ctx, jobName, namespace, imageName, args, envs)
-		if err != nil {
-			clogg.Error(ctx, "error creating k8s job", clogg.String("error", err.Error()))
-			return nil, err
-		}
-	} else {
-		// This is a mock for the k8s job on dev environment
-		for _, file := range objectNames {
-			if err := s.FileRepository.Process(ctx, tx, file); err != nil {
-				clogg.Error(ctx, "error processing file", clogg.String("error", err.Error()))
-			}
-		}
+	// Queue the processing of the files, a worker processes them once the note is committed
+	fileIds := make([]uuid.UUID, 0, len(files))
+	for _, file := range files {
+		fileIds = append(fileIds, file.Id)
+	}
+	if err = s.FileJobRepository.EnqueueFileJobs(ctx, tx, fileIds); err != nil {
+		return nil, err
 	}
 
 	// Generate the presigned urls to get the original files concurrently
@@ -197,6 +252,8 @@ func (s *noteService) CreateNote(ctx context.Context, title string, content stri
 			}
 			mu3.Lock()
 			file.Url = url
+			// The renditions are created once the file is processed
+			file.RenditionUrls = map[string]string{}
 			mu3.Unlock()
 		}(file)
 	}
@@ -211,36 +268,161 @@ func (s *noteService) CreateNote(ctx context.Context, title string, content stri
 	// Include the files in the note
 	note.Files = files
 
+	// Include the attached tags in the note
+	if note.Tags, err = s.listNoteTags(ctx, tx, note.Id); err != nil {
+		return nil, err
+	}
+
+	events = append(events, domain.NewNoteEvent(domain.NoteCreated, note))
 	return &CreateNoteResponse{Note: note}, nil
 }
 
-func (s *noteService) ListNotesByUser(ctx context.Context, cursor time.Time) (*[]domain.Note, error) {
+func (s *noteService) ListNotesByUser(ctx context.Context, sort domain.NoteSort, cursor *domain.NoteListCursor, limit int, filter domain.NoteFilter) (*ListNotesResponse, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// The cursor is a position in the order of its listing, it can't continue a listing with another sort
+	if cursor != nil && cursor.Sort != sort {
+		return nil, errors.New("cursor does not match the sort")
+	}
+
+	// Get one note past the page to know if there is a next page
+	notes, err := s.NoteRepository.ListNotesByUser(ctx, userId, sort, cursor, int32(limit+1), filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.notesPage(ctx, *notes, limit, sort.Cursor)
+}
+
+// notesPage trims the note past the page, includes the files and the tags of the notes
+// and returns the cursor of the last note, cursorOf is the position of a note in the listing
+func (s *noteService) notesPage(ctx context.Context, notes []domain.Note, limit int, cursorOf func(domain.Note) domain.NoteListCursor) (*ListNotesResponse, error) {
+	res := &ListNotesResponse{Notes: notes}
+	if len(notes) > limit {
+		res.Notes = notes[:limit]
+		res.HasNextPage = true
+		last := res.Notes[limit-1]
+		res.NextCursor = cursorOf(last).Encode()
+	}
+
+	// Include the files and the tags of the notes
+	notesPtrs := make([]*domain.Note, len(res.Notes))
+	for i := range res.Notes {
+		notesPtrs[i] = &res.Notes[i]
+	}
+	if err := s.includeFiles(ctx, notesPtrs); err != nil {
+		return nil, err
+	}
+	if err := s.includeTags(ctx, notesPtrs); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *noteService) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
 	// Get the user ID from the context
 	userId := domain.GetUserIdFromContext(ctx)
 
-	// Get the notes
-	notes, err := s.NoteRepository.ListNotesByUser(ctx, userId, cursor)
+	// Get the note, a note of another user or in the trash is reported as not found
+	note, err := s.getUserNote(ctx, id, userId)
 	if err != nil {
 		return nil, err
 	}
 
+	// Include the files of the note with their presigned urls and its tags
+	if err = s.includeFiles(ctx, []*domain.Note{note}); err != nil {
+		return nil, err
+	}
+	if err = s.includeTags(ctx, []*domain.Note{note}); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+func (s *noteService) SearchNotes(ctx context.Context, query string, includeTrash bool, cursor *domain.NoteSearchCursor, limit int) (*SearchNotesResponse, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Search the notes of the user, the result past the page tells if there is a next page
+	results, err := s.NoteRepository.SearchNotes(ctx, userId, query, includeTrash, cursor, int32(limit+1))
+	if err != nil {
+		return nil, err
+	}
+	var nextCursor string
+	if len(*results) > limit {
+		*results = (*results)[:limit]
+		last := (*results)[limit-1]
+		nextCursor = domain.NoteSearchCursor{Rank: last.Rank, Id: last.Note.Id}.Encode()
+	}
+
+	// Include the files of the notes with their presigned urls
+	notes := make([]*domain.Note, len(*results))
+	for i := range *results {
+		notes[i] = &(*results)[i].Note
+	}
+	if err = s.includeFiles(ctx, notes); err != nil {
+		return nil, err
+	}
+	if err = s.includeTags(ctx, notes); err != nil {
+		return nil, err
+	}
+
+	return &SearchNotesResponse{
+		Results:    *results,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// includeFiles fetches the files of the notes in a single query, generates their presigned urls
+// and sets them in the notes
+func (s *noteService) includeFiles(ctx context.Context, notes []*domain.Note) error {
 	// Get all the ids from notes
-	ids := make([]uuid.UUID, len(*notes))
-	for i, note := range *notes {
+	ids := make([]uuid.UUID, len(notes))
+	for i, note := range notes {
 		ids[i] = note.Id
 	}
 
 	// Get the files for each note
 	files, err := s.FileRepository.ListFilesByNotesIds(ctx, ids)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Generate the presigned urls to get the files
+	if err := s.presignFiles(ctx, *files); err != nil {
+		return err
+	}
+
+	// Group the files by note id
+	fileMap := make(map[uuid.UUID][]*domain.File)
+	for i := range *files {
+		file := &(*files)[i]
+		fileMap[file.NoteId] = append(fileMap[file.NoteId], file)
+	}
+
+	// Include the files in the notes
+	for _, note := range notes {
+		note.Files = fileMap[note.Id]
+	}
+
+	return nil
+}
+
+// presignFiles generates the presigned urls to get the files and their renditions concurrently, the processed copy is preferred
+func (s *noteService) presignFiles(ctx context.Context, files []domain.File) error {
+	// Every file and rendition sends at most one error
+	count := len(files)
+	for _, file := range files {
+		count += len(file.Renditions)
+	}
+
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	errChan := make(chan error, len(*files))
-	for i := range *files {
+	errChan := make(chan error, count)
+	for i := range files {
+		files[i].RenditionUrls = make(map[string]string, len(files[i].Renditions))
 		wg.Add(1)
 		go func(file *domain.File) {
 			defer wg.Done()
@@ -255,114 +437,250 @@ func (s *noteService) ListNotesByUser(ctx context.Context, cursor time.Time) (*[
 				errChan <- err
 				return
 			}
-			mu.Lock()
 			file.Url = url
-			mu.Unlock()
-		}(&(*files)[i])
+		}(&files[i])
+		for _, rendition := range files[i].Renditions {
+			wg.Add(1)
+			go func(file *domain.File, rendition domain.FileRendition) {
+				defer wg.Done()
+				url, err := s.Oss.PresignedGetObject(ctx, s.Config.ObjectStorageServiceBucket, rendition.ObjectName, time.Second*24*60*60)
+				if err != nil {
+					errChan <- err
+					return
+				}
+				mu.Lock()
+				file.RenditionUrls[strconv.Itoa(int(rendition.Size))] = url
+				mu.Unlock()
+			}(&files[i], rendition)
+		}
 	}
 
 	wg.Wait()
 	close(errChan)
 
 	if len(errChan) > 0 {
-		return nil, errors.New("error getting the presigned urls")
+		return errors.New("error getting the presigned urls")
 	}
 
-	// Include the files in the notes
-	// Create a map to group files by NoteId
-	fileMap := make(map[uuid.UUID][]*domain.File)
-	for _, file := range *files {
-		fileMap[file.NoteId] = append(fileMap[file.NoteId], &file)
+	return nil
+}
+
+// listNoteTags returns the tags of a note as seen by the transaction
+func (s *noteService) listNoteTags(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) ([]*domain.Tag, error) {
+	tags, err := s.TagRepository.ListTagsByNoteId(ctx, tx, noteId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*domain.Tag, len(*tags))
+	for i := range *tags {
+		res[i] = &(*tags)[i]
+	}
+	return res, nil
+}
+
+// includeTags fetches the tags of the notes in a single query and sets them in the notes
+func (s *noteService) includeTags(ctx context.Context, notes []*domain.Note) error {
+	// Get all the ids from notes
+	ids := make([]uuid.UUID, len(notes))
+	for i, note := range notes {
+		ids[i] = note.Id
 	}
 
-	// Include the files in the notes
-	for i, note := range *notes {
-		if noteFiles, ok := fileMap[note.Id]; ok {
-			(*notes)[i].Files = noteFiles
-		}
+	// Get the tags for each note
+	noteTags, err := s.TagRepository.ListTagsByNotesIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	// Group the tags by note id
+	tagMap := make(map[uuid.UUID][]*domain.Tag)
+	for i := range *noteTags {
+		noteTag := &(*noteTags)[i]
+		tagMap[noteTag.NoteId] = append(tagMap[noteTag.NoteId], &noteTag.Tag)
+	}
+
+	// Include the tags in the notes
+	for _, note := range notes {
+		note.Tags = tagMap[note.Id]
 	}
 
-	return notes, nil
+	return nil
 }
 
-func (s *noteService) ListTrashNotesByUser(ctx context.Context, cursor time.Time) (*[]domain.Note, error) {
+func (s *noteService) ListTrashNotesByUser(ctx context.Context, cursor *domain.NoteListCursor, limit int, filter domain.NoteFilter) (*ListNotesResponse, error) {
 	// Get the user ID from the context
 	userId := domain.GetUserIdFromContext(ctx)
 
-	// Get the notes
-	notes, err := s.NoteRepository.ListTrashNotesByUser(ctx, userId, cursor)
+	// The trash is listed by the delete time alone, the cursors of the sorted listing don't fit in it
+	if cursor != nil && cursor.Sort != (domain.NoteSort{}) {
+		return nil, errors.New("cursor does not match the sort")
+	}
+
+	// Get one note past the page to know if there is a next page
+	notes, err := s.NoteRepository.ListTrashNotesByUser(ctx, userId, cursor, int32(limit+1), filter)
 	if err != nil {
 		return nil, err
 	}
+	return s.notesPage(ctx, *notes, limit, func(note domain.Note) domain.NoteListCursor {
+		return domain.NoteListCursor{Time: note.DeleteTime, Id: note.Id}
+	})
+}
 
-	// Get all the ids from notes
-	ids := make([]uuid.UUID, len(*notes))
-	for i, note := range *notes {
-		ids[i] = note.Id
+func (s *noteService) ListArchivedNotesByUser(ctx context.Context, cursor *domain.NoteListCursor, limit int, filter domain.NoteFilter) (*ListNotesResponse, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// The archive is listed by the archive time alone, the cursors of the sorted listing don't fit in it
+	if cursor != nil && cursor.Sort != (domain.NoteSort{}) {
+		return nil, errors.New("cursor does not match the sort")
 	}
 
-	// Get the files for each note
-	files, err := s.FileRepository.ListFilesByNotesIds(ctx, ids)
+	// Get one note past the page to know if there is a next page
+	notes, err := s.NoteRepository.ListArchivedNotesByUser(ctx, userId, cursor, int32(limit+1), filter)
 	if err != nil {
 		return nil, err
 	}
+	return s.notesPage(ctx, *notes, limit, func(note domain.Note) domain.NoteListCursor {
+		return domain.NoteListCursor{Time: note.ArchiveTime, Id: note.Id}
+	})
+}
 
-	// Generate the presigned urls to get the files
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(*files))
-	for i := range *files {
-		wg.Add(1)
-		go func(file *domain.File) {
-			defer wg.Done()
-			var objectName string
-			if file.ProcessedFile != "" {
-				objectName = file.ProcessedFile
-			} else {
-				objectName = file.OriginalFile
+func (s *noteService) MoveNote(ctx context.Context, id uuid.UUID, notebookId *uuid.UUID) (*domain.Note, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
 			}
-			url, err := s.Oss.PresignedGetObject(ctx, s.Config.ObjectStorageServiceBucket, objectName, time.Second*24*60*60)
-			if err != nil {
-				errChan <- err
-				return
+		}
+	}()
+
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Only a notebook of the user can hold the note, a nil notebook moves the note out of any notebook
+	if notebookId != nil {
+		if _, err = s.NotebookRepository.GetNotebook(ctx, *notebookId, userId); err != nil {
+			switch err.(type) {
+			case *customerrors.RecordNotFound:
+				return nil, errors.New("notebook not found")
+			default:
+				return nil, err
 			}
-			mu.Lock()
-			file.Url = url
-			mu.Unlock()
-		}(&(*files)[i])
+		}
 	}
 
-	wg.Wait()
-	close(errChan)
-
-	if len(errChan) > 0 {
-		return nil, errors.New("error getting the presigned urls")
+	// Move the note, a note of another user is reported as not found
+	note, err := s.NoteRepository.MoveNote(ctx, tx, id, userId, notebookId)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("note not found")
+		default:
+			return nil, err
+		}
 	}
 
-	// Include the files in the notes
-	// Create a map to group files by NoteId
-	fileMap := make(map[uuid.UUID][]*domain.File)
-	for _, file := range *files {
-		fileMap[file.NoteId] = append(fileMap[file.NoteId], &file)
+	events = append(events, domain.NewNoteEvent(domain.NoteUpdated, note))
+	return note, nil
+}
+
+func (s *noteService) RestoreNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	// Include the files in the notes
-	for i, note := range *notes {
-		if noteFiles, ok := fileMap[note.Id]; ok {
-			(*notes)[i].Files = noteFiles
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
+		}
+	}()
+
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Restore the note, a note of another user is reported as not found
+	note, err := s.NoteRepository.RestoreNote(ctx, tx, id, userId)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("note not found")
+		default:
+			return nil, err
 		}
 	}
 
-	return notes, nil
+	events = append(events, domain.NewNoteEvent(domain.NoteRestored, note))
+	return note, nil
 }
 
-func (s *noteService) RestoreNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+func (s *noteService) PinNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+	return s.setNoteState(ctx, func(tx *sql.Tx, userId uuid.UUID) (*domain.Note, error) {
+		return s.NoteRepository.SetNotePinned(ctx, tx, id, userId, true)
+	})
+}
+
+func (s *noteService) UnpinNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+	return s.setNoteState(ctx, func(tx *sql.Tx, userId uuid.UUID) (*domain.Note, error) {
+		return s.NoteRepository.SetNotePinned(ctx, tx, id, userId, false)
+	})
+}
+
+func (s *noteService) ArchiveNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+	return s.setNoteState(ctx, func(tx *sql.Tx, userId uuid.UUID) (*domain.Note, error) {
+		return s.NoteRepository.SetNoteArchived(ctx, tx, id, userId, true)
+	})
+}
+
+func (s *noteService) UnarchiveNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+	return s.setNoteState(ctx, func(tx *sql.Tx, userId uuid.UUID) (*domain.Note, error) {
+		return s.NoteRepository.SetNoteArchived(ctx, tx, id, userId, false)
+	})
+}
+
+// setNoteState runs a change of the pinned or archived state of a note of the user in a transaction.
+// The notes in the trash and the notes of another user are reported as not found.
+func (s *noteService) setNoteState(ctx context.Context, set func(tx *sql.Tx, userId uuid.UUID) (*domain.Note, error)) (*domain.Note, error) {
 	// Start the sql transaction
 	tx, err := s.Db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
 	// Defer the transaction rollback or commit
 	defer func() {
 		if p := recover(); p != nil {
@@ -372,27 +690,40 @@ func (s *noteService) RestoreNote(ctx context.Context, id uuid.UUID) (*domain.No
 			tx.Rollback()
 		} else {
 			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
 		}
 	}()
 
-	note, err := s.NoteRepository.RestoreNote(ctx, tx, id)
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	note, err := set(tx, userId)
 	if err != nil {
 		switch err.(type) {
 		case *customerrors.RecordNotFound:
 			return nil, errors.New("note not found")
+		default:
+			return nil, err
 		}
 	}
 
+	events = append(events, domain.NewNoteEvent(domain.NoteUpdated, note))
 	return note, nil
 }
 
-func (s *noteService) UpdateNote(ctx context.Context, note *domain.Note) (*domain.Note, error) {
+func (s *noteService) UpdateNote(ctx context.Context, note *domain.Note, addTagIds []uuid.UUID, removeTagIds []uuid.UUID) (*domain.Note, error) {
 	// Start the sql transaction
 	tx, err := s.Db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
 	// Defer the transaction rollback or commit
 	defer func() {
 		if p := recover(); p != nil {
@@ -402,20 +733,269 @@ func (s *noteService) UpdateNote(ctx context.Context, note *domain.Note) (*domai
 			tx.Rollback()
 		} else {
 			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
 		}
 	}()
 
+	// Only the owner can update the note, a note of another user is reported as not found
+	note.UserId = domain.GetUserIdFromContext(ctx)
+
+	// Keep a revision with the title and the content before they are overwritten,
+	// a patch that sends them unchanged doesn't fill the revision history
+	if note.Title != "" || note.Content != "" {
+		var current *domain.Note
+		if current, err = s.getUserNote(ctx, note.Id, note.UserId); err != nil {
+			return nil, err
+		}
+		if (note.Title != "" && note.Title != current.Title) || (note.Content != "" && note.Content != current.Content) {
+			if err = s.RevisionRepository.CreateNoteRevision(ctx, tx, note.Id, note.UserId, s.Config.NoteRevisionsRetention); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The note is only updated if its version is the one the client last saw
+	id := note.Id
 	note, err = s.NoteRepository.UpdateNote(ctx, tx, note)
 	if err != nil {
 		switch err.(type) {
 		case *customerrors.RecordNotFound:
-			return nil, errors.New("note not found")
+			// A note of the user that isn't updated was modified by another request,
+			// the current copy is returned along with the error
+			current, getErr := s.getUserNote(ctx, id, domain.GetUserIdFromContext(ctx))
+			if getErr != nil {
+				return nil, getErr
+			}
+			if getErr = s.includeFiles(ctx, []*domain.Note{current}); getErr != nil {
+				return nil, getErr
+			}
+			if getErr = s.includeTags(ctx, []*domain.Note{current}); getErr != nil {
+				return nil, getErr
+			}
+			err = errors.New("note version conflict")
+			return current, err
+		default:
+			return nil, err
+		}
+	}
+
+	// Detach and attach the tags, only the tags of the user can be attached
+	if err = s.TagRepository.DetachTagsFromNote(ctx, tx, note.Id, note.UserId, removeTagIds); err != nil {
+		return nil, err
+	}
+	if err = s.TagRepository.AttachTagsToNote(ctx, tx, note.Id, note.UserId, addTagIds); err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("tag not found")
+		default:
+			return nil, err
 		}
 	}
 
+	// Include the tags of the note after the changes
+	if note.Tags, err = s.listNoteTags(ctx, tx, note.Id); err != nil {
+		return nil, err
+	}
+
+	events = append(events, domain.NewNoteEvent(domain.NoteUpdated, note))
 	return note, nil
 }
 
+func (s *noteService) ListNoteRevisions(ctx context.Context, noteId uuid.UUID) (*[]domain.NoteRevision, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Check the note exists, a note of another user is reported as not found
+	if _, err := s.getUserNote(ctx, noteId, userId); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.RevisionRepository.ListNoteRevisions(ctx, noteId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (s *noteService) GetNoteRevision(ctx context.Context, noteId uuid.UUID, id uuid.UUID) (*domain.NoteRevision, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Get the revision, a revision of another note or user is reported as not found
+	revision, err := s.RevisionRepository.GetNoteRevision(ctx, id, noteId, userId)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("revision not found")
+		default:
+			return nil, err
+		}
+	}
+
+	return revision, nil
+}
+
+func (s *noteService) DiffNoteRevisions(ctx context.Context, noteId uuid.UUID, fromId uuid.UUID, toId uuid.UUID) (*domain.NoteRevisionDiff, error) {
+	from, err := s.GetNoteRevision(ctx, noteId, fromId)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetNoteRevision(ctx, noteId, toId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.NoteRevisionDiff{
+		From:    from.Id,
+		To:      to.Id,
+		Title:   domain.DiffLines(from.Title, to.Title),
+		Content: domain.DiffLines(from.Content, to.Content),
+	}, nil
+}
+
+func (s *noteService) RestoreNoteRevision(ctx context.Context, noteId uuid.UUID, id uuid.UUID) (*domain.Note, error) {
+	revision, err := s.GetNoteRevision(ctx, noteId, id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.getUserNote(ctx, noteId, revision.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The revision is restored as a new update of the current version, so the current
+	// title and content are kept as a revision too and the restore can be undone
+	note := &domain.Note{
+		Id:      noteId,
+		Title:   revision.Title,
+		Content: revision.Content,
+		Version: current.Version,
+	}
+	return s.UpdateNote(ctx, note, nil, nil)
+}
+
+// getUserNote returns the note, a note that doesn't exist, belongs to another user or is in the trash is reported as not found
+func (s *noteService) getUserNote(ctx context.Context, noteId uuid.UUID, userId uuid.UUID) (*domain.Note, error) {
+	note, err := s.NoteRepository.GetNote(ctx, noteId)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("note not found")
+		default:
+			return nil, err
+		}
+	}
+	if note.UserId != userId || !note.DeleteTime.IsZero() {
+		return nil, errors.New("note not found")
+	}
+	return note, nil
+}
+
+// syncPageSize is the maximum number of changes returned by a sync
+const syncPageSize = 500
+
+func (s *noteService) Sync(ctx context.Context, token string) (*domain.SyncChanges, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	position, err := domain.DecodeSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the notes, files and tombstones from one snapshot
+	tx, err := s.Db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	changes, err := s.SyncRepository.ListChangesSince(ctx, tx, userId, position, syncPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Include the tags in the notes, the files are returned as changes of their own
+	notes := make([]*domain.Note, len(changes.Notes))
+	for i := range changes.Notes {
+		notes[i] = &changes.Notes[i]
+	}
+	if len(notes) > 0 {
+		if err := s.includeTags(ctx, notes); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.FileRepository.IncludeRenditions(ctx, changes.Files); err != nil {
+		return nil, err
+	}
+	if err := s.presignFiles(ctx, changes.Files); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (s *noteService) SubscribeNoteEvents(ctx context.Context) (<-chan domain.NoteEvent, error) {
+	// Get the user ID from the context, only the events of the user are received
+	userId := domain.GetUserIdFromContext(ctx)
+
+	events, err := s.EventRepository.SubscribeNoteEvents(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *noteService) StreamNoteEvents(ctx context.Context, lastEventId string) (<-chan domain.NoteEvent, error) {
+	// Get the user ID from the context, only the events of the user are received
+	userId := domain.GetUserIdFromContext(ctx)
+
+	if _, _, ok := domain.ParseNoteEventId(lastEventId); lastEventId != "" && !ok {
+		return nil, errors.New("invalid last event id")
+	}
+
+	events, err := s.EventRepository.ReadNoteEvents(ctx, userId, lastEventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// noteEventIds returns the ids of the notes changed by the events, their cached copies are removed once the changes are committed
+func noteEventIds(events []*domain.NoteEvent) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.NoteId)
+	}
+	return ids
+}
+
+// publishNoteEvents publishes the committed changes, an error is logged without failing the request
+func (s *noteService) publishNoteEvents(ctx context.Context, events []*domain.NoteEvent) {
+	for _, event := range events {
+		if err := s.EventRepository.PublishNoteEvent(ctx, event, s.Config.NoteEventsStreamLength); err != nil {
+			clogg.Error(ctx, "error publishing the note event", clogg.String("error", err.Error()))
+		}
+	}
+}
+
 func (s *noteService) DeleteNote(ctx context.Context, id uuid.UUID, isHard bool) error {
 	// Start the sql transaction
 	tx, err := s.Db.BeginTx(ctx, nil)
@@ -423,6 +1003,9 @@ func (s *noteService) DeleteNote(ctx context.Context, id uuid.UUID, isHard bool)
 		return err
 	}
 
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
 	// Defer the transaction rollback or commit
 	defer func() {
 		if p := recover(); p != nil {
@@ -432,19 +1015,27 @@ func (s *noteService) DeleteNote(ctx context.Context, id uuid.UUID, isHard bool)
 			tx.Rollback()
 		} else {
 			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
 		}
 	}()
 
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
 	var files *[]domain.File
 
-	// Get the files if isHard is true before they are deleted from the database
+	// Lock the files if isHard is true before they are deleted from the database, so a file attached or processed meanwhile isn't missed
 	if isHard {
-		if files, err = s.FileRepository.ListFilesByNoteId(ctx, id); err != nil {
+		if files, err = s.FileRepository.LockFilesByNotesIds(ctx, tx, []uuid.UUID{id}); err != nil {
 			return err
 		}
 	}
 
-	if err = s.NoteRepository.DeleteNote(ctx, tx, id, isHard); err != nil {
+	// Delete the note, a note of another user is reported as not found and its files are kept
+	if err = s.NoteRepository.DeleteNote(ctx, tx, id, userId, isHard); err != nil {
 		if _, ok := err.(*customerrors.RecordNotFound); ok {
 			return errors.New("note not found")
 		}
@@ -458,9 +1049,193 @@ func (s *noteService) DeleteNote(ctx context.Context, id uuid.UUID, isHard bool)
 		}
 	}
 
+	events = append(events, &domain.NoteEvent{Type: domain.NoteDeleted, NoteId: id, UserId: userId, Hard: isHard, Time: time.Now().UTC()})
 	return nil
 }
 
+func (s *noteService) SoftDeleteNotes(ctx context.Context, ids []uuid.UUID) (*BulkNotesResponse, error) {
+	return s.bulkNotes(ctx, bulkNoteOperation{
+		ids: requestedNoteIds(ids),
+		apply: func(tx *sql.Tx, userId uuid.UUID, ids []uuid.UUID) (*[]domain.Note, error) {
+			return s.NoteRepository.SoftDeleteNotes(ctx, tx, ids, userId)
+		},
+		event: func(note *domain.Note) *domain.NoteEvent {
+			return &domain.NoteEvent{Type: domain.NoteDeleted, NoteId: note.Id, UserId: note.UserId, Time: time.Now().UTC()}
+		},
+	})
+}
+
+func (s *noteService) RestoreNotes(ctx context.Context, ids []uuid.UUID) (*BulkNotesResponse, error) {
+	return s.bulkNotes(ctx, bulkNoteOperation{
+		ids: requestedNoteIds(ids),
+		apply: func(tx *sql.Tx, userId uuid.UUID, ids []uuid.UUID) (*[]domain.Note, error) {
+			return s.NoteRepository.RestoreNotes(ctx, tx, ids, userId)
+		},
+		event: func(note *domain.Note) *domain.NoteEvent {
+			return domain.NewNoteEvent(domain.NoteRestored, note)
+		},
+	})
+}
+
+func (s *noteService) HardDeleteNotes(ctx context.Context, ids []uuid.UUID) (*BulkNotesResponse, error) {
+	return s.bulkNotes(ctx, bulkNoteOperation{
+		ids: requestedNoteIds(ids),
+		apply: func(tx *sql.Tx, userId uuid.UUID, ids []uuid.UUID) (*[]domain.Note, error) {
+			return s.NoteRepository.HardDeleteNotes(ctx, tx, ids, userId)
+		},
+		event: func(note *domain.Note) *domain.NoteEvent {
+			return &domain.NoteEvent{Type: domain.NoteDeleted, NoteId: note.Id, UserId: note.UserId, Hard: true, Time: time.Now().UTC()}
+		},
+		hard: true,
+	})
+}
+
+func (s *noteService) EmptyTrash(ctx context.Context) (*BulkNotesResponse, error) {
+	// The trash is emptied in batches of a bulk operation, the client repeats the call while there are more notes
+	hasMore := false
+	res, err := s.bulkNotes(ctx, bulkNoteOperation{
+		// Lock the notes in the trash, so they can't be restored while the trash is emptied
+		ids: func(tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error) {
+			// Get one note past the batch to know if there are more notes
+			ids, err := s.NoteRepository.ListTrashNoteIds(ctx, tx, userId, domain.MaxBulkNotes+1)
+			if err != nil {
+				return nil, err
+			}
+			if len(ids) > domain.MaxBulkNotes {
+				ids = ids[:domain.MaxBulkNotes]
+				hasMore = true
+			}
+			return ids, nil
+		},
+		apply: func(tx *sql.Tx, userId uuid.UUID, ids []uuid.UUID) (*[]domain.Note, error) {
+			return s.NoteRepository.HardDeleteNotes(ctx, tx, ids, userId)
+		},
+		event: func(note *domain.Note) *domain.NoteEvent {
+			return &domain.NoteEvent{Type: domain.NoteDeleted, NoteId: note.Id, UserId: note.UserId, Hard: true, Time: time.Now().UTC()}
+		},
+		hard: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.HasMore = hasMore
+	return res, nil
+}
+
+// bulkNoteOperation describes a change applied to many notes of the user at once
+type bulkNoteOperation struct {
+	// ids returns the notes of the operation, in the order of the results
+	ids func(tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error)
+	// apply changes the notes and returns the changed ones, the rest are reported as not found
+	apply func(tx *sql.Tx, userId uuid.UUID, ids []uuid.UUID) (*[]domain.Note, error)
+	// event returns the event published for a changed note
+	event func(note *domain.Note) *domain.NoteEvent
+	// hard removes the files of the changed notes from the cloud
+	hard bool
+}
+
+// requestedNoteIds validates the ids of a bulk operation and drops the duplicates
+func requestedNoteIds(ids []uuid.UUID) func(tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error) {
+	return func(tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error) {
+		if len(ids) == 0 {
+			return nil, errors.New("ids required")
+		}
+		unique := make([]uuid.UUID, 0, len(ids))
+		seen := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				unique = append(unique, id)
+			}
+		}
+		if len(unique) > domain.MaxBulkNotes {
+			return nil, errors.New("too many ids")
+		}
+		return unique, nil
+	}
+}
+
+// bulkNotes runs a bulk operation in a single transaction and reports the result of every note.
+func (s *noteService) bulkNotes(ctx context.Context, op bulkNoteOperation) (res *BulkNotesResponse, err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
+		}
+	}()
+
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	ids, err := op.ids(tx, userId)
+	if err != nil {
+		return nil, err
+	}
+	res = &BulkNotesResponse{Results: make([]BulkNoteResult, 0, len(ids))}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	// Lock the files before they are deleted from the database
+	var noteFiles *[]domain.File
+	if op.hard {
+		if noteFiles, err = s.FileRepository.LockFilesByNotesIds(ctx, tx, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	// The notes of another user and the notes the operation doesn't apply to are not changed
+	notes, err := op.apply(tx, userId, ids)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[uuid.UUID]bool, len(*notes))
+	for i := range *notes {
+		changed[(*notes)[i].Id] = true
+		events = append(events, op.event(&(*notes)[i]))
+	}
+
+	// Delete the files of the deleted notes from the cloud
+	if op.hard {
+		files := make([]domain.File, 0, len(*noteFiles))
+		for _, file := range *noteFiles {
+			if changed[file.NoteId] {
+				files = append(files, file)
+			}
+		}
+		if err = s.FileRepository.HardDeleteFiles(ctx, tx, &files); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range ids {
+		if changed[id] {
+			res.Results = append(res.Results, BulkNoteResult{Id: id, Ok: true})
+		} else {
+			res.Results = append(res.Results, BulkNoteResult{Id: id, Error: "note not found"})
+		}
+	}
+	return res, nil
+}
+
 func (s *noteService) GetPresignedUrls(ctx context.Context, objectNames []string) (*GetPresignedUrlsResponse, error) {
 	// Make a slice of presigned urls
 	urls := make([]PresignedUrl, 0, len(objectNames))
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+type NotebookService interface {
+	CreateNotebook(ctx context.Context, name string, parentId *uuid.UUID) (*domain.Notebook, error)
+	ListNotebooks(ctx context.Context) (*[]domain.Notebook, error)
+	RenameNotebook(ctx context.Context, id uuid.UUID, name string) (*domain.Notebook, error)
+	MoveNotebook(ctx context.Context, id uuid.UUID, parentId *uuid.UUID) (*domain.Notebook, error)
+	DeleteNotebook(ctx context.Context, id uuid.UUID, trashNotes bool) error
+}
+
+type notebookService struct {
+	Config             config.Configuration
+	NotebookRepository domain.NotebookRepository
+	NoteRepository     domain.NoteRepository
+	EventRepository    domain.NoteEventRepository
+	Db                 *sql.DB
+}
+
+func NewNotebookService(notebookRepository domain.NotebookRepository, noteRepository domain.NoteRepository, eventRepository domain.NoteEventRepository, cfg config.Configuration, db *sql.DB) NotebookService {
+	return &notebookService{
+		Config:             cfg,
+		NotebookRepository: notebookRepository,
+		NoteRepository:     noteRepository,
+		EventRepository:    eventRepository,
+		Db:                 db,
+	}
+}
+
+func (s *notebookService) CreateNotebook(ctx context.Context, name string, parentId *uuid.UUID) (*domain.Notebook, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	notebook := &domain.Notebook{
+		UserId:   domain.GetUserIdFromContext(ctx),
+		ParentId: parentId,
+		Name:     name,
+	}
+
+	// Only a notebook of the user can be the parent
+	if parentId != nil {
+		if _, err = s.NotebookRepository.GetNotebook(ctx, *parentId, notebook.UserId); err != nil {
+			switch err.(type) {
+			case *customerrors.RecordNotFound:
+				return nil, errors.New("parent notebook not found")
+			default:
+				return nil, err
+			}
+		}
+	}
+
+	notebook, err = s.NotebookRepository.CreateNotebook(ctx, tx, notebook)
+	if err != nil {
+		return nil, err
+	}
+
+	return notebook, nil
+}
+
+func (s *notebookService) ListNotebooks(ctx context.Context) (*[]domain.Notebook, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Get all the notebooks of the user, the clients build the hierarchy from the parent ids
+	notebooks, err := s.NotebookRepository.ListNotebooksByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return notebooks, nil
+}
+
+func (s *notebookService) RenameNotebook(ctx context.Context, id uuid.UUID, name string) (*domain.Notebook, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Rename the notebook, a notebook of another user is reported as not found
+	notebook, err := s.NotebookRepository.RenameNotebook(ctx, tx, id, userId, name)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("notebook not found")
+		default:
+			return nil, err
+		}
+	}
+
+	return notebook, nil
+}
+
+func (s *notebookService) MoveNotebook(ctx context.Context, id uuid.UUID, parentId *uuid.UUID) (*domain.Notebook, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// A nil parent moves the notebook to the root
+	if parentId != nil {
+		// Lock the notebooks of the user, so a concurrent move can't make the tree a cycle after the check
+		if err = s.NotebookRepository.LockNotebooks(ctx, tx, userId); err != nil {
+			return nil, err
+		}
+
+		// The notebook can't be moved inside itself or one of its descendants
+		var tree []uuid.UUID
+		tree, err = s.NotebookRepository.ListNotebookTreeIds(ctx, tx, id, userId)
+		if err != nil {
+			return nil, err
+		}
+		if len(tree) == 0 {
+			err = errors.New("notebook not found")
+			return nil, err
+		}
+		if slices.Contains(tree, *parentId) {
+			err = errors.New("invalid parent notebook")
+			return nil, err
+		}
+
+		// Only a notebook of the user can be the parent
+		if _, err = s.NotebookRepository.GetNotebook(ctx, *parentId, userId); err != nil {
+			switch err.(type) {
+			case *customerrors.RecordNotFound:
+				return nil, errors.New("parent notebook not found")
+			default:
+				return nil, err
+			}
+		}
+	}
+
+	// Move the notebook, a notebook of another user is reported as not found
+	notebook, err := s.NotebookRepository.MoveNotebook(ctx, tx, id, userId, parentId)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("notebook not found")
+		default:
+			return nil, err
+		}
+	}
+
+	return notebook, nil
+}
+
+func (s *notebookService) DeleteNotebook(ctx context.Context, id uuid.UUID, trashNotes bool) error {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// The changes of the notes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
+		}
+	}()
+
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Lock the notebooks of the user, so a concurrent move can't add a notebook to the tree after it is read
+	if err = s.NotebookRepository.LockNotebooks(ctx, tx, userId); err != nil {
+		return err
+	}
+
+	// Get the notebook and all its descendants, they are deleted together
+	tree, err := s.NotebookRepository.ListNotebookTreeIds(ctx, tx, id, userId)
+	if err != nil {
+		return err
+	}
+	if len(tree) == 0 {
+		err = errors.New("notebook not found")
+		return err
+	}
+
+	// Take the notes out of the notebooks, they are moved to the trash if requested
+	notes, err := s.NoteRepository.RemoveNotesFromNotebooks(ctx, tx, userId, tree, trashNotes)
+	if err != nil {
+		return err
+	}
+	for i := range *notes {
+		note := &(*notes)[i]
+		if trashNotes {
+			events = append(events, &domain.NoteEvent{Type: domain.NoteDeleted, NoteId: note.Id, UserId: note.UserId, Time: time.Now().UTC()})
+		} else {
+			events = append(events, domain.NewNoteEvent(domain.NoteUpdated, note))
+		}
+	}
+
+	// Delete the notebook, the descendants are deleted by cascade
+	if err = s.NotebookRepository.DeleteNotebook(ctx, tx, id, userId); err != nil {
+		if _, ok := err.(*customerrors.RecordNotFound); ok {
+			return errors.New("notebook not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// publishNoteEvents notifies the owner of the notes taken out of the deleted notebooks, a failure is only logged because the deletion is committed
+func (s *notebookService) publishNoteEvents(ctx context.Context, events []*domain.NoteEvent) {
+	for _, event := range events {
+		if err := s.EventRepository.PublishNoteEvent(ctx, event, s.Config.NoteEventsStreamLength); err != nil {
+			clogg.Error(ctx, "error publishing the note event", clogg.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// maxObjectDeletionBackoff caps the wait between the attempts of a failing object deletion
+const maxObjectDeletionBackoff = 6 * time.Hour
+
+// DeleteObjectsOptions configures a run of the object deletion worker
+type DeleteObjectsOptions struct {
+	// BatchSize is the number of deletions attempted in each transaction
+	BatchSize int
+	// MaxAttempts is the number of failed attempts before a deletion is dead-lettered
+	MaxAttempts int
+	// RetryBackoff is the wait after the first failed attempt, it doubles with every attempt
+	RetryBackoff time.Duration
+}
+
+// DeleteObjectsSummary reports what a run of the object deletion worker did
+type DeleteObjectsSummary struct {
+	Removed      int
+	Failed       int
+	DeadLettered int
+	Batches      int
+}
+
+type ObjectDeletionService interface {
+	DeleteObjects(ctx context.Context, opts DeleteObjectsOptions) (*DeleteObjectsSummary, error)
+	RequeueDeadObjectDeletions(ctx context.Context) (int64, error)
+}
+
+type objectDeletionService struct {
+	ObjectDeletionRepository domain.ObjectDeletionRepository
+	Db                       *sql.DB
+}
+
+func NewObjectDeletionService(objectDeletionRepository domain.ObjectDeletionRepository, db *sql.DB) ObjectDeletionService {
+	return &objectDeletionService{
+		ObjectDeletionRepository: objectDeletionRepository,
+		Db:                       db,
+	}
+}
+
+// DeleteObjects removes the objects of the deletions due for an attempt from the object storage service.
+// Every batch runs in its own transaction, a failed deletion is retried later with a backoff until it runs out of attempts.
+func (s *objectDeletionService) DeleteObjects(ctx context.Context, opts DeleteObjectsOptions) (*DeleteObjectsSummary, error) {
+	summary := &DeleteObjectsSummary{}
+
+	// A zero batch size would never drain the outbox, zero attempts would dead-letter every deletion on its first failure
+	switch {
+	case opts.BatchSize < 1:
+		return summary, errors.New("batch size must be positive")
+	case opts.MaxAttempts < 1:
+		return summary, errors.New("max attempts must be positive")
+	case opts.RetryBackoff <= 0:
+		return summary, errors.New("retry backoff must be positive")
+	}
+
+	for {
+		listed, err := s.deleteBatch(ctx, summary, opts)
+		if err != nil {
+			return summary, err
+		}
+		if listed == 0 {
+			return summary, nil
+		}
+		summary.Batches++
+		// The failed deletions are not due anymore, so the next batch lists the following ones
+		if listed < opts.BatchSize {
+			return summary, nil
+		}
+	}
+}
+
+// deleteBatch attempts the next batch of due deletions in a transaction and adds its outcome to the summary
+func (s *objectDeletionService) deleteBatch(ctx context.Context, summary *DeleteObjectsSummary, opts DeleteObjectsOptions) (listed int, err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// The outcome is only added to the summary once it is committed
+	var removed, failed, dead int
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+			if err == nil {
+				summary.Removed += removed
+				summary.Failed += failed
+				summary.DeadLettered += dead
+			}
+		}
+	}()
+
+	// Lock the due deletions, the deletions locked by another worker are skipped
+	deletions, err := s.ObjectDeletionRepository.ListDueObjectDeletions(ctx, tx, int32(opts.BatchSize))
+	if err != nil {
+		return 0, err
+	}
+	if len(*deletions) == 0 {
+		return 0, nil
+	}
+
+	// Remove the objects from the cloud
+	errs := make([]error, len(*deletions))
+	var wg sync.WaitGroup
+	for i := range *deletions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.ObjectDeletionRepository.RemoveObject(ctx, &(*deletions)[i])
+		}()
+	}
+	wg.Wait()
+
+	removedIds := make([]uuid.UUID, 0, len(*deletions))
+	for i, deletion := range *deletions {
+		if errs[i] == nil {
+			removedIds = append(removedIds, deletion.Id)
+			continue
+		}
+
+		// Retry the deletion later, or dead-letter it once it runs out of attempts
+		attempts := int(deletion.Attempts) + 1
+		isDead := attempts >= opts.MaxAttempts
+		nextAttemptTime := time.Now().UTC().Add(retryBackoff(opts.RetryBackoff, attempts, maxObjectDeletionBackoff))
+		if err = s.ObjectDeletionRepository.FailObjectDeletion(ctx, tx, deletion.Id, errs[i].Error(), nextAttemptTime, isDead); err != nil {
+			return 0, err
+		}
+		if isDead {
+			dead++
+			clogg.Error(ctx, "object deletion dead-lettered", clogg.String("object", deletion.ObjectName), clogg.Int("attempts", attempts), clogg.String("error", errs[i].Error()))
+		} else {
+			failed++
+		}
+	}
+
+	// The removed objects are done with
+	if len(removedIds) > 0 {
+		if err = s.ObjectDeletionRepository.DeleteObjectDeletions(ctx, tx, removedIds); err != nil {
+			return 0, err
+		}
+	}
+	removed = len(removedIds)
+
+	return len(*deletions), nil
+}
+
+// RequeueDeadObjectDeletions gives the dead-lettered deletions a new round of attempts
+func (s *objectDeletionService) RequeueDeadObjectDeletions(ctx context.Context) (int64, error) {
+	return s.ObjectDeletionRepository.RequeueDeadObjectDeletions(ctx)
+}
+
+// retryBackoff is the wait after the given number of failed attempts, the base doubles with every attempt up to the max
+func retryBackoff(base time.Duration, attempts int, max time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= max {
+			return max
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ReprocessFilesOptions configures a run of the reprocessing of the files
+type ReprocessFilesOptions struct {
+	Filter domain.FileFilter
+	// Force replaces the processed copies of the files that already have one, otherwise those files are skipped
+	Force bool
+	// Concurrency is the number of files processed at once
+	Concurrency int
+	// BatchSize is the number of files listed at once
+	BatchSize int
+	// DryRun only reports the files that would be reprocessed
+	DryRun bool
+	// Progress is called after every file
+	Progress func(progress ReprocessFilesProgress)
+}
+
+// ReprocessFilesSummary reports what a run of the reprocessing of the files did
+type ReprocessFilesSummary struct {
+	// Total is the number of selected files when the run started
+	Total       int
+	Reprocessed int
+	Skipped     int
+	Failed      int
+}
+
+// ReprocessFilesProgress reports the outcome of a file along the summary so far
+type ReprocessFilesProgress struct {
+	File domain.File
+	// Skipped is set for a file that already has a processed copy and is not forced
+	Skipped bool
+	Err     error
+	Summary ReprocessFilesSummary
+}
+
+// ReprocessFiles processes the selected files again, batch by batch, in the order of their ids.
+// Every file is processed in its own transaction, a failed file is reported and the others go on.
+func (s *fileJobService) ReprocessFiles(ctx context.Context, opts ReprocessFilesOptions) (*ReprocessFilesSummary, error) {
+	// A zero concurrency would block on the first file
+	if opts.Concurrency < 1 {
+		return nil, errors.New("concurrency must be positive")
+	}
+
+	total, err := s.FileRepository.CountFilesForReprocessing(ctx, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	summary := &ReprocessFilesSummary{Total: int(total)}
+
+	var mu sync.Mutex
+	cursor := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		files, err := s.FileRepository.ListFilesForReprocessing(ctx, opts.Filter, cursor, int32(opts.BatchSize))
+		if err != nil {
+			return summary, err
+		}
+		if len(*files) == 0 {
+			return summary, nil
+		}
+		cursor = (*files)[len(*files)-1].Id
+
+		// Process the batch with at most the concurrency files at once
+		sem := make(chan struct{}, opts.Concurrency)
+		var wg sync.WaitGroup
+		for i := range *files {
+			file := (*files)[i]
+			skipped := file.ProcessedFile != "" && !opts.Force
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				var err error
+				if !skipped && !opts.DryRun {
+					err = s.reprocessFile(ctx, &file)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case skipped:
+					summary.Skipped++
+				case err != nil:
+					summary.Failed++
+				default:
+					summary.Reprocessed++
+				}
+				if opts.Progress != nil {
+					opts.Progress(ReprocessFilesProgress{File: file, Skipped: skipped, Err: err, Summary: *summary})
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(*files) < opts.BatchSize {
+			return summary, nil
+		}
+	}
+}
+
+// reprocessFile processes the file again in a transaction and notifies the owner of the note once it is committed
+func (s *fileJobService) reprocessFile(ctx context.Context, file *domain.File) error {
+	processed, err := s.replaceProcessedFile(ctx, file)
+	if err != nil {
+		s.discardUnsavedObjects(ctx, err, file.OriginalFile)
+		return err
+	}
+	s.publishFileProcessed(ctx, processed)
+	return nil
+}
+
+// replaceProcessedFile replaces the processed copy of the file and completes its pending jobs, so the worker doesn't process it again
+func (s *fileJobService) replaceProcessedFile(ctx context.Context, file *domain.File) (processed *domain.File, err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if processed, err = s.FileRepository.Reprocess(ctx, tx, file); err != nil {
+		return nil, err
+	}
+	if err = s.FileJobRepository.CompleteFileJobsByFileId(ctx, tx, file.Id); err != nil {
+		return nil, &domain.UnsavedObjectsError{ObjectNames: processed.ProcessedObjectNames(), Err: err}
+	}
+	return processed, nil
+}
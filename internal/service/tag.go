@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+type TagService interface {
+	CreateTag(ctx context.Context, name string) (*domain.Tag, error)
+	ListTags(ctx context.Context) (*[]domain.Tag, error)
+	UpdateTag(ctx context.Context, id uuid.UUID, name string) (*domain.Tag, error)
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+}
+
+type tagService struct {
+	TagRepository domain.TagRepository
+	Db            *sql.DB
+}
+
+func NewTagService(tagRepository domain.TagRepository, db *sql.DB) TagService {
+	return &tagService{
+		TagRepository: tagRepository,
+		Db:            db,
+	}
+}
+
+func (s *tagService) CreateTag(ctx context.Context, name string) (*domain.Tag, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	tag := &domain.Tag{
+		UserId: domain.GetUserIdFromContext(ctx),
+		Name:   name,
+	}
+
+	// The name of the tag is unique for the user
+	tag, err = s.TagRepository.CreateTag(ctx, tx, tag)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.DuplicateRecord:
+			return nil, errors.New("tag already exists")
+		default:
+			return nil, err
+		}
+	}
+
+	return tag, nil
+}
+
+func (s *tagService) ListTags(ctx context.Context) (*[]domain.Tag, error) {
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Get the tags of the user
+	tags, err := s.TagRepository.ListTagsByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *tagService) UpdateTag(ctx context.Context, id uuid.UUID, name string) (*domain.Tag, error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Only the owner can rename the tag, a tag of another user is reported as not found
+	tag := &domain.Tag{
+		Id:     id,
+		UserId: domain.GetUserIdFromContext(ctx),
+		Name:   name,
+	}
+
+	tag, err = s.TagRepository.UpdateTag(ctx, tx, tag)
+	if err != nil {
+		switch err.(type) {
+		case *customerrors.RecordNotFound:
+			return nil, errors.New("tag not found")
+		case *customerrors.DuplicateRecord:
+			return nil, errors.New("tag already exists")
+		default:
+			return nil, err
+		}
+	}
+
+	return tag, nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// Get the user ID from the context
+	userId := domain.GetUserIdFromContext(ctx)
+
+	// Delete the tag, the notes are kept and only lose the tag
+	if err = s.TagRepository.DeleteTag(ctx, tx, id, userId); err != nil {
+		if _, ok := err.(*customerrors.RecordNotFound); ok {
+			return errors.New("tag not found")
+		}
+		return err
+	}
+
+	return nil
+}
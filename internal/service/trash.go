@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/daniarmas/clogg"
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PurgeTrashOptions configures a purge of the trash
+type PurgeTrashOptions struct {
+	// Retention is how long a note stays in the trash before it is purged
+	Retention time.Duration
+	// BatchSize is the number of notes purged in each transaction
+	BatchSize int
+	// DryRun counts the notes and files to purge without deleting them
+	DryRun bool
+}
+
+// PurgeTrashSummary reports what a purge of the trash did, or would do on a dry run
+type PurgeTrashSummary struct {
+	Before        time.Time
+	Notes         int
+	Files         int
+	Batches       int
+	FailedBatches int
+	DryRun        bool
+}
+
+type TrashService interface {
+	PurgeTrash(ctx context.Context, opts PurgeTrashOptions) (*PurgeTrashSummary, error)
+}
+
+type trashService struct {
+	Config          config.Configuration
+	NoteRepository  domain.NoteRepository
+	FileRepository  domain.FileRepository
+	EventRepository domain.NoteEventRepository
+	Db              *sql.DB
+}
+
+func NewTrashService(noteRepository domain.NoteRepository, fileRepository domain.FileRepository, eventRepository domain.NoteEventRepository, cfg config.Configuration, db *sql.DB) TrashService {
+	return &trashService{
+		Config:          cfg,
+		NoteRepository:  noteRepository,
+		FileRepository:  fileRepository,
+		EventRepository: eventRepository,
+		Db:              db,
+	}
+}
+
+// purgeBatchResult is the outcome of a batch, last is the position of its last listed note
+type purgeBatchResult struct {
+	Listed int
+	Notes  int
+	Files  int
+	Last   *domain.NoteListCursor
+}
+
+// PurgeTrash hard deletes the notes that are in the trash for longer than the retention, with their files.
+// Every batch runs in its own transaction, a failed batch is skipped so it doesn't block the rest of the trash.
+func (s *trashService) PurgeTrash(ctx context.Context, opts PurgeTrashOptions) (*PurgeTrashSummary, error) {
+	// A retention that is not positive would purge the notes deleted right now, so the whole trash
+	if opts.Retention <= 0 {
+		return &PurgeTrashSummary{DryRun: opts.DryRun}, errors.New("trash retention must be positive")
+	}
+	// A batch size that is not positive would list no note, so nothing would be purged
+	if opts.BatchSize <= 0 {
+		return &PurgeTrashSummary{DryRun: opts.DryRun}, errors.New("trash purge batch size must be positive")
+	}
+
+	summary := &PurgeTrashSummary{
+		Before: time.Now().UTC().Add(-opts.Retention),
+		DryRun: opts.DryRun,
+	}
+
+	var cursor *domain.NoteListCursor
+	for {
+		res, err := s.purgeBatch(ctx, summary.Before, cursor, opts)
+		if res == nil || res.Last == nil {
+			// Either the trash has no more expired notes or the batch couldn't be listed, so there is no way to skip it
+			return summary, err
+		}
+		summary.Batches++
+		if err != nil {
+			summary.FailedBatches++
+			clogg.Error(ctx, "error purging a batch of the trash", clogg.String("error", err.Error()))
+		} else {
+			summary.Notes += res.Notes
+			summary.Files += res.Files
+		}
+		if res.Listed < opts.BatchSize {
+			return summary, nil
+		}
+		cursor = res.Last
+	}
+}
+
+// purgeBatch purges the next batch of expired notes after the cursor in a transaction
+func (s *trashService) purgeBatch(ctx context.Context, before time.Time, cursor *domain.NoteListCursor, opts PurgeTrashOptions) (res *purgeBatchResult, err error) {
+	// Start the sql transaction
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The changes are published to the subscribers once they are committed
+	var events []*domain.NoteEvent
+
+	// Defer the transaction rollback or commit
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+			if err == nil {
+				s.NoteRepository.DeleteCachedNotes(ctx, noteEventIds(events))
+				s.publishNoteEvents(ctx, events)
+			}
+		}
+	}()
+
+	// Lock the expired notes, the notes locked by another purge are skipped
+	notes, err := s.NoteRepository.ListExpiredTrashNotes(ctx, tx, before, cursor, int32(opts.BatchSize))
+	if err != nil {
+		return nil, err
+	}
+	res = &purgeBatchResult{Listed: len(*notes)}
+	if len(*notes) == 0 {
+		return res, nil
+	}
+	last := (*notes)[len(*notes)-1]
+	res.Last = &domain.NoteListCursor{Time: last.DeleteTime, Id: last.Id}
+
+	ids := make([]uuid.UUID, len(*notes))
+	for i, note := range *notes {
+		ids[i] = note.Id
+	}
+
+	// Lock the files before they are deleted from the database
+	files, err := s.FileRepository.LockFilesByNotesIds(ctx, tx, ids)
+	if err != nil {
+		return res, err
+	}
+
+	if opts.DryRun {
+		res.Notes = len(*notes)
+		res.Files = len(*files)
+		return res, nil
+	}
+
+	// Delete the notes, their files are deleted with them
+	deleted, err := s.NoteRepository.HardDeleteTrashNotes(ctx, tx, ids)
+	if err != nil {
+		return res, err
+	}
+
+	// Delete the files from the cloud
+	if err = s.FileRepository.HardDeleteFiles(ctx, tx, files); err != nil {
+		return res, err
+	}
+
+	res.Notes = len(*deleted)
+	res.Files = len(*files)
+	for _, note := range *deleted {
+		events = append(events, &domain.NoteEvent{Type: domain.NoteDeleted, NoteId: note.Id, UserId: note.UserId, Hard: true, Time: time.Now().UTC()})
+	}
+	return res, nil
+}
+
+// publishNoteEvents notifies the owners of the purged notes, a failure is only logged because the purge is committed
+func (s *trashService) publishNoteEvents(ctx context.Context, events []*domain.NoteEvent) {
+	for _, event := range events {
+		if err := s.EventRepository.PublishNoteEvent(ctx, event, s.Config.NoteEventsStreamLength); err != nil {
+			clogg.Error(ctx, "error publishing the note event", clogg.String("error", err.Error()))
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	domain "github.com/daniarmas/notes/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,9 +18,9 @@ type AccessTokenDatabaseDs struct {
 	mock.Mock
 }
 
-// CreateAccessToken provides a mock function with given fields: ctx, accessToken
-func (_m *AccessTokenDatabaseDs) CreateAccessToken(ctx context.Context, accessToken *domain.AccessToken) (*domain.AccessToken, error) {
-	ret := _m.Called(ctx, accessToken)
+// CreateAccessToken provides a mock function with given fields: ctx, tx, accessToken
+func (_m *AccessTokenDatabaseDs) CreateAccessToken(ctx context.Context, tx *sql.Tx, accessToken *domain.AccessToken) (*domain.AccessToken, error) {
+	ret := _m.Called(ctx, tx, accessToken)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAccessToken")
@@ -26,19 +28,19 @@ func (_m *AccessTokenDatabaseDs) CreateAccessToken(ctx context.Context, accessTo
 
 	var r0 *domain.AccessToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.AccessToken) (*domain.AccessToken, error)); ok {
-		return rf(ctx, accessToken)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.AccessToken) (*domain.AccessToken, error)); ok {
+		return rf(ctx, tx, accessToken)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.AccessToken) *domain.AccessToken); ok {
-		r0 = rf(ctx, accessToken)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.AccessToken) *domain.AccessToken); ok {
+		r0 = rf(ctx, tx, accessToken)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.AccessToken)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.AccessToken) error); ok {
-		r1 = rf(ctx, accessToken)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.AccessToken) error); ok {
+		r1 = rf(ctx, tx, accessToken)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,29 +48,59 @@ func (_m *AccessTokenDatabaseDs) CreateAccessToken(ctx context.Context, accessTo
 	return r0, r1
 }
 
-// DeleteAccessTokenByUserId provides a mock function with given fields: ctx, userId
-func (_m *AccessTokenDatabaseDs) DeleteAccessTokenByUserId(ctx context.Context, userId uuid.UUID) (*uuid.UUID, error) {
-	ret := _m.Called(ctx, userId)
+// DeleteAccessTokensByRefreshTokenFamilyId provides a mock function with given fields: ctx, tx, familyId
+func (_m *AccessTokenDatabaseDs) DeleteAccessTokensByRefreshTokenFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) (*[]uuid.UUID, error) {
+	ret := _m.Called(ctx, tx, familyId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteAccessTokenByUserId")
+		panic("no return value specified for DeleteAccessTokensByRefreshTokenFamilyId")
 	}
 
-	var r0 *uuid.UUID
+	var r0 *[]uuid.UUID
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*uuid.UUID, error)); ok {
-		return rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) (*[]uuid.UUID, error)); ok {
+		return rf(ctx, tx, familyId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *uuid.UUID); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) *[]uuid.UUID); ok {
+		r0 = rf(ctx, tx, familyId)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*uuid.UUID)
+			r0 = ret.Get(0).(*[]uuid.UUID)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userId)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, familyId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccessTokensByRefreshTokenId provides a mock function with given fields: ctx, tx, refreshTokenId
+func (_m *AccessTokenDatabaseDs) DeleteAccessTokensByRefreshTokenId(ctx context.Context, tx *sql.Tx, refreshTokenId uuid.UUID) (*[]uuid.UUID, error) {
+	ret := _m.Called(ctx, tx, refreshTokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccessTokensByRefreshTokenId")
+	}
+
+	var r0 *[]uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) (*[]uuid.UUID, error)); ok {
+		return rf(ctx, tx, refreshTokenId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) *[]uuid.UUID); ok {
+		r0 = rf(ctx, tx, refreshTokenId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, refreshTokenId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -8,6 +8,8 @@ import (
 	domain "github.com/daniarmas/notes/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,9 +18,9 @@ type AccessTokenRepository struct {
 	mock.Mock
 }
 
-// CreateAccessToken provides a mock function with given fields: ctx, userId, refreshTokenId
-func (_m *AccessTokenRepository) CreateAccessToken(ctx context.Context, userId uuid.UUID, refreshTokenId uuid.UUID) (*domain.AccessToken, error) {
-	ret := _m.Called(ctx, userId, refreshTokenId)
+// CreateAccessToken provides a mock function with given fields: ctx, tx, userId, refreshTokenId
+func (_m *AccessTokenRepository) CreateAccessToken(ctx context.Context, tx *sql.Tx, userId uuid.UUID, refreshTokenId uuid.UUID) (*domain.AccessToken, error) {
+	ret := _m.Called(ctx, tx, userId, refreshTokenId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAccessToken")
@@ -26,19 +28,19 @@ func (_m *AccessTokenRepository) CreateAccessToken(ctx context.Context, userId u
 
 	var r0 *domain.AccessToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*domain.AccessToken, error)); ok {
-		return rf(ctx, userId, refreshTokenId)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) (*domain.AccessToken, error)); ok {
+		return rf(ctx, tx, userId, refreshTokenId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *domain.AccessToken); ok {
-		r0 = rf(ctx, userId, refreshTokenId)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) *domain.AccessToken); ok {
+		r0 = rf(ctx, tx, userId, refreshTokenId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.AccessToken)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
-		r1 = rf(ctx, userId, refreshTokenId)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, userId, refreshTokenId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,17 +48,35 @@ func (_m *AccessTokenRepository) CreateAccessToken(ctx context.Context, userId u
 	return r0, r1
 }
 
-// DeleteAccessTokenByUserId provides a mock function with given fields: ctx, userId
-func (_m *AccessTokenRepository) DeleteAccessTokenByUserId(ctx context.Context, userId uuid.UUID) error {
-	ret := _m.Called(ctx, userId)
+// DeleteAccessTokensByRefreshTokenFamilyId provides a mock function with given fields: ctx, tx, familyId
+func (_m *AccessTokenRepository) DeleteAccessTokensByRefreshTokenFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, familyId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccessTokensByRefreshTokenFamilyId")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, familyId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAccessTokensByRefreshTokenId provides a mock function with given fields: ctx, tx, refreshTokenId
+func (_m *AccessTokenRepository) DeleteAccessTokensByRefreshTokenId(ctx context.Context, tx *sql.Tx, refreshTokenId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, refreshTokenId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteAccessTokenByUserId")
+		panic("no return value specified for DeleteAccessTokensByRefreshTokenId")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, refreshTokenId)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -7,6 +7,8 @@ import (
 
 	service "github.com/daniarmas/notes/internal/service"
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // AuthenticationService is an autogenerated mock type for the AuthenticationService type
@@ -14,9 +16,135 @@ type AuthenticationService struct {
 	mock.Mock
 }
 
-// SignIn provides a mock function with given fields: ctx, email, password
-func (_m *AuthenticationService) SignIn(ctx context.Context, email string, password string) (*service.SignInResponse, error) {
-	ret := _m.Called(ctx, email, password)
+// ListSessions provides a mock function with given fields: ctx
+func (_m *AuthenticationService) ListSessions(ctx context.Context) (*service.ListSessionsResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 *service.ListSessionsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*service.ListSessionsResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *service.ListSessionsResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ListSessionsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Me provides a mock function with given fields: ctx
+func (_m *AuthenticationService) Me(ctx context.Context) (*service.MeResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Me")
+	}
+
+	var r0 *service.MeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*service.MeResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *service.MeResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.MeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: ctx, refreshToken
+func (_m *AuthenticationService) RefreshToken(ctx context.Context, refreshToken string) (*service.SignInResponse, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *service.SignInResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*service.SignInResponse, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *service.SignInResponse); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.SignInResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeOtherSessions provides a mock function with given fields: ctx
+func (_m *AuthenticationService) RevokeOtherSessions(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeOtherSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: ctx, id
+func (_m *AuthenticationService) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SignIn provides a mock function with given fields: ctx, email, password, deviceName
+func (_m *AuthenticationService) SignIn(ctx context.Context, email string, password string, deviceName string) (*service.SignInResponse, error) {
+	ret := _m.Called(ctx, email, password, deviceName)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SignIn")
@@ -24,19 +152,67 @@ func (_m *AuthenticationService) SignIn(ctx context.Context, email string, passw
 
 	var r0 *service.SignInResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*service.SignInResponse, error)); ok {
-		return rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*service.SignInResponse, error)); ok {
+		return rf(ctx, email, password, deviceName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *service.SignInResponse); ok {
+		r0 = rf(ctx, email, password, deviceName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.SignInResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, email, password, deviceName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SignOut provides a mock function with given fields: ctx
+func (_m *AuthenticationService) SignOut(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignOut")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SignUp provides a mock function with given fields: ctx, name, email, password, deviceName
+func (_m *AuthenticationService) SignUp(ctx context.Context, name string, email string, password string, deviceName string) (*service.SignInResponse, error) {
+	ret := _m.Called(ctx, name, email, password, deviceName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignUp")
+	}
+
+	var r0 *service.SignInResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (*service.SignInResponse, error)); ok {
+		return rf(ctx, name, email, password, deviceName)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *service.SignInResponse); ok {
-		r0 = rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *service.SignInResponse); ok {
+		r0 = rf(ctx, name, email, password, deviceName)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*service.SignInResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, name, email, password, deviceName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,370 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	uuid "github.com/google/uuid"
+)
+
+// FileDatabaseDs is an autogenerated mock type for the FileDatabaseDs type
+type FileDatabaseDs struct {
+	mock.Mock
+}
+
+// CountFilesForReprocessing provides a mock function with given fields: ctx, filter
+func (_m *FileDatabaseDs) CountFilesForReprocessing(ctx context.Context, filter domain.FileFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountFilesForReprocessing")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter) (int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.FileFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateFile provides a mock function with given fields: ctx, tx, file
+func (_m *FileDatabaseDs) CreateFile(ctx context.Context, tx *sql.Tx, file *domain.File) (*domain.File, error) {
+	ret := _m.Called(ctx, tx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFile")
+	}
+
+	var r0 *domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.File) (*domain.File, error)); ok {
+		return rf(ctx, tx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.File) *domain.File); ok {
+		r0 = rf(ctx, tx, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.File) error); ok {
+		r1 = rf(ctx, tx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HardDeleteFilesByNoteId provides a mock function with given fields: ctx, tx, noteId
+func (_m *FileDatabaseDs) HardDeleteFilesByNoteId(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) (*[]domain.File, error) {
+	ret := _m.Called(ctx, tx, noteId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HardDeleteFilesByNoteId")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) (*[]domain.File, error)); ok {
+		return rf(ctx, tx, noteId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) *[]domain.File); ok {
+		r0 = rf(ctx, tx, noteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, noteId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFileRenditionsByFileIds provides a mock function with given fields: ctx, fileIds
+func (_m *FileDatabaseDs) ListFileRenditionsByFileIds(ctx context.Context, fileIds []uuid.UUID) (*[]domain.FileRendition, error) {
+	ret := _m.Called(ctx, fileIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFileRenditionsByFileIds")
+	}
+
+	var r0 *[]domain.FileRendition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (*[]domain.FileRendition, error)); ok {
+		return rf(ctx, fileIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) *[]domain.FileRendition); ok {
+		r0 = rf(ctx, fileIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.FileRendition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, fileIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFilesByNoteId provides a mock function with given fields: ctx, noteId
+func (_m *FileDatabaseDs) ListFilesByNoteId(ctx context.Context, noteId uuid.UUID) (*[]domain.File, error) {
+	ret := _m.Called(ctx, noteId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesByNoteId")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*[]domain.File, error)); ok {
+		return rf(ctx, noteId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *[]domain.File); ok {
+		r0 = rf(ctx, noteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, noteId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFilesByNotesIds provides a mock function with given fields: ctx, noteId
+func (_m *FileDatabaseDs) ListFilesByNotesIds(ctx context.Context, noteId []uuid.UUID) (*[]domain.File, error) {
+	ret := _m.Called(ctx, noteId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesByNotesIds")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (*[]domain.File, error)); ok {
+		return rf(ctx, noteId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) *[]domain.File); ok {
+		r0 = rf(ctx, noteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, noteId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFilesForReprocessing provides a mock function with given fields: ctx, filter, cursor, limit
+func (_m *FileDatabaseDs) ListFilesForReprocessing(ctx context.Context, filter domain.FileFilter, cursor uuid.UUID, limit int32) (*[]domain.File, error) {
+	ret := _m.Called(ctx, filter, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesForReprocessing")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter, uuid.UUID, int32) (*[]domain.File, error)); ok {
+		return rf(ctx, filter, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter, uuid.UUID, int32) *[]domain.File); ok {
+		r0 = rf(ctx, filter, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.FileFilter, uuid.UUID, int32) error); ok {
+		r1 = rf(ctx, filter, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockFilesByNotesIds provides a mock function with given fields: ctx, tx, noteIds
+func (_m *FileDatabaseDs) LockFilesByNotesIds(ctx context.Context, tx *sql.Tx, noteIds []uuid.UUID) (*[]domain.File, error) {
+	ret := _m.Called(ctx, tx, noteIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockFilesByNotesIds")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) (*[]domain.File, error)); ok {
+		return rf(ctx, tx, noteIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) *[]domain.File); ok {
+		r0 = rf(ctx, tx, noteIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, []uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, noteIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceFileRenditions provides a mock function with given fields: ctx, tx, fileId, renditions
+func (_m *FileDatabaseDs) ReplaceFileRenditions(ctx context.Context, tx *sql.Tx, fileId uuid.UUID, renditions []domain.FileRendition) (*[]domain.FileRendition, *[]domain.FileRendition, error) {
+	ret := _m.Called(ctx, tx, fileId, renditions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceFileRenditions")
+	}
+
+	var r0 *[]domain.FileRendition
+	var r1 *[]domain.FileRendition
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, []domain.FileRendition) (*[]domain.FileRendition, *[]domain.FileRendition, error)); ok {
+		return rf(ctx, tx, fileId, renditions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, []domain.FileRendition) *[]domain.FileRendition); ok {
+		r0 = rf(ctx, tx, fileId, renditions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.FileRendition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, []domain.FileRendition) *[]domain.FileRendition); ok {
+		r1 = rf(ctx, tx, fileId, renditions)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*[]domain.FileRendition)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *sql.Tx, uuid.UUID, []domain.FileRendition) error); ok {
+		r2 = rf(ctx, tx, fileId, renditions)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReplaceProcessedFile provides a mock function with given fields: ctx, tx, id, processedFileId, audio
+func (_m *FileDatabaseDs) ReplaceProcessedFile(ctx context.Context, tx *sql.Tx, id uuid.UUID, processedFileId string, audio *domain.AudioMetadata) (*domain.File, error) {
+	ret := _m.Called(ctx, tx, id, processedFileId, audio)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceProcessedFile")
+	}
+
+	var r0 *domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, string, *domain.AudioMetadata) (*domain.File, error)); ok {
+		return rf(ctx, tx, id, processedFileId, audio)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, string, *domain.AudioMetadata) *domain.File); ok {
+		r0 = rf(ctx, tx, id, processedFileId, audio)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, string, *domain.AudioMetadata) error); ok {
+		r1 = rf(ctx, tx, id, processedFileId, audio)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFileByOriginalId provides a mock function with given fields: ctx, tx, originalFileId, processFileId, audio
+func (_m *FileDatabaseDs) UpdateFileByOriginalId(ctx context.Context, tx *sql.Tx, originalFileId string, processFileId string, audio *domain.AudioMetadata) (*domain.File, error) {
+	ret := _m.Called(ctx, tx, originalFileId, processFileId, audio)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFileByOriginalId")
+	}
+
+	var r0 *domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string, *domain.AudioMetadata) (*domain.File, error)); ok {
+		return rf(ctx, tx, originalFileId, processFileId, audio)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string, *domain.AudioMetadata) *domain.File); ok {
+		r0 = rf(ctx, tx, originalFileId, processFileId, audio)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, string, *domain.AudioMetadata) error); ok {
+		r1 = rf(ctx, tx, originalFileId, processFileId, audio)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFileDatabaseDs creates a new instance of FileDatabaseDs. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFileDatabaseDs(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FileDatabaseDs {
+	mock := &FileDatabaseDs{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
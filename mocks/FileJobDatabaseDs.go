@@ -0,0 +1,137 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// FileJobDatabaseDs is an autogenerated mock type for the FileJobDatabaseDs type
+type FileJobDatabaseDs struct {
+	mock.Mock
+}
+
+// ClaimFileJobs provides a mock function with given fields: ctx, tx, worker, now, leaseExpireTime, limit
+func (_m *FileJobDatabaseDs) ClaimFileJobs(ctx context.Context, tx *sql.Tx, worker string, now time.Time, leaseExpireTime time.Time, limit int32) (*[]domain.FileJob, error) {
+	ret := _m.Called(ctx, tx, worker, now, leaseExpireTime, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimFileJobs")
+	}
+
+	var r0 *[]domain.FileJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Time, time.Time, int32) (*[]domain.FileJob, error)); ok {
+		return rf(ctx, tx, worker, now, leaseExpireTime, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Time, time.Time, int32) *[]domain.FileJob); ok {
+		r0 = rf(ctx, tx, worker, now, leaseExpireTime, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.FileJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, time.Time, time.Time, int32) error); ok {
+		r1 = rf(ctx, tx, worker, now, leaseExpireTime, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteFileJob provides a mock function with given fields: ctx, tx, job, worker
+func (_m *FileJobDatabaseDs) CompleteFileJob(ctx context.Context, tx *sql.Tx, job *domain.FileJob, worker string) error {
+	ret := _m.Called(ctx, tx, job, worker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteFileJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.FileJob, string) error); ok {
+		r0 = rf(ctx, tx, job, worker)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteFileJobsByFileId provides a mock function with given fields: ctx, tx, fileId
+func (_m *FileJobDatabaseDs) CompleteFileJobsByFileId(ctx context.Context, tx *sql.Tx, fileId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, fileId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteFileJobsByFileId")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, fileId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnqueueFileJobs provides a mock function with given fields: ctx, tx, fileIds
+func (_m *FileJobDatabaseDs) EnqueueFileJobs(ctx context.Context, tx *sql.Tx, fileIds []uuid.UUID) error {
+	ret := _m.Called(ctx, tx, fileIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueFileJobs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, fileIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailFileJob provides a mock function with given fields: ctx, tx, job, worker, lastError, runTime, dead
+func (_m *FileJobDatabaseDs) FailFileJob(ctx context.Context, tx *sql.Tx, job *domain.FileJob, worker string, lastError string, runTime time.Time, dead bool) error {
+	ret := _m.Called(ctx, tx, job, worker, lastError, runTime, dead)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailFileJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.FileJob, string, string, time.Time, bool) error); ok {
+		r0 = rf(ctx, tx, job, worker, lastError, runTime, dead)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFileJobDatabaseDs creates a new instance of FileJobDatabaseDs. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFileJobDatabaseDs(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FileJobDatabaseDs {
+	mock := &FileJobDatabaseDs{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,137 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// FileJobRepository is an autogenerated mock type for the FileJobRepository type
+type FileJobRepository struct {
+	mock.Mock
+}
+
+// ClaimFileJobs provides a mock function with given fields: ctx, tx, worker, lease, limit
+func (_m *FileJobRepository) ClaimFileJobs(ctx context.Context, tx *sql.Tx, worker string, lease time.Duration, limit int32) (*[]domain.FileJob, error) {
+	ret := _m.Called(ctx, tx, worker, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimFileJobs")
+	}
+
+	var r0 *[]domain.FileJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Duration, int32) (*[]domain.FileJob, error)); ok {
+		return rf(ctx, tx, worker, lease, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Duration, int32) *[]domain.FileJob); ok {
+		r0 = rf(ctx, tx, worker, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.FileJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, time.Duration, int32) error); ok {
+		r1 = rf(ctx, tx, worker, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteFileJob provides a mock function with given fields: ctx, tx, job, worker
+func (_m *FileJobRepository) CompleteFileJob(ctx context.Context, tx *sql.Tx, job *domain.FileJob, worker string) error {
+	ret := _m.Called(ctx, tx, job, worker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteFileJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.FileJob, string) error); ok {
+		r0 = rf(ctx, tx, job, worker)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteFileJobsByFileId provides a mock function with given fields: ctx, tx, fileId
+func (_m *FileJobRepository) CompleteFileJobsByFileId(ctx context.Context, tx *sql.Tx, fileId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, fileId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteFileJobsByFileId")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, fileId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnqueueFileJobs provides a mock function with given fields: ctx, tx, fileIds
+func (_m *FileJobRepository) EnqueueFileJobs(ctx context.Context, tx *sql.Tx, fileIds []uuid.UUID) error {
+	ret := _m.Called(ctx, tx, fileIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueFileJobs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, fileIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailFileJob provides a mock function with given fields: ctx, tx, job, worker, lastError, runTime, dead
+func (_m *FileJobRepository) FailFileJob(ctx context.Context, tx *sql.Tx, job *domain.FileJob, worker string, lastError string, runTime time.Time, dead bool) error {
+	ret := _m.Called(ctx, tx, job, worker, lastError, runTime, dead)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailFileJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.FileJob, string, string, time.Time, bool) error); ok {
+		r0 = rf(ctx, tx, job, worker, lastError, runTime, dead)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFileJobRepository creates a new instance of FileJobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFileJobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FileJobRepository {
+	mock := &FileJobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
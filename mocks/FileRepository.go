@@ -0,0 +1,361 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	uuid "github.com/google/uuid"
+)
+
+// FileRepository is an autogenerated mock type for the FileRepository type
+type FileRepository struct {
+	mock.Mock
+}
+
+// CountFilesForReprocessing provides a mock function with given fields: ctx, filter
+func (_m *FileRepository) CountFilesForReprocessing(ctx context.Context, filter domain.FileFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountFilesForReprocessing")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter) (int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.FileFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, tx, ossFileId, path, noteID
+func (_m *FileRepository) Create(ctx context.Context, tx *sql.Tx, ossFileId string, path string, noteID uuid.UUID) (*domain.File, error) {
+	ret := _m.Called(ctx, tx, ossFileId, path, noteID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string, uuid.UUID) (*domain.File, error)); ok {
+		return rf(ctx, tx, ossFileId, path, noteID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string, uuid.UUID) *domain.File); ok {
+		r0 = rf(ctx, tx, ossFileId, path, noteID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, ossFileId, path, noteID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardObjects provides a mock function with given fields: ctx, tx, objectNames
+func (_m *FileRepository) DiscardObjects(ctx context.Context, tx *sql.Tx, objectNames []string) error {
+	ret := _m.Called(ctx, tx, objectNames)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiscardObjects")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []string) error); ok {
+		r0 = rf(ctx, tx, objectNames)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HardDeleteFiles provides a mock function with given fields: ctx, tx, files
+func (_m *FileRepository) HardDeleteFiles(ctx context.Context, tx *sql.Tx, files *[]domain.File) error {
+	ret := _m.Called(ctx, tx, files)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HardDeleteFiles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *[]domain.File) error); ok {
+		r0 = rf(ctx, tx, files)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IncludeRenditions provides a mock function with given fields: ctx, files
+func (_m *FileRepository) IncludeRenditions(ctx context.Context, files []domain.File) error {
+	ret := _m.Called(ctx, files)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncludeRenditions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.File) error); ok {
+		r0 = rf(ctx, files)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListFilesByNoteId provides a mock function with given fields: ctx, noteId
+func (_m *FileRepository) ListFilesByNoteId(ctx context.Context, noteId uuid.UUID) (*[]domain.File, error) {
+	ret := _m.Called(ctx, noteId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesByNoteId")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*[]domain.File, error)); ok {
+		return rf(ctx, noteId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *[]domain.File); ok {
+		r0 = rf(ctx, noteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, noteId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFilesByNotesIds provides a mock function with given fields: ctx, noteId
+func (_m *FileRepository) ListFilesByNotesIds(ctx context.Context, noteId []uuid.UUID) (*[]domain.File, error) {
+	ret := _m.Called(ctx, noteId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesByNotesIds")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (*[]domain.File, error)); ok {
+		return rf(ctx, noteId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) *[]domain.File); ok {
+		r0 = rf(ctx, noteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, noteId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFilesForReprocessing provides a mock function with given fields: ctx, filter, cursor, limit
+func (_m *FileRepository) ListFilesForReprocessing(ctx context.Context, filter domain.FileFilter, cursor uuid.UUID, limit int32) (*[]domain.File, error) {
+	ret := _m.Called(ctx, filter, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesForReprocessing")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter, uuid.UUID, int32) (*[]domain.File, error)); ok {
+		return rf(ctx, filter, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FileFilter, uuid.UUID, int32) *[]domain.File); ok {
+		r0 = rf(ctx, filter, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.FileFilter, uuid.UUID, int32) error); ok {
+		r1 = rf(ctx, filter, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockFilesByNotesIds provides a mock function with given fields: ctx, tx, noteIds
+func (_m *FileRepository) LockFilesByNotesIds(ctx context.Context, tx *sql.Tx, noteIds []uuid.UUID) (*[]domain.File, error) {
+	ret := _m.Called(ctx, tx, noteIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockFilesByNotesIds")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) (*[]domain.File, error)); ok {
+		return rf(ctx, tx, noteIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) *[]domain.File); ok {
+		r0 = rf(ctx, tx, noteIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, []uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, noteIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields:
+func (_m *FileRepository) Move() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Process provides a mock function with given fields: ctx, tx, ossFileId
+func (_m *FileRepository) Process(ctx context.Context, tx *sql.Tx, ossFileId string) (*domain.File, error) {
+	ret := _m.Called(ctx, tx, ossFileId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Process")
+	}
+
+	var r0 *domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (*domain.File, error)); ok {
+		return rf(ctx, tx, ossFileId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) *domain.File); ok {
+		r0 = rf(ctx, tx, ossFileId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, ossFileId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reprocess provides a mock function with given fields: ctx, tx, file
+func (_m *FileRepository) Reprocess(ctx context.Context, tx *sql.Tx, file *domain.File) (*domain.File, error) {
+	ret := _m.Called(ctx, tx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reprocess")
+	}
+
+	var r0 *domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.File) (*domain.File, error)); ok {
+		return rf(ctx, tx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.File) *domain.File); ok {
+		r0 = rf(ctx, tx, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.File) error); ok {
+		r1 = rf(ctx, tx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields:
+func (_m *FileRepository) Update() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFileRepository creates a new instance of FileRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFileRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FileRepository {
+	mock := &FileRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
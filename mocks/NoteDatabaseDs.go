@@ -8,6 +8,10 @@ import (
 	domain "github.com/daniarmas/notes/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,9 +20,9 @@ type NoteDatabaseDs struct {
 	mock.Mock
 }
 
-// CreateNote provides a mock function with given fields: ctx, note
-func (_m *NoteDatabaseDs) CreateNote(ctx context.Context, note *domain.Note) (*domain.Note, error) {
-	ret := _m.Called(ctx, note)
+// CreateNote provides a mock function with given fields: ctx, tx, note
+func (_m *NoteDatabaseDs) CreateNote(ctx context.Context, tx *sql.Tx, note *domain.Note) (*domain.Note, error) {
+	ret := _m.Called(ctx, tx, note)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateNote")
@@ -26,19 +30,19 @@ func (_m *NoteDatabaseDs) CreateNote(ctx context.Context, note *domain.Note) (*d
 
 	var r0 *domain.Note
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Note) (*domain.Note, error)); ok {
-		return rf(ctx, note)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Note) (*domain.Note, error)); ok {
+		return rf(ctx, tx, note)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Note) *domain.Note); ok {
-		r0 = rf(ctx, note)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Note) *domain.Note); ok {
+		r0 = rf(ctx, tx, note)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Note)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.Note) error); ok {
-		r1 = rf(ctx, note)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Note) error); ok {
+		r1 = rf(ctx, tx, note)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,17 +50,47 @@ func (_m *NoteDatabaseDs) CreateNote(ctx context.Context, note *domain.Note) (*d
 	return r0, r1
 }
 
-// DeleteNote provides a mock function with given fields: ctx, id
-func (_m *NoteDatabaseDs) DeleteNote(ctx context.Context, id uuid.UUID) error {
+// GetNote provides a mock function with given fields: ctx, id
+func (_m *NoteDatabaseDs) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
 	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteNote")
+		panic("no return value specified for GetNote")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+	var r0 *domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Note, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Note); ok {
 		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HardDeleteNote provides a mock function with given fields: ctx, tx, id, userId
+func (_m *NoteDatabaseDs) HardDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HardDeleteNote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, id, userId)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -64,29 +98,239 @@ func (_m *NoteDatabaseDs) DeleteNote(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
-// GetNote provides a mock function with given fields: ctx, id
-func (_m *NoteDatabaseDs) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
-	ret := _m.Called(ctx, id)
+// HardDeleteNotes provides a mock function with given fields: ctx, tx, ids, userId
+func (_m *NoteDatabaseDs) HardDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, tx, ids, userId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetNote")
+		panic("no return value specified for HardDeleteNotes")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) (*[]domain.Note, error)); ok {
+		return rf(ctx, tx, ids, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) *[]domain.Note); ok {
+		r0 = rf(ctx, tx, ids, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, ids, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HardDeleteTrashNotes provides a mock function with given fields: ctx, tx, ids
+func (_m *NoteDatabaseDs) HardDeleteTrashNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, tx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HardDeleteTrashNotes")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) (*[]domain.Note, error)); ok {
+		return rf(ctx, tx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) *[]domain.Note); ok {
+		r0 = rf(ctx, tx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, []uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListArchivedNotesByUser provides a mock function with given fields: ctx, user_id, cursor, limit, filter
+func (_m *NoteDatabaseDs) ListArchivedNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *domain.NoteListCursor, limit int32, filter domain.NoteFilter) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, user_id, cursor, limit, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListArchivedNotesByUser")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.NoteListCursor, int32, domain.NoteFilter) (*[]domain.Note, error)); ok {
+		return rf(ctx, user_id, cursor, limit, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.NoteListCursor, int32, domain.NoteFilter) *[]domain.Note); ok {
+		r0 = rf(ctx, user_id, cursor, limit, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *domain.NoteListCursor, int32, domain.NoteFilter) error); ok {
+		r1 = rf(ctx, user_id, cursor, limit, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListExpiredTrashNotes provides a mock function with given fields: ctx, tx, before, cursor, limit
+func (_m *NoteDatabaseDs) ListExpiredTrashNotes(ctx context.Context, tx *sql.Tx, before time.Time, cursor *domain.NoteListCursor, limit int32) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, tx, before, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListExpiredTrashNotes")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, time.Time, *domain.NoteListCursor, int32) (*[]domain.Note, error)); ok {
+		return rf(ctx, tx, before, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, time.Time, *domain.NoteListCursor, int32) *[]domain.Note); ok {
+		r0 = rf(ctx, tx, before, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, time.Time, *domain.NoteListCursor, int32) error); ok {
+		r1 = rf(ctx, tx, before, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListNotesByUser provides a mock function with given fields: ctx, user_id, sort, cursor, limit, filter
+func (_m *NoteDatabaseDs) ListNotesByUser(ctx context.Context, user_id uuid.UUID, sort domain.NoteSort, cursor *domain.NoteListCursor, limit int32, filter domain.NoteFilter) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, user_id, sort, cursor, limit, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNotesByUser")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.NoteSort, *domain.NoteListCursor, int32, domain.NoteFilter) (*[]domain.Note, error)); ok {
+		return rf(ctx, user_id, sort, cursor, limit, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.NoteSort, *domain.NoteListCursor, int32, domain.NoteFilter) *[]domain.Note); ok {
+		r0 = rf(ctx, user_id, sort, cursor, limit, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.NoteSort, *domain.NoteListCursor, int32, domain.NoteFilter) error); ok {
+		r1 = rf(ctx, user_id, sort, cursor, limit, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrashNoteIds provides a mock function with given fields: ctx, tx, userId, limit
+func (_m *NoteDatabaseDs) ListTrashNoteIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID, limit int32) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, tx, userId, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrashNoteIds")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, int32) ([]uuid.UUID, error)); ok {
+		return rf(ctx, tx, userId, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, int32) []uuid.UUID); ok {
+		r0 = rf(ctx, tx, userId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, int32) error); ok {
+		r1 = rf(ctx, tx, userId, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrashNotesByUser provides a mock function with given fields: ctx, user_id, cursor, limit, filter
+func (_m *NoteDatabaseDs) ListTrashNotesByUser(ctx context.Context, user_id uuid.UUID, cursor *domain.NoteListCursor, limit int32, filter domain.NoteFilter) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, user_id, cursor, limit, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrashNotesByUser")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.NoteListCursor, int32, domain.NoteFilter) (*[]domain.Note, error)); ok {
+		return rf(ctx, user_id, cursor, limit, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.NoteListCursor, int32, domain.NoteFilter) *[]domain.Note); ok {
+		r0 = rf(ctx, user_id, cursor, limit, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *domain.NoteListCursor, int32, domain.NoteFilter) error); ok {
+		r1 = rf(ctx, user_id, cursor, limit, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveNote provides a mock function with given fields: ctx, tx, id, userId, notebookId
+func (_m *NoteDatabaseDs) MoveNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, notebookId *uuid.UUID) (*domain.Note, error) {
+	ret := _m.Called(ctx, tx, id, userId, notebookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveNote")
 	}
 
 	var r0 *domain.Note
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Note, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, *uuid.UUID) (*domain.Note, error)); ok {
+		return rf(ctx, tx, id, userId, notebookId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Note); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, *uuid.UUID) *domain.Note); ok {
+		r0 = rf(ctx, tx, id, userId, notebookId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Note)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, *uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, id, userId, notebookId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -94,29 +338,227 @@ func (_m *NoteDatabaseDs) GetNote(ctx context.Context, id uuid.UUID) (*domain.No
 	return r0, r1
 }
 
-// ListNotesByUserId provides a mock function with given fields: ctx, user_id
-func (_m *NoteDatabaseDs) ListNotesByUserId(ctx context.Context, user_id uuid.UUID) (*[]domain.Note, error) {
-	ret := _m.Called(ctx, user_id)
+// RemoveNotesFromNotebooks provides a mock function with given fields: ctx, tx, userId, notebookIds, trash
+func (_m *NoteDatabaseDs) RemoveNotesFromNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID, notebookIds []uuid.UUID, trash bool) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, tx, userId, notebookIds, trash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListNotesByUserId")
+		panic("no return value specified for RemoveNotesFromNotebooks")
 	}
 
 	var r0 *[]domain.Note
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*[]domain.Note, error)); ok {
-		return rf(ctx, user_id)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, []uuid.UUID, bool) (*[]domain.Note, error)); ok {
+		return rf(ctx, tx, userId, notebookIds, trash)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *[]domain.Note); ok {
-		r0 = rf(ctx, user_id)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, []uuid.UUID, bool) *[]domain.Note); ok {
+		r0 = rf(ctx, tx, userId, notebookIds, trash)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]domain.Note)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, user_id)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, []uuid.UUID, bool) error); ok {
+		r1 = rf(ctx, tx, userId, notebookIds, trash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreNote provides a mock function with given fields: ctx, tx, id, userId
+func (_m *NoteDatabaseDs) RestoreNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) (*domain.Note, error) {
+	ret := _m.Called(ctx, tx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreNote")
+	}
+
+	var r0 *domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) (*domain.Note, error)); ok {
+		return rf(ctx, tx, id, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) *domain.Note); ok {
+		r0 = rf(ctx, tx, id, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, id, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreNotes provides a mock function with given fields: ctx, tx, ids, userId
+func (_m *NoteDatabaseDs) RestoreNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, tx, ids, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreNotes")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) (*[]domain.Note, error)); ok {
+		return rf(ctx, tx, ids, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) *[]domain.Note); ok {
+		r0 = rf(ctx, tx, ids, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, ids, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchNotes provides a mock function with given fields: ctx, userId, query, includeTrash, cursor, limit
+func (_m *NoteDatabaseDs) SearchNotes(ctx context.Context, userId uuid.UUID, query string, includeTrash bool, cursor *domain.NoteSearchCursor, limit int32) (*[]domain.NoteSearchResult, error) {
+	ret := _m.Called(ctx, userId, query, includeTrash, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchNotes")
+	}
+
+	var r0 *[]domain.NoteSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, bool, *domain.NoteSearchCursor, int32) (*[]domain.NoteSearchResult, error)); ok {
+		return rf(ctx, userId, query, includeTrash, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, bool, *domain.NoteSearchCursor, int32) *[]domain.NoteSearchResult); ok {
+		r0 = rf(ctx, userId, query, includeTrash, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.NoteSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, bool, *domain.NoteSearchCursor, int32) error); ok {
+		r1 = rf(ctx, userId, query, includeTrash, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetNoteArchived provides a mock function with given fields: ctx, tx, id, userId, archived
+func (_m *NoteDatabaseDs) SetNoteArchived(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, archived bool) (*domain.Note, error) {
+	ret := _m.Called(ctx, tx, id, userId, archived)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNoteArchived")
+	}
+
+	var r0 *domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, bool) (*domain.Note, error)); ok {
+		return rf(ctx, tx, id, userId, archived)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, bool) *domain.Note); ok {
+		r0 = rf(ctx, tx, id, userId, archived)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, bool) error); ok {
+		r1 = rf(ctx, tx, id, userId, archived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetNotePinned provides a mock function with given fields: ctx, tx, id, userId, pinned
+func (_m *NoteDatabaseDs) SetNotePinned(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, pinned bool) (*domain.Note, error) {
+	ret := _m.Called(ctx, tx, id, userId, pinned)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNotePinned")
+	}
+
+	var r0 *domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, bool) (*domain.Note, error)); ok {
+		return rf(ctx, tx, id, userId, pinned)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, bool) *domain.Note); ok {
+		r0 = rf(ctx, tx, id, userId, pinned)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, bool) error); ok {
+		r1 = rf(ctx, tx, id, userId, pinned)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SoftDeleteNote provides a mock function with given fields: ctx, tx, id, userId
+func (_m *NoteDatabaseDs) SoftDeleteNote(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDeleteNote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, id, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SoftDeleteNotes provides a mock function with given fields: ctx, tx, ids, userId
+func (_m *NoteDatabaseDs) SoftDeleteNotes(ctx context.Context, tx *sql.Tx, ids []uuid.UUID, userId uuid.UUID) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, tx, ids, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDeleteNotes")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) (*[]domain.Note, error)); ok {
+		return rf(ctx, tx, ids, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) *[]domain.Note); ok {
+		r0 = rf(ctx, tx, ids, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, []uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, ids, userId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -124,9 +566,9 @@ func (_m *NoteDatabaseDs) ListNotesByUserId(ctx context.Context, user_id uuid.UU
 	return r0, r1
 }
 
-// UpdateNote provides a mock function with given fields: ctx, note
-func (_m *NoteDatabaseDs) UpdateNote(ctx context.Context, note *domain.Note) (*domain.Note, error) {
-	ret := _m.Called(ctx, note)
+// UpdateNote provides a mock function with given fields: ctx, tx, note
+func (_m *NoteDatabaseDs) UpdateNote(ctx context.Context, tx *sql.Tx, note *domain.Note) (*domain.Note, error) {
+	ret := _m.Called(ctx, tx, note)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateNote")
@@ -134,19 +576,19 @@ func (_m *NoteDatabaseDs) UpdateNote(ctx context.Context, note *domain.Note) (*d
 
 	var r0 *domain.Note
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Note) (*domain.Note, error)); ok {
-		return rf(ctx, note)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Note) (*domain.Note, error)); ok {
+		return rf(ctx, tx, note)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Note) *domain.Note); ok {
-		r0 = rf(ctx, note)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Note) *domain.Note); ok {
+		r0 = rf(ctx, tx, note)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Note)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.Note) error); ok {
-		r1 = rf(ctx, note)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Note) error); ok {
+		r1 = rf(ctx, tx, note)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// NoteEventDs is an autogenerated mock type for the NoteEventDs type
+type NoteEventDs struct {
+	mock.Mock
+}
+
+// PublishNoteEvent provides a mock function with given fields: ctx, event, streamLength
+func (_m *NoteEventDs) PublishNoteEvent(ctx context.Context, event *domain.NoteEvent, streamLength int64) error {
+	ret := _m.Called(ctx, event, streamLength)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishNoteEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.NoteEvent, int64) error); ok {
+		r0 = rf(ctx, event, streamLength)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadNoteEvents provides a mock function with given fields: ctx, userId, lastEventId
+func (_m *NoteEventDs) ReadNoteEvents(ctx context.Context, userId uuid.UUID, lastEventId string) (<-chan domain.NoteEvent, error) {
+	ret := _m.Called(ctx, userId, lastEventId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadNoteEvents")
+	}
+
+	var r0 <-chan domain.NoteEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (<-chan domain.NoteEvent, error)); ok {
+		return rf(ctx, userId, lastEventId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) <-chan domain.NoteEvent); ok {
+		r0 = rf(ctx, userId, lastEventId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan domain.NoteEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userId, lastEventId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeNoteEvents provides a mock function with given fields: ctx, userId
+func (_m *NoteEventDs) SubscribeNoteEvents(ctx context.Context, userId uuid.UUID) (<-chan domain.NoteEvent, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeNoteEvents")
+	}
+
+	var r0 <-chan domain.NoteEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (<-chan domain.NoteEvent, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) <-chan domain.NoteEvent); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan domain.NoteEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewNoteEventDs creates a new instance of NoteEventDs. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNoteEventDs(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NoteEventDs {
+	mock := &NoteEventDs{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,129 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	uuid "github.com/google/uuid"
+)
+
+// NoteRevisionDatabaseDs is an autogenerated mock type for the NoteRevisionDatabaseDs type
+type NoteRevisionDatabaseDs struct {
+	mock.Mock
+}
+
+// CreateNoteRevision provides a mock function with given fields: ctx, tx, noteId, userId
+func (_m *NoteRevisionDatabaseDs) CreateNoteRevision(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, noteId, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNoteRevision")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, noteId, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetNoteRevision provides a mock function with given fields: ctx, id, noteId, userId
+func (_m *NoteRevisionDatabaseDs) GetNoteRevision(ctx context.Context, id uuid.UUID, noteId uuid.UUID, userId uuid.UUID) (*domain.NoteRevision, error) {
+	ret := _m.Called(ctx, id, noteId, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNoteRevision")
+	}
+
+	var r0 *domain.NoteRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*domain.NoteRevision, error)); ok {
+		return rf(ctx, id, noteId, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) *domain.NoteRevision); ok {
+		r0 = rf(ctx, id, noteId, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.NoteRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, id, noteId, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListNoteRevisions provides a mock function with given fields: ctx, noteId, userId
+func (_m *NoteRevisionDatabaseDs) ListNoteRevisions(ctx context.Context, noteId uuid.UUID, userId uuid.UUID) (*[]domain.NoteRevision, error) {
+	ret := _m.Called(ctx, noteId, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNoteRevisions")
+	}
+
+	var r0 *[]domain.NoteRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*[]domain.NoteRevision, error)); ok {
+		return rf(ctx, noteId, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *[]domain.NoteRevision); ok {
+		r0 = rf(ctx, noteId, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.NoteRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, noteId, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneNoteRevisions provides a mock function with given fields: ctx, tx, noteId, keep
+func (_m *NoteRevisionDatabaseDs) PruneNoteRevisions(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, keep int) error {
+	ret := _m.Called(ctx, tx, noteId, keep)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneNoteRevisions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, int) error); ok {
+		r0 = rf(ctx, tx, noteId, keep)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNoteRevisionDatabaseDs creates a new instance of NoteRevisionDatabaseDs. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNoteRevisionDatabaseDs(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NoteRevisionDatabaseDs {
+	mock := &NoteRevisionDatabaseDs{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
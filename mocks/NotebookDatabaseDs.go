@@ -0,0 +1,249 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	uuid "github.com/google/uuid"
+)
+
+// NotebookDatabaseDs is an autogenerated mock type for the NotebookDatabaseDs type
+type NotebookDatabaseDs struct {
+	mock.Mock
+}
+
+// CreateNotebook provides a mock function with given fields: ctx, tx, notebook
+func (_m *NotebookDatabaseDs) CreateNotebook(ctx context.Context, tx *sql.Tx, notebook *domain.Notebook) (*domain.Notebook, error) {
+	ret := _m.Called(ctx, tx, notebook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotebook")
+	}
+
+	var r0 *domain.Notebook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Notebook) (*domain.Notebook, error)); ok {
+		return rf(ctx, tx, notebook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Notebook) *domain.Notebook); ok {
+		r0 = rf(ctx, tx, notebook)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Notebook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Notebook) error); ok {
+		r1 = rf(ctx, tx, notebook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteNotebook provides a mock function with given fields: ctx, tx, id, userId
+func (_m *NotebookDatabaseDs) DeleteNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteNotebook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, id, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetNotebook provides a mock function with given fields: ctx, id, userId
+func (_m *NotebookDatabaseDs) GetNotebook(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Notebook, error) {
+	ret := _m.Called(ctx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotebook")
+	}
+
+	var r0 *domain.Notebook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*domain.Notebook, error)); ok {
+		return rf(ctx, id, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *domain.Notebook); ok {
+		r0 = rf(ctx, id, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Notebook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, id, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListNotebookTreeIds provides a mock function with given fields: ctx, tx, id, userId
+func (_m *NotebookDatabaseDs) ListNotebookTreeIds(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, tx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNotebookTreeIds")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) ([]uuid.UUID, error)); ok {
+		return rf(ctx, tx, id, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) []uuid.UUID); ok {
+		r0 = rf(ctx, tx, id, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, id, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListNotebooksByUser provides a mock function with given fields: ctx, userId
+func (_m *NotebookDatabaseDs) ListNotebooksByUser(ctx context.Context, userId uuid.UUID) (*[]domain.Notebook, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNotebooksByUser")
+	}
+
+	var r0 *[]domain.Notebook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*[]domain.Notebook, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *[]domain.Notebook); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Notebook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockNotebooks provides a mock function with given fields: ctx, tx, userId
+func (_m *NotebookDatabaseDs) LockNotebooks(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockNotebooks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MoveNotebook provides a mock function with given fields: ctx, tx, id, userId, parentId
+func (_m *NotebookDatabaseDs) MoveNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, parentId *uuid.UUID) (*domain.Notebook, error) {
+	ret := _m.Called(ctx, tx, id, userId, parentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveNotebook")
+	}
+
+	var r0 *domain.Notebook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, *uuid.UUID) (*domain.Notebook, error)); ok {
+		return rf(ctx, tx, id, userId, parentId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, *uuid.UUID) *domain.Notebook); ok {
+		r0 = rf(ctx, tx, id, userId, parentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Notebook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, *uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, id, userId, parentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenameNotebook provides a mock function with given fields: ctx, tx, id, userId, name
+func (_m *NotebookDatabaseDs) RenameNotebook(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID, name string) (*domain.Notebook, error) {
+	ret := _m.Called(ctx, tx, id, userId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameNotebook")
+	}
+
+	var r0 *domain.Notebook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, string) (*domain.Notebook, error)); ok {
+		return rf(ctx, tx, id, userId, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, string) *domain.Notebook); ok {
+		r0 = rf(ctx, tx, id, userId, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Notebook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, tx, id, userId, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewNotebookDatabaseDs creates a new instance of NotebookDatabaseDs. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotebookDatabaseDs(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotebookDatabaseDs {
+	mock := &NotebookDatabaseDs{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,147 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// ObjectDeletionRepository is an autogenerated mock type for the ObjectDeletionRepository type
+type ObjectDeletionRepository struct {
+	mock.Mock
+}
+
+// DeleteObjectDeletions provides a mock function with given fields: ctx, tx, ids
+func (_m *ObjectDeletionRepository) DeleteObjectDeletions(ctx context.Context, tx *sql.Tx, ids []uuid.UUID) error {
+	ret := _m.Called(ctx, tx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteObjectDeletions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, []uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailObjectDeletion provides a mock function with given fields: ctx, tx, id, lastError, nextAttemptTime, dead
+func (_m *ObjectDeletionRepository) FailObjectDeletion(ctx context.Context, tx *sql.Tx, id uuid.UUID, lastError string, nextAttemptTime time.Time, dead bool) error {
+	ret := _m.Called(ctx, tx, id, lastError, nextAttemptTime, dead)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailObjectDeletion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, string, time.Time, bool) error); ok {
+		r0 = rf(ctx, tx, id, lastError, nextAttemptTime, dead)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListDueObjectDeletions provides a mock function with given fields: ctx, tx, limit
+func (_m *ObjectDeletionRepository) ListDueObjectDeletions(ctx context.Context, tx *sql.Tx, limit int32) (*[]domain.ObjectDeletion, error) {
+	ret := _m.Called(ctx, tx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDueObjectDeletions")
+	}
+
+	var r0 *[]domain.ObjectDeletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, int32) (*[]domain.ObjectDeletion, error)); ok {
+		return rf(ctx, tx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, int32) *[]domain.ObjectDeletion); ok {
+		r0 = rf(ctx, tx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.ObjectDeletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, int32) error); ok {
+		r1 = rf(ctx, tx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveObject provides a mock function with given fields: ctx, deletion
+func (_m *ObjectDeletionRepository) RemoveObject(ctx context.Context, deletion *domain.ObjectDeletion) error {
+	ret := _m.Called(ctx, deletion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveObject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ObjectDeletion) error); ok {
+		r0 = rf(ctx, deletion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequeueDeadObjectDeletions provides a mock function with given fields: ctx
+func (_m *ObjectDeletionRepository) RequeueDeadObjectDeletions(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueDeadObjectDeletions")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewObjectDeletionRepository creates a new instance of ObjectDeletionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewObjectDeletionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ObjectDeletionRepository {
+	mock := &ObjectDeletionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,186 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ObjectStorageService is an autogenerated mock type for the ObjectStorageService type
+type ObjectStorageService struct {
+	mock.Mock
+}
+
+// GetObject provides a mock function with given fields: ctx, bucketName, objectName
+func (_m *ObjectStorageService) GetObject(ctx context.Context, bucketName string, objectName string) (string, error) {
+	ret := _m.Called(ctx, bucketName, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetObject")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, bucketName, objectName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, bucketName, objectName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, bucketName, objectName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HealthCheck provides a mock function with given fields:
+func (_m *ObjectStorageService) HealthCheck() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for HealthCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ObjectExists provides a mock function with given fields: ctx, bucketName, objectName
+func (_m *ObjectStorageService) ObjectExists(ctx context.Context, bucketName string, objectName string) error {
+	ret := _m.Called(ctx, bucketName, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ObjectExists")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, bucketName, objectName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PresignedGetObject provides a mock function with given fields: ctx, bucketName, objectName, expiry
+func (_m *ObjectStorageService) PresignedGetObject(ctx context.Context, bucketName string, objectName string, expiry time.Duration) (string, error) {
+	ret := _m.Called(ctx, bucketName, objectName, expiry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresignedGetObject")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (string, error)); ok {
+		return rf(ctx, bucketName, objectName, expiry)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) string); ok {
+		r0 = rf(ctx, bucketName, objectName, expiry)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, bucketName, objectName, expiry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PresignedPutObject provides a mock function with given fields: ctx, bucketName, objectName
+func (_m *ObjectStorageService) PresignedPutObject(ctx context.Context, bucketName string, objectName string) (string, error) {
+	ret := _m.Called(ctx, bucketName, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresignedPutObject")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, bucketName, objectName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, bucketName, objectName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, bucketName, objectName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutObject provides a mock function with given fields: ctx, bucketName, objectName, filePath
+func (_m *ObjectStorageService) PutObject(ctx context.Context, bucketName string, objectName string, filePath string) error {
+	ret := _m.Called(ctx, bucketName, objectName, filePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutObject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, bucketName, objectName, filePath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveObject provides a mock function with given fields: ctx, bucketName, objectName
+func (_m *ObjectStorageService) RemoveObject(ctx context.Context, bucketName string, objectName string) error {
+	ret := _m.Called(ctx, bucketName, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveObject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, bucketName, objectName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewObjectStorageService creates a new instance of ObjectStorageService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewObjectStorageService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ObjectStorageService {
+	mock := &ObjectStorageService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,6 +8,8 @@ import (
 	domain "github.com/daniarmas/notes/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,9 +18,9 @@ type RefreshTokenRepository struct {
 	mock.Mock
 }
 
-// CreateRefreshToken provides a mock function with given fields: ctx, refreshToken
-func (_m *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, refreshToken *domain.RefreshToken) (*domain.RefreshToken, error) {
-	ret := _m.Called(ctx, refreshToken)
+// CreateRefreshToken provides a mock function with given fields: ctx, tx, refreshToken
+func (_m *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken *domain.RefreshToken) (*domain.RefreshToken, error) {
+	ret := _m.Called(ctx, tx, refreshToken)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateRefreshToken")
@@ -26,19 +28,19 @@ func (_m *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, refres
 
 	var r0 *domain.RefreshToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.RefreshToken) (*domain.RefreshToken, error)); ok {
-		return rf(ctx, refreshToken)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.RefreshToken) (*domain.RefreshToken, error)); ok {
+		return rf(ctx, tx, refreshToken)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.RefreshToken) *domain.RefreshToken); ok {
-		r0 = rf(ctx, refreshToken)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.RefreshToken) *domain.RefreshToken); ok {
+		r0 = rf(ctx, tx, refreshToken)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.RefreshToken)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.RefreshToken) error); ok {
-		r1 = rf(ctx, refreshToken)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.RefreshToken) error); ok {
+		r1 = rf(ctx, tx, refreshToken)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,17 +48,17 @@ func (_m *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, refres
 	return r0, r1
 }
 
-// DeleteRefreshTokenByUserId provides a mock function with given fields: ctx, userId
-func (_m *RefreshTokenRepository) DeleteRefreshTokenByUserId(ctx context.Context, userId uuid.UUID) error {
-	ret := _m.Called(ctx, userId)
+// DeleteRefreshTokensByFamilyId provides a mock function with given fields: ctx, tx, familyId
+func (_m *RefreshTokenRepository) DeleteRefreshTokensByFamilyId(ctx context.Context, tx *sql.Tx, familyId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, familyId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRefreshTokenByUserId")
+		panic("no return value specified for DeleteRefreshTokensByFamilyId")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, familyId)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -94,6 +96,54 @@ func (_m *RefreshTokenRepository) GetRefreshToken(ctx context.Context, id uuid.U
 	return r0, r1
 }
 
+// ListSessionsByUserId provides a mock function with given fields: ctx, userId
+func (_m *RefreshTokenRepository) ListSessionsByUserId(ctx context.Context, userId uuid.UUID) (*[]domain.Session, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessionsByUserId")
+	}
+
+	var r0 *[]domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*[]domain.Session, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *[]domain.Session); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, tx, id
+func (_m *RefreshTokenRepository) RotateRefreshToken(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	ret := _m.Called(ctx, tx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRefreshTokenRepository creates a new instance of RefreshTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRefreshTokenRepository(t interface {
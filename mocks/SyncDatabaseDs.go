@@ -0,0 +1,151 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	uuid "github.com/google/uuid"
+)
+
+// SyncDatabaseDs is an autogenerated mock type for the SyncDatabaseDs type
+type SyncDatabaseDs struct {
+	mock.Mock
+}
+
+// GetSyncHorizon provides a mock function with given fields: ctx, tx
+func (_m *SyncDatabaseDs) GetSyncHorizon(ctx context.Context, tx *sql.Tx) (int64, error) {
+	ret := _m.Called(ctx, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSyncHorizon")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx) (int64, error)); ok {
+		return rf(ctx, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx) int64); ok {
+		r0 = rf(ctx, tx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx) error); ok {
+		r1 = rf(ctx, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFilesChangedSince provides a mock function with given fields: ctx, tx, userId, position, horizon, limit
+func (_m *SyncDatabaseDs) ListFilesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.File, error) {
+	ret := _m.Called(ctx, tx, userId, position, horizon, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesChangedSince")
+	}
+
+	var r0 *[]domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) (*[]domain.File, error)); ok {
+		return rf(ctx, tx, userId, position, horizon, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) *[]domain.File); ok {
+		r0 = rf(ctx, tx, userId, position, horizon, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) error); ok {
+		r1 = rf(ctx, tx, userId, position, horizon, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListNotesChangedSince provides a mock function with given fields: ctx, tx, userId, position, horizon, limit
+func (_m *SyncDatabaseDs) ListNotesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.Note, error) {
+	ret := _m.Called(ctx, tx, userId, position, horizon, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNotesChangedSince")
+	}
+
+	var r0 *[]domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) (*[]domain.Note, error)); ok {
+		return rf(ctx, tx, userId, position, horizon, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) *[]domain.Note); ok {
+		r0 = rf(ctx, tx, userId, position, horizon, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) error); ok {
+		r1 = rf(ctx, tx, userId, position, horizon, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTombstonesSince provides a mock function with given fields: ctx, tx, userId, position, horizon, limit
+func (_m *SyncDatabaseDs) ListTombstonesSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.Tombstone, error) {
+	ret := _m.Called(ctx, tx, userId, position, horizon, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTombstonesSince")
+	}
+
+	var r0 *[]domain.Tombstone
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) (*[]domain.Tombstone, error)); ok {
+		return rf(ctx, tx, userId, position, horizon, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) *[]domain.Tombstone); ok {
+		r0 = rf(ctx, tx, userId, position, horizon, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Tombstone)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int64, int32) error); ok {
+		r1 = rf(ctx, tx, userId, position, horizon, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSyncDatabaseDs creates a new instance of SyncDatabaseDs. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSyncDatabaseDs(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SyncDatabaseDs {
+	mock := &SyncDatabaseDs{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
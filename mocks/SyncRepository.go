@@ -0,0 +1,63 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	uuid "github.com/google/uuid"
+)
+
+// SyncRepository is an autogenerated mock type for the SyncRepository type
+type SyncRepository struct {
+	mock.Mock
+}
+
+// ListChangesSince provides a mock function with given fields: ctx, tx, userId, position, limit
+func (_m *SyncRepository) ListChangesSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, limit int) (*domain.SyncChanges, error) {
+	ret := _m.Called(ctx, tx, userId, position, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListChangesSince")
+	}
+
+	var r0 *domain.SyncChanges
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int) (*domain.SyncChanges, error)); ok {
+		return rf(ctx, tx, userId, position, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int) *domain.SyncChanges); ok {
+		r0 = rf(ctx, tx, userId, position, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SyncChanges)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID, domain.SyncPosition, int) error); ok {
+		r1 = rf(ctx, tx, userId, position, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSyncRepository creates a new instance of SyncRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSyncRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SyncRepository {
+	mock := &SyncRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
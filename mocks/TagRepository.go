@@ -0,0 +1,237 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/daniarmas/notes/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	uuid "github.com/google/uuid"
+)
+
+// TagRepository is an autogenerated mock type for the TagRepository type
+type TagRepository struct {
+	mock.Mock
+}
+
+// AttachTagsToNote provides a mock function with given fields: ctx, tx, noteId, userId, tagIds
+func (_m *TagRepository) AttachTagsToNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error {
+	ret := _m.Called(ctx, tx, noteId, userId, tagIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachTagsToNote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, noteId, userId, tagIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTag provides a mock function with given fields: ctx, tx, tag
+func (_m *TagRepository) CreateTag(ctx context.Context, tx *sql.Tx, tag *domain.Tag) (*domain.Tag, error) {
+	ret := _m.Called(ctx, tx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTag")
+	}
+
+	var r0 *domain.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Tag) (*domain.Tag, error)); ok {
+		return rf(ctx, tx, tag)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Tag) *domain.Tag); ok {
+		r0 = rf(ctx, tx, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Tag) error); ok {
+		r1 = rf(ctx, tx, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTag provides a mock function with given fields: ctx, tx, id, userId
+func (_m *TagRepository) DeleteTag(ctx context.Context, tx *sql.Tx, id uuid.UUID, userId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, id, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachTagsFromNote provides a mock function with given fields: ctx, tx, noteId, userId, tagIds
+func (_m *TagRepository) DetachTagsFromNote(ctx context.Context, tx *sql.Tx, noteId uuid.UUID, userId uuid.UUID, tagIds []uuid.UUID) error {
+	ret := _m.Called(ctx, tx, noteId, userId, tagIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachTagsFromNote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, noteId, userId, tagIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListTagsByNoteId provides a mock function with given fields: ctx, tx, noteId
+func (_m *TagRepository) ListTagsByNoteId(ctx context.Context, tx *sql.Tx, noteId uuid.UUID) (*[]domain.Tag, error) {
+	ret := _m.Called(ctx, tx, noteId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTagsByNoteId")
+	}
+
+	var r0 *[]domain.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) (*[]domain.Tag, error)); ok {
+		return rf(ctx, tx, noteId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) *[]domain.Tag); ok {
+		r0 = rf(ctx, tx, noteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, noteId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTagsByNotesIds provides a mock function with given fields: ctx, noteIds
+func (_m *TagRepository) ListTagsByNotesIds(ctx context.Context, noteIds []uuid.UUID) (*[]domain.NoteTag, error) {
+	ret := _m.Called(ctx, noteIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTagsByNotesIds")
+	}
+
+	var r0 *[]domain.NoteTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (*[]domain.NoteTag, error)); ok {
+		return rf(ctx, noteIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) *[]domain.NoteTag); ok {
+		r0 = rf(ctx, noteIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.NoteTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, noteIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTagsByUser provides a mock function with given fields: ctx, userId
+func (_m *TagRepository) ListTagsByUser(ctx context.Context, userId uuid.UUID) (*[]domain.Tag, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTagsByUser")
+	}
+
+	var r0 *[]domain.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*[]domain.Tag, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *[]domain.Tag); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTag provides a mock function with given fields: ctx, tx, tag
+func (_m *TagRepository) UpdateTag(ctx context.Context, tx *sql.Tx, tag *domain.Tag) (*domain.Tag, error) {
+	ret := _m.Called(ctx, tx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTag")
+	}
+
+	var r0 *domain.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Tag) (*domain.Tag, error)); ok {
+		return rf(ctx, tx, tag)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Tag) *domain.Tag); ok {
+		r0 = rf(ctx, tx, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Tag) error); ok {
+		r1 = rf(ctx, tx, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTagRepository creates a new instance of TagRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTagRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TagRepository {
+	mock := &TagRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
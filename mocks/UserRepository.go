@@ -8,6 +8,8 @@ import (
 	domain "github.com/daniarmas/notes/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,9 +18,9 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with given fields: ctx, user
-func (_m *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
-	ret := _m.Called(ctx, user)
+// CreateUser provides a mock function with given fields: ctx, tx, user
+func (_m *UserRepository) CreateUser(ctx context.Context, tx *sql.Tx, user *domain.User) (*domain.User, error) {
+	ret := _m.Called(ctx, tx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
@@ -26,19 +28,19 @@ func (_m *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*d
 
 	var r0 *domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) (*domain.User, error)); ok {
-		return rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.User) (*domain.User, error)); ok {
+		return rf(ctx, tx, user)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) *domain.User); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.User) *domain.User); ok {
+		r0 = rf(ctx, tx, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
-		r1 = rf(ctx, user)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.User) error); ok {
+		r1 = rf(ctx, tx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -14,46 +14,390 @@ INSERT INTO users (
 )
 RETURNING *;
 
--- name: ListNotesByUserId :many
+-- name: ListNotesByUserIdUpdateTimeDesc :many
 SELECT * FROM notes
-WHERE user_id = $1 AND update_time < $2 AND delete_time IS NULL
-ORDER BY update_time DESC
-LIMIT 10;
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NULL
+  AND (sqlc.arg(include_archived)::boolean OR notes.archive_time IS NULL)
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.pinned_time IS NOT NULL, notes.update_time, notes.id) < (sqlc.arg(cursor_pinned)::boolean, sqlc.arg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.pinned_time IS NOT NULL DESC, notes.update_time DESC, notes.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListNotesByUserIdUpdateTimeAsc :many
+SELECT * FROM notes
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NULL
+  AND (sqlc.arg(include_archived)::boolean OR notes.archive_time IS NULL)
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.pinned_time IS NULL, notes.update_time, notes.id) > (NOT sqlc.arg(cursor_pinned)::boolean, sqlc.arg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.pinned_time IS NULL, notes.update_time, notes.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListNotesByUserIdCreateTimeDesc :many
+SELECT * FROM notes
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NULL
+  AND (sqlc.arg(include_archived)::boolean OR notes.archive_time IS NULL)
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.pinned_time IS NOT NULL, notes.create_time, notes.id) < (sqlc.arg(cursor_pinned)::boolean, sqlc.arg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.pinned_time IS NOT NULL DESC, notes.create_time DESC, notes.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListNotesByUserIdCreateTimeAsc :many
+SELECT * FROM notes
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NULL
+  AND (sqlc.arg(include_archived)::boolean OR notes.archive_time IS NULL)
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.pinned_time IS NULL, notes.create_time, notes.id) > (NOT sqlc.arg(cursor_pinned)::boolean, sqlc.arg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.pinned_time IS NULL, notes.create_time, notes.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListNotesByUserIdTitleDesc :many
+SELECT * FROM notes
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NULL
+  AND (sqlc.arg(include_archived)::boolean OR notes.archive_time IS NULL)
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.pinned_time IS NOT NULL, COALESCE(notes.title, ''), notes.id) < (sqlc.arg(cursor_pinned)::boolean, sqlc.arg(cursor_title)::text, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.pinned_time IS NOT NULL DESC, COALESCE(notes.title, '') DESC, notes.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListNotesByUserIdTitleAsc :many
+SELECT * FROM notes
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NULL
+  AND (sqlc.arg(include_archived)::boolean OR notes.archive_time IS NULL)
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.pinned_time IS NULL, COALESCE(notes.title, ''), notes.id) > (NOT sqlc.arg(cursor_pinned)::boolean, sqlc.arg(cursor_title)::text, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.pinned_time IS NULL, COALESCE(notes.title, ''), notes.id
+LIMIT sqlc.arg(page_limit);
 
 -- name: ListTrashNotesByUserId :many
 SELECT * FROM notes
-WHERE user_id = $1 AND delete_time < $2 AND delete_time IS NOT NULL
-ORDER BY delete_time DESC
-LIMIT 10;
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NOT NULL
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.delete_time, notes.id) < (sqlc.arg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.delete_time DESC, notes.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListArchivedNotesByUserId :many
+SELECT * FROM notes
+WHERE notes.user_id = sqlc.arg(user_id) AND notes.delete_time IS NULL AND notes.archive_time IS NOT NULL
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.archive_time, notes.id) < (sqlc.arg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR notes.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR notes.create_time < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(updated_after)::timestamp IS NULL OR notes.update_time >= sqlc.narg(updated_after)::timestamp)
+  AND (sqlc.narg(updated_before)::timestamp IS NULL OR notes.update_time < sqlc.narg(updated_before)::timestamp)
+  AND (sqlc.narg(tag_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM note_tags WHERE note_tags.note_id = notes.id AND note_tags.tag_id = sqlc.narg(tag_id)::uuid
+  ))
+  AND (sqlc.narg(notebook_id)::uuid IS NULL OR notes.notebook_id = sqlc.narg(notebook_id)::uuid)
+ORDER BY notes.archive_time DESC, notes.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetNoteById :one
+SELECT * FROM notes
+WHERE id = $1 LIMIT 1;
+
+-- name: SearchNotes :many
+SELECT
+  sqlc.embed(notes),
+  ts_rank(notes.search_vector, query)::float8 AS rank,
+  ts_headline('english', COALESCE(notes.title, ''), query, 'HighlightAll=true')::text AS title_snippet,
+  ts_headline('english', COALESCE(notes.content, ''), query, 'MaxFragments=2, MaxWords=20, MinWords=5')::text AS content_snippet
+FROM notes, websearch_to_tsquery('english', sqlc.arg(query)::text) query
+WHERE notes.user_id = sqlc.arg(user_id)
+  AND notes.search_vector @@ query
+  AND (sqlc.arg(include_trash)::boolean OR notes.delete_time IS NULL)
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR ts_rank(notes.search_vector, query)::float8 < sqlc.arg(cursor_rank)::float8
+    OR (ts_rank(notes.search_vector, query)::float8 = sqlc.arg(cursor_rank)::float8 AND notes.id < sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY rank DESC, notes.id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreateNote :one
 INSERT INTO notes (
-  user_id, title, content, create_time, update_time
+  user_id, title, content, create_time, update_time, notebook_id
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6
 )
 RETURNING *;
 
 -- name: UpdateNoteById :one
 UPDATE notes SET
-  title = COALESCE(NULLIF($2, ''), title), content = COALESCE(NULLIF($3, ''), content), update_time = $4
-WHERE id = $1 RETURNING *;
+  title = COALESCE(NULLIF($2, ''), title), content = COALESCE(NULLIF($3, ''), content), update_time = $4, version = version + 1
+WHERE id = $1 AND user_id = $5 AND version = $6 RETURNING *;
+
+-- name: MoveNoteById :one
+UPDATE notes SET
+  notebook_id = $3, update_time = $4, version = version + 1
+WHERE id = $1 AND user_id = $2 AND delete_time IS NULL
+RETURNING *;
+
+-- name: RemoveNotesFromNotebooks :many
+UPDATE notes SET
+  notebook_id = NULL,
+  delete_time = CASE WHEN sqlc.arg(trash)::boolean THEN COALESCE(delete_time, sqlc.arg(delete_time)::timestamp) ELSE delete_time END,
+  update_time = sqlc.arg(update_time), version = version + 1
+WHERE user_id = sqlc.arg(user_id) AND notebook_id = ANY(sqlc.arg(notebook_ids)::uuid[])
+RETURNING *;
+
+-- name: SetNotePinnedById :one
+UPDATE notes SET
+  pinned_time = CASE WHEN sqlc.arg(pinned)::boolean THEN COALESCE(pinned_time, sqlc.arg(pinned_time)::timestamp) ELSE NULL END,
+  update_time = sqlc.arg(update_time), version = version + 1
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND delete_time IS NULL
+RETURNING *;
+
+-- name: SetNoteArchivedById :one
+UPDATE notes SET
+  archive_time = CASE WHEN sqlc.arg(archived)::boolean THEN COALESCE(archive_time, sqlc.arg(archive_time)::timestamp) ELSE NULL END,
+  update_time = sqlc.arg(update_time), version = version + 1
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id) AND delete_time IS NULL
+RETURNING *;
 
 -- name: RestoreNoteById :one
 UPDATE notes SET
-  delete_time = NULL
-WHERE id = $1 AND delete_time IS NOT NULL
+  delete_time = NULL, version = version + 1, update_time = now()
+WHERE id = $1 AND user_id = $2 AND delete_time IS NOT NULL
 RETURNING *;
 
 -- name: HardDeleteNoteById :one
-DELETE FROM notes WHERE id = $1 RETURNING *;
+DELETE FROM notes WHERE id = $1 AND user_id = $2 RETURNING *;
 
 -- name: SoftDeleteNoteById :one
 UPDATE notes SET
-  delete_time = $2
-WHERE id = $1 AND delete_time IS NULL
+  delete_time = $2, version = version + 1, update_time = now()
+WHERE id = $1 AND user_id = $3 AND delete_time IS NULL
+RETURNING *;
+
+-- name: ListExpiredTrashNotes :many
+SELECT * FROM notes
+WHERE notes.delete_time IS NOT NULL AND notes.delete_time < sqlc.arg(before)::timestamp
+  AND (NOT sqlc.arg(has_cursor)::boolean OR (notes.delete_time, notes.id) > (sqlc.arg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid))
+ORDER BY notes.delete_time, notes.id
+LIMIT sqlc.arg(page_limit)
+FOR UPDATE SKIP LOCKED;
+
+-- name: HardDeleteTrashNotesByIds :many
+DELETE FROM notes WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND delete_time IS NOT NULL RETURNING *;
+
+-- name: SoftDeleteNotesByIds :many
+UPDATE notes SET
+  delete_time = sqlc.arg(delete_time), version = version + 1, update_time = now()
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND user_id = sqlc.arg(user_id) AND delete_time IS NULL
+RETURNING *;
+
+-- name: RestoreNotesByIds :many
+UPDATE notes SET
+  delete_time = NULL, version = version + 1, update_time = now()
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND user_id = sqlc.arg(user_id) AND delete_time IS NOT NULL
+RETURNING *;
+
+-- name: HardDeleteNotesByIds :many
+DELETE FROM notes WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND user_id = sqlc.arg(user_id) RETURNING *;
+
+-- name: ListTrashNoteIdsByUserId :many
+SELECT id FROM notes
+WHERE user_id = $1 AND delete_time IS NOT NULL
+ORDER BY delete_time, id
+LIMIT $2
+FOR UPDATE;
+
+-- name: GetSyncHorizon :one
+-- The oldest transaction still in progress, the changes of the older transactions can't change their place anymore
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS horizon;
+
+-- name: ListNotesChangedSince :many
+SELECT * FROM notes
+WHERE user_id = sqlc.arg(user_id)
+	AND (change_xid, change_seq) > (sqlc.arg(change_xid)::bigint, sqlc.arg(change_seq)::bigint)
+	AND change_xid < sqlc.arg(horizon)::bigint
+ORDER BY change_xid, change_seq
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListFilesChangedSince :many
+SELECT files.* FROM files
+JOIN notes ON notes.id = files.note_id
+WHERE notes.user_id = sqlc.arg(user_id)
+	AND (files.change_xid, files.change_seq) > (sqlc.arg(change_xid)::bigint, sqlc.arg(change_seq)::bigint)
+	AND files.change_xid < sqlc.arg(horizon)::bigint
+ORDER BY files.change_xid, files.change_seq
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListTombstonesSince :many
+SELECT * FROM tombstones
+WHERE user_id = sqlc.arg(user_id)
+	AND (change_xid, change_seq) > (sqlc.arg(change_xid)::bigint, sqlc.arg(change_seq)::bigint)
+	AND change_xid < sqlc.arg(horizon)::bigint
+ORDER BY change_xid, change_seq
+LIMIT sqlc.arg(page_limit);
+
+-- name: CreateNotebook :one
+INSERT INTO notebooks (
+  user_id, parent_id, name, create_time, update_time
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetNotebookById :one
+SELECT * FROM notebooks
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListNotebooksByUserId :many
+SELECT * FROM notebooks
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: LockNotebooksByUserId :exec
+SELECT id FROM notebooks
+WHERE user_id = $1
+FOR UPDATE;
+
+-- name: ListNotebookTreeIds :many
+WITH RECURSIVE tree AS (
+  SELECT notebooks.id FROM notebooks WHERE notebooks.id = $1 AND notebooks.user_id = $2
+  UNION
+  SELECT children.id FROM notebooks children JOIN tree ON children.parent_id = tree.id
+)
+SELECT tree.id FROM tree;
+
+-- name: RenameNotebookById :one
+UPDATE notebooks SET
+  name = $3, update_time = $4
+WHERE id = $1 AND user_id = $2 RETURNING *;
+
+-- name: MoveNotebookById :one
+UPDATE notebooks SET
+  parent_id = $3, update_time = $4
+WHERE id = $1 AND user_id = $2 RETURNING *;
+
+-- name: DeleteNotebookById :one
+DELETE FROM notebooks WHERE id = $1 AND user_id = $2 RETURNING *;
+
+-- name: CreateNoteRevision :exec
+INSERT INTO note_revisions (
+  note_id, user_id, title, content, create_time
+)
+SELECT notes.id, notes.user_id, notes.title, notes.content, $3
+FROM notes
+WHERE notes.id = $1 AND notes.user_id = $2;
+
+-- name: ListNoteRevisionsByNoteId :many
+SELECT * FROM note_revisions
+WHERE note_id = $1 AND user_id = $2
+ORDER BY create_time DESC, id DESC;
+
+-- name: GetNoteRevisionById :one
+SELECT * FROM note_revisions
+WHERE id = $1 AND note_id = $2 AND user_id = $3 LIMIT 1;
+
+-- name: PruneNoteRevisions :exec
+DELETE FROM note_revisions
+WHERE note_revisions.note_id = sqlc.arg(note_id) AND note_revisions.id NOT IN (
+  SELECT kept.id FROM note_revisions kept
+  WHERE kept.note_id = sqlc.arg(note_id)
+  ORDER BY kept.create_time DESC, kept.id DESC
+  LIMIT sqlc.arg(keep)
+);
+
+-- name: CreateTag :one
+INSERT INTO tags (
+  user_id, name, create_time, update_time
+) VALUES (
+  $1, $2, $3, $4
+)
 RETURNING *;
 
+-- name: ListTagsByUserId :many
+SELECT * FROM tags
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: ListTagsByIds :many
+SELECT * FROM tags
+WHERE user_id = $1 AND id = ANY($2::uuid[]);
+
+-- name: UpdateTagById :one
+UPDATE tags SET
+  name = $3, update_time = $4
+WHERE id = $1 AND user_id = $2 RETURNING *;
+
+-- name: DeleteTagById :one
+DELETE FROM tags WHERE id = $1 AND user_id = $2 RETURNING *;
+
+-- name: AttachTagsToNote :exec
+INSERT INTO note_tags (
+  note_id, tag_id, user_id
+)
+SELECT $1, unnest($3::uuid[]), $2
+ON CONFLICT DO NOTHING;
+
+-- name: DetachTagsFromNote :exec
+DELETE FROM note_tags
+WHERE note_id = $1 AND user_id = $2 AND tag_id = ANY($3::uuid[]);
+
+-- name: ListTagsByNoteId :many
+SELECT tags.* FROM tags
+JOIN note_tags ON note_tags.tag_id = tags.id
+WHERE note_tags.note_id = $1
+ORDER BY tags.name;
+
+-- name: ListTagsByNotesIds :many
+SELECT note_tags.note_id, tags.*
+FROM note_tags
+JOIN tags ON tags.id = note_tags.tag_id
+WHERE note_tags.note_id = ANY($1::uuid[])
+ORDER BY tags.name;
+
 -- name: GetAccessTokenById :one
 SELECT * FROM access_tokens
 WHERE id = $1 LIMIT 1;
@@ -66,8 +410,13 @@ INSERT INTO access_tokens (
 )
 RETURNING *;
 
--- name: DeleteAccessTokenByUserId :one
-DELETE FROM access_tokens WHERE user_id = $1 RETURNING id;
+-- name: DeleteAccessTokensByRefreshTokenId :many
+DELETE FROM access_tokens WHERE refresh_token_id = $1 RETURNING id;
+
+-- name: DeleteAccessTokensByRefreshTokenFamilyId :many
+DELETE FROM access_tokens USING refresh_tokens
+WHERE access_tokens.refresh_token_id = refresh_tokens.id AND refresh_tokens.family_id = $1
+RETURNING access_tokens.id;
 
 -- name: GetRefreshTokenById :one
 SELECT * FROM refresh_tokens
@@ -75,19 +424,38 @@ WHERE id = $1 LIMIT 1;
 
 -- name: CreateRefreshToken :one
 INSERT INTO refresh_tokens (
-  user_id
+  user_id, family_id, device_name, user_agent, ip_address
 ) VALUES (
-  $1
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
--- name: DeleteRefreshTokenByUserId :one
-DELETE FROM refresh_tokens WHERE user_id = $1 RETURNING id;
+-- name: RotateRefreshTokenById :one
+UPDATE refresh_tokens SET
+  rotate_time = $2, update_time = $2
+WHERE id = $1 AND rotate_time IS NULL
+RETURNING *;
+
+-- name: ListSessionsByUserId :many
+SELECT
+  refresh_tokens.*,
+  (SELECT MIN(family.create_time) FROM refresh_tokens family WHERE family.family_id = refresh_tokens.family_id)::timestamp AS sign_in_time
+FROM refresh_tokens
+WHERE refresh_tokens.user_id = $1 AND refresh_tokens.rotate_time IS NULL AND refresh_tokens.create_time > $2
+ORDER BY refresh_tokens.create_time DESC;
+
+-- name: DeleteRefreshTokensByFamilyId :many
+DELETE FROM refresh_tokens WHERE family_id = $1 RETURNING id;
 
 -- name: ListFilesByNotesIds :many
 SELECT * FROM files 
 WHERE note_id = ANY($1::uuid[]);
 
+-- name: LockFilesByNotesIds :many
+SELECT * FROM files
+WHERE note_id = ANY($1::uuid[])
+FOR UPDATE;
+
 -- name: ListFileByNoteId :many
 SELECT * FROM files 
 WHERE note_id = $1;
@@ -105,5 +473,129 @@ DELETE FROM files WHERE note_id = $1 RETURNING *;
 
 -- name: UpdateFileByOriginalId :one
 UPDATE files SET
-  processed_file = $2, update_time = $3
-WHERE original_file = $1 AND (processed_file IS NULL OR processed_file = '') RETURNING *;
\ No newline at end of file
+  processed_file = $2, update_time = $3, duration = $4, sample_rate = $5, waveform = $6
+WHERE original_file = $1 AND (processed_file IS NULL OR processed_file = '') RETURNING *;
+
+-- name: ListFilesForReprocessing :many
+SELECT files.* FROM files
+JOIN notes ON notes.id = files.note_id
+WHERE files.delete_time IS NULL AND files.processing_status <> 'running'
+  AND (sqlc.narg(cursor_id)::uuid IS NULL OR files.id > sqlc.narg(cursor_id)::uuid)
+  AND (sqlc.narg(note_id)::uuid IS NULL OR files.note_id = sqlc.narg(note_id)::uuid)
+  AND (sqlc.narg(user_id)::uuid IS NULL OR notes.user_id = sqlc.narg(user_id)::uuid)
+  AND (sqlc.narg(extensions)::varchar[] IS NULL OR lower(substring(files.original_file from '\.[^./]*$')) = ANY(sqlc.narg(extensions)::varchar[]))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR files.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR files.create_time < sqlc.narg(created_before)::timestamp)
+  AND (NOT sqlc.arg(unprocessed)::boolean OR files.processing_status <> 'succeeded' OR files.processed_file IS NULL OR files.processed_file = '')
+ORDER BY files.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountFilesForReprocessing :one
+SELECT count(*) FROM files
+JOIN notes ON notes.id = files.note_id
+WHERE files.delete_time IS NULL AND files.processing_status <> 'running'
+  AND (sqlc.narg(note_id)::uuid IS NULL OR files.note_id = sqlc.narg(note_id)::uuid)
+  AND (sqlc.narg(user_id)::uuid IS NULL OR notes.user_id = sqlc.narg(user_id)::uuid)
+  AND (sqlc.narg(extensions)::varchar[] IS NULL OR lower(substring(files.original_file from '\.[^./]*$')) = ANY(sqlc.narg(extensions)::varchar[]))
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR files.create_time >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR files.create_time < sqlc.narg(created_before)::timestamp)
+  AND (NOT sqlc.arg(unprocessed)::boolean OR files.processing_status <> 'succeeded' OR files.processed_file IS NULL OR files.processed_file = '');
+
+-- name: ReplaceProcessedFileById :one
+UPDATE files SET
+  processed_file = sqlc.arg(processed_file), processing_status = 'succeeded', update_time = sqlc.arg(update_time),
+  duration = sqlc.narg(duration), sample_rate = sqlc.narg(sample_rate), waveform = sqlc.narg(waveform)
+WHERE id = sqlc.arg(id) RETURNING *;
+
+-- name: CreateObjectDeletions :exec
+INSERT INTO object_deletions (bucket, object_name)
+SELECT sqlc.arg(bucket), unnest(sqlc.arg(object_names)::varchar[]);
+
+-- name: ListDueObjectDeletions :many
+SELECT * FROM object_deletions
+WHERE dead_time IS NULL AND next_attempt_time <= sqlc.arg(now)::timestamp
+ORDER BY next_attempt_time, id
+LIMIT sqlc.arg(page_limit)
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteObjectDeletionsByIds :exec
+DELETE FROM object_deletions WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: FailObjectDeletionById :exec
+UPDATE object_deletions SET
+  attempts = attempts + 1,
+  last_error = sqlc.arg(last_error),
+  next_attempt_time = sqlc.arg(next_attempt_time),
+  dead_time = sqlc.narg(dead_time)
+WHERE id = sqlc.arg(id);
+
+-- name: RequeueDeadObjectDeletions :execrows
+UPDATE object_deletions SET
+  attempts = 0,
+  next_attempt_time = CURRENT_TIMESTAMP,
+  dead_time = NULL
+WHERE dead_time IS NOT NULL;
+
+-- name: EnqueueFileJobs :exec
+INSERT INTO file_jobs (file_id)
+SELECT unnest(sqlc.arg(file_ids)::uuid[]);
+
+-- name: ClaimFileJobs :many
+UPDATE file_jobs SET
+  status = 'running',
+  attempts = file_jobs.attempts + 1,
+  lease_owner = sqlc.arg(worker)::varchar,
+  lease_expire_time = sqlc.arg(lease_expire_time)::timestamp,
+  update_time = sqlc.arg(now)
+FROM files
+WHERE files.id = file_jobs.file_id AND file_jobs.id IN (
+  SELECT id FROM file_jobs AS due
+  WHERE (due.status = 'queued' AND due.run_time <= sqlc.arg(now)) OR (due.status = 'running' AND due.lease_expire_time <= sqlc.arg(now))
+  ORDER BY due.run_time, due.id
+  LIMIT sqlc.arg(page_limit)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING file_jobs.*, files.original_file;
+
+-- name: CompleteFileJob :execrows
+UPDATE file_jobs SET
+  status = 'succeeded',
+  last_error = NULL,
+  lease_owner = NULL,
+  lease_expire_time = NULL,
+  update_time = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND status = 'running' AND lease_owner = sqlc.arg(worker)::varchar;
+
+-- name: FailFileJob :execrows
+UPDATE file_jobs SET
+  status = sqlc.arg(status),
+  last_error = sqlc.arg(last_error),
+  run_time = sqlc.arg(run_time),
+  lease_owner = NULL,
+  lease_expire_time = NULL,
+  update_time = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND status = 'running' AND lease_owner = sqlc.arg(worker)::varchar;
+
+-- name: SetFilesProcessingStatus :exec
+UPDATE files SET
+  processing_status = sqlc.arg(processing_status)
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: CompleteFileJobsByFileId :exec
+UPDATE file_jobs SET
+  status = 'succeeded', lease_owner = NULL, lease_expire_time = NULL, update_time = sqlc.arg(update_time)
+WHERE file_id = sqlc.arg(file_id) AND status <> 'running';
+
+-- name: ListFileRenditionsByFileIds :many
+SELECT * FROM file_renditions
+WHERE file_id = ANY(sqlc.arg(file_ids)::uuid[])
+ORDER BY file_id, size;
+
+-- name: DeleteFileRenditionsByFileId :many
+DELETE FROM file_renditions
+WHERE file_id = sqlc.arg(file_id) RETURNING *;
+
+-- name: CreateFileRenditions :many
+INSERT INTO file_renditions (file_id, size, object_name, width, height)
+SELECT sqlc.arg(file_id), unnest(sqlc.arg(sizes)::integer[]), unnest(sqlc.arg(object_names)::varchar[]), unnest(sqlc.arg(widths)::integer[]), unnest(sqlc.arg(heights)::integer[])
+RETURNING *;
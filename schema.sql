@@ -8,21 +8,94 @@ CREATE TABLE IF NOT EXISTS users (
 	CONSTRAINT pk PRIMARY KEY (id)
 );
 
+CREATE TABLE IF NOT EXISTS notebooks (
+	id UUID DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL,
+	parent_id UUID,
+	name VARCHAR NOT NULL,
+	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT notebooks_pk PRIMARY KEY (id),
+	CONSTRAINT fk_user
+		FOREIGN KEY (user_id) 
+		REFERENCES users(id)
+		ON DELETE CASCADE,
+	CONSTRAINT fk_parent
+		FOREIGN KEY (parent_id) 
+		REFERENCES notebooks(id)
+		ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS notebooks_parent_id_idx ON notebooks (parent_id);
+
+-- Every change of a note or a file takes the next value and the id of its transaction. The transactions commit out
+-- of the order of the sequence, so the delta sync orders the changes by transaction and then by value, and only
+-- returns the changes of the transactions older than every transaction still in progress
+CREATE SEQUENCE IF NOT EXISTS change_seq;
+
+CREATE OR REPLACE FUNCTION set_change_seq() RETURNS trigger AS $$
+BEGIN
+	NEW.change_seq := nextval('change_seq');
+	NEW.change_xid := pg_current_xact_id()::text::bigint;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
 CREATE TABLE IF NOT EXISTS notes (
 	id UUID DEFAULT gen_random_uuid(),
 	user_id UUID NOT NULL,
+	notebook_id UUID,
 	title VARCHAR,
 	content VARCHAR,
     create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
     update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
     delete_time TIMESTAMP,
+    pinned_time TIMESTAMP,
+    archive_time TIMESTAMP,
+	version INTEGER DEFAULT 1 NOT NULL,
+	change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL,
+	change_xid BIGINT DEFAULT pg_current_xact_id()::text::bigint NOT NULL,
+	search_vector TSVECTOR GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+		setweight(to_tsvector('english', COALESCE(content, '')), 'B')
+	) STORED,
 	CONSTRAINT pk PRIMARY KEY (id),
 	CONSTRAINT fk_user
     	FOREIGN KEY (user_id) 
     	REFERENCES users(id)
-    	ON DELETE CASCADE
+    	ON DELETE CASCADE,
+	CONSTRAINT fk_notebook
+		FOREIGN KEY (notebook_id) 
+		REFERENCES notebooks(id)
+		ON DELETE SET NULL
 );
 
+CREATE INDEX IF NOT EXISTS notes_search_vector_idx ON notes USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS notes_notebook_id_idx ON notes (notebook_id);
+
+CREATE INDEX IF NOT EXISTS notes_user_id_change_xid_idx ON notes (user_id, change_xid, change_seq);
+
+-- The note listing puts the pinned notes first, the descending listings scan the pinned_time IS NOT NULL indexes
+-- backwards and the ascending listings scan the pinned_time IS NULL indexes forwards
+CREATE INDEX IF NOT EXISTS notes_user_id_pinned_create_time_idx ON notes (user_id, (pinned_time IS NOT NULL), create_time, id) WHERE delete_time IS NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_unpinned_create_time_idx ON notes (user_id, (pinned_time IS NULL), create_time, id) WHERE delete_time IS NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_pinned_update_time_idx ON notes (user_id, (pinned_time IS NOT NULL), update_time, id) WHERE delete_time IS NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_unpinned_update_time_idx ON notes (user_id, (pinned_time IS NULL), update_time, id) WHERE delete_time IS NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_pinned_title_idx ON notes (user_id, (pinned_time IS NOT NULL), (COALESCE(title, '')), id) WHERE delete_time IS NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_unpinned_title_idx ON notes (user_id, (pinned_time IS NULL), (COALESCE(title, '')), id) WHERE delete_time IS NULL;
+
+CREATE INDEX IF NOT EXISTS notes_delete_time_idx ON notes (delete_time, id) WHERE delete_time IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS notes_user_id_archive_time_idx ON notes (user_id, archive_time, id) WHERE archive_time IS NOT NULL;
+
+CREATE OR REPLACE TRIGGER notes_change_seq BEFORE UPDATE ON notes FOR EACH ROW EXECUTE FUNCTION set_change_seq();
+
 CREATE TABLE IF NOT EXISTS files (
 	id UUID DEFAULT gen_random_uuid(),
 	processed_file VARCHAR,
@@ -31,6 +104,13 @@ CREATE TABLE IF NOT EXISTS files (
 	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
 	update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
 	delete_time TIMESTAMP,
+	change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL,
+	change_xid BIGINT DEFAULT pg_current_xact_id()::text::bigint NOT NULL,
+	processing_status VARCHAR DEFAULT 'queued' NOT NULL,
+	-- The metadata of the processed audio files, the waveform holds the peaks of the samples from 0 to 1
+	duration DOUBLE PRECISION,
+	sample_rate INTEGER,
+	waveform REAL[],
 	CONSTRAINT pk PRIMARY KEY (id),
 	CONSTRAINT fk_note
 		FOREIGN KEY (note_id) 
@@ -38,11 +118,168 @@ CREATE TABLE IF NOT EXISTS files (
 		ON DELETE CASCADE
 );
 
+CREATE INDEX IF NOT EXISTS files_change_xid_idx ON files (change_xid, change_seq);
+
+CREATE OR REPLACE TRIGGER files_change_seq BEFORE UPDATE ON files FOR EACH ROW EXECUTE FUNCTION set_change_seq();
+
+-- Queue of the files to process, a worker leases the job while it processes the file.
+-- The processing status of the file follows the status of its job.
+CREATE TABLE IF NOT EXISTS file_jobs (
+	id UUID DEFAULT gen_random_uuid(),
+	file_id UUID NOT NULL,
+	status VARCHAR DEFAULT 'queued' NOT NULL,
+	attempts INTEGER DEFAULT 0 NOT NULL,
+	last_error VARCHAR,
+	run_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	lease_owner VARCHAR,
+	lease_expire_time TIMESTAMP,
+	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT file_jobs_pk PRIMARY KEY (id),
+	CONSTRAINT file_jobs_file_fk
+		FOREIGN KEY (file_id)
+		REFERENCES files(id)
+		ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS file_jobs_run_time_idx ON file_jobs (run_time) WHERE status IN ('queued', 'running');
+
+-- Resized copies of the processed images, one per configured size smaller than the image
+CREATE TABLE IF NOT EXISTS file_renditions (
+	id UUID DEFAULT gen_random_uuid(),
+	file_id UUID NOT NULL,
+	size INTEGER NOT NULL,
+	object_name VARCHAR NOT NULL,
+	width INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT file_renditions_pk PRIMARY KEY (id),
+	CONSTRAINT file_renditions_file_id_size_key UNIQUE (file_id, size),
+	CONSTRAINT file_renditions_file_fk
+		FOREIGN KEY (file_id)
+		REFERENCES files(id)
+		ON DELETE CASCADE
+);
+
+-- Outbox of the objects to remove from the object storage service. The objects of the hard deleted files are recorded
+-- in the transaction that deletes the files and removed by a worker once it is committed.
+CREATE TABLE IF NOT EXISTS object_deletions (
+	id UUID DEFAULT gen_random_uuid(),
+	bucket VARCHAR NOT NULL,
+	object_name VARCHAR NOT NULL,
+	attempts INTEGER DEFAULT 0 NOT NULL,
+	last_error VARCHAR,
+	next_attempt_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	dead_time TIMESTAMP,
+	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT object_deletions_pk PRIMARY KEY (id)
+);
+
+-- The dead-lettered deletions are not retried
+CREATE INDEX IF NOT EXISTS object_deletions_next_attempt_time_idx ON object_deletions (next_attempt_time) WHERE dead_time IS NULL;
+
+-- Hard deleted notes and files, the delta sync reports them to the clients that synced them before.
+-- There is no foreign key to the user, so the tombstones of the notes of a deleted user can be created.
+CREATE TABLE IF NOT EXISTS tombstones (
+	id UUID DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL,
+	entity_type VARCHAR NOT NULL,
+	entity_id UUID NOT NULL,
+	note_id UUID NOT NULL,
+	change_seq BIGINT DEFAULT nextval('change_seq') NOT NULL,
+	change_xid BIGINT DEFAULT pg_current_xact_id()::text::bigint NOT NULL,
+	delete_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT tombstones_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS tombstones_user_id_change_xid_idx ON tombstones (user_id, change_xid, change_seq);
+
+CREATE OR REPLACE FUNCTION create_note_tombstone() RETURNS trigger AS $$
+BEGIN
+	INSERT INTO tombstones (user_id, entity_type, entity_id, note_id) VALUES (OLD.user_id, 'note', OLD.id, OLD.id);
+	RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER notes_tombstone AFTER DELETE ON notes FOR EACH ROW EXECUTE FUNCTION create_note_tombstone();
+
+-- The files deleted along with their note are covered by the tombstone of the note
+CREATE OR REPLACE FUNCTION create_file_tombstone() RETURNS trigger AS $$
+BEGIN
+	INSERT INTO tombstones (user_id, entity_type, entity_id, note_id)
+	SELECT notes.user_id, 'file', OLD.id, OLD.note_id FROM notes WHERE notes.id = OLD.note_id;
+	RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER files_tombstone AFTER DELETE ON files FOR EACH ROW EXECUTE FUNCTION create_file_tombstone();
+
+CREATE TABLE IF NOT EXISTS tags (
+	id UUID DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL,
+	name VARCHAR NOT NULL,
+	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT tags_pk PRIMARY KEY (id),
+	CONSTRAINT tags_user_id_name_key UNIQUE (user_id, name),
+	CONSTRAINT fk_user
+		FOREIGN KEY (user_id) 
+		REFERENCES users(id)
+		ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS note_tags (
+	note_id UUID NOT NULL,
+	tag_id UUID NOT NULL,
+	user_id UUID NOT NULL,
+	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT note_tags_pk PRIMARY KEY (note_id, tag_id),
+	CONSTRAINT fk_note
+		FOREIGN KEY (note_id) 
+		REFERENCES notes(id)
+		ON DELETE CASCADE,
+	CONSTRAINT fk_tag
+		FOREIGN KEY (tag_id) 
+		REFERENCES tags(id)
+		ON DELETE CASCADE,
+	CONSTRAINT fk_user
+		FOREIGN KEY (user_id) 
+		REFERENCES users(id)
+		ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS note_tags_tag_id_idx ON note_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS note_revisions (
+	id UUID DEFAULT gen_random_uuid(),
+	note_id UUID NOT NULL,
+	user_id UUID NOT NULL,
+	title VARCHAR,
+	content VARCHAR,
+	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT note_revisions_pk PRIMARY KEY (id),
+	CONSTRAINT fk_note
+		FOREIGN KEY (note_id) 
+		REFERENCES notes(id)
+		ON DELETE CASCADE,
+	CONSTRAINT fk_user
+		FOREIGN KEY (user_id) 
+		REFERENCES users(id)
+		ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS note_revisions_note_id_create_time_idx ON note_revisions (note_id, create_time DESC);
+
 CREATE TABLE IF NOT EXISTS refresh_tokens (
 	id UUID DEFAULT gen_random_uuid(),
 	user_id UUID NOT NULL,
+	family_id UUID DEFAULT gen_random_uuid() NOT NULL,
+	device_name VARCHAR,
+	user_agent VARCHAR,
+	ip_address VARCHAR,
 	create_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
 	update_time TIMESTAMP,
+	rotate_time TIMESTAMP,
 	CONSTRAINT pk PRIMARY KEY (id),
 	CONSTRAINT fk_user
 		FOREIGN KEY (user_id) 
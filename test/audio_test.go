@@ -0,0 +1,44 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pcm encodes the samples as 16 bit little endian samples
+func pcm(samples []int16) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
+
+// Test that the waveform peaks are the highest absolute samples of their spans
+func TestWaveformPeaks(t *testing.T) {
+	t.Run("spans", func(t *testing.T) {
+		// A second at the sample rate of the waveform, the loudest sample of every quarter grows
+		samples := make([]int16, 8000)
+		for i, peak := range []int16{1000, -16384, 32767, math.MinInt16} {
+			samples[i*2000+1000] = peak
+		}
+		peaks, err := domain.WaveformPeaks(bytes.NewReader(pcm(samples)), 4)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{0.031, 0.5, 1, 1}, peaks)
+	})
+	t.Run("short audio is stretched", func(t *testing.T) {
+		// A single block of samples fills every peak
+		peaks, err := domain.WaveformPeaks(bytes.NewReader(pcm([]int16{0, 8192, -4096})), 3)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{0.25, 0.25, 0.25}, peaks)
+	})
+	t.Run("silence", func(t *testing.T) {
+		peaks, err := domain.WaveformPeaks(bytes.NewReader(nil), 3)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{0, 0, 0}, peaks)
+	})
+}
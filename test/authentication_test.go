@@ -0,0 +1,254 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that a refresh token is exchanged once, and that its reuse revokes the whole session family
+func TestRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{Id: uuid.New(), Name: "name", Email: "user@example.com"}
+	token := &domain.RefreshToken{Id: uuid.New(), UserId: user.Id, FamilyId: uuid.New(), DeviceName: "phone", UserAgent: "agent", IpAddress: "10.0.0.1"}
+
+	// setup parses the jwt as the refresh token of the user and returns the stored token
+	setup := func(t *testing.T, stored *domain.RefreshToken) *testMocks {
+		m := newTestMocks(t)
+		m.JwtDatasource.On("ParseJWT", mock.Anything).Run(func(args mock.Arguments) {
+			metadata := args.Get(0).(*domain.JWTMetadata)
+			metadata.TokenId = token.Id
+			metadata.UserId = user.Id
+		}).Return(nil).Once()
+		m.RefreshTokenRepository.On("GetRefreshToken", ctx, token.Id).Return(stored, nil)
+		return m
+	}
+
+	// expectRevoked expects the tokens of the session family to be deleted
+	expectRevoked := func(m *testMocks) {
+		m.AccessTokenRepository.On("DeleteAccessTokensByRefreshTokenFamilyId", ctx, mock.Anything, token.FamilyId).Return(nil).Once()
+		m.RefreshTokenRepository.On("DeleteRefreshTokensByFamilyId", ctx, mock.Anything, token.FamilyId).Return(nil).Once()
+	}
+
+	t.Run("Test a refresh token is rotated into the same family", func(t *testing.T) {
+		m := setup(t, token)
+		accessTokenId := uuid.New()
+		newToken := &domain.RefreshToken{Id: uuid.New(), UserId: user.Id, FamilyId: token.FamilyId}
+		accessToken := "access"
+		refreshToken := "refresh"
+		m.RefreshTokenRepository.On("RotateRefreshToken", ctx, mock.Anything, token.Id).Return(nil).Once()
+		m.AccessTokenRepository.On("DeleteAccessTokensByRefreshTokenId", ctx, mock.Anything, token.Id).Return(nil).Once()
+		m.UserRepository.On("GetUserById", ctx, user.Id).Return(user, nil)
+		// The device of the session is kept when the request has no client
+		m.RefreshTokenRepository.On("CreateRefreshToken", ctx, mock.Anything, &domain.RefreshToken{
+			UserId:     user.Id,
+			FamilyId:   token.FamilyId,
+			DeviceName: token.DeviceName,
+			UserAgent:  token.UserAgent,
+			IpAddress:  token.IpAddress,
+		}).Return(newToken, nil).Once()
+		m.AccessTokenRepository.On("CreateAccessToken", ctx, mock.Anything, user.Id, newToken.Id).Return(&domain.AccessToken{Id: accessTokenId}, nil).Once()
+		m.JwtDatasource.On("CreateJWT", &domain.JWTMetadata{TokenId: newToken.Id, UserId: user.Id}, mock.Anything).Return(&refreshToken, nil).Once()
+		m.JwtDatasource.On("CreateJWT", &domain.JWTMetadata{TokenId: accessTokenId, UserId: user.Id}, mock.Anything).Return(&accessToken, nil).Once()
+
+		res, err := m.authenticationService().RefreshToken(ctx, "jwt")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access", res.AccessToken)
+		assert.Equal(t, "refresh", res.RefreshToken)
+		commits, rollbacks := m.Txs.Counts()
+		assert.Equal(t, 1, commits)
+		assert.Equal(t, 0, rollbacks)
+	})
+
+	t.Run("Test a rotated refresh token revokes the session family", func(t *testing.T) {
+		rotated := *token
+		rotated.RotateTime = time.Now()
+		m := setup(t, &rotated)
+		expectRevoked(m)
+
+		res, err := m.authenticationService().RefreshToken(ctx, "jwt")
+
+		assert.EqualError(t, err, "refresh token reused")
+		assert.Nil(t, res)
+		// The rotation is rolled back and the revocation is committed
+		commits, rollbacks := m.Txs.Counts()
+		assert.Equal(t, 1, commits)
+		assert.Equal(t, 1, rollbacks)
+	})
+
+	t.Run("Test a refresh token rotated concurrently revokes the session family", func(t *testing.T) {
+		m := setup(t, token)
+		// Another exchange rotated the token between the read and the update
+		m.RefreshTokenRepository.On("RotateRefreshToken", ctx, mock.Anything, token.Id).Return(&customerrors.RecordNotFound{}).Once()
+		expectRevoked(m)
+
+		res, err := m.authenticationService().RefreshToken(ctx, "jwt")
+
+		assert.EqualError(t, err, "refresh token reused")
+		assert.Nil(t, res)
+		m.AccessTokenRepository.AssertNotCalled(t, "DeleteAccessTokensByRefreshTokenId", mock.Anything, mock.Anything, mock.Anything)
+		commits, rollbacks := m.Txs.Counts()
+		assert.Equal(t, 1, commits)
+		assert.Equal(t, 1, rollbacks)
+	})
+
+	t.Run("Test a failed revocation is reported", func(t *testing.T) {
+		rotated := *token
+		rotated.RotateTime = time.Now()
+		m := setup(t, &rotated)
+		m.AccessTokenRepository.On("DeleteAccessTokensByRefreshTokenFamilyId", ctx, mock.Anything, token.FamilyId).Return(errors.New("connection lost")).Once()
+
+		res, err := m.authenticationService().RefreshToken(ctx, "jwt")
+
+		assert.EqualError(t, err, "connection lost")
+		assert.Nil(t, res)
+		commits, rollbacks := m.Txs.Counts()
+		assert.Equal(t, 0, commits)
+		assert.Equal(t, 2, rollbacks)
+	})
+
+	t.Run("Test a refresh token of another user is rejected", func(t *testing.T) {
+		foreign := *token
+		foreign.UserId = uuid.New()
+		m := setup(t, &foreign)
+
+		res, err := m.authenticationService().RefreshToken(ctx, "jwt")
+
+		assert.EqualError(t, err, "invalid refresh token")
+		assert.Nil(t, res)
+		m.RefreshTokenRepository.AssertNotCalled(t, "DeleteRefreshTokensByFamilyId", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test an expired refresh token is rejected", func(t *testing.T) {
+		m := newTestMocks(t)
+		m.JwtDatasource.On("ParseJWT", mock.Anything).Return(errors.New("Token is expired")).Once()
+
+		res, err := m.authenticationService().RefreshToken(ctx, "jwt")
+
+		assert.EqualError(t, err, "refresh token expired")
+		assert.Nil(t, res)
+	})
+}
+
+// Test that a sign up creates the user with the hashed password and opens its first session
+func TestSignUp(t *testing.T) {
+	ctx := domain.SetClientInContext(context.Background(), "agent", "10.0.0.1")
+	user := &domain.User{Id: uuid.New(), Name: "name", Email: "user@example.com", Password: "hash"}
+
+	t.Run("Test a sign up opens a session for the new user", func(t *testing.T) {
+		m := newTestMocks(t)
+		newToken := &domain.RefreshToken{Id: uuid.New(), UserId: user.Id}
+		accessTokenId := uuid.New()
+		accessToken := "access"
+		refreshToken := "refresh"
+		m.HashDatasource.On("Hash", "password").Return("hash", nil).Once()
+		m.UserRepository.On("CreateUser", ctx, mock.Anything, &domain.User{Name: "name", Email: "user@example.com", Password: "hash"}).Return(user, nil).Once()
+		// The session starts a family of its own with the device and the client of the request
+		m.RefreshTokenRepository.On("CreateRefreshToken", ctx, mock.Anything, mock.MatchedBy(func(session *domain.RefreshToken) bool {
+			return session.UserId == user.Id && session.FamilyId != uuid.Nil && session.DeviceName == "phone" && session.UserAgent == "agent" && session.IpAddress == "10.0.0.1"
+		})).Return(newToken, nil).Once()
+		m.AccessTokenRepository.On("CreateAccessToken", ctx, mock.Anything, user.Id, newToken.Id).Return(&domain.AccessToken{Id: accessTokenId}, nil).Once()
+		m.JwtDatasource.On("CreateJWT", &domain.JWTMetadata{TokenId: newToken.Id, UserId: user.Id}, mock.Anything).Return(&refreshToken, nil).Once()
+		m.JwtDatasource.On("CreateJWT", &domain.JWTMetadata{TokenId: accessTokenId, UserId: user.Id}, mock.Anything).Return(&accessToken, nil).Once()
+
+		res, err := m.authenticationService().SignUp(ctx, "name", "user@example.com", "password", "phone")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access", res.AccessToken)
+		assert.Equal(t, "refresh", res.RefreshToken)
+		assert.Equal(t, user.Id, res.User.Id)
+		commits, rollbacks := m.Txs.Counts()
+		assert.Equal(t, 1, commits)
+		assert.Equal(t, 0, rollbacks)
+	})
+
+	t.Run("Test a sign up with a taken email is rolled back", func(t *testing.T) {
+		m := newTestMocks(t)
+		m.HashDatasource.On("Hash", "password").Return("hash", nil).Once()
+		m.UserRepository.On("CreateUser", ctx, mock.Anything, mock.Anything).Return(nil, &customerrors.DuplicateRecord{Field: "email"}).Once()
+
+		res, err := m.authenticationService().SignUp(ctx, "name", "user@example.com", "password", "phone")
+
+		assert.IsType(t, &customerrors.DuplicateRecord{}, err)
+		assert.Nil(t, res)
+		m.RefreshTokenRepository.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+		commits, rollbacks := m.Txs.Counts()
+		assert.Equal(t, 0, commits)
+		assert.Equal(t, 1, rollbacks)
+	})
+}
+
+// Test that the sessions of a user are listed, and that a user only revokes its own sessions
+func TestSessions(t *testing.T) {
+	userId := uuid.New()
+	accessTokenId := uuid.New()
+	refreshTokenId := uuid.New()
+	ctx := domain.SetAccessTokenIdInContext(domain.SetUserInContext(context.Background(), userId), accessTokenId)
+	current := domain.Session{Id: uuid.New(), DeviceName: "phone"}
+	other := domain.Session{Id: uuid.New(), DeviceName: "laptop"}
+
+	// setup returns the sessions of the user, the access token of the request belongs to the current one
+	setup := func(t *testing.T) *testMocks {
+		m := newTestMocks(t)
+		m.RefreshTokenRepository.On("ListSessionsByUserId", ctx, userId).Return(&[]domain.Session{current, other}, nil).Once()
+		m.AccessTokenRepository.On("GetAccessToken", ctx, accessTokenId).Return(&domain.AccessToken{Id: accessTokenId, UserId: userId, RefreshTokenId: refreshTokenId}, nil).Maybe()
+		m.RefreshTokenRepository.On("GetRefreshToken", ctx, refreshTokenId).Return(&domain.RefreshToken{Id: refreshTokenId, UserId: userId, FamilyId: current.Id}, nil).Maybe()
+		return m
+	}
+
+	t.Run("Test the current session is marked", func(t *testing.T) {
+		m := setup(t)
+
+		res, err := m.authenticationService().ListSessions(ctx)
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Sessions, 2)
+		assert.True(t, res.Sessions[0].Current)
+		assert.False(t, res.Sessions[1].Current)
+	})
+
+	t.Run("Test a session of the user is revoked", func(t *testing.T) {
+		m := setup(t)
+		m.AccessTokenRepository.On("DeleteAccessTokensByRefreshTokenFamilyId", ctx, mock.Anything, other.Id).Return(nil).Once()
+		m.RefreshTokenRepository.On("DeleteRefreshTokensByFamilyId", ctx, mock.Anything, other.Id).Return(nil).Once()
+
+		err := m.authenticationService().RevokeSession(ctx, other.Id)
+
+		assert.NoError(t, err)
+		commits, _ := m.Txs.Counts()
+		assert.Equal(t, 1, commits)
+	})
+
+	t.Run("Test a session of another user is not found", func(t *testing.T) {
+		m := setup(t)
+
+		err := m.authenticationService().RevokeSession(ctx, uuid.New())
+
+		assert.EqualError(t, err, "session not found")
+		m.RefreshTokenRepository.AssertNotCalled(t, "DeleteRefreshTokensByFamilyId", mock.Anything, mock.Anything, mock.Anything)
+		commits, rollbacks := m.Txs.Counts()
+		assert.Equal(t, 0, commits)
+		assert.Equal(t, 1, rollbacks)
+	})
+
+	t.Run("Test the other sessions are revoked and the current one is kept", func(t *testing.T) {
+		m := setup(t)
+		m.AccessTokenRepository.On("DeleteAccessTokensByRefreshTokenFamilyId", ctx, mock.Anything, other.Id).Return(nil).Once()
+		m.RefreshTokenRepository.On("DeleteRefreshTokensByFamilyId", ctx, mock.Anything, other.Id).Return(nil).Once()
+
+		err := m.authenticationService().RevokeOtherSessions(ctx)
+
+		assert.NoError(t, err)
+		m.RefreshTokenRepository.AssertNotCalled(t, "DeleteRefreshTokensByFamilyId", mock.Anything, mock.Anything, current.Id)
+		commits, _ := m.Txs.Counts()
+		assert.Equal(t, 1, commits)
+	})
+}
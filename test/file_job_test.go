@@ -0,0 +1,124 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the worker completes the processed jobs, retries the failed ones with a backoff, fails the ones that
+// run out of attempts and leaves the ones whose lease was lost to the next worker
+func TestProcessFileJobs(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	noteId := uuid.New()
+	succeeded := domain.FileJob{Id: uuid.New(), OriginalFile: "original/a.jpg", Attempts: 1}
+	retried := domain.FileJob{Id: uuid.New(), OriginalFile: "original/b.jpg", Attempts: 2}
+	failed := domain.FileJob{Id: uuid.New(), OriginalFile: "original/c.jpg", Attempts: 3}
+	expired := domain.FileJob{Id: uuid.New(), OriginalFile: "original/d.jpg", Attempts: 4}
+	lost := domain.FileJob{Id: uuid.New(), OriginalFile: "original/e.jpg", Attempts: 1}
+	opts := service.ProcessFileJobsOptions{Worker: "worker-1", Concurrency: 5, Lease: time.Minute, MaxAttempts: 3, RetryBackoff: 30 * time.Second}
+
+	fileJobRepository := mocks.NewFileJobRepository(t)
+	fileJobRepository.On("ClaimFileJobs", ctx, mock.Anything, "worker-1", time.Minute, int32(5)).Return(&[]domain.FileJob{succeeded, retried, failed, expired, lost}, nil).Once()
+	fileJobRepository.On("CompleteFileJob", ctx, mock.Anything, mock.MatchedBy(func(job *domain.FileJob) bool { return job.Id == succeeded.Id }), "worker-1").Return(nil).Once()
+	fileJobRepository.On("CompleteFileJob", ctx, mock.Anything, mock.MatchedBy(func(job *domain.FileJob) bool { return job.Id == lost.Id }), "worker-1").Return(&customerrors.RecordNotFound{}).Once()
+	// The second failed attempt waits twice the backoff
+	fileJobRepository.On("FailFileJob", ctx, mock.Anything, mock.MatchedBy(func(job *domain.FileJob) bool { return job.Id == retried.Id }), "worker-1", "timeout", mock.MatchedBy(func(runTime time.Time) bool {
+		wait := time.Until(runTime)
+		return wait > 30*time.Second && wait <= time.Minute
+	}), false).Return(nil).Once()
+	fileJobRepository.On("FailFileJob", ctx, mock.Anything, mock.MatchedBy(func(job *domain.FileJob) bool { return job.Id == failed.Id }), "worker-1", "unsupported file", mock.Anything, true).Return(nil).Once()
+	// The file of a job whose lease expired on every attempt is not processed again
+	fileJobRepository.On("FailFileJob", ctx, mock.Anything, mock.MatchedBy(func(job *domain.FileJob) bool { return job.Id == expired.Id }), "worker-1", mock.Anything, mock.Anything, true).Return(nil).Once()
+
+	fileRepository := mocks.NewFileRepository(t)
+	fileRepository.On("Process", ctx, mock.Anything, "original/a.jpg").Return(&domain.File{NoteId: noteId, OriginalFile: "original/a.jpg", ProcessedFile: "processed/a.jpg"}, nil).Once()
+	fileRepository.On("Process", ctx, mock.Anything, "original/b.jpg").Return(nil, errors.New("timeout")).Once()
+	fileRepository.On("Process", ctx, mock.Anything, "original/c.jpg").Return(nil, errors.New("unsupported file")).Once()
+	fileRepository.On("Process", ctx, mock.Anything, "original/e.jpg").Return(&domain.File{NoteId: noteId, OriginalFile: "original/e.jpg"}, nil).Once()
+
+	// Only the committed file is published
+	noteRepository := mocks.NewNoteRepository(t)
+	noteRepository.On("GetNote", ctx, noteId).Return(&domain.Note{Id: noteId, UserId: userId}, nil).Once()
+	eventRepository := mocks.NewNoteEventRepository(t)
+	eventRepository.On("PublishNoteEvent", ctx, mock.MatchedBy(func(event *domain.NoteEvent) bool {
+		return event.UserId == userId && event.File.OriginalFile == "original/a.jpg" && event.File.ProcessingStatus == domain.FileProcessingSucceeded
+	}), mock.Anything).Return(nil).Once()
+
+	fileJobService := service.NewFileJobService(fileJobRepository, fileRepository, noteRepository, eventRepository, config.Configuration{}, newStubDb())
+
+	summary, err := fileJobService.ProcessFileJobs(ctx, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &service.ProcessFileJobsSummary{Claimed: 5, Succeeded: 1, Retried: 1, Failed: 2, Lost: 1}, summary)
+}
+
+// Test that the processed copies of a job that is not saved are discarded, and that the job of a file that is
+// already processed is completed instead of being retried
+func TestProcessFileJobsUnsavedObjects(t *testing.T) {
+	ctx := context.Background()
+	processed := domain.FileJob{Id: uuid.New(), OriginalFile: "original/a.jpg", Attempts: 1}
+	lost := domain.FileJob{Id: uuid.New(), OriginalFile: "original/b.jpg", Attempts: 1}
+	opts := service.ProcessFileJobsOptions{Worker: "worker-1", Concurrency: 2, Lease: time.Minute, MaxAttempts: 3, RetryBackoff: 30 * time.Second}
+
+	fileJobRepository := mocks.NewFileJobRepository(t)
+	fileJobRepository.On("ClaimFileJobs", ctx, mock.Anything, "worker-1", time.Minute, int32(2)).Return(&[]domain.FileJob{processed, lost}, nil).Once()
+	fileJobRepository.On("CompleteFileJob", ctx, mock.Anything, mock.MatchedBy(func(job *domain.FileJob) bool { return job.Id == processed.Id }), "worker-1").Return(nil).Once()
+	fileJobRepository.On("CompleteFileJob", ctx, mock.Anything, mock.MatchedBy(func(job *domain.FileJob) bool { return job.Id == lost.Id }), "worker-1").Return(&customerrors.RecordNotFound{}).Once()
+
+	fileRepository := mocks.NewFileRepository(t)
+	fileRepository.On("Process", ctx, mock.Anything, "original/a.jpg").Return(nil, &domain.UnsavedObjectsError{
+		ObjectNames: []string{"processed/a.jpg", "processed/a-256.jpg"},
+		Err:         domain.ErrFileAlreadyProcessed,
+	}).Once()
+	fileRepository.On("Process", ctx, mock.Anything, "original/b.jpg").Return(&domain.File{
+		OriginalFile:  "original/b.jpg",
+		ProcessedFile: "processed/b.jpg",
+		Renditions:    []domain.FileRendition{{Size: 256, ObjectName: "processed/b-256.jpg"}},
+	}, nil).Once()
+	fileRepository.On("DiscardObjects", ctx, mock.Anything, []string{"processed/a.jpg", "processed/a-256.jpg"}).Return(nil).Once()
+	fileRepository.On("DiscardObjects", ctx, mock.Anything, []string{"processed/b.jpg", "processed/b-256.jpg"}).Return(nil).Once()
+
+	// Nothing changed on the files, so no event is published
+	fileJobService := service.NewFileJobService(fileJobRepository, fileRepository, mocks.NewNoteRepository(t), mocks.NewNoteEventRepository(t), config.Configuration{}, newStubDb())
+
+	summary, err := fileJobService.ProcessFileJobs(ctx, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &service.ProcessFileJobsSummary{Claimed: 2, Succeeded: 1, Lost: 1}, summary)
+
+	t.Run("Test the non positive options are rejected", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			modify func(opts *service.ProcessFileJobsOptions)
+			err    string
+		}{
+			{"concurrency", func(opts *service.ProcessFileJobsOptions) { opts.Concurrency = 0 }, "concurrency must be positive"},
+			{"lease", func(opts *service.ProcessFileJobsOptions) { opts.Lease = 0 }, "lease must be positive"},
+			{"max attempts", func(opts *service.ProcessFileJobsOptions) { opts.MaxAttempts = 0 }, "max attempts must be positive"},
+			{"retry backoff", func(opts *service.ProcessFileJobsOptions) { opts.RetryBackoff = 0 }, "retry backoff must be positive"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				opts := opts
+				tt.modify(&opts)
+
+				summary, err := fileJobService.ProcessFileJobs(ctx, opts)
+
+				assert.EqualError(t, err, tt.err)
+				assert.Nil(t, summary)
+			})
+		}
+	})
+}
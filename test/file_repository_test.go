@@ -0,0 +1,48 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that a failed upload of a rendition returns the processed copy and the renditions uploaded so far, so they are discarded
+func TestProcessFailedRenditionUpload(t *testing.T) {
+	ctx := context.Background()
+	// The processing removes the downloaded file, so it gets a copy of the image
+	data, err := os.ReadFile(filepath.Join("testdata", "images", "orientation-6.jpg"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "original.jpg")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	objectStorageService := mocks.NewObjectStorageService(t)
+	objectStorageService.On("GetObject", ctx, "bucket", "original/a.jpg").Return(path, nil).Once()
+	objectStorageService.On("PutObject", ctx, "bucket", mock.MatchedBy(func(objectName string) bool {
+		return !strings.HasSuffix(objectName, "-16.jpg")
+	}), mock.Anything).Return(nil).Twice()
+	objectStorageService.On("PutObject", ctx, "bucket", mock.MatchedBy(func(objectName string) bool {
+		return strings.HasSuffix(objectName, "-16.jpg")
+	}), mock.Anything).Return(errors.New("timeout")).Once()
+	fileRepository := domain.NewFileRepository(nil, nil, objectStorageService, &config.Configuration{ObjectStorageServiceBucket: "bucket", ImageRenditionSizes: []int{8, 16}})
+
+	file, err := fileRepository.Process(ctx, nil, "original/a.jpg")
+
+	assert.Nil(t, file)
+	var unsaved *domain.UnsavedObjectsError
+	require.ErrorAs(t, err, &unsaved)
+	assert.EqualError(t, unsaved.Err, "timeout")
+	require.Len(t, unsaved.ObjectNames, 2)
+	processed := unsaved.ObjectNames[0]
+	assert.True(t, strings.HasPrefix(processed, "processed-photos/"), "the processed copy isn't discarded")
+	assert.Equal(t, strings.TrimSuffix(processed, ".jpg")+"-8.jpg", unsaved.ObjectNames[1])
+}
@@ -0,0 +1,338 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"image"
+	"image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run the tests with -update to write the golden files again after a change of the encoders
+var update = flag.Bool("update", false, "update the golden files")
+
+// Test that the images are detected from their magic bytes
+func TestDetectImageFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"jpeg", []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F'}, domain.ImageJpeg},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"), domain.ImagePng},
+		{"gif", []byte("GIF89a\x10\x00\x10\x00"), domain.ImageGif},
+		{"webp", []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), domain.ImageWebp},
+		{"heic", []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), domain.ImageHeic},
+		{"heif", []byte("\x00\x00\x00\x18ftypmif1\x00\x00\x00\x00"), domain.ImageHeic},
+		{"avif", []byte("\x00\x00\x00\x18ftypavif\x00\x00\x00\x00"), ""},
+		{"audio", []byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00"), ""},
+		{"empty", []byte{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.DetectImageFormat(tt.header))
+		})
+	}
+}
+
+// Test the encoders of the images against the golden files in testdata/images/golden
+func TestCompressImage(t *testing.T) {
+	tests := []struct {
+		input  string
+		golden string
+		// ffmpeg encodes the image, its output changes between the builds so there is no golden file
+		ffmpeg bool
+		check  func(t *testing.T, output []byte)
+	}{
+		{
+			// The orientation 6 is rotated clockwise, the green bottom left quadrant ends up top left
+			input:  "orientation-6.jpg",
+			golden: "orientation-6.jpg",
+			check: func(t *testing.T, output []byte) {
+				img, _, err := image.Decode(bytes.NewReader(output))
+				require.NoError(t, err)
+				assert.Equal(t, image.Rect(0, 0, 16, 32), img.Bounds())
+				r, g, b, _ := img.At(2, 2).RGBA()
+				assert.True(t, g > 0xc000 && r < 0x4000 && b < 0x4000, "the top left pixel isn't green")
+			},
+		},
+		{
+			// The format is detected from the content, so the image is encoded as JPEG
+			input:  "misnamed-jpeg.png",
+			golden: "orientation-6.jpg",
+		},
+		{
+			// The orientation 8 is rotated counterclockwise, the transparent bottom right quadrant ends up top right
+			input:  "transparent-orientation-8.png",
+			golden: "transparent-orientation-8.png",
+			check: func(t *testing.T, output []byte) {
+				img, _, err := image.Decode(bytes.NewReader(output))
+				require.NoError(t, err)
+				assert.Equal(t, image.Rect(0, 0, 16, 32), img.Bounds())
+				_, _, _, a := img.At(14, 2).RGBA()
+				assert.Zero(t, a, "the top right pixel isn't transparent")
+				_, _, b, a := img.At(2, 2).RGBA()
+				assert.True(t, b == 0xffff && a == 0xffff, "the top left pixel isn't blue")
+			},
+		},
+		{
+			// The frames, the delays and the transparent color are kept
+			input:  "animated-transparent.gif",
+			golden: "animated-transparent.gif",
+			check: func(t *testing.T, output []byte) {
+				img, err := gif.DecodeAll(bytes.NewReader(output))
+				require.NoError(t, err)
+				assert.Len(t, img.Image, 2)
+				assert.Equal(t, []int{20, 20}, img.Delay)
+				_, _, _, a := img.Image[0].At(0, 0).RGBA()
+				assert.Zero(t, a, "the transparent pixel isn't transparent")
+			},
+		},
+		{
+			// The orientation 6 of the EXIF chunk is rotated clockwise, the green bottom left quadrant ends up top left
+			input:  "orientation-6.webp",
+			ffmpeg: true,
+			check: func(t *testing.T, output []byte) {
+				assert.Equal(t, domain.ImageWebp, domain.DetectImageFormat(output))
+				img := decodeWithFfmpeg(t, output)
+				assert.Equal(t, image.Rect(0, 0, 16, 32), img.Bounds())
+				r, g, b, _ := img.At(2, 2).RGBA()
+				assert.True(t, g > 0xc000 && r < 0x4000 && b < 0x4000, "the top left pixel isn't green")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if tt.ffmpeg {
+				requireFfmpeg(t)
+			}
+			outputPath, err := domain.CompressImage(filepath.Join("testdata", "images", tt.input))
+			require.NoError(t, err)
+			defer os.Remove(outputPath)
+
+			output, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			if !tt.ffmpeg {
+				assert.Equal(t, filepath.Ext(tt.golden), filepath.Ext(outputPath))
+				goldenPath := filepath.Join("testdata", "images", "golden", tt.golden)
+				if *update {
+					require.NoError(t, os.WriteFile(goldenPath, output, 0644))
+				}
+				golden, err := os.ReadFile(goldenPath)
+				require.NoError(t, err)
+				assert.True(t, bytes.Equal(golden, output), "the output doesn't match the golden file %s", goldenPath)
+			}
+
+			if tt.check != nil {
+				tt.check(t, output)
+			}
+		})
+	}
+}
+
+// Test that the HEIC images are converted to JPEG. There is no encoder of HEIC images to write a fixture with,
+// so the frame is encoded by ffmpeg and wrapped in the HEIF container by the test.
+func TestCompressHeicImage(t *testing.T) {
+	requireFfmpeg(t)
+	hevcPath := filepath.Join(t.TempDir(), "green.hevc")
+	cmd := exec.Command("ffmpeg", "-y", "-loglevel", "error", "-f", "lavfi", "-i", "color=c=green:s=64x32", "-frames:v", "1",
+		"-c:v", "libx265", "-x265-params", "log-level=none", "-pix_fmt", "yuv420p", "-f", "hevc", hevcPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("ffmpeg can't encode HEVC: %v: %s", err, output)
+	}
+	hevc, err := os.ReadFile(hevcPath)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "green.heic")
+	require.NoError(t, os.WriteFile(path, heif(hevc, 64, 32), 0644))
+
+	outputPath, err := domain.CompressImage(path)
+	require.NoError(t, err)
+	defer os.Remove(outputPath)
+	assert.Equal(t, ".jpg", filepath.Ext(outputPath))
+
+	output, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	img, format, err := image.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, image.Rect(0, 0, 64, 32), img.Bounds())
+	r, g, b, _ := img.At(32, 16).RGBA()
+	assert.True(t, g > 0x6000 && r < 0x2000 && b < 0x2000, "the image isn't green")
+}
+
+// Test that the images over the cap of pixels are rejected from the size of their header, before they are decoded
+func TestCompressImageTooLarge(t *testing.T) {
+	// The header of a GIF image of 65535x65535 pixels without a color table
+	path := filepath.Join(t.TempDir(), "large.gif")
+	require.NoError(t, os.WriteFile(path, []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00"), 0644))
+
+	_, err := domain.CompressImage(path)
+	assert.ErrorContains(t, err, "the image of 65535x65535 pixels is too large")
+
+	_, err = domain.CreateImageRenditions(path, []int{256})
+	assert.ErrorContains(t, err, "the image of 65535x65535 pixels is too large")
+}
+
+// Test that the renditions keep the format and the aspect ratio of the processed image, and that it isn't enlarged
+func TestCreateImageRenditions(t *testing.T) {
+	tests := []struct {
+		input string
+		check func(t *testing.T, output []byte)
+	}{
+		{
+			// The transparent top right quadrant stays transparent
+			input: "transparent-orientation-8.png",
+			check: func(t *testing.T, output []byte) {
+				img, _, err := image.Decode(bytes.NewReader(output))
+				require.NoError(t, err)
+				_, _, _, a := img.At(img.Bounds().Dx()-1, 0).RGBA()
+				assert.Zero(t, a, "the top right pixel isn't transparent")
+				_, _, b, a := img.At(0, 0).RGBA()
+				assert.True(t, b == 0xffff && a == 0xffff, "the top left pixel isn't blue")
+			},
+		},
+		{
+			// The frames and the delays are kept
+			input: "animated-transparent.gif",
+			check: func(t *testing.T, output []byte) {
+				img, err := gif.DecodeAll(bytes.NewReader(output))
+				require.NoError(t, err)
+				assert.Len(t, img.Image, 2)
+				assert.Equal(t, []int{20, 20}, img.Delay)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			path := filepath.Join("testdata", "images", "golden", tt.input)
+			config, _, err := image.DecodeConfig(openFile(t, path))
+			require.NoError(t, err)
+			longest := max(config.Width, config.Height)
+
+			// The sizes from the longest side up are skipped
+			sizes := []int{longest / 4, longest / 2}
+			renditions, err := domain.CreateImageRenditions(path, append(sizes, longest, 2*longest))
+			require.NoError(t, err)
+			defer func() {
+				for _, rendition := range renditions {
+					os.Remove(rendition.Path)
+				}
+			}()
+
+			require.Len(t, renditions, 2)
+			for i, rendition := range renditions {
+				assert.Equal(t, sizes[i], rendition.Size)
+				assert.Equal(t, filepath.Ext(path), filepath.Ext(rendition.Path))
+				assert.Equal(t, rendition.Size, max(rendition.Width, rendition.Height))
+				assert.Equal(t, config.Width*rendition.Height, config.Height*rendition.Width)
+
+				output, err := os.ReadFile(rendition.Path)
+				require.NoError(t, err)
+				size, _, err := image.DecodeConfig(bytes.NewReader(output))
+				require.NoError(t, err)
+				assert.Equal(t, rendition.Width, size.Width)
+				assert.Equal(t, rendition.Height, size.Height)
+				tt.check(t, output)
+			}
+		})
+	}
+}
+
+// openFile opens the file and closes it once the test is done
+func openFile(t *testing.T, path string) *os.File {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+// requireFfmpeg skips the test without ffmpeg 7.1 or newer
+func requireFfmpeg(t *testing.T) {
+	if err := domain.CheckFfmpeg(); err != nil {
+		t.Skip(err)
+	}
+}
+
+// decodeWithFfmpeg decodes the image with ffmpeg, for the formats without a decoder in the standard library
+func decodeWithFfmpeg(t *testing.T, data []byte) image.Image {
+	cmd := exec.Command("ffmpeg", "-loglevel", "error", "-i", "pipe:0", "-frames:v", "1", "-f", "image2pipe", "-c:v", "png", "pipe:1")
+	cmd.Stdin = bytes.NewReader(data)
+	output, err := cmd.Output()
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	return img
+}
+
+// heif wraps the frame of the HEVC stream in a HEIF container with a single image item.
+// The parameter sets go to the decoder configuration and the slices to the data of the item.
+func heif(hevc []byte, width int, height int) []byte {
+	var parameterSets [][]byte
+	var slices []byte
+	for _, nal := range bytes.Split(hevc, []byte{0, 0, 1}) {
+		nal = bytes.TrimRight(nal, "\x00")
+		if len(nal) < 2 {
+			continue
+		}
+		switch nalType := nal[0] >> 1 & 0x3f; {
+		case nalType >= 32 && nalType <= 34:
+			// The video, sequence and picture parameter sets
+			parameterSets = append(parameterSets, nal)
+		case nalType < 32:
+			slices = binary.BigEndian.AppendUint32(slices, uint32(len(nal)))
+			slices = append(slices, nal...)
+		}
+	}
+
+	// The decoder configuration of the Main profile with 4 bytes long lengths of the slices
+	hvcC := []byte{1, 0x01, 0x60, 0, 0, 0, 0x90, 0, 0, 0, 0, 0, 90, 0xf0, 0, 0xfc, 0xfd, 0xf8, 0xf8, 0, 0, 0x0f, byte(len(parameterSets))}
+	for _, nal := range parameterSets {
+		hvcC = append(hvcC, 0x80|nal[0]>>1&0x3f, 0, 1)
+		hvcC = binary.BigEndian.AppendUint16(hvcC, uint16(len(nal)))
+		hvcC = append(hvcC, nal...)
+	}
+	ispe := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, uint32(width)), uint32(height))
+
+	ftyp := isoBox("ftyp", []byte("heic\x00\x00\x00\x00mif1heic"))
+	meta := func(offset int) []byte {
+		// The item 1 is the primary image, its data is a single extent of the mdat box
+		iloc := []byte{0x44, 0, 0, 1, 0, 1, 0, 0, 0, 1}
+		iloc = binary.BigEndian.AppendUint32(iloc, uint32(offset))
+		iloc = binary.BigEndian.AppendUint32(iloc, uint32(len(slices)))
+		return isoFullBox("meta", 0, []byte{},
+			isoFullBox("hdlr", 0, []byte("\x00\x00\x00\x00pict\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")),
+			isoFullBox("pitm", 0, []byte{0, 1}),
+			isoFullBox("iloc", 0, iloc),
+			isoFullBox("iinf", 0, []byte{0, 1}, isoFullBox("infe", 2, []byte("\x00\x01\x00\x00hvc1\x00"))),
+			isoBox("iprp", isoBox("ipco", isoBox("hvcC", hvcC), isoFullBox("ispe", 0, ispe)),
+				// The hvcC property is essential to decode the item, the ispe property isn't
+				isoFullBox("ipma", 0, []byte{0, 0, 0, 1, 0, 1, 2, 0x81, 0x02})),
+		)
+	}
+	// The offset doesn't change the size of the meta box
+	offset := len(ftyp) + len(meta(0)) + 8
+	return bytes.Join([][]byte{ftyp, meta(offset), isoBox("mdat", slices)}, nil)
+}
+
+// isoBox returns the box of the ISO base media file format with the payloads
+func isoBox(boxType string, payloads ...[]byte) []byte {
+	payload := bytes.Join(payloads, nil)
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(payload)))
+	box = append(box, boxType...)
+	return append(box, payload...)
+}
+
+// isoFullBox returns the box with the version and zero flags before the payloads
+func isoFullBox(boxType string, version byte, payloads ...[]byte) []byte {
+	return isoBox(boxType, append([][]byte{{version, 0, 0, 0}}, payloads...)...)
+}
@@ -0,0 +1,174 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/httpserver/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the SetUserInContext middleware checks the token id against the stored access tokens
+func TestSetUserInContext(t *testing.T) {
+	userId := uuid.New()
+	tokenId := uuid.New()
+
+	// setup builds the middleware with a jwt datasource that always parses the token successfully
+	setup := func(t *testing.T) (*testMocks, http.Handler) {
+		m := newTestMocks(t)
+		m.JwtDatasource.On("ParseJWT", mock.Anything).Run(func(args mock.Arguments) {
+			metadata := args.Get(0).(*domain.JWTMetadata)
+			metadata.TokenId = tokenId
+			metadata.UserId = userId
+		}).Return(nil)
+		return m, m.setUserInContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, userId, domain.GetUserIdFromContext(r.Context()))
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+
+	t.Run("Test a stored access token is accepted", func(t *testing.T) {
+		m, handler := setup(t)
+		m.AccessTokenRepository.On("GetAccessToken", mock.Anything, tokenId).Return(&domain.AccessToken{Id: tokenId, UserId: userId}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Test a revoked access token is rejected", func(t *testing.T) {
+		m, handler := setup(t)
+		m.AccessTokenRepository.On("GetAccessToken", mock.Anything, tokenId).Return(nil, &customerrors.RecordNotFound{})
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Authorization token has been revoked")
+	})
+}
+
+// Test that the SetClientInContext middleware only reads the proxy headers of the trusted proxies
+func TestSetClientInContext(t *testing.T) {
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	// clientIp returns the ip address the middleware sets in the context of the request
+	clientIp := func(remoteAddr string, headers map[string]string) string {
+		var ipAddress string
+		handler := middleware.SetClientInContext(trustedProxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, ipAddress = domain.GetClientFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.RemoteAddr = remoteAddr
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return ipAddress
+	}
+
+	t.Run("Test the proxy headers of an untrusted client are ignored", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIp("203.0.113.7:4000", map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-Ip": "198.51.100.2"}))
+	})
+
+	t.Run("Test the nearest untrusted address behind a trusted proxy is the client", func(t *testing.T) {
+		// The first address is set by the client, the last ones by the proxies
+		assert.Equal(t, "198.51.100.1", clientIp("10.0.0.2:4000", map[string]string{"X-Forwarded-For": "192.0.2.99, 198.51.100.1, 10.0.0.3"}))
+	})
+
+	t.Run("Test the real ip header of a trusted proxy is used", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.2", clientIp("10.0.0.2:4000", map[string]string{"X-Real-Ip": "198.51.100.2"}))
+	})
+
+	t.Run("Test a trusted proxy without proxy headers is the client", func(t *testing.T) {
+		assert.Equal(t, "10.0.0.2", clientIp("10.0.0.2:4000", nil))
+	})
+}
+
+// Test that the websocket upgrades are only allowed from the allowed origins and the same origin
+func TestCheckOrigin(t *testing.T) {
+	checkOrigin := middleware.CheckOrigin([]string{"https://notes.example.com"})
+
+	// upgrade returns an upgrade request of the origin to the graphql server
+	upgrade := func(origin string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "http://api.example.com/query", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		return req
+	}
+
+	assert.True(t, checkOrigin(upgrade("https://notes.example.com")))
+	assert.True(t, checkOrigin(upgrade("http://api.example.com")))
+	assert.True(t, checkOrigin(upgrade("")))
+	assert.False(t, checkOrigin(upgrade("https://evil.example.com")))
+}
+
+// Test that the preflight requests of the allowed origins can send the sync headers and read the ETag
+func TestCors(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.Cors([]string{"https://notes.example.com"})(next)
+
+	t.Run("Test the preflight of an allowed origin is answered", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "http://api.example.com/note/1", nil)
+		req.Header.Set("Origin", "https://notes.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://notes.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), http.MethodPatch)
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Last-Event-ID")
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "If-Match")
+		assert.Equal(t, "ETag", rec.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("Test another origin is not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://api.example.com/note", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+// Test that the websocket connection of an access token is closed once the token expires
+func TestWebsocketInitExpiration(t *testing.T) {
+	userId := uuid.New()
+	tokenId := uuid.New()
+	m := newTestMocks(t)
+	// The token expires a moment after the connection is initialized
+	createTime := time.Now().Add(-domain.AccessTokenLifetime + 50*time.Millisecond)
+	m.AccessTokenRepository.On("GetAccessToken", mock.Anything, tokenId).Return(&domain.AccessToken{Id: tokenId, UserId: userId, CreateTime: createTime}, nil)
+	ctx := domain.SetAccessTokenIdInContext(domain.SetUserInContext(context.Background(), userId), tokenId)
+
+	ctx, _, err := middleware.WebsocketInit(m.JwtDatasource, m.AccessTokenRepository)(ctx, transport.InitPayload{})
+
+	assert.NoError(t, err)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("the connection was not closed when the access token expired")
+	}
+}
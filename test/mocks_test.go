@@ -0,0 +1,80 @@
+package test
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/httpserver/middleware"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+// testMocks are the mocked dependencies of the services and the middlewares under test.
+// Every test builds a fresh set and only sets the expectations it needs, the unused mocks expect no calls.
+type testMocks struct {
+	NoteRepository         *mocks.NoteRepository
+	FileRepository         *mocks.FileRepository
+	TagRepository          *mocks.TagRepository
+	NotebookRepository     *mocks.NotebookRepository
+	NoteRevisionRepository *mocks.NoteRevisionRepository
+	NoteEventRepository    *mocks.NoteEventRepository
+	SyncRepository         *mocks.SyncRepository
+	FileJobRepository      *mocks.FileJobRepository
+	AccessTokenRepository  *mocks.AccessTokenRepository
+	RefreshTokenRepository *mocks.RefreshTokenRepository
+	UserRepository         *mocks.UserRepository
+	JwtDatasource          *mocks.JwtDatasource
+	HashDatasource         *mocks.HashDatasource
+	Config                 config.Configuration
+	// Db is a stub database, Txs counts its committed and rolled back transactions
+	Db  *sql.DB
+	Txs *stubTxs
+}
+
+func newTestMocks(t *testing.T) *testMocks {
+	db, txs := newRecordingStubDb()
+	m := &testMocks{
+		NoteRepository:         mocks.NewNoteRepository(t),
+		FileRepository:         mocks.NewFileRepository(t),
+		TagRepository:          mocks.NewTagRepository(t),
+		NotebookRepository:     mocks.NewNotebookRepository(t),
+		NoteRevisionRepository: mocks.NewNoteRevisionRepository(t),
+		NoteEventRepository:    mocks.NewNoteEventRepository(t),
+		SyncRepository:         mocks.NewSyncRepository(t),
+		FileJobRepository:      mocks.NewFileJobRepository(t),
+		AccessTokenRepository:  mocks.NewAccessTokenRepository(t),
+		RefreshTokenRepository: mocks.NewRefreshTokenRepository(t),
+		UserRepository:         mocks.NewUserRepository(t),
+		JwtDatasource:          mocks.NewJwtDatasource(t),
+		HashDatasource:         mocks.NewHashDatasource(t),
+		Db:                     db,
+		Txs:                    txs,
+	}
+	// The cached copies of the changed notes are removed after every committed change
+	m.NoteRepository.On("DeleteCachedNotes", mock.Anything, mock.Anything).Maybe()
+	return m
+}
+
+func (m *testMocks) noteService() service.NoteService {
+	return service.NewNoteService(m.NoteRepository, nil, m.FileRepository, m.TagRepository, m.NotebookRepository, m.NoteRevisionRepository, m.NoteEventRepository, m.SyncRepository, m.Config, m.FileJobRepository, m.Db)
+}
+
+func (m *testMocks) notebookService() service.NotebookService {
+	return service.NewNotebookService(m.NotebookRepository, m.NoteRepository, m.NoteEventRepository, m.Config, m.Db)
+}
+
+func (m *testMocks) trashService() service.TrashService {
+	return service.NewTrashService(m.NoteRepository, m.FileRepository, m.NoteEventRepository, m.Config, m.Db)
+}
+
+func (m *testMocks) authenticationService() service.AuthenticationService {
+	return service.NewAuthenticationService(m.JwtDatasource, m.HashDatasource, m.UserRepository, m.AccessTokenRepository, m.RefreshTokenRepository, m.Db)
+}
+
+// setUserInContext wraps the handler with the authentication middleware
+func (m *testMocks) setUserInContext(next http.Handler) http.Handler {
+	return middleware.SetUserInContext(m.JwtDatasource, m.AccessTokenRepository)(next)
+}
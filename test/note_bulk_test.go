@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the bulk note operations report the result of every requested note and
+// only remove the files of the deleted notes
+func TestBulkNotes(t *testing.T) {
+	userId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+	ownId := uuid.New()
+	foreignId := uuid.New()
+
+	// setup expects the events of the own note, the foreign note is never published
+	setup := func(t *testing.T) (*testMocks, service.NoteService) {
+		m := newTestMocks(t)
+		m.NoteEventRepository.On("PublishNoteEvent", ctx, mock.MatchedBy(func(event *domain.NoteEvent) bool {
+			return event.NoteId == ownId
+		}), mock.Anything).Return(nil).Maybe()
+		return m, m.noteService()
+	}
+
+	t.Run("Test a foreign note is reported as not found", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("SoftDeleteNotes", ctx, mock.Anything, []uuid.UUID{ownId, foreignId}, userId).Return(&[]domain.Note{{Id: ownId, UserId: userId}}, nil)
+
+		res, err := noteService.SoftDeleteNotes(ctx, []uuid.UUID{ownId, foreignId, ownId})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []service.BulkNoteResult{{Id: ownId, Ok: true}, {Id: foreignId, Error: "note not found"}}, res.Results)
+	})
+
+	t.Run("Test the files of the deleted notes are removed", func(t *testing.T) {
+		m, noteService := setup(t)
+		ownFile := domain.File{Id: uuid.New(), NoteId: ownId}
+		m.FileRepository.On("LockFilesByNotesIds", ctx, mock.Anything, []uuid.UUID{ownId, foreignId}).Return(&[]domain.File{ownFile, {Id: uuid.New(), NoteId: foreignId}}, nil)
+		m.NoteRepository.On("HardDeleteNotes", ctx, mock.Anything, []uuid.UUID{ownId, foreignId}, userId).Return(&[]domain.Note{{Id: ownId, UserId: userId}}, nil)
+		m.FileRepository.On("HardDeleteFiles", ctx, mock.Anything, &[]domain.File{ownFile}).Return(nil).Once()
+
+		res, err := noteService.HardDeleteNotes(ctx, []uuid.UUID{ownId, foreignId})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []service.BulkNoteResult{{Id: ownId, Ok: true}, {Id: foreignId, Error: "note not found"}}, res.Results)
+	})
+
+	t.Run("Test the files are kept when the delete fails", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.FileRepository.On("LockFilesByNotesIds", ctx, mock.Anything, []uuid.UUID{ownId}).Return(&[]domain.File{{Id: uuid.New(), NoteId: ownId}}, nil)
+		m.NoteRepository.On("HardDeleteNotes", ctx, mock.Anything, []uuid.UUID{ownId}, userId).Return(nil, assert.AnError)
+
+		res, err := noteService.HardDeleteNotes(ctx, []uuid.UUID{ownId})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		m.FileRepository.AssertNotCalled(t, "HardDeleteFiles", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test the trash is emptied in batches", func(t *testing.T) {
+		m, noteService := setup(t)
+		ids := make([]uuid.UUID, domain.MaxBulkNotes+1)
+		deleted := make([]domain.Note, domain.MaxBulkNotes)
+		for i := range ids {
+			ids[i] = uuid.New()
+		}
+		for i := range deleted {
+			deleted[i] = domain.Note{Id: ids[i], UserId: userId}
+		}
+		m.NoteRepository.On("ListTrashNoteIds", ctx, mock.Anything, userId, int32(domain.MaxBulkNotes+1)).Return(ids, nil)
+		m.FileRepository.On("LockFilesByNotesIds", ctx, mock.Anything, ids[:domain.MaxBulkNotes]).Return(&[]domain.File{}, nil)
+		m.NoteRepository.On("HardDeleteNotes", ctx, mock.Anything, ids[:domain.MaxBulkNotes], userId).Return(&deleted, nil)
+		m.FileRepository.On("HardDeleteFiles", ctx, mock.Anything, &[]domain.File{}).Return(nil)
+		m.NoteEventRepository.On("PublishNoteEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := noteService.EmptyTrash(ctx)
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Results, domain.MaxBulkNotes)
+		assert.True(t, res.HasMore)
+	})
+
+	t.Run("Test too many ids are rejected", func(t *testing.T) {
+		_, noteService := setup(t)
+		ids := make([]uuid.UUID, domain.MaxBulkNotes+1)
+		for i := range ids {
+			ids[i] = uuid.New()
+		}
+
+		res, err := noteService.RestoreNotes(ctx, ids)
+
+		assert.EqualError(t, err, "too many ids")
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the ids of the events, they are the ids of the entries of the redis streams
+func TestNoteEventId(t *testing.T) {
+	t.Run("Test an id with and without the sequence is valid", func(t *testing.T) {
+		ms, seq, ok := domain.ParseNoteEventId("1700000000000-3")
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1700000000000), ms)
+		assert.Equal(t, uint64(3), seq)
+
+		_, seq, ok = domain.ParseNoteEventId("1700000000000")
+		assert.True(t, ok)
+		assert.Equal(t, uint64(0), seq)
+	})
+
+	t.Run("Test a malformed id is invalid", func(t *testing.T) {
+		for _, id := range []string{"", "abc", "1-", "-1", "1-2-3"} {
+			_, _, ok := domain.ParseNoteEventId(id)
+			assert.False(t, ok, id)
+		}
+	})
+
+	t.Run("Test the ids are ordered by time and then by sequence", func(t *testing.T) {
+		assert.True(t, domain.NoteEventIdBefore("9-5", "10-0"))
+		assert.True(t, domain.NoteEventIdBefore("10-1", "10-2"))
+		assert.False(t, domain.NoteEventIdBefore("10-2", "10-2"))
+		assert.False(t, domain.NoteEventIdBefore("11-0", "10-9"))
+	})
+}
+
+// Test that an invalid Last-Event-ID is rejected before the stream is read
+func TestNoteServiceStreamInvalidLastEventId(t *testing.T) {
+	ctx := domain.SetUserInContext(context.Background(), uuid.New())
+	eventRepository := mocks.NewNoteEventRepository(t)
+	noteService := service.NewNoteService(mocks.NewNoteRepository(t), nil, mocks.NewFileRepository(t), mocks.NewTagRepository(t), mocks.NewNotebookRepository(t), mocks.NewNoteRevisionRepository(t), eventRepository, mocks.NewSyncRepository(t), config.Configuration{}, nil, newStubDb())
+
+	events, err := noteService.StreamNoteEvents(ctx, "not-an-id")
+
+	assert.EqualError(t, err, "invalid last event id")
+	assert.Nil(t, events)
+}
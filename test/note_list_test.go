@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test the encoding and decoding of the note list cursor
+func TestNoteListCursor(t *testing.T) {
+	t.Run("Test the cursor round trip", func(t *testing.T) {
+		want := domain.NoteListCursor{Time: time.Date(2024, 5, 1, 10, 30, 0, 123456000, time.UTC), Id: uuid.New(), Pinned: true, Sort: domain.NoteSort{Key: domain.NoteSortCreateTime, Ascending: true}}
+		got, err := domain.DecodeNoteListCursor(want.Encode())
+		if err != nil {
+			t.Fatalf("TestNoteListCursor failed: unexpected error %v", err)
+		}
+		if !got.Time.Equal(want.Time) || got.Id != want.Id || got.Pinned != want.Pinned || got.Sort != want.Sort {
+			t.Errorf("TestNoteListCursor failed: got %+v, want %+v", *got, want)
+		}
+	})
+
+	t.Run("Test an invalid cursor", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "bm8tY29tbWE", "MjAyNC0wNS0wMSwxMjM"} {
+			if _, err := domain.DecodeNoteListCursor(cursor); err == nil || err.Error() != "invalid cursor" {
+				t.Errorf("TestNoteListCursor failed: got %v for %q, want invalid cursor", err, cursor)
+			}
+		}
+	})
+}
+
+// Test the parsing of the sort key and the direction of the note listing
+func TestParseNoteSort(t *testing.T) {
+	sort, err := domain.ParseNoteSort("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DefaultNoteSort, sort)
+
+	sort, err = domain.ParseNoteSort("title", "ASC")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NoteSort{Key: domain.NoteSortTitle, Ascending: true}, sort)
+
+	_, err = domain.ParseNoteSort("content", "")
+	assert.EqualError(t, err, "invalid sort")
+
+	_, err = domain.ParseNoteSort("create_time", "up")
+	assert.EqualError(t, err, "invalid sort direction")
+}
+
+// Test that the list notes page is trimmed to the limit and points to the next page
+func TestNoteServiceListNotesPage(t *testing.T) {
+	userId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+	now := time.Now().UTC()
+	notes := []domain.Note{
+		{Id: uuid.New(), UserId: userId, UpdateTime: now},
+		{Id: uuid.New(), UserId: userId, UpdateTime: now.Add(-time.Minute)},
+		{Id: uuid.New(), UserId: userId, UpdateTime: now.Add(-2 * time.Minute)},
+	}
+
+	setup := func(t *testing.T, notes []domain.Note, limit int) service.NoteService {
+		noteRepository := mocks.NewNoteRepository(t)
+		// One note past the page is requested to know if there is a next page
+		noteRepository.On("ListNotesByUser", ctx, userId, mock.Anything, (*domain.NoteListCursor)(nil), int32(limit+1), domain.NoteFilter{}).Return(&notes, nil)
+		fileRepository := mocks.NewFileRepository(t)
+		fileRepository.On("ListFilesByNotesIds", ctx, mock.Anything).Return(&[]domain.File{}, nil)
+		tagRepository := mocks.NewTagRepository(t)
+		tagRepository.On("ListTagsByNotesIds", ctx, mock.Anything).Return(&[]domain.NoteTag{}, nil)
+		return service.NewNoteService(noteRepository, nil, fileRepository, tagRepository, mocks.NewNotebookRepository(t), mocks.NewNoteRevisionRepository(t), mocks.NewNoteEventRepository(t), mocks.NewSyncRepository(t), config.Configuration{}, nil, newStubDb())
+	}
+
+	t.Run("Test a full page has a next cursor", func(t *testing.T) {
+		res, err := setup(t, notes, 2).ListNotesByUser(ctx, domain.DefaultNoteSort, nil, 2, domain.NoteFilter{})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Notes, 2)
+		assert.True(t, res.HasNextPage)
+		cursor, err := domain.DecodeNoteListCursor(res.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, notes[1].Id, cursor.Id)
+		assert.True(t, notes[1].UpdateTime.Equal(cursor.Time))
+	})
+
+	t.Run("Test the next cursor follows the sort key", func(t *testing.T) {
+		notes := []domain.Note{
+			{Id: uuid.New(), UserId: userId, Title: "a, b"},
+			{Id: uuid.New(), UserId: userId, Title: "c"},
+		}
+		res, err := setup(t, notes, 1).ListNotesByUser(ctx, domain.NoteSort{Key: domain.NoteSortTitle, Ascending: true}, nil, 1, domain.NoteFilter{})
+
+		assert.NoError(t, err)
+		cursor, err := domain.DecodeNoteListCursor(res.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, "a, b", cursor.Title)
+		assert.Equal(t, notes[0].Id, cursor.Id)
+	})
+
+	t.Run("Test a cursor of another sort is rejected", func(t *testing.T) {
+		cursor := domain.NoteSort{Key: domain.NoteSortTitle}.Cursor(notes[0])
+		noteService := service.NewNoteService(mocks.NewNoteRepository(t), nil, mocks.NewFileRepository(t), mocks.NewTagRepository(t), mocks.NewNotebookRepository(t), mocks.NewNoteRevisionRepository(t), mocks.NewNoteEventRepository(t), mocks.NewSyncRepository(t), config.Configuration{}, nil, newStubDb())
+
+		res, err := noteService.ListNotesByUser(ctx, domain.DefaultNoteSort, &cursor, 2, domain.NoteFilter{})
+
+		assert.EqualError(t, err, "cursor does not match the sort")
+		assert.Nil(t, res)
+	})
+
+	t.Run("Test the last page has no next cursor", func(t *testing.T) {
+		res, err := setup(t, notes[:2], 2).ListNotesByUser(ctx, domain.DefaultNoteSort, nil, 2, domain.NoteFilter{})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Notes, 2)
+		assert.False(t, res.HasNextPage)
+		assert.Empty(t, res.NextCursor)
+	})
+}
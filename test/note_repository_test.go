@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the GetNote func of the note repository
+func TestNoteRepositoryGetNote(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	note := &domain.Note{Id: id, UserId: uuid.New(), Title: "title"}
+
+	t.Run("Test the note is read from the cache", func(t *testing.T) {
+		var noteCacheDs domain.NoteCacheDs = mocks.NewNoteCacheDs(t)
+		var noteDatabaseDs domain.NoteDatabaseDs = mocks.NewNoteDatabaseDs(t)
+		noteCacheDs.(*mocks.NoteCacheDs).On("GetNote", ctx, id).Return(note, nil)
+		noteRepository := domain.NewNoteRepository(&noteCacheDs, &noteDatabaseDs)
+
+		result, err := noteRepository.GetNote(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, note, result)
+	})
+
+	t.Run("Test a cache miss reads the database and caches the note", func(t *testing.T) {
+		var noteCacheDs domain.NoteCacheDs = mocks.NewNoteCacheDs(t)
+		var noteDatabaseDs domain.NoteDatabaseDs = mocks.NewNoteDatabaseDs(t)
+		noteCacheDs.(*mocks.NoteCacheDs).On("GetNote", ctx, id).Return(nil, &customerrors.RecordNotFound{})
+		noteDatabaseDs.(*mocks.NoteDatabaseDs).On("GetNote", ctx, id).Return(note, nil)
+		noteCacheDs.(*mocks.NoteCacheDs).On("CreateNote", ctx, note).Return(nil)
+		noteRepository := domain.NewNoteRepository(&noteCacheDs, &noteDatabaseDs)
+
+		result, err := noteRepository.GetNote(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, note, result)
+	})
+
+	t.Run("Test a missing note returns a not found error", func(t *testing.T) {
+		var noteCacheDs domain.NoteCacheDs = mocks.NewNoteCacheDs(t)
+		var noteDatabaseDs domain.NoteDatabaseDs = mocks.NewNoteDatabaseDs(t)
+		noteCacheDs.(*mocks.NoteCacheDs).On("GetNote", ctx, id).Return(nil, &customerrors.RecordNotFound{})
+		noteDatabaseDs.(*mocks.NoteDatabaseDs).On("GetNote", ctx, id).Return(nil, &customerrors.RecordNotFound{})
+		noteRepository := domain.NewNoteRepository(&noteCacheDs, &noteDatabaseDs)
+
+		result, err := noteRepository.GetNote(ctx, id)
+
+		assert.IsType(t, &customerrors.RecordNotFound{}, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the line diff between the title or the content of two revisions
+func TestDiffLines(t *testing.T) {
+	t.Run("Test equal texts only have equal lines", func(t *testing.T) {
+		diff := domain.DiffLines("a\nb", "a\nb")
+
+		assert.Equal(t, []domain.DiffLine{
+			{Type: domain.DiffEqual, Text: "a"},
+			{Type: domain.DiffEqual, Text: "b"},
+		}, diff)
+	})
+
+	t.Run("Test a changed line is a delete followed by an insert", func(t *testing.T) {
+		diff := domain.DiffLines("a\nb\nc", "a\nx\nc")
+
+		assert.Equal(t, []domain.DiffLine{
+			{Type: domain.DiffEqual, Text: "a"},
+			{Type: domain.DiffDelete, Text: "b"},
+			{Type: domain.DiffInsert, Text: "x"},
+			{Type: domain.DiffEqual, Text: "c"},
+		}, diff)
+	})
+
+	t.Run("Test inserted and deleted lines keep the common lines", func(t *testing.T) {
+		diff := domain.DiffLines("a\nb\nc\nd", "b\nc\ne\nd")
+
+		assert.Equal(t, []domain.DiffLine{
+			{Type: domain.DiffDelete, Text: "a"},
+			{Type: domain.DiffEqual, Text: "b"},
+			{Type: domain.DiffEqual, Text: "c"},
+			{Type: domain.DiffInsert, Text: "e"},
+			{Type: domain.DiffEqual, Text: "d"},
+		}, diff)
+	})
+
+	t.Run("Test an empty text has no lines", func(t *testing.T) {
+		assert.Equal(t, []domain.DiffLine{{Type: domain.DiffInsert, Text: "a"}}, domain.DiffLines("", "a"))
+		assert.Equal(t, []domain.DiffLine{{Type: domain.DiffDelete, Text: "a"}}, domain.DiffLines("a", ""))
+		assert.Empty(t, domain.DiffLines("", ""))
+	})
+}
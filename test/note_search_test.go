@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the search results are paged in the order of their rank and that the notes in the trash are only searched on request
+func TestSearchNotes(t *testing.T) {
+	userId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+
+	// results returns the search results ranked from the highest to the lowest rank
+	results := func(n int) *[]domain.NoteSearchResult {
+		res := make([]domain.NoteSearchResult, n)
+		for i := range res {
+			res[i] = domain.NoteSearchResult{Note: domain.Note{Id: uuid.New(), UserId: userId}, Rank: 1 / float64(i+1)}
+		}
+		return &res
+	}
+
+	setup := func(t *testing.T) (*testMocks, service.NoteService) {
+		m := newTestMocks(t)
+		m.FileRepository.On("ListFilesByNotesIds", ctx, mock.Anything).Return(&[]domain.File{}, nil)
+		m.TagRepository.On("ListTagsByNotesIds", ctx, mock.Anything).Return(&[]domain.NoteTag{}, nil)
+		return m, m.noteService()
+	}
+
+	t.Run("Test a full page keeps the rank order and returns the cursor of its last result", func(t *testing.T) {
+		m, noteService := setup(t)
+		ranked := results(4)
+		// One result past the page is requested to know there is a next page
+		m.NoteRepository.On("SearchNotes", ctx, userId, "meeting", false, (*domain.NoteSearchCursor)(nil), int32(4)).Return(ranked, nil).Once()
+
+		res, err := noteService.SearchNotes(ctx, "meeting", false, nil, 3)
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Results, 3)
+		for i := range res.Results {
+			assert.Equal(t, (*ranked)[i].Note.Id, res.Results[i].Note.Id)
+		}
+		last := res.Results[2]
+		assert.Equal(t, domain.NoteSearchCursor{Rank: last.Rank, Id: last.Note.Id}.Encode(), res.NextCursor)
+	})
+
+	t.Run("Test the last page has no cursor", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("SearchNotes", ctx, userId, "meeting", false, (*domain.NoteSearchCursor)(nil), int32(4)).Return(results(3), nil).Once()
+
+		res, err := noteService.SearchNotes(ctx, "meeting", false, nil, 3)
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Results, 3)
+		assert.Empty(t, res.NextCursor)
+	})
+
+	t.Run("Test the notes in the trash are searched on request", func(t *testing.T) {
+		m, noteService := setup(t)
+		cursor := &domain.NoteSearchCursor{Rank: 0.5, Id: uuid.New()}
+		m.NoteRepository.On("SearchNotes", ctx, userId, "meeting", true, cursor, int32(11)).Return(results(1), nil).Once()
+
+		_, err := noteService.SearchNotes(ctx, "meeting", true, cursor, 10)
+
+		assert.NoError(t, err)
+		m.NoteRepository.AssertNotCalled(t, "SearchNotes", ctx, userId, "meeting", false, mock.Anything, mock.Anything)
+	})
+}
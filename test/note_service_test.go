@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the note mutations are scoped to the user in the context and that
+// a note of another user is reported as not found
+func TestNoteServiceOwnership(t *testing.T) {
+	userId := uuid.New()
+	noteId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+
+	setup := func(t *testing.T) (*testMocks, service.NoteService) {
+		m := newTestMocks(t)
+		// The revision is copied from the note of the user, so it is a no-op for a foreign note
+		m.NoteRevisionRepository.On("CreateNoteRevision", ctx, mock.Anything, noteId, userId, mock.Anything).Return(nil).Maybe()
+		return m, m.noteService()
+	}
+
+	t.Run("Test updating a foreign note returns not found", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("UpdateNote", ctx, mock.Anything, mock.MatchedBy(func(note *domain.Note) bool {
+			return note.Id == noteId && note.UserId == userId
+		})).Return(nil, &customerrors.RecordNotFound{}).Maybe()
+		m.NoteRepository.On("GetNote", ctx, noteId).Return(&domain.Note{Id: noteId, UserId: uuid.New()}, nil)
+
+		result, err := noteService.UpdateNote(ctx, &domain.Note{Id: noteId, Title: "title"}, nil, nil)
+
+		assert.EqualError(t, err, "note not found")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test restoring a foreign note returns not found", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("RestoreNote", ctx, mock.Anything, noteId, userId).Return(nil, &customerrors.RecordNotFound{})
+
+		result, err := noteService.RestoreNote(ctx, noteId)
+
+		assert.EqualError(t, err, "note not found")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test pinning a foreign note returns not found", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("SetNotePinned", ctx, mock.Anything, noteId, userId, true).Return(nil, &customerrors.RecordNotFound{})
+
+		result, err := noteService.PinNote(ctx, noteId)
+
+		assert.EqualError(t, err, "note not found")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test archiving a foreign note returns not found", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("SetNoteArchived", ctx, mock.Anything, noteId, userId, true).Return(nil, &customerrors.RecordNotFound{})
+
+		result, err := noteService.ArchiveNote(ctx, noteId)
+
+		assert.EqualError(t, err, "note not found")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test restoring a note publishes the restored event", func(t *testing.T) {
+		m := newTestMocks(t)
+		noteService := m.noteService()
+		note := &domain.Note{Id: noteId, UserId: userId, Title: "title"}
+		m.NoteRepository.On("RestoreNote", ctx, mock.Anything, noteId, userId).Return(note, nil)
+		m.NoteEventRepository.On("PublishNoteEvent", ctx, mock.MatchedBy(func(event *domain.NoteEvent) bool {
+			return event.Type == domain.NoteRestored && event.NoteId == noteId && event.UserId == userId && event.Note == note
+		}), mock.Anything).Return(nil)
+
+		result, err := noteService.RestoreNote(ctx, noteId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, note, result)
+		// The cached note is removed once the restore is committed
+		m.NoteRepository.AssertCalled(t, "DeleteCachedNotes", ctx, []uuid.UUID{noteId})
+	})
+
+	t.Run("Test a note in the trash is not found", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("GetNote", ctx, noteId).Return(&domain.Note{Id: noteId, UserId: userId, DeleteTime: time.Now()}, nil)
+
+		result, err := noteService.GetNote(ctx, noteId)
+
+		assert.EqualError(t, err, "note not found")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test soft deleting a foreign note returns not found", func(t *testing.T) {
+		m, noteService := setup(t)
+		m.NoteRepository.On("DeleteNote", ctx, mock.Anything, noteId, userId, false).Return(&customerrors.RecordNotFound{})
+
+		err := noteService.DeleteNote(ctx, noteId, false)
+
+		assert.EqualError(t, err, "note not found")
+		// A rolled back change keeps the cached note
+		m.NoteRepository.AssertNotCalled(t, "DeleteCachedNotes", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Test hard deleting a foreign note returns not found and keeps its files", func(t *testing.T) {
+		m, noteService := setup(t)
+		files := []domain.File{{Id: uuid.New(), NoteId: noteId, OriginalFile: "original/file.jpg"}}
+		m.FileRepository.On("LockFilesByNotesIds", ctx, mock.Anything, []uuid.UUID{noteId}).Return(&files, nil)
+		m.NoteRepository.On("DeleteNote", ctx, mock.Anything, noteId, userId, true).Return(&customerrors.RecordNotFound{})
+
+		err := noteService.DeleteNote(ctx, noteId, true)
+
+		assert.EqualError(t, err, "note not found")
+		m.FileRepository.AssertNotCalled(t, "HardDeleteFiles", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// Test that an update with a stale version is rejected with the current copy of the note
+func TestNoteServiceVersionConflict(t *testing.T) {
+	userId := uuid.New()
+	noteId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+
+	m := newTestMocks(t)
+	noteService := m.noteService()
+
+	current := &domain.Note{Id: noteId, UserId: userId, Title: "title", Content: "server content", Version: 3}
+	m.NoteRevisionRepository.On("CreateNoteRevision", ctx, mock.Anything, noteId, userId, mock.Anything).Return(nil)
+	m.NoteRepository.On("UpdateNote", ctx, mock.Anything, mock.MatchedBy(func(note *domain.Note) bool {
+		return note.Id == noteId && note.Version == 2
+	})).Return(nil, &customerrors.RecordNotFound{})
+	m.NoteRepository.On("GetNote", ctx, noteId).Return(current, nil)
+	m.FileRepository.On("ListFilesByNotesIds", ctx, []uuid.UUID{noteId}).Return(&[]domain.File{}, nil)
+	m.TagRepository.On("ListTagsByNotesIds", ctx, []uuid.UUID{noteId}).Return(&[]domain.NoteTag{}, nil)
+
+	result, err := noteService.UpdateNote(ctx, &domain.Note{Id: noteId, Content: "client content", Version: 2}, nil, nil)
+
+	assert.EqualError(t, err, "note version conflict")
+	assert.Equal(t, current, result)
+}
+
+// Test that a patch that sends the title and the content unchanged doesn't add a revision
+func TestNoteServiceUnchangedRevision(t *testing.T) {
+	userId := uuid.New()
+	noteId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+
+	m := newTestMocks(t)
+	noteService := m.noteService()
+
+	current := &domain.Note{Id: noteId, UserId: userId, Title: "title", Content: "content", Version: 2}
+	updated := &domain.Note{Id: noteId, UserId: userId, Title: "title", Content: "content", Version: 3}
+	m.NoteRepository.On("GetNote", ctx, noteId).Return(current, nil)
+	m.NoteRepository.On("UpdateNote", ctx, mock.Anything, mock.Anything).Return(updated, nil)
+	m.TagRepository.On("DetachTagsFromNote", ctx, mock.Anything, noteId, userId, mock.Anything).Return(nil)
+	m.TagRepository.On("AttachTagsToNote", ctx, mock.Anything, noteId, userId, mock.Anything).Return(nil)
+	m.TagRepository.On("ListTagsByNoteId", ctx, mock.Anything, noteId).Return(&[]domain.Tag{}, nil)
+	m.NoteRepository.On("DeleteCachedNotes", ctx, mock.Anything).Return(nil).Maybe()
+	m.NoteEventRepository.On("PublishNoteEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+
+	result, err := noteService.UpdateNote(ctx, &domain.Note{Id: noteId, Title: "title", Content: "content", Version: 2}, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, updated, result)
+	m.NoteRevisionRepository.AssertNotCalled(t, "CreateNoteRevision", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
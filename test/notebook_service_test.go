@@ -0,0 +1,98 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that a notebook can't be moved inside itself or one of its descendants
+func TestNotebookServiceMove(t *testing.T) {
+	userId := uuid.New()
+	notebookId := uuid.New()
+	childId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+
+	// setup returns the tree of the notebook, it is only read once the notebooks of the user are locked
+	setup := func(t *testing.T, tree []uuid.UUID) *testMocks {
+		m := newTestMocks(t)
+		lock := m.NotebookRepository.On("LockNotebooks", ctx, mock.Anything, userId).Return(nil).Once()
+		m.NotebookRepository.On("ListNotebookTreeIds", ctx, mock.Anything, notebookId, userId).Return(tree, nil).NotBefore(lock)
+		return m
+	}
+
+	t.Run("Test moving a notebook inside a descendant is rejected", func(t *testing.T) {
+		m := setup(t, []uuid.UUID{notebookId, childId})
+
+		result, err := m.notebookService().MoveNotebook(ctx, notebookId, &childId)
+
+		assert.EqualError(t, err, "invalid parent notebook")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test moving a notebook inside itself is rejected", func(t *testing.T) {
+		m := setup(t, []uuid.UUID{notebookId})
+
+		result, err := m.notebookService().MoveNotebook(ctx, notebookId, &notebookId)
+
+		assert.EqualError(t, err, "invalid parent notebook")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Test moving a foreign notebook returns not found", func(t *testing.T) {
+		m := setup(t, []uuid.UUID{})
+
+		result, err := m.notebookService().MoveNotebook(ctx, notebookId, &childId)
+
+		assert.EqualError(t, err, "notebook not found")
+		assert.Nil(t, result)
+	})
+}
+
+// Test that the notes of a deleted notebook are published as changed
+func TestNotebookServiceDelete(t *testing.T) {
+	userId := uuid.New()
+	notebookId := uuid.New()
+	ctx := domain.SetUserInContext(context.Background(), userId)
+	notes := []domain.Note{{Id: uuid.New(), UserId: userId}, {Id: uuid.New(), UserId: userId}}
+
+	setup := func(t *testing.T, trashNotes bool) *testMocks {
+		m := newTestMocks(t)
+		lock := m.NotebookRepository.On("LockNotebooks", ctx, mock.Anything, userId).Return(nil).Once()
+		m.NotebookRepository.On("ListNotebookTreeIds", ctx, mock.Anything, notebookId, userId).Return([]uuid.UUID{notebookId}, nil).NotBefore(lock)
+		m.NoteRepository.On("RemoveNotesFromNotebooks", ctx, mock.Anything, userId, []uuid.UUID{notebookId}, trashNotes).Return(&notes, nil).Once()
+		m.NotebookRepository.On("DeleteNotebook", ctx, mock.Anything, notebookId, userId).Return(nil).Once()
+		return m
+	}
+
+	t.Run("Test the notes moved to the root are published as updated", func(t *testing.T) {
+		m := setup(t, false)
+		for _, note := range notes {
+			m.NoteEventRepository.On("PublishNoteEvent", ctx, mock.MatchedBy(func(event *domain.NoteEvent) bool {
+				return event.Type == domain.NoteUpdated && event.NoteId == note.Id && event.Note != nil
+			}), mock.Anything).Return(nil).Once()
+		}
+
+		err := m.notebookService().DeleteNotebook(ctx, notebookId, false)
+
+		assert.NoError(t, err)
+		m.NoteRepository.AssertCalled(t, "DeleteCachedNotes", ctx, []uuid.UUID{notes[0].Id, notes[1].Id})
+	})
+
+	t.Run("Test the notes moved to the trash are published as deleted", func(t *testing.T) {
+		m := setup(t, true)
+		for _, note := range notes {
+			m.NoteEventRepository.On("PublishNoteEvent", ctx, mock.MatchedBy(func(event *domain.NoteEvent) bool {
+				return event.Type == domain.NoteDeleted && event.NoteId == note.Id && !event.Hard
+			}), mock.Anything).Return(nil).Once()
+		}
+
+		err := m.notebookService().DeleteNotebook(ctx, notebookId, true)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the objects of the hard deleted files are recorded in the outbox instead of removed
+func TestHardDeleteFilesRecordsObjectDeletions(t *testing.T) {
+	ctx := context.Background()
+	objectDeletionDatabaseDs := mocks.NewObjectDeletionDatabaseDs(t)
+	fileRepository := domain.NewFileRepository(nil, objectDeletionDatabaseDs, nil, &config.Configuration{ObjectStorageServiceBucket: "bucket"})
+	objectDeletionDatabaseDs.On("CreateObjectDeletions", ctx, mock.Anything, "bucket", []string{"processed/a.jpg", "original/a.jpg", "processed/a-128.jpg", "original/b.m4a"}).Return(nil).Once()
+
+	err := fileRepository.HardDeleteFiles(ctx, nil, &[]domain.File{
+		{OriginalFile: "original/a.jpg", ProcessedFile: "processed/a.jpg", Renditions: []domain.FileRendition{{Size: 128, ObjectName: "processed/a-128.jpg"}}},
+		{OriginalFile: "original/b.m4a"},
+	})
+
+	assert.NoError(t, err)
+}
+
+// Test that the worker removes the due objects, retries the failed ones with a backoff and
+// dead-letters the ones that run out of attempts
+func TestDeleteObjects(t *testing.T) {
+	ctx := context.Background()
+	removed := domain.ObjectDeletion{Id: uuid.New(), Bucket: "bucket", ObjectName: "original/a.jpg"}
+	retried := domain.ObjectDeletion{Id: uuid.New(), Bucket: "bucket", ObjectName: "original/b.jpg", Attempts: 1}
+	dead := domain.ObjectDeletion{Id: uuid.New(), Bucket: "bucket", ObjectName: "original/c.jpg", Attempts: 2}
+	opts := service.DeleteObjectsOptions{BatchSize: 10, MaxAttempts: 3, RetryBackoff: time.Minute}
+
+	objectDeletionRepository := mocks.NewObjectDeletionRepository(t)
+	objectDeletionRepository.On("ListDueObjectDeletions", ctx, mock.Anything, int32(10)).Return(&[]domain.ObjectDeletion{removed, retried, dead}, nil).Once()
+	objectDeletionRepository.On("RemoveObject", ctx, &removed).Return(nil)
+	objectDeletionRepository.On("RemoveObject", ctx, &retried).Return(errors.New("timeout"))
+	objectDeletionRepository.On("RemoveObject", ctx, &dead).Return(errors.New("access denied"))
+	// The second failed attempt waits twice the backoff
+	objectDeletionRepository.On("FailObjectDeletion", ctx, mock.Anything, retried.Id, "timeout", mock.MatchedBy(func(next time.Time) bool {
+		wait := time.Until(next)
+		return wait > time.Minute && wait <= 2*time.Minute
+	}), false).Return(nil).Once()
+	objectDeletionRepository.On("FailObjectDeletion", ctx, mock.Anything, dead.Id, "access denied", mock.Anything, true).Return(nil).Once()
+	objectDeletionRepository.On("DeleteObjectDeletions", ctx, mock.Anything, []uuid.UUID{removed.Id}).Return(nil).Once()
+	objectDeletionService := service.NewObjectDeletionService(objectDeletionRepository, newStubDb())
+
+	summary, err := objectDeletionService.DeleteObjects(ctx, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &service.DeleteObjectsSummary{Removed: 1, Failed: 1, DeadLettered: 1, Batches: 1}, summary)
+}
+
+// Test that the non positive options are rejected before any deletion is listed
+func TestDeleteObjectsOptions(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		opts service.DeleteObjectsOptions
+		err  string
+	}{
+		{"batch size", service.DeleteObjectsOptions{BatchSize: 0, MaxAttempts: 3, RetryBackoff: time.Minute}, "batch size must be positive"},
+		{"max attempts", service.DeleteObjectsOptions{BatchSize: 10, MaxAttempts: 0, RetryBackoff: time.Minute}, "max attempts must be positive"},
+		{"retry backoff", service.DeleteObjectsOptions{BatchSize: 10, MaxAttempts: 3, RetryBackoff: 0}, "retry backoff must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objectDeletionService := service.NewObjectDeletionService(mocks.NewObjectDeletionRepository(t), newStubDb())
+
+			summary, err := objectDeletionService.DeleteObjects(ctx, tt.opts)
+
+			assert.EqualError(t, err, tt.err)
+			assert.Equal(t, &service.DeleteObjectsSummary{}, summary)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/config"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the reprocessing goes through the selected files batch by batch and reports every file
+func TestReprocessFiles(t *testing.T) {
+	ctx := context.Background()
+	filter := domain.FileFilter{Type: "picture"}
+	unprocessed := domain.File{Id: uuid.New(), NoteId: uuid.New(), OriginalFile: "original/a.jpg"}
+	processed := domain.File{Id: uuid.New(), NoteId: uuid.New(), OriginalFile: "original/b.jpg", ProcessedFile: "processed/b.jpg"}
+	broken := domain.File{Id: uuid.New(), NoteId: uuid.New(), OriginalFile: "original/c.jpg"}
+
+	// listFiles lists the files in two batches
+	listFiles := func(fileRepository *mocks.FileRepository) {
+		fileRepository.On("CountFilesForReprocessing", ctx, filter).Return(int64(3), nil).Once()
+		fileRepository.On("ListFilesForReprocessing", ctx, filter, uuid.Nil, int32(2)).Return(&[]domain.File{unprocessed, processed}, nil).Once()
+		fileRepository.On("ListFilesForReprocessing", ctx, filter, processed.Id, int32(2)).Return(&[]domain.File{broken}, nil).Once()
+	}
+
+	t.Run("the processed files are skipped unless forced and a failed file doesn't stop the others", func(t *testing.T) {
+		fileRepository := mocks.NewFileRepository(t)
+		listFiles(fileRepository)
+		fileRepository.On("Reprocess", ctx, mock.Anything, &unprocessed).Return(&domain.File{Id: unprocessed.Id, NoteId: unprocessed.NoteId}, nil).Once()
+		fileRepository.On("Reprocess", ctx, mock.Anything, &broken).Return(nil, errors.New("failed to decode JPEG image")).Once()
+		// The pending jobs of the reprocessed file are completed in its transaction
+		fileJobRepository := mocks.NewFileJobRepository(t)
+		fileJobRepository.On("CompleteFileJobsByFileId", ctx, mock.Anything, unprocessed.Id).Return(nil).Once()
+		noteRepository := mocks.NewNoteRepository(t)
+		noteRepository.On("GetNote", ctx, unprocessed.NoteId).Return(&domain.Note{Id: unprocessed.NoteId, UserId: uuid.New()}, nil).Once()
+		eventRepository := mocks.NewNoteEventRepository(t)
+		eventRepository.On("PublishNoteEvent", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+		fileJobService := service.NewFileJobService(fileJobRepository, fileRepository, noteRepository, eventRepository, config.Configuration{}, newStubDb())
+
+		var progress []service.ReprocessFilesProgress
+		summary, err := fileJobService.ReprocessFiles(ctx, service.ReprocessFilesOptions{
+			Filter:      filter,
+			Concurrency: 1,
+			BatchSize:   2,
+			Progress:    func(p service.ReprocessFilesProgress) { progress = append(progress, p) },
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &service.ReprocessFilesSummary{Total: 3, Reprocessed: 1, Skipped: 1, Failed: 1}, summary)
+		assert.Len(t, progress, 3)
+		assert.True(t, progress[1].Skipped)
+		assert.EqualError(t, progress[2].Err, "failed to decode JPEG image")
+	})
+
+	t.Run("the dry run only reports the files", func(t *testing.T) {
+		fileRepository := mocks.NewFileRepository(t)
+		listFiles(fileRepository)
+		fileJobService := service.NewFileJobService(mocks.NewFileJobRepository(t), fileRepository, mocks.NewNoteRepository(t), mocks.NewNoteEventRepository(t), config.Configuration{}, newStubDb())
+
+		summary, err := fileJobService.ReprocessFiles(ctx, service.ReprocessFilesOptions{
+			Filter:      filter,
+			Force:       true,
+			Concurrency: 2,
+			BatchSize:   2,
+			DryRun:      true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &service.ReprocessFilesSummary{Total: 3, Reprocessed: 3}, summary)
+	})
+
+	t.Run("the objects uploaded for a file that is not saved are discarded", func(t *testing.T) {
+		fileRepository := mocks.NewFileRepository(t)
+		listFiles(fileRepository)
+		fileRepository.On("Reprocess", ctx, mock.Anything, &unprocessed).Return(nil, &domain.UnsavedObjectsError{
+			ObjectNames: []string{"processed/a.jpg", "processed/a-128.jpg"},
+			Err:         errors.New("timeout"),
+		}).Once()
+		fileRepository.On("Reprocess", ctx, mock.Anything, &broken).Return(nil, errors.New("failed to decode JPEG image")).Once()
+		// Only the objects of the file whose copies were uploaded are discarded
+		fileRepository.On("DiscardObjects", ctx, mock.Anything, []string{"processed/a.jpg", "processed/a-128.jpg"}).Return(nil).Once()
+		fileJobService := service.NewFileJobService(mocks.NewFileJobRepository(t), fileRepository, mocks.NewNoteRepository(t), mocks.NewNoteEventRepository(t), config.Configuration{}, newStubDb())
+
+		summary, err := fileJobService.ReprocessFiles(ctx, service.ReprocessFilesOptions{
+			Filter:      filter,
+			Concurrency: 1,
+			BatchSize:   2,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &service.ReprocessFilesSummary{Total: 3, Skipped: 1, Failed: 2}, summary)
+	})
+
+	t.Run("a zero concurrency is rejected", func(t *testing.T) {
+		fileJobService := service.NewFileJobService(mocks.NewFileJobRepository(t), mocks.NewFileRepository(t), mocks.NewNoteRepository(t), mocks.NewNoteEventRepository(t), config.Configuration{}, newStubDb())
+
+		summary, err := fileJobService.ReprocessFiles(ctx, service.ReprocessFilesOptions{Filter: filter, BatchSize: 2})
+
+		assert.EqualError(t, err, "concurrency must be positive")
+		assert.Nil(t, summary)
+	})
+}
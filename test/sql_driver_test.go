@@ -0,0 +1,85 @@
+package test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// stubDriver is a database/sql driver that only supports transactions. It lets the
+// services open and commit their transactions while the repositories are mocked.
+type stubDriver struct{}
+
+type stubConn struct {
+	txs *stubTxs
+}
+
+type stubTx struct {
+	txs *stubTxs
+}
+
+// stubTxs counts the transactions committed and rolled back through a stub database
+type stubTxs struct {
+	mu        sync.Mutex
+	commits   int
+	rollbacks int
+}
+
+// stubDatabases are the transaction counters of the stub databases by their data source name
+var stubDatabases sync.Map
+
+func (stubDriver) Open(name string) (driver.Conn, error) {
+	txs, _ := stubDatabases.LoadOrStore(name, &stubTxs{})
+	return stubConn{txs: txs.(*stubTxs)}, nil
+}
+
+func (stubConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("stub driver does not run queries")
+}
+
+func (stubConn) Close() error { return nil }
+
+func (c stubConn) Begin() (driver.Tx, error) { return stubTx{txs: c.txs}, nil }
+
+func (t stubTx) Commit() error {
+	t.txs.mu.Lock()
+	defer t.txs.mu.Unlock()
+	t.txs.commits++
+	return nil
+}
+
+func (t stubTx) Rollback() error {
+	t.txs.mu.Lock()
+	defer t.txs.mu.Unlock()
+	t.txs.rollbacks++
+	return nil
+}
+
+// Counts returns the number of committed and rolled back transactions
+func (t *stubTxs) Counts() (commits int, rollbacks int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.commits, t.rollbacks
+}
+
+func init() {
+	sql.Register("stub", stubDriver{})
+}
+
+// newStubDb returns a *sql.DB backed by the stub driver
+func newStubDb() *sql.DB {
+	db, _ := newRecordingStubDb()
+	return db
+}
+
+// newRecordingStubDb returns a *sql.DB backed by the stub driver along the counters of its transactions
+func newRecordingStubDb() (*sql.DB, *stubTxs) {
+	name := uuid.NewString()
+	txs := &stubTxs{}
+	stubDatabases.Store(name, txs)
+	db, _ := sql.Open("stub", name)
+	return db, txs
+}
@@ -0,0 +1,200 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test the sync token round trip and the rejection of the tokens not issued by a sync
+func TestSyncToken(t *testing.T) {
+	position, err := domain.DecodeSyncToken(domain.EncodeSyncToken(domain.SyncPosition{ChangeXid: 7, ChangeSeq: 42}))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SyncPosition{ChangeXid: 7, ChangeSeq: 42}, position)
+
+	position, err = domain.DecodeSyncToken("")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SyncPosition{}, position)
+
+	// A token with the change sequence alone starts over
+	position, err = domain.DecodeSyncToken("NDI")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SyncPosition{}, position)
+
+	for _, token := range []string{"%%%", "YWJj", domain.EncodeSyncToken(domain.SyncPosition{ChangeXid: -1, ChangeSeq: 1})} {
+		_, err = domain.DecodeSyncToken(token)
+		assert.EqualError(t, err, "invalid sync token", token)
+	}
+}
+
+// Test that the changes of the notes, files and tombstones are merged in the order of the changes
+func TestSyncRepositoryListChangesSince(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	since := domain.SyncPosition{ChangeXid: 5, ChangeSeq: 10}
+
+	setup := func(t *testing.T, notes []domain.Note, files []domain.File, tombstones []domain.Tombstone) domain.SyncRepository {
+		ds := mocks.NewSyncDatabaseDs(t)
+		ds.On("GetSyncHorizon", ctx, mock.Anything).Return(int64(9), nil)
+		ds.On("ListNotesChangedSince", ctx, mock.Anything, userId, since, int64(9), int32(3)).Return(&notes, nil)
+		ds.On("ListFilesChangedSince", ctx, mock.Anything, userId, since, int64(9), int32(3)).Return(&files, nil)
+		ds.On("ListTombstonesSince", ctx, mock.Anything, userId, since, int64(9), int32(3)).Return(&tombstones, nil)
+		return domain.NewSyncRepository(ds)
+	}
+
+	t.Run("Test a full page stops at the last change of the page", func(t *testing.T) {
+		repository := setup(t,
+			[]domain.Note{{ChangeXid: 6, ChangeSeq: 11}, {ChangeXid: 7, ChangeSeq: 14}, {ChangeXid: 8, ChangeSeq: 15}},
+			[]domain.File{{ChangeXid: 6, ChangeSeq: 12}},
+			[]domain.Tombstone{{ChangeXid: 7, ChangeSeq: 13}},
+		)
+
+		changes, err := repository.ListChangesSince(ctx, nil, userId, since, 2)
+
+		assert.NoError(t, err)
+		assert.True(t, changes.HasMore)
+		assert.Equal(t, domain.EncodeSyncToken(domain.SyncPosition{ChangeXid: 6, ChangeSeq: 12}), changes.Token)
+		assert.Equal(t, []domain.Note{{ChangeXid: 6, ChangeSeq: 11}}, changes.Notes)
+		assert.Equal(t, []domain.File{{ChangeXid: 6, ChangeSeq: 12}}, changes.Files)
+		assert.Empty(t, changes.Tombstones)
+	})
+
+	t.Run("Test the changes are ordered by transaction before the change sequence", func(t *testing.T) {
+		repository := setup(t,
+			[]domain.Note{{ChangeXid: 6, ChangeSeq: 20}, {ChangeXid: 8, ChangeSeq: 12}},
+			[]domain.File{{ChangeXid: 7, ChangeSeq: 11}},
+			[]domain.Tombstone{},
+		)
+
+		changes, err := repository.ListChangesSince(ctx, nil, userId, since, 2)
+
+		assert.NoError(t, err)
+		assert.True(t, changes.HasMore)
+		assert.Equal(t, domain.EncodeSyncToken(domain.SyncPosition{ChangeXid: 7, ChangeSeq: 11}), changes.Token)
+		assert.Equal(t, []domain.Note{{ChangeXid: 6, ChangeSeq: 20}}, changes.Notes)
+		assert.Equal(t, []domain.File{{ChangeXid: 7, ChangeSeq: 11}}, changes.Files)
+	})
+
+	t.Run("Test the token is kept when there are no changes", func(t *testing.T) {
+		repository := setup(t, []domain.Note{}, []domain.File{}, []domain.Tombstone{})
+
+		changes, err := repository.ListChangesSince(ctx, nil, userId, since, 2)
+
+		assert.NoError(t, err)
+		assert.False(t, changes.HasMore)
+		assert.Equal(t, domain.EncodeSyncToken(since), changes.Token)
+	})
+}
+
+// syncDatabase is a database of note changes with the visibility rules of postgres. The transactions take their id
+// when they begin and a change sequence value on every write, the changes are visible once their transaction commits.
+type syncDatabase struct {
+	nextXid    int64
+	nextSeq    int64
+	inProgress map[int64]bool
+	notes      []domain.Note
+}
+
+func newSyncDatabase() *syncDatabase {
+	return &syncDatabase{nextXid: 100, nextSeq: 1, inProgress: map[int64]bool{}}
+}
+
+func (d *syncDatabase) begin() int64 {
+	xid := d.nextXid
+	d.nextXid++
+	d.inProgress[xid] = true
+	return xid
+}
+
+func (d *syncDatabase) write(xid int64, note domain.Note) {
+	note.ChangeXid = xid
+	note.ChangeSeq = d.nextSeq
+	d.nextSeq++
+	d.notes = append(d.notes, note)
+}
+
+func (d *syncDatabase) commit(xid int64) {
+	delete(d.inProgress, xid)
+}
+
+func (d *syncDatabase) GetSyncHorizon(ctx context.Context, tx *sql.Tx) (int64, error) {
+	horizon := d.nextXid
+	for xid := range d.inProgress {
+		horizon = min(horizon, xid)
+	}
+	return horizon, nil
+}
+
+func (d *syncDatabase) ListNotesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.Note, error) {
+	notes := []domain.Note{}
+	for _, note := range d.notes {
+		changed := domain.SyncPosition{ChangeXid: note.ChangeXid, ChangeSeq: note.ChangeSeq}
+		if !d.inProgress[note.ChangeXid] && changed.After(position) && note.ChangeXid < horizon {
+			notes = append(notes, note)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return domain.SyncPosition{ChangeXid: notes[j].ChangeXid, ChangeSeq: notes[j].ChangeSeq}.After(domain.SyncPosition{ChangeXid: notes[i].ChangeXid, ChangeSeq: notes[i].ChangeSeq})
+	})
+	if len(notes) > int(limit) {
+		notes = notes[:limit]
+	}
+	return &notes, nil
+}
+
+func (d *syncDatabase) ListFilesChangedSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.File, error) {
+	return &[]domain.File{}, nil
+}
+
+func (d *syncDatabase) ListTombstonesSince(ctx context.Context, tx *sql.Tx, userId uuid.UUID, position domain.SyncPosition, horizon int64, limit int32) (*[]domain.Tombstone, error) {
+	return &[]domain.Tombstone{}, nil
+}
+
+// Test that no change is lost when two writers overlap and commit out of the order of the change sequence
+func TestSyncRepositoryOverlappingWriters(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	first := domain.Note{Id: uuid.New(), UserId: userId}
+	second := domain.Note{Id: uuid.New(), UserId: userId}
+
+	db := newSyncDatabase()
+	repository := domain.NewSyncRepository(db)
+
+	// The first writer takes the lower change sequence value but commits after the second one
+	firstXid := db.begin()
+	secondXid := db.begin()
+	db.write(firstXid, first)
+	db.write(secondXid, second)
+	db.commit(secondXid)
+
+	changes, err := repository.ListChangesSince(ctx, nil, userId, domain.SyncPosition{}, 10)
+
+	// The change of the second writer waits for the first writer, the token can't move past the first change
+	assert.NoError(t, err)
+	assert.Empty(t, changes.Notes)
+
+	db.commit(firstXid)
+	position, err := domain.DecodeSyncToken(changes.Token)
+	assert.NoError(t, err)
+
+	changes, err = repository.ListChangesSince(ctx, nil, userId, position, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, changes.Notes, 2)
+	assert.Equal(t, first.Id, changes.Notes[0].Id)
+	assert.Equal(t, second.Id, changes.Notes[1].Id)
+
+	// A later sync from the last token has nothing left
+	position, err = domain.DecodeSyncToken(changes.Token)
+	assert.NoError(t, err)
+	changes, err = repository.ListChangesSince(ctx, nil, userId, position, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, changes.Notes)
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/daniarmas/notes/internal/customerrors"
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that only the tags of the user are attached to a note
+func TestTagRepositoryAttachTagsToNote(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	noteId := uuid.New()
+	tagId := uuid.New()
+
+	t.Run("Test attaching the tags of the user", func(t *testing.T) {
+		tagDatabaseDs := mocks.NewTagDatabaseDs(t)
+		tagRepository := domain.NewTagRepository(tagDatabaseDs)
+		tagIds := []uuid.UUID{tagId, tagId}
+		tagDatabaseDs.On("ListTagsByIds", ctx, userId, tagIds).Return(&[]domain.Tag{{Id: tagId, UserId: userId}}, nil)
+		tagDatabaseDs.On("AttachTagsToNote", ctx, mock.Anything, noteId, userId, tagIds).Return(nil)
+
+		err := tagRepository.AttachTagsToNote(ctx, nil, noteId, userId, tagIds)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test attaching a tag of another user returns not found", func(t *testing.T) {
+		tagDatabaseDs := mocks.NewTagDatabaseDs(t)
+		tagRepository := domain.NewTagRepository(tagDatabaseDs)
+		foreignTagId := uuid.New()
+		tagIds := []uuid.UUID{tagId, foreignTagId}
+		tagDatabaseDs.On("ListTagsByIds", ctx, userId, tagIds).Return(&[]domain.Tag{{Id: tagId, UserId: userId}}, nil)
+
+		err := tagRepository.AttachTagsToNote(ctx, nil, noteId, userId, tagIds)
+
+		assert.IsType(t, &customerrors.RecordNotFound{}, err)
+		tagDatabaseDs.AssertNotCalled(t, "AttachTagsToNote", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/daniarmas/notes/internal/domain"
+	"github.com/daniarmas/notes/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test that the trash is purged in batches that advance past the last note of the previous batch
+func TestPurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	deleteTime := time.Now().UTC().Add(-60 * 24 * time.Hour)
+	first := []domain.Note{{Id: uuid.New(), DeleteTime: deleteTime}, {Id: uuid.New(), DeleteTime: deleteTime}}
+	second := []domain.Note{{Id: uuid.New(), DeleteTime: deleteTime.Add(time.Minute)}}
+	files := []domain.File{{Id: uuid.New(), NoteId: first[0].Id}}
+
+	// setup expects the batches of the expired notes, only the notes that are purged are published
+	setup := func(t *testing.T) (*testMocks, service.TrashService) {
+		m := newTestMocks(t)
+		m.NoteRepository.On("ListExpiredTrashNotes", ctx, mock.Anything, mock.Anything, (*domain.NoteListCursor)(nil), int32(2)).Return(&first, nil)
+		m.NoteRepository.On("ListExpiredTrashNotes", ctx, mock.Anything, mock.Anything, &domain.NoteListCursor{Time: deleteTime, Id: first[1].Id}, int32(2)).Return(&second, nil)
+		m.FileRepository.On("LockFilesByNotesIds", ctx, mock.Anything, []uuid.UUID{first[0].Id, first[1].Id}).Return(&files, nil)
+		m.FileRepository.On("LockFilesByNotesIds", ctx, mock.Anything, []uuid.UUID{second[0].Id}).Return(&[]domain.File{}, nil)
+		m.NoteEventRepository.On("PublishNoteEvent", ctx, mock.MatchedBy(func(event *domain.NoteEvent) bool {
+			return event.NoteId == second[0].Id && event.Type == domain.NoteDeleted && event.Hard
+		}), mock.Anything).Return(nil).Maybe()
+		return m, m.trashService()
+	}
+
+	t.Run("Test a dry run counts the notes and files without deleting them", func(t *testing.T) {
+		_, trashService := setup(t)
+
+		summary, err := trashService.PurgeTrash(ctx, service.PurgeTrashOptions{Retention: 30 * 24 * time.Hour, BatchSize: 2, DryRun: true})
+
+		assert.NoError(t, err)
+		assert.True(t, summary.DryRun)
+		assert.Equal(t, 3, summary.Notes)
+		assert.Equal(t, 1, summary.Files)
+		assert.Equal(t, 2, summary.Batches)
+		assert.Equal(t, 0, summary.FailedBatches)
+	})
+
+	t.Run("Test a failed batch is skipped", func(t *testing.T) {
+		m, trashService := setup(t)
+		m.NoteRepository.On("HardDeleteTrashNotes", ctx, mock.Anything, []uuid.UUID{first[0].Id, first[1].Id}).Return(nil, assert.AnError)
+		m.NoteRepository.On("HardDeleteTrashNotes", ctx, mock.Anything, []uuid.UUID{second[0].Id}).Return(&second, nil)
+		m.FileRepository.On("HardDeleteFiles", ctx, mock.Anything, &[]domain.File{}).Return(nil)
+
+		summary, err := trashService.PurgeTrash(ctx, service.PurgeTrashOptions{Retention: 30 * 24 * time.Hour, BatchSize: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, summary.Notes)
+		assert.Equal(t, 0, summary.Files)
+		assert.Equal(t, 2, summary.Batches)
+		assert.Equal(t, 1, summary.FailedBatches)
+	})
+}
+
+// Test that a purge without a positive retention or batch size is refused, it would purge the whole trash or nothing
+func TestPurgeTrashRetention(t *testing.T) {
+	for _, retention := range []time.Duration{0, -time.Hour} {
+		m := newTestMocks(t)
+
+		summary, err := m.trashService().PurgeTrash(context.Background(), service.PurgeTrashOptions{Retention: retention, BatchSize: 2})
+
+		assert.EqualError(t, err, "trash retention must be positive")
+		assert.NotNil(t, summary)
+		assert.Equal(t, 0, summary.Notes)
+	}
+	for _, batchSize := range []int{0, -1} {
+		m := newTestMocks(t)
+
+		summary, err := m.trashService().PurgeTrash(context.Background(), service.PurgeTrashOptions{Retention: time.Hour, BatchSize: batchSize})
+
+		assert.EqualError(t, err, "trash purge batch size must be positive")
+		assert.NotNil(t, summary)
+		assert.Equal(t, 0, summary.Notes)
+	}
+}